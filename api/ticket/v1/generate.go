@@ -0,0 +1,4 @@
+// Package ticketv1 is the gRPC API of the ticket service.
+package ticketv1
+
+//go:generate sh -c "cd ../.. && buf generate"
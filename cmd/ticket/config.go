@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/BurntSushi/toml"
 	"github.com/cronnoss/tk-api/internal/app"
@@ -31,7 +32,6 @@ func NewConfig() Config {
 		fmt.Fprintf(os.Stderr, "Can't load config file:%v error: %v\n", configFile, err)
 		os.Exit(1)
 	}
-	fmt.Println("Config:", config)
 	return config
 }
 
@@ -40,5 +40,12 @@ func (c *Config) LoadConfigFile(filename string) error {
 	if err != nil {
 		return err
 	}
-	return toml.Unmarshal(filedata, c)
+	if err := toml.Unmarshal(filedata, c); err != nil {
+		return err
+	}
+	// Templates live alongside the config.
+	if t := c.Tickets.PDFTemplate; t != "" && !filepath.IsAbs(t) {
+		c.Tickets.PDFTemplate = filepath.Join(filepath.Dir(filename), t)
+	}
+	return nil
 }
@@ -2,23 +2,65 @@ package main
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"time"
+	_ "time/tzdata" // nolint: revive
 
 	"github.com/cronnoss/tk-api/internal/app"
+	"github.com/cronnoss/tk-api/internal/cache"
 	"github.com/cronnoss/tk-api/internal/logger"
+	internalgrpc "github.com/cronnoss/tk-api/internal/server/grpc"
 	internalhttp "github.com/cronnoss/tk-api/internal/server/http"
 	"github.com/cronnoss/tk-api/internal/storage"
+	"github.com/cronnoss/tk-api/internal/upstream"
 )
 
 func main() {
 	conf := NewConfig().TicketConf
 	storage := storage.NewStorage(conf.Storage)
+	var provider upstream.Provider = upstream.NewClient(conf.Upstream.URL, http.DefaultClient)
 	logger := logger.NewLogger(conf.Logger.Level, os.Stdout)
-	ticket, _ := app.NewTicket(logger, conf, storage)
-	httpsrv := internalhttp.NewServer(logger, ticket, conf.HTTP.Host, conf.HTTP.Port)
+	c, err := cache.New(conf.Cache)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't create cache: %v\n", err)
+		os.Exit(1)
+	}
+	var cached *cache.Storage
+	if c != nil {
+		cached = cache.NewStorage(storage, c)
+		storage = cached
+		provider = cache.NewUpstream(provider, c)
+	}
+	ticket, err := app.NewTicket(logger, conf, storage)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't create app: %v\n", err)
+		os.Exit(1)
+	}
+	if cached != nil {
+		ticket.Subscribe("cache", cached.Invalidate)
+	}
+	timeZone, err := time.LoadLocation(conf.Events.DefaultTimeZone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't load time zone:%v error: %v\n", conf.Events.DefaultTimeZone, err)
+		os.Exit(1)
+	}
+	httpsrv := internalhttp.NewServer(logger, ticket, conf.HTTP.Host, conf.HTTP.Port,
+		internalhttp.WithUpstream(provider),
+		internalhttp.WithInvalidItemPolicy(conf.Upstream.InvalidItems),
+		internalhttp.WithPlacesSyncInterval(conf.Upstream.PlacesSyncInterval),
+		internalhttp.WithTimeZone(timeZone),
+		internalhttp.WithGraphQLLimits(conf.GraphQL.MaxDepth, conf.GraphQL.MaxComplexity),
+		internalhttp.WithAdminTokens(conf.HTTP.AdminTokens))
 
-	ticket.Run(httpsrv)
+	servers := []app.Server{httpsrv}
+	if conf.GRPC.Port != "" {
+		servers = append(servers, internalgrpc.NewServer(logger, ticket, conf.GRPC.Host, conf.GRPC.Port,
+			internalgrpc.WithTokens(conf.GRPC.Tokens)))
+	}
+
+	ticket.Run(servers...)
 
 	filename := filepath.Base(os.Args[0])
 	fmt.Printf("%s stopped\n", filename)
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cronnoss/tk-api/internal/server"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+// Notifier delivers notifications to customers and integrations.
+type Notifier interface {
+	Notify(ctx context.Context, n models.Notification) error
+}
+
+// logNotifier writes notifications to the log until a delivery channel is configured.
+type logNotifier struct {
+	log server.Logger
+}
+
+func (l logNotifier) Notify(_ context.Context, n models.Notification) error {
+	data, err := json.Marshal(n.Data)
+	if err != nil {
+		return err
+	}
+	l.log.Infof("notification %s for customer %d: %s\n", n.Type, n.CustomerID, data)
+	return nil
+}
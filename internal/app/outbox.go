@@ -0,0 +1,145 @@
+package app
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/availability"
+	"github.com/cronnoss/tk-api/internal/domain"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+const (
+	// outboxBatchSize is how many domain events are claimed at once.
+	outboxBatchSize = 100
+	// outboxClaim is how long claimed events are left to a dispatcher, events it fails to dispatch
+	// are retried after it.
+	outboxClaim = 30 * time.Second
+)
+
+// domainEvents counts dispatched domain events by type, it is published with the other expvars.
+var domainEvents = expvar.NewMap("domain_events")
+
+// Subscribe adds an in-process subscriber of domain events of the types, of all types if none are given.
+func (t *Ticket) Subscribe(name string, h domain.Handler, types ...string) {
+	t.bus.Subscribe(name, h, types...)
+}
+
+// subscribe adds the built-in subscribers.
+func (t *Ticket) subscribe() {
+	t.bus.Subscribe("metrics", countDomainEvent)
+	t.bus.Subscribe("availability", t.publishPlaces, domain.PlaceHeld, domain.PlaceReleased, domain.PlaceSold)
+	t.bus.Subscribe("webhooks", t.queueWebhooks)
+	if t.publisher != nil {
+		t.bus.Subscribe("broker", t.publisher.Publish, t.conf.Broker.Types...)
+	}
+}
+
+func countDomainEvent(_ context.Context, e models.DomainEvent) error {
+	domainEvents.Add(e.Type, 1)
+	domainEvents.AddFloat(e.Type+" lag seconds", time.Since(e.CreatedAt).Seconds())
+	return nil
+}
+
+// publishPlaces sends the places of a place event to availability subscribers.
+func (t *Ticket) publishPlaces(_ context.Context, e models.DomainEvent) error {
+	var data domain.PlacesData
+	if err := domain.Decode(e, &data); err != nil {
+		return err
+	}
+	switch e.Type {
+	case domain.PlaceHeld:
+		t.publish(data.EventID, availability.Held, models.PlaceHeld, data.PlaceIDs)
+	case domain.PlaceReleased:
+		t.publish(data.EventID, availability.Released, models.PlaceAvailable, data.PlaceIDs)
+	case domain.PlaceSold:
+		t.publish(data.EventID, availability.Sold, models.PlaceSold, data.PlaceIDs)
+	}
+	return nil
+}
+
+// wakeOutbox asks the dispatcher to hand out domain events without waiting for its interval.
+func (t *Ticket) wakeOutbox() {
+	select {
+	case t.outboxWake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatchOutbox hands domain events to subscribers every interval and whenever some are written
+// until ctx is done. Dispatched events are purged once they are older than the retention.
+func (t *Ticket) dispatchOutbox(ctx context.Context) error {
+	ticker := time.NewTicker(t.conf.Outbox.DispatchInterval)
+	defer ticker.Stop()
+	purge := time.NewTicker(t.conf.Outbox.PurgeInterval)
+	defer purge.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-purge.C:
+			ctxPurge, cancel := context.WithTimeout(ctx, 5*time.Second)
+			n, err := t.storage.DeleteDispatchedDomainEvents(ctxPurge, now.Add(-t.conf.Outbox.Retention))
+			cancel()
+			if err != nil {
+				t.log.Errorf("failed to purge domain events:%v\n", err)
+			} else if n > 0 {
+				t.log.Debugf("purged %d domain events\n", n)
+			}
+			continue
+		case <-ticker.C:
+		case <-t.outboxWake:
+		}
+		// Keep going while full batches are claimed.
+		for ctx.Err() == nil {
+			n, err := t.dispatchDomainEvents(ctx, time.Now())
+			if err != nil {
+				t.log.Errorf("failed to dispatch domain events:%v\n", err)
+				break
+			}
+			if n < outboxBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// dispatchDomainEvents hands a batch of events to subscribers in order and returns how many were claimed.
+// Events that fail are dispatched again to the subscribers that failed them once the claim runs out,
+// so a subscriber whose backend is down doesn't hold back the others.
+func (t *Ticket) dispatchDomainEvents(ctx context.Context, now time.Time) (int, error) {
+	ctxClaim, cancel := context.WithTimeout(ctx, 5*time.Second)
+	events, err := t.storage.ClaimDomainEvents(ctxClaim, now, now.Add(outboxClaim), outboxBatchSize)
+	cancel()
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	ids := make([]int64, 0, len(events))
+	var errDispatch error
+	for _, e := range events {
+		ctxEvent, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err := t.bus.Publish(ctxEvent, e)
+		cancel()
+		if err != nil {
+			if errDispatch == nil {
+				errDispatch = fmt.Errorf("failed to dispatch %s event %d: %w", e.Type, e.ID, err)
+			}
+			continue
+		}
+		ids = append(ids, e.ID)
+	}
+	if n := len(events) - len(ids); n > 1 {
+		errDispatch = fmt.Errorf("%w (and %d more events)", errDispatch, n-1)
+	}
+
+	ctxMark, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := t.storage.MarkDomainEventsDispatched(ctxMark, ids, time.Now()); err != nil {
+		return len(events), err
+	}
+	return len(events), errDispatch
+}
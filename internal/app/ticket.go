@@ -1,18 +1,36 @@
 package app
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
 	"os/signal"
+	"sort"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/cronnoss/tk-api/internal/availability"
+	"github.com/cronnoss/tk-api/internal/broker"
+	"github.com/cronnoss/tk-api/internal/cache"
+	"github.com/cronnoss/tk-api/internal/domain"
+	"github.com/cronnoss/tk-api/internal/ical"
 	"github.com/cronnoss/tk-api/internal/logger"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/money"
+	"github.com/cronnoss/tk-api/internal/pricing"
+	"github.com/cronnoss/tk-api/internal/refunds"
+	"github.com/cronnoss/tk-api/internal/seating"
 	"github.com/cronnoss/tk-api/internal/server"
 	"github.com/cronnoss/tk-api/internal/storage"
 	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/cronnoss/tk-api/internal/ticketpdf"
+	"github.com/cronnoss/tk-api/internal/tickets"
+	"github.com/cronnoss/tk-api/internal/webhooks"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -22,13 +40,122 @@ type TicketConf struct {
 	HTTP    struct {
 		Host string `toml:"host"`
 		Port string `toml:"port"`
+		// AdminTokens are the bearer tokens of administrators, admin routes are closed if empty.
+		AdminTokens []string `toml:"admin-tokens"`
 	} `toml:"http-server"`
+	GRPC struct {
+		Host string `toml:"host"`
+		// Port of the gRPC server, it isn't started if empty.
+		Port string `toml:"port"`
+		// Tokens are the bearer tokens of gRPC clients, any client is let in if empty.
+		Tokens []string `toml:"tokens"`
+	} `toml:"grpc-server"`
+	Upstream struct {
+		// URL of the remote API, upstream.DefaultURL if empty.
+		URL          string                  `toml:"url"`
+		InvalidItems model.InvalidItemPolicy `toml:"invalid-items"`
+		// PlacesSyncInterval is how often places of upstream events with live subscribers are refetched,
+		// never if zero.
+		PlacesSyncInterval time.Duration `toml:"places-sync-interval"`
+	} `toml:"upstream"`
+	// Cache keeps reads of storage and the upstream API, nothing is cached without a backend.
+	Cache  cache.Conf `toml:"cache"`
+	Events struct {
+		DefaultTimeZone string `toml:"default-time-zone"`
+	} `toml:"events"`
+	GraphQL struct {
+		// MaxDepth and MaxComplexity bound /graphql queries, defaults apply if zero.
+		MaxDepth      int `toml:"max-depth"`
+		MaxComplexity int `toml:"max-complexity"`
+	} `toml:"graphql"`
+	Idempotency struct {
+		TTL           time.Duration `toml:"ttl"`
+		PurgeInterval time.Duration `toml:"purge-interval"`
+	} `toml:"idempotency"`
+	Holds struct {
+		TTL             time.Duration `toml:"ttl"`
+		ReleaseInterval time.Duration `toml:"release-interval"`
+	} `toml:"holds"`
+	Waitlist struct {
+		OfferTTL      time.Duration `toml:"offer-ttl"`
+		MatchInterval time.Duration `toml:"match-interval"`
+	} `toml:"waitlist"`
+	Refunds refunds.Policy `toml:"refunds"`
+	Tickets struct {
+		tickets.Conf
+		// PDFTemplate is the layout of printable tickets, the built-in one if empty.
+		PDFTemplate string `toml:"pdf-template"`
+	} `toml:"tickets"`
+	Webhooks struct {
+		webhooks.Policy
+		// DispatchInterval is how often due deliveries are looked for besides when events are queued.
+		DispatchInterval time.Duration `toml:"dispatch-interval"`
+		// Timeout bounds an attempt to send a delivery.
+		Timeout time.Duration `toml:"timeout"`
+		// AllowPrivate lets endpoints on loopback and private networks receive deliveries, for local
+		// development only.
+		AllowPrivate bool `toml:"allow-private"`
+	} `toml:"webhooks"`
+	Outbox struct {
+		// DispatchInterval is how often domain events are looked for besides when they are written.
+		DispatchInterval time.Duration `toml:"dispatch-interval"`
+		// Retention is how long dispatched events are kept, they are purged every PurgeInterval.
+		Retention     time.Duration `toml:"retention"`
+		PurgeInterval time.Duration `toml:"purge-interval"`
+	} `toml:"outbox"`
+	// Broker streams domain events to an external message broker.
+	Broker   broker.Conf `toml:"broker"`
+	Calendar struct {
+		// EventDuration is how long events last in calendars, events have no end of their own.
+		EventDuration time.Duration `toml:"event-duration"`
+		// UIDDomain makes event UIDs globally unique.
+		UIDDomain string `toml:"uid-domain"`
+	} `toml:"calendar"`
 }
 
+const (
+	defaultIdempotencyTTL           = 24 * time.Hour
+	defaultIdempotencyPurgeInterval = time.Hour
+	defaultHoldTTL                  = 15 * time.Minute
+	defaultHoldReleaseInterval      = 30 * time.Second
+	defaultWaitlistOfferTTL         = 10 * time.Minute
+	defaultWaitlistMatchInterval    = time.Minute
+	defaultCalendarEventDuration    = 2 * time.Hour
+	defaultCalendarUIDDomain        = "tk-api.local"
+	defaultWebhookMaxAttempts       = 8
+	defaultWebhookRetryBase         = 30 * time.Second
+	defaultWebhookRetryMax          = time.Hour
+	defaultWebhookDispatchInterval  = 5 * time.Second
+	defaultWebhookTimeout           = 10 * time.Second
+	defaultOutboxDispatchInterval   = 5 * time.Second
+	defaultOutboxRetention          = 24 * time.Hour
+	defaultOutboxPurgeInterval      = time.Hour
+
+	// bestSeatsAttempts bounds retries when chosen places are taken by a concurrent hold.
+	bestSeatsAttempts = 3
+)
+
 type Ticket struct {
-	conf    TicketConf
-	log     server.Logger
-	storage Storage
+	conf     TicketConf
+	log      server.Logger
+	storage  Storage
+	notifier Notifier
+	signer   *tickets.Signer
+	// pdfTemplate lays out printable tickets.
+	pdfTemplate *ticketpdf.Template
+	// waitlistWake asks the waitlist matcher to run now, places may have become available.
+	waitlistWake chan struct{}
+	// availability streams place status changes to live subscribers.
+	availability *availability.Broker
+	// webhookWake asks the webhook dispatcher to send due deliveries now.
+	webhookWake chan struct{}
+	sender      *webhooks.Sender
+	// bus fans domain events out to in-process subscribers.
+	bus *domain.Bus
+	// outboxWake asks the outbox dispatcher to hand out written events now.
+	outboxWake chan struct{}
+	// publisher sends domain events to the configured broker, nil if there is none.
+	publisher broker.Publisher
 }
 
 type Storage interface {
@@ -37,12 +164,87 @@ type Storage interface {
 	GetShows(ctx context.Context) ([]models.Show, error)
 	CreateShows(ctx context.Context, shows []models.Show) ([]models.Show, error)
 	CreateShow(ctx context.Context, shows models.Show) (models.Show, error)
-	GetEvents(ctx context.Context) ([]models.Event, error)
+	GetEvents(ctx context.Context, filter models.EventFilter) ([]models.Event, error)
 	CreateEvents(ctx context.Context, events []models.Event) ([]models.Event, error)
 	CreateEvent(ctx context.Context, event models.Event) (models.Event, error)
-	GetPlaces(ctx context.Context) ([]models.Place, error)
+	GetPlaces(ctx context.Context, filter models.PlaceFilter) ([]models.Place, error)
+	GetPlacesInRect(ctx context.Context, eventID int64, r models.Rect) ([]models.Place, error)
+	GetNearestPlaces(ctx context.Context, eventID int64, pt models.Point, n int) ([]models.Place, error)
+	GetPlaceAdjacency(ctx context.Context, eventID int64) ([]models.Adjacency, error)
 	CreatePlaces(ctx context.Context, places []models.Place) ([]models.Place, error)
 	CreatePlace(ctx context.Context, place models.Place) (models.Place, error)
+	GetEvent(ctx context.Context, id int64) (models.Event, error)
+	AttachEventToHall(ctx context.Context, eventID, hallID int64, check models.Check[models.Event],
+	) (models.Event, error)
+	CancelEvent(ctx context.Context, id int64, now time.Time, check models.Check[models.Event]) (models.Event, error)
+	CreateVenue(ctx context.Context, venue models.Venue) (models.Venue, error)
+	GetVenues(ctx context.Context) ([]models.Venue, error)
+	GetVenue(ctx context.Context, id int64) (models.Venue, error)
+	CreateHall(ctx context.Context, hall models.Hall) (models.Hall, error)
+	GetHalls(ctx context.Context, venueID int64) ([]models.Hall, error)
+	GetHall(ctx context.Context, id int64) (models.Hall, error)
+	CreateSection(ctx context.Context, section models.Section) (models.Section, error)
+	GetSections(ctx context.Context, hallID int64) ([]models.Section, error)
+	CreateHallPlaces(ctx context.Context, hallID int64, places []models.Place) ([]models.Place, error)
+	GetIdempotencyKey(ctx context.Context, key string) (models.IdempotencyKey, error)
+	CreateIdempotencyKey(ctx context.Context, key models.IdempotencyKey) (models.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, key models.IdempotencyKey) error
+	DeleteIdempotencyKey(ctx context.Context, key string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+	CreateQuarantinedItems(ctx context.Context, items []models.QuarantinedItem) ([]models.QuarantinedItem, error)
+	GetQuarantinedItems(ctx context.Context) ([]models.QuarantinedItem, error)
+	CreateHold(ctx context.Context, hold models.Hold) (models.Hold, error)
+	GetHold(ctx context.Context, id int64) (models.Hold, error)
+	ReleaseHold(ctx context.Context, id int64, now time.Time) (models.Hold, error)
+	ReleaseExpiredHolds(ctx context.Context, now time.Time) ([]models.Hold, error)
+	CreatePriceCategory(ctx context.Context, category models.PriceCategory) (models.PriceCategory, error)
+	GetPriceCategories(ctx context.Context, eventID int64) ([]models.PriceCategory, error)
+	UpdatePriceCategory(ctx context.Context, category models.PriceCategory, check models.Check[models.PriceCategory],
+	) (models.PriceCategory, error)
+	DeletePriceCategory(ctx context.Context, id int64) error
+	AssignPrices(ctx context.Context, eventID int64, assignments []models.PriceAssignment,
+		check models.Check[models.Event]) (int64, error)
+	CreateOrder(ctx context.Context, order models.Order) (models.Order, error)
+	GetOrder(ctx context.Context, id int64) (models.Order, error)
+	CreatePromotion(ctx context.Context, promotion models.Promotion) (models.Promotion, error)
+	GetPromotions(ctx context.Context) ([]models.Promotion, error)
+	GetPromotion(ctx context.Context, id int64) (models.Promotion, error)
+	DeactivatePromotion(ctx context.Context, id int64, now time.Time) (models.Promotion, error)
+	GetCheckoutPromotions(ctx context.Context, codes []string) ([]models.Promotion, error)
+	GetCustomerRedemptions(ctx context.Context, customerID int64) (map[int64]int, error)
+	CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error)
+	GetCustomer(ctx context.Context, id int64) (models.Customer, error)
+	UpdateCustomer(ctx context.Context, customer models.Customer, check models.Check[models.Customer],
+	) (models.Customer, error)
+	EraseCustomer(ctx context.Context, id int64, now time.Time) (models.Customer, error)
+	GetCustomerOrders(ctx context.Context, customerID int64) ([]models.Order, error)
+	GetCustomerHolds(ctx context.Context, customerID int64) ([]models.Hold, error)
+	CreateWaitlistEntry(ctx context.Context, entry models.WaitlistEntry) (models.WaitlistEntry, error)
+	GetWaitlistEntry(ctx context.Context, id int64) (models.WaitlistEntry, error)
+	GetWaitlist(ctx context.Context, eventID int64) ([]models.WaitlistEntry, error)
+	GetWaitingEntries(ctx context.Context) ([]models.WaitlistEntry, error)
+	OfferWaitlistEntry(ctx context.Context, id, holdID int64, now time.Time) (models.WaitlistEntry, error)
+	CancelWaitlistEntry(ctx context.Context, id int64, now time.Time) (models.WaitlistEntry, error)
+	RefundOrder(ctx context.Context, refund models.Refund) (models.Refund, error)
+	ExchangeOrderItems(ctx context.Context, exchange models.Exchange) (models.Exchange, error)
+	GetAuditTrail(ctx context.Context, entity string, id int64) ([]models.AuditRecord, error)
+	GetOrderItem(ctx context.Context, id int64) (models.OrderItem, error)
+	UseOrderItem(ctx context.Context, id int64, now time.Time) (models.OrderItem, error)
+	CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, id int64) (models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	CreateWebhookDeliveries(ctx context.Context, delivery models.WebhookDelivery) ([]models.WebhookDelivery, error)
+	ClaimWebhookDeliveries(ctx context.Context, now, until time.Time, limit int) ([]models.WebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, delivery models.WebhookDelivery,
+		attempt models.WebhookAttempt) (models.WebhookDelivery, error)
+	GetWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (models.WebhookDelivery, error)
+	GetWebhookAttempts(ctx context.Context, deliveryID int64) ([]models.WebhookAttempt, error)
+	RedeliverWebhookDelivery(ctx context.Context, id int64, now time.Time) (models.WebhookDelivery, error)
+	ClaimDomainEvents(ctx context.Context, now, until time.Time, limit int) ([]models.DomainEvent, error)
+	MarkDomainEventsDispatched(ctx context.Context, ids []int64, now time.Time) error
+	DeleteDispatchedDomainEvents(ctx context.Context, before time.Time) (int64, error)
 }
 
 type Server interface {
@@ -59,49 +261,1307 @@ func (t *Ticket) GetShows(ctx context.Context) ([]models.Show, error) {
 func (t *Ticket) CreateShows(ctx context.Context, shows []models.Show) ([]models.Show, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
-	return t.storage.CreateShows(ctx, shows)
+	shows, err := t.storage.CreateShows(ctx, shows)
+	if err != nil {
+		return shows, err
+	}
+	t.wakeOutbox()
+	return shows, nil
 }
 
 func (t *Ticket) CreateShow(ctx context.Context, show models.Show) (models.Show, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
-	return t.storage.CreateShow(ctx, show)
+	show, err := t.storage.CreateShow(ctx, show)
+	if err != nil {
+		return show, err
+	}
+	t.wakeOutbox()
+	return show, nil
 }
 
-func (t *Ticket) GetEvents(ctx context.Context) ([]models.Event, error) {
+func (t *Ticket) GetEvents(ctx context.Context, filter models.EventFilter) ([]models.Event, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
-	return t.storage.GetEvents(ctx)
+	return t.storage.GetEvents(ctx, filter)
 }
 
 func (t *Ticket) CreateEvents(ctx context.Context, events []models.Event) ([]models.Event, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
-	return t.storage.CreateEvents(ctx, events)
+	events, err := t.storage.CreateEvents(ctx, events)
+	if err != nil {
+		return events, err
+	}
+	t.wakeOutbox()
+	return events, nil
 }
 
 func (t *Ticket) CreateEvent(ctx context.Context, event models.Event) (models.Event, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
-	return t.storage.CreateEvent(ctx, event)
+	event, err := t.storage.CreateEvent(ctx, event)
+	if err != nil {
+		return event, err
+	}
+	t.wakeOutbox()
+	return event, nil
+}
+
+func (t *Ticket) GetPlaces(ctx context.Context, filter models.PlaceFilter) ([]models.Place, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetPlaces(ctx, filter)
+}
+
+func (t *Ticket) GetPlacesInRect(ctx context.Context, eventID int64, r models.Rect) ([]models.Place, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetPlacesInRect(ctx, eventID, r)
+}
+
+func (t *Ticket) GetNearestPlaces(ctx context.Context, eventID int64, pt models.Point, n int) ([]models.Place, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetNearestPlaces(ctx, eventID, pt, n)
 }
 
-func (t *Ticket) GetPlaces(ctx context.Context) ([]models.Place, error) {
+func (t *Ticket) GetPlaceAdjacency(ctx context.Context, eventID int64) ([]models.Adjacency, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
-	return t.storage.GetPlaces(ctx)
+	return t.storage.GetPlaceAdjacency(ctx, eventID)
 }
 
 func (t *Ticket) CreatePlaces(ctx context.Context, places []models.Place) ([]models.Place, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
-	return t.storage.CreatePlaces(ctx, places)
+	defer t.wakeWaitlist()
+	places, err := t.storage.CreatePlaces(ctx, places)
+	if err != nil {
+		return places, err
+	}
+	t.publishSynced(places...)
+	return places, nil
 }
 
 func (t *Ticket) CreatePlace(ctx context.Context, place models.Place) (models.Place, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
-	return t.storage.CreatePlace(ctx, place)
+	defer t.wakeWaitlist()
+	place, err := t.storage.CreatePlace(ctx, place)
+	if err != nil {
+		return place, err
+	}
+	t.publishSynced(place)
+	return place, nil
+}
+
+func (t *Ticket) GetEvent(ctx context.Context, id int64) (models.Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetEvent(ctx, id)
+}
+
+func (t *Ticket) AttachEventToHall(ctx context.Context, eventID, hallID int64, check models.Check[models.Event],
+) (models.Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	defer t.wakeWaitlist()
+	return t.storage.AttachEventToHall(ctx, eventID, hallID, check)
+}
+
+// CancelEvent marks an event as cancelled, its places can't be held any more.
+func (t *Ticket) CancelEvent(ctx context.Context, id int64, check models.Check[models.Event]) (models.Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	event, err := t.storage.CancelEvent(ctx, id, time.Now(), check)
+	if err != nil {
+		return event, err
+	}
+	t.wakeOutbox()
+	return event, nil
+}
+
+func (t *Ticket) CreateVenue(ctx context.Context, venue models.Venue) (models.Venue, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.CreateVenue(ctx, venue)
+}
+
+func (t *Ticket) GetVenues(ctx context.Context) ([]models.Venue, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetVenues(ctx)
+}
+
+func (t *Ticket) GetVenue(ctx context.Context, id int64) (models.Venue, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetVenue(ctx, id)
+}
+
+func (t *Ticket) CreateHall(ctx context.Context, hall models.Hall) (models.Hall, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.CreateHall(ctx, hall)
+}
+
+func (t *Ticket) GetHalls(ctx context.Context, venueID int64) ([]models.Hall, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetHalls(ctx, venueID)
+}
+
+func (t *Ticket) GetHall(ctx context.Context, id int64) (models.Hall, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetHall(ctx, id)
+}
+
+func (t *Ticket) CreateSection(ctx context.Context, section models.Section) (models.Section, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.CreateSection(ctx, section)
+}
+
+func (t *Ticket) GetSections(ctx context.Context, hallID int64) ([]models.Section, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetSections(ctx, hallID)
+}
+
+func (t *Ticket) CreateHallPlaces(ctx context.Context, hallID int64, places []models.Place) ([]models.Place, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.CreateHallPlaces(ctx, hallID, places)
+}
+
+func (t *Ticket) GetIdempotencyKey(ctx context.Context, key string) (models.IdempotencyKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetIdempotencyKey(ctx, key)
+}
+
+// CreateIdempotencyKey reserves the key until the configured TTL elapses.
+func (t *Ticket) CreateIdempotencyKey(ctx context.Context, key models.IdempotencyKey) (models.IdempotencyKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	key.CreatedAt = time.Now()
+	key.ExpiresAt = key.CreatedAt.Add(t.conf.Idempotency.TTL)
+	return t.storage.CreateIdempotencyKey(ctx, key)
+}
+
+func (t *Ticket) CompleteIdempotencyKey(ctx context.Context, key models.IdempotencyKey) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.CompleteIdempotencyKey(ctx, key)
+}
+
+func (t *Ticket) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.DeleteIdempotencyKey(ctx, key)
+}
+
+func (t *Ticket) QuarantineItems(ctx context.Context, items []models.QuarantinedItem) ([]models.QuarantinedItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.CreateQuarantinedItems(ctx, items)
+}
+
+func (t *Ticket) GetQuarantinedItems(ctx context.Context) ([]models.QuarantinedItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetQuarantinedItems(ctx)
+}
+
+// CreateHold holds the places of the event for the customer, if set, until the configured TTL elapses.
+func (t *Ticket) CreateHold(ctx context.Context, eventID, customerID int64, placeIDs []int64) (models.Hold, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if err := t.checkCustomer(ctx, customerID); err != nil {
+		return models.Hold{}, err
+	}
+	// Storage checks the event isn't cancelled when the hold is written.
+	now := time.Now()
+	hold, err := t.storage.CreateHold(ctx, models.Hold{
+		EventID:    eventID,
+		PlaceIDs:   placeIDs,
+		CustomerID: sql.NullInt64{Int64: customerID, Valid: customerID != 0},
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(t.conf.Holds.TTL),
+	})
+	if err != nil {
+		return hold, err
+	}
+	t.wakeOutbox()
+	return hold, nil
+}
+
+func (t *Ticket) GetHold(ctx context.Context, id int64) (models.Hold, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetHold(ctx, id)
+}
+
+func (t *Ticket) ReleaseHold(ctx context.Context, id int64) (models.Hold, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	defer t.wakeWaitlist()
+	hold, err := t.storage.ReleaseHold(ctx, id, time.Now())
+	if err != nil {
+		return hold, err
+	}
+	t.wakeOutbox()
+	return hold, nil
+}
+
+// BestSeats picks the best available places of the event for prefs and, if hold is set,
+// holds them for the customer. When the picked places are held concurrently the choice
+// is repeated on fresh availability.
+func (t *Ticket) BestSeats(ctx context.Context, eventID int64, prefs seating.Preferences, hold bool,
+	customerID int64,
+) ([]models.Place, *models.Hold, error) {
+	for attempt := 1; ; attempt++ {
+		places, err := t.GetPlaces(ctx, models.PlaceFilter{EventID: eventID})
+		if err != nil {
+			return nil, nil, err
+		}
+		if prefs.MaxPrice > 0 && prefs.Prices == nil {
+			categories, err := t.GetPriceCategories(ctx, eventID)
+			if err != nil {
+				return nil, nil, err
+			}
+			prefs.Prices = pricing.NewCategories(categories)
+		}
+		best, err := seating.Best(places, prefs)
+		if err != nil || !hold {
+			return best, nil, err
+		}
+
+		ids := make([]int64, 0, len(best))
+		for _, p := range best {
+			ids = append(ids, p.ID)
+		}
+		h, err := t.CreateHold(ctx, eventID, customerID, ids)
+		if errors.Is(err, model.ErrUnavailable) && attempt < bestSeatsAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range best {
+			best[i].IsAvailable = false
+			best[i].HoldID = sql.NullInt64{Int64: h.ID, Valid: true}
+		}
+		return best, &h, nil
+	}
+}
+
+func (t *Ticket) CreatePriceCategory(ctx context.Context, category models.PriceCategory,
+) (models.PriceCategory, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.CreatePriceCategory(ctx, category)
+}
+
+func (t *Ticket) GetPriceCategories(ctx context.Context, eventID int64) ([]models.PriceCategory, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetPriceCategories(ctx, eventID)
+}
+
+func (t *Ticket) UpdatePriceCategory(ctx context.Context, category models.PriceCategory,
+	check models.Check[models.PriceCategory],
+) (models.PriceCategory, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.UpdatePriceCategory(ctx, category, check)
+}
+
+func (t *Ticket) DeletePriceCategory(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.DeletePriceCategory(ctx, id)
+}
+
+func (t *Ticket) AssignPrices(ctx context.Context, eventID int64, assignments []models.PriceAssignment,
+	check models.Check[models.Event],
+) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	defer t.wakeWaitlist()
+	return t.storage.AssignPrices(ctx, eventID, assignments, check)
+}
+
+// QuoteHold prices the places of an active hold and applies the promotions of the checkout
+// without selling anything.
+func (t *Ticket) QuoteHold(ctx context.Context, checkout models.Checkout) (pricing.Quote, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	_, quote, err := t.quote(ctx, checkout, time.Now())
+	return quote, err
+}
+
+// CreateOrder sells the places of an active hold at their current prices less the promotions of the checkout.
+func (t *Ticket) CreateOrder(ctx context.Context, checkout models.Checkout) (models.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	hold, quote, err := t.quote(ctx, checkout, now)
+	if err != nil {
+		return models.Order{}, err
+	}
+	if checkout.CustomerID == 0 {
+		checkout.CustomerID = hold.CustomerID.Int64
+	}
+
+	order := models.Order{
+		EventID:    hold.EventID,
+		HoldID:     sql.NullInt64{Int64: hold.ID, Valid: true},
+		Status:     models.OrderConfirmed,
+		Currency:   quote.Currency,
+		Subtotal:   quote.Subtotal,
+		Total:      quote.Total,
+		CustomerID: sql.NullInt64{Int64: checkout.CustomerID, Valid: checkout.CustomerID != 0},
+		CreatedAt:  now,
+	}
+	for _, line := range quote.Lines {
+		order.Items = append(order.Items, models.OrderItem{
+			PlaceID:         line.PlaceID,
+			PriceCategoryID: sql.NullInt64{Int64: line.CategoryID, Valid: true},
+			Amount:          line.Amount,
+			Discount:        line.Discount,
+		})
+	}
+	for _, d := range quote.Discounts {
+		order.Discounts = append(order.Discounts, models.OrderDiscount{
+			PromotionID: d.PromotionID,
+			Code:        d.Code,
+			Name:        d.Name,
+			Description: d.Description,
+			Amount:      d.Amount,
+		})
+	}
+	order, err = t.storage.CreateOrder(ctx, order)
+	if err != nil {
+		return order, err
+	}
+	t.wakeOutbox()
+	return order, nil
+}
+
+// quote prices the places of the checkout hold if it is active at now and applies promotions.
+func (t *Ticket) quote(ctx context.Context, checkout models.Checkout, now time.Time,
+) (models.Hold, pricing.Quote, error) {
+	hold, err := t.storage.GetHold(ctx, checkout.HoldID)
+	if err != nil {
+		return hold, pricing.Quote{}, err
+	}
+	if !hold.IsActive(now) {
+		return hold, pricing.Quote{}, model.ErrUnavailable
+	}
+	// The order goes to the customer of the hold, another customer can't buy it.
+	switch {
+	case checkout.CustomerID == 0:
+		checkout.CustomerID = hold.CustomerID.Int64
+	case hold.CustomerID.Valid && hold.CustomerID.Int64 != checkout.CustomerID:
+		return hold, pricing.Quote{}, model.ErrInvalidUserID
+	default:
+		if err := t.checkCustomer(ctx, checkout.CustomerID); err != nil {
+			return hold, pricing.Quote{}, err
+		}
+	}
+	places, err := t.holdPlaces(ctx, hold)
+	if err != nil {
+		return hold, pricing.Quote{}, err
+	}
+	categories, err := t.storage.GetPriceCategories(ctx, hold.EventID)
+	if err != nil {
+		return hold, pricing.Quote{}, err
+	}
+	quote, err := pricing.Price(places, pricing.NewCategories(categories))
+	if err != nil {
+		return hold, pricing.Quote{}, err
+	}
+
+	event, err := t.storage.GetEvent(ctx, hold.EventID)
+	if err != nil {
+		return hold, pricing.Quote{}, err
+	}
+	codes := make([]string, 0, len(checkout.PromoCodes))
+	for _, code := range checkout.PromoCodes {
+		codes = append(codes, pricing.NormalizeCode(code))
+	}
+	promotions, err := t.storage.GetCheckoutPromotions(ctx, codes)
+	if err != nil {
+		return hold, pricing.Quote{}, err
+	}
+	c := pricing.Checkout{
+		ShowID:     event.ShowID,
+		EventID:    event.ID,
+		Codes:      codes,
+		Now:        now,
+		CustomerID: checkout.CustomerID,
+	}
+	if checkout.CustomerID != 0 {
+		if c.Redeemed, err = t.storage.GetCustomerRedemptions(ctx, checkout.CustomerID); err != nil {
+			return hold, pricing.Quote{}, err
+		}
+	}
+	quote, err = pricing.Apply(quote, promotions, c)
+	return hold, quote, err
+}
+
+// holdPlaces returns the places of the hold.
+func (t *Ticket) holdPlaces(ctx context.Context, hold models.Hold) ([]models.Place, error) {
+	places, err := t.storage.GetPlaces(ctx, models.PlaceFilter{EventID: hold.EventID})
+	if err != nil {
+		return nil, err
+	}
+	held := make(map[int64]bool, len(hold.PlaceIDs))
+	for _, id := range hold.PlaceIDs {
+		held[id] = true
+	}
+	res := make([]models.Place, 0, len(hold.PlaceIDs))
+	for _, p := range places {
+		if held[p.ID] {
+			res = append(res, p)
+		}
+	}
+	return res, nil
+}
+
+func (t *Ticket) GetOrder(ctx context.Context, id int64) (models.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetOrder(ctx, id)
+}
+
+// CreatePromotion creates a promotion, its code is stored normalized.
+func (t *Ticket) CreatePromotion(ctx context.Context, promotion models.Promotion) (models.Promotion, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	promotion.Code = pricing.NormalizeCode(promotion.Code)
+	return t.storage.CreatePromotion(ctx, promotion)
+}
+
+func (t *Ticket) GetPromotions(ctx context.Context) ([]models.Promotion, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetPromotions(ctx)
+}
+
+func (t *Ticket) GetPromotion(ctx context.Context, id int64) (models.Promotion, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetPromotion(ctx, id)
+}
+
+func (t *Ticket) DeactivatePromotion(ctx context.Context, id int64) (models.Promotion, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.DeactivatePromotion(ctx, id, time.Now())
+}
+
+// issuedItems returns the issued unused items of the order with the IDs, all of them if itemIDs is empty.
+// ErrUnavailable is returned if any of the items is refunded, exchanged or used, or there is none.
+func issuedItems(order models.Order, itemIDs []int64) ([]models.OrderItem, error) {
+	returnable := func(item models.OrderItem) bool {
+		return item.Status == models.ItemIssued && !item.UsedAt.Valid
+	}
+	byID := make(map[int64]models.OrderItem, len(order.Items))
+	var issued []models.OrderItem
+	for _, item := range order.Items {
+		byID[item.ID] = item
+		if returnable(item) {
+			issued = append(issued, item)
+		}
+	}
+	if len(itemIDs) == 0 {
+		if len(issued) == 0 {
+			return nil, model.ErrUnavailable
+		}
+		return issued, nil
+	}
+	res := make([]models.OrderItem, 0, len(itemIDs))
+	for _, id := range itemIDs {
+		item, ok := byID[id]
+		if !ok || !returnable(item) {
+			return nil, model.ErrUnavailable
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+// refundsOpen returns refunds.ErrDeadlinePassed if the event of any item is too close.
+func (t *Ticket) refundsOpen(ctx context.Context, items []models.OrderItem, now time.Time) error {
+	checked := make(map[int64]bool)
+	for _, item := range items {
+		if checked[item.EventID] {
+			continue
+		}
+		event, err := t.storage.GetEvent(ctx, item.EventID)
+		if err != nil {
+			return err
+		}
+		if err := t.conf.Refunds.Open(event.Date, now); err != nil {
+			return err
+		}
+		checked[item.EventID] = true
+	}
+	return nil
+}
+
+// RefundOrder refunds issued items of an order, all of them if itemIDs is empty, less the policy fees.
+// Their places go back on sale.
+func (t *Ticket) RefundOrder(ctx context.Context, orderID int64, itemIDs []int64, reason string,
+) (models.Refund, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	order, err := t.storage.GetOrder(ctx, orderID)
+	if err != nil {
+		return models.Refund{}, err
+	}
+	items, err := issuedItems(order, itemIDs)
+	if err != nil {
+		return models.Refund{}, err
+	}
+	now := time.Now()
+	if err := t.refundsOpen(ctx, items, now); err != nil {
+		return models.Refund{}, err
+	}
+
+	refund := models.Refund{OrderID: orderID, Reason: reason, CreatedAt: now}
+	for _, item := range items {
+		fee := t.conf.Refunds.Fee(item.Paid())
+		refund.ItemIDs = append(refund.ItemIDs, item.ID)
+		refund.Fee += fee
+		refund.Amount += item.Paid() - fee
+	}
+	refund, err = t.storage.RefundOrder(ctx, refund)
+	if err != nil {
+		return refund, err
+	}
+	t.wakeOutbox()
+	t.wakeWaitlist()
+	return refund, nil
+}
+
+// ExchangeOrderItems exchanges issued items of an order for available places of another event of the same show,
+// one place for every item. The new places are priced at their categories.
+func (t *Ticket) ExchangeOrderItems(ctx context.Context, orderID int64, itemIDs []int64, eventID int64,
+	placeIDs []int64,
+) (models.Exchange, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	order, err := t.storage.GetOrder(ctx, orderID)
+	if err != nil {
+		return models.Exchange{}, err
+	}
+	items, err := issuedItems(order, itemIDs)
+	if err != nil {
+		return models.Exchange{}, err
+	}
+	if len(placeIDs) != len(items) {
+		return models.Exchange{}, model.ErrInvalidValue
+	}
+	from, err := t.storage.GetEvent(ctx, order.EventID)
+	if err != nil {
+		return models.Exchange{}, err
+	}
+	to, err := t.storage.GetEvent(ctx, eventID)
+	if err != nil {
+		return models.Exchange{}, err
+	}
+	if from.ShowID != to.ShowID {
+		return models.Exchange{}, model.ErrInvalidShowIDs
+	}
+	now := time.Now()
+	if err := t.refundsOpen(ctx, items, now); err != nil {
+		return models.Exchange{}, err
+	}
+	if err := t.conf.Refunds.Open(to.Date, now); err != nil {
+		return models.Exchange{}, err
+	}
+
+	places, err := t.storage.GetPlaces(ctx, models.PlaceFilter{EventID: eventID})
+	if err != nil {
+		return models.Exchange{}, err
+	}
+	byID := make(map[int64]models.Place, len(places))
+	for _, p := range places {
+		byID[p.ID] = p
+	}
+	wanted := make([]models.Place, 0, len(placeIDs))
+	for _, id := range placeIDs {
+		p, ok := byID[id]
+		if !ok || !p.IsAvailable {
+			return models.Exchange{}, model.ErrUnavailable
+		}
+		wanted = append(wanted, p)
+	}
+	categories, err := t.storage.GetPriceCategories(ctx, eventID)
+	if err != nil {
+		return models.Exchange{}, err
+	}
+	quote, err := pricing.Price(wanted, pricing.NewCategories(categories))
+	if err != nil {
+		return models.Exchange{}, err
+	}
+	if quote.Currency != order.Currency {
+		return models.Exchange{}, pricing.ErrMixedCurrencies
+	}
+
+	exchange := models.Exchange{
+		OrderID:   orderID,
+		EventID:   eventID,
+		Fee:       t.conf.Refunds.ExchangeFee * int64(len(items)),
+		CreatedAt: now,
+	}
+	for i, line := range quote.Lines {
+		exchange.ItemIDs = append(exchange.ItemIDs, items[i].ID)
+		exchange.Items = append(exchange.Items, models.OrderItem{
+			PlaceID:         line.PlaceID,
+			PriceCategoryID: sql.NullInt64{Int64: line.CategoryID, Valid: true},
+			Amount:          line.Amount,
+			ExchangedFrom:   sql.NullInt64{Int64: items[i].ID, Valid: true},
+		})
+		exchange.Difference += line.Amount - items[i].Paid()
+	}
+	exchange, err = t.storage.ExchangeOrderItems(ctx, exchange)
+	if err != nil {
+		return exchange, err
+	}
+	t.wakeOutbox()
+	t.wakeWaitlist()
+	return exchange, nil
+}
+
+// GetOrderAudit returns the lifecycle of an order oldest first.
+func (t *Ticket) GetOrderAudit(ctx context.Context, orderID int64) ([]models.AuditRecord, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if _, err := t.storage.GetOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+	return t.storage.GetAuditTrail(ctx, "order", orderID)
+}
+
+// TicketCode returns the signed code of an issued ticket, the order item with the ID.
+func (t *Ticket) TicketCode(ctx context.Context, id int64) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	item, err := t.storage.GetOrderItem(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if item.Status != models.ItemIssued {
+		return "", model.ErrUnavailable
+	}
+	return t.signer.Sign(ticketClaims(item)), nil
+}
+
+func ticketClaims(item models.OrderItem) tickets.Claims {
+	return tickets.Claims{
+		TicketID: item.ID,
+		OrderID:  item.OrderID,
+		EventID:  item.EventID,
+		PlaceID:  item.PlaceID,
+	}
+}
+
+// TicketKeys returns the public keys ticket codes are verified with, the current one first.
+func (t *Ticket) TicketKeys() []ed25519.PublicKey {
+	return t.signer.PublicKeys()
+}
+
+// ScanTicket admits the ticket with the code at the door of the event, any event if eventID is 0,
+// and marks it as used. The ticket is returned with ErrUnavailable if it is refunded or exchanged
+// and with ErrAlreadyUsed if it has been admitted before.
+func (t *Ticket) ScanTicket(ctx context.Context, code string, eventID int64) (models.OrderItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	claims, err := t.signer.Verify(code)
+	if err != nil {
+		return models.OrderItem{}, err
+	}
+	if eventID != 0 && claims.EventID != eventID {
+		return models.OrderItem{EventID: claims.EventID}, model.ErrWrongEvent
+	}
+	item, err := t.storage.GetOrderItem(ctx, claims.TicketID)
+	if err != nil {
+		return item, err
+	}
+	if item.OrderID != claims.OrderID || item.PlaceID != claims.PlaceID || item.EventID != claims.EventID {
+		// Signed by us for another ticket, the storage has been rebuilt since.
+		return models.OrderItem{}, tickets.ErrSignature
+	}
+	return t.storage.UseOrderItem(ctx, item.ID, time.Now())
+}
+
+// orderEvent is an event an order has tickets for with what tickets say about it.
+type orderEvent struct {
+	event  models.Event
+	show   string
+	venue  models.Venue
+	hall   models.Hall
+	places map[int64]models.Place
+}
+
+// orderEvents returns the events of the issued tickets of an order by ID,
+// exchanged tickets may be for other events of the show.
+func (t *Ticket) orderEvents(ctx context.Context, order models.Order) (map[int64]*orderEvent, error) {
+	shows, err := t.showNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events := make(map[int64]*orderEvent)
+	for _, item := range order.Items {
+		if _, ok := events[item.EventID]; ok || item.Status != models.ItemIssued {
+			continue
+		}
+		event, err := t.storage.GetEvent(ctx, item.EventID)
+		if err != nil {
+			return nil, err
+		}
+		e := &orderEvent{event: event, show: shows[event.ShowID], places: make(map[int64]models.Place)}
+		if e.venue, e.hall, err = t.eventVenue(ctx, event); err != nil {
+			return nil, err
+		}
+		places, err := t.storage.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range places {
+			e.places[p.ID] = p
+		}
+		events[event.ID] = e
+	}
+	return events, nil
+}
+
+func (t *Ticket) showNames(ctx context.Context) (map[int64]string, error) {
+	shows, err := t.storage.GetShows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int64]string, len(shows))
+	for _, s := range shows {
+		names[s.ID] = s.Name
+	}
+	return names, nil
+}
+
+// eventVenue returns the venue and hall of an event, zero values if it has no hall.
+func (t *Ticket) eventVenue(ctx context.Context, event models.Event) (models.Venue, models.Hall, error) {
+	if !event.HallID.Valid {
+		return models.Venue{}, models.Hall{}, nil
+	}
+	hall, err := t.storage.GetHall(ctx, event.HallID.Int64)
+	if err != nil {
+		return models.Venue{}, models.Hall{}, err
+	}
+	venue, err := t.storage.GetVenue(ctx, hall.VenueID)
+	if err != nil {
+		return models.Venue{}, models.Hall{}, err
+	}
+	return venue, hall, nil
+}
+
+// OrderTicketsPDF returns a PDF with a printable page for every issued ticket of an order.
+// ErrUnavailable is returned if the order has no issued tickets.
+func (t *Ticket) OrderTicketsPDF(ctx context.Context, orderID int64) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	order, err := t.storage.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := t.orderEvents(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []ticketpdf.Ticket
+	for _, item := range order.Items {
+		if item.Status != models.ItemIssued {
+			continue
+		}
+		e := events[item.EventID]
+		list = append(list, ticketpdf.Ticket{
+			TicketID: item.ID,
+			OrderID:  order.ID,
+			Show:     e.show,
+			Venue:    e.venue.Name,
+			Hall:     e.hall.Name,
+			Date:     e.event.LocalDate(),
+			Seat:     e.places[item.PlaceID].Label(),
+			Price:    money.Format(item.Paid(), order.Currency),
+			Code:     t.signer.Sign(ticketClaims(item)),
+		})
+	}
+	if len(list) == 0 {
+		return nil, model.ErrUnavailable
+	}
+
+	var b bytes.Buffer
+	if err := t.pdfTemplate.Render(&b, list); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// calendarEvent returns the calendar event of an event in the venue time zone.
+func (t *Ticket) calendarEvent(ctx context.Context, event models.Event, show string) (ical.Event, error) {
+	venue, hall, err := t.eventVenue(ctx, event)
+	if err != nil {
+		return ical.Event{}, err
+	}
+	var location []string
+	for _, part := range []string{venue.Name, hall.Name, venue.Address} {
+		if part != "" {
+			location = append(location, part)
+		}
+	}
+	return ical.Event{
+		UID:       fmt.Sprintf("event-%d@%s", event.ID, t.conf.Calendar.UIDDomain),
+		Start:     event.LocalDate(),
+		Duration:  t.conf.Calendar.EventDuration,
+		Summary:   show,
+		Location:  strings.Join(location, ", "),
+		Sequence:  event.Sequence,
+		Cancelled: event.CancelledAt.Valid,
+		Modified:  event.UpdatedAt.Time,
+	}, nil
+}
+
+// ShowCalendar returns a calendar of the events of a show, cancelled ones included.
+func (t *Ticket) ShowCalendar(ctx context.Context, showID int64) (ical.Calendar, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	shows, err := t.showNames(ctx)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	name, ok := shows[showID]
+	if !ok {
+		return ical.Calendar{}, model.ErrNotFound
+	}
+	events, err := t.storage.GetEvents(ctx, models.EventFilter{ShowID: showID})
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	cal := ical.Calendar{Name: name}
+	for _, event := range events {
+		e, err := t.calendarEvent(ctx, event, name)
+		if err != nil {
+			return ical.Calendar{}, err
+		}
+		cal.Events = append(cal.Events, e)
+	}
+	return cal, nil
+}
+
+// OrderCalendar returns a calendar of the events an order has issued tickets for, described by the seats.
+// ErrUnavailable is returned if the order has no issued tickets.
+func (t *Ticket) OrderCalendar(ctx context.Context, orderID int64) (ical.Calendar, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	order, err := t.storage.GetOrder(ctx, orderID)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	events, err := t.orderEvents(ctx, order)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	if len(events) == 0 {
+		return ical.Calendar{}, model.ErrUnavailable
+	}
+	seats := make(map[int64][]string)
+	for _, item := range order.Items {
+		if e, ok := events[item.EventID]; ok && item.Status == models.ItemIssued {
+			seats[item.EventID] = append(seats[item.EventID], e.places[item.PlaceID].Label())
+		}
+	}
+
+	cal := ical.Calendar{Name: fmt.Sprintf("Order %d", order.ID)}
+	for _, e := range events {
+		ce, err := t.calendarEvent(ctx, e.event, e.show)
+		if err != nil {
+			return ical.Calendar{}, err
+		}
+		ce.Description = fmt.Sprintf("Order %d: %s", order.ID, strings.Join(seats[e.event.ID], "; "))
+		cal.Events = append(cal.Events, ce)
+	}
+	sort.Slice(cal.Events, func(i, j int) bool { return cal.Events[i].Start.Before(cal.Events[j].Start) })
+	return cal, nil
+}
+
+// checkCustomer returns ErrInvalidUserID unless the customer, if set, exists and is not erased.
+func (t *Ticket) checkCustomer(ctx context.Context, customerID int64) error {
+	if customerID == 0 {
+		return nil
+	}
+	c, err := t.storage.GetCustomer(ctx, customerID)
+	if errors.Is(err, model.ErrNotFound) || (err == nil && c.ErasedAt.Valid) {
+		return model.ErrInvalidUserID
+	}
+	return err
+}
+
+// CreateCustomer creates a customer, emails are stored lower case.
+func (t *Ticket) CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	customer.Email = strings.ToLower(strings.TrimSpace(customer.Email))
+	customer.CreatedAt = time.Now()
+	return t.storage.CreateCustomer(ctx, customer)
+}
+
+func (t *Ticket) GetCustomer(ctx context.Context, id int64) (models.Customer, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetCustomer(ctx, id)
+}
+
+func (t *Ticket) UpdateCustomer(ctx context.Context, customer models.Customer, check models.Check[models.Customer],
+) (models.Customer, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	customer.Email = strings.ToLower(strings.TrimSpace(customer.Email))
+	customer.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	return t.storage.UpdateCustomer(ctx, customer, check)
+}
+
+// EraseCustomer anonymises a customer. Orders keep their totals but no longer identify the person.
+func (t *Ticket) EraseCustomer(ctx context.Context, id int64) (models.Customer, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.EraseCustomer(ctx, id, time.Now())
+}
+
+// GetCustomerOrders returns the purchase history of a customer.
+func (t *Ticket) GetCustomerOrders(ctx context.Context, customerID int64) ([]models.Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if _, err := t.storage.GetCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+	return t.storage.GetCustomerOrders(ctx, customerID)
+}
+
+// ExportCustomer collects everything stored about a customer.
+func (t *Ticket) ExportCustomer(ctx context.Context, id int64) (models.CustomerExport, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	var export models.CustomerExport
+	var err error
+	if export.Customer, err = t.storage.GetCustomer(ctx, id); err != nil {
+		return export, err
+	}
+	if export.Holds, err = t.storage.GetCustomerHolds(ctx, id); err != nil {
+		return export, err
+	}
+	if export.Orders, err = t.storage.GetCustomerOrders(ctx, id); err != nil {
+		return export, err
+	}
+	return export, nil
+}
+
+// JoinWaitlist puts a customer on the waitlist of an event.
+// ErrPlacesAvailable is returned if places matching the entry can be bought right away.
+func (t *Ticket) JoinWaitlist(ctx context.Context, entry models.WaitlistEntry) (models.WaitlistEntry, error) {
+	if entry.CustomerID == 0 {
+		return models.WaitlistEntry{}, model.ErrInvalidUserID
+	}
+	if err := t.checkCustomer(ctx, entry.CustomerID); err != nil {
+		return models.WaitlistEntry{}, err
+	}
+	_, _, err := t.BestSeats(ctx, entry.EventID, seating.Preferences{
+		Quantity: entry.Quantity,
+		MaxPrice: entry.MaxPrice,
+	}, false, 0)
+	if err == nil {
+		return models.WaitlistEntry{}, model.ErrPlacesAvailable
+	}
+	if !errors.Is(err, seating.ErrNoSeats) {
+		return models.WaitlistEntry{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	entry.CreatedAt = time.Now()
+	return t.storage.CreateWaitlistEntry(ctx, entry)
+}
+
+func (t *Ticket) GetWaitlist(ctx context.Context, eventID int64) ([]models.WaitlistEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetWaitlist(ctx, eventID)
+}
+
+func (t *Ticket) GetWaitlistEntry(ctx context.Context, id int64) (models.WaitlistEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetWaitlistEntry(ctx, id)
+}
+
+// CancelWaitlistEntry takes an entry off the waitlist, places offered to it go back on sale.
+func (t *Ticket) CancelWaitlistEntry(ctx context.Context, id int64) (models.WaitlistEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	now := time.Now()
+	entry, err := t.storage.CancelWaitlistEntry(ctx, id, now)
+	if err != nil {
+		return entry, err
+	}
+	if entry.HoldID.Valid {
+		if _, err := t.storage.ReleaseHold(ctx, entry.HoldID.Int64, now); err != nil &&
+			!errors.Is(err, model.ErrNotFound) {
+			return entry, err
+		}
+		t.wakeOutbox()
+		t.wakeWaitlist()
+	}
+	return entry, nil
+}
+
+// SubscribeAvailability subscribes to place status changes of an event after lastID, see availability.Broker.
+func (t *Ticket) SubscribeAvailability(eventID, lastID int64) *availability.Subscription {
+	return t.availability.Subscribe(eventID, lastID)
+}
+
+// AvailabilitySubscribed returns the events with live availability subscribers.
+func (t *Ticket) AvailabilitySubscribed() []int64 {
+	return t.availability.Subscribed()
+}
+
+// publish sends a status change of places of an event to availability subscribers.
+func (t *Ticket) publish(eventID int64, kind availability.Kind, status models.PlaceStatus, placeIDs []int64) {
+	now := time.Now()
+	changes := make([]availability.Change, 0, len(placeIDs))
+	for _, id := range placeIDs {
+		changes = append(changes, availability.Change{
+			EventID: eventID, PlaceID: id, Kind: kind, Status: status, At: now,
+		})
+	}
+	t.availability.Publish(changes...)
+}
+
+// publishSynced publishes statuses of places stored from upstream.
+func (t *Ticket) publishSynced(places ...models.Place) {
+	now := time.Now()
+	changes := make([]availability.Change, 0, len(places))
+	for _, p := range places {
+		if !p.EventID.Valid {
+			continue
+		}
+		changes = append(changes, availability.Change{
+			EventID: p.EventID.Int64, PlaceID: p.ID, Kind: availability.Synced, Status: p.Status(), At: now,
+		})
+	}
+	t.availability.Publish(changes...)
+}
+
+// wakeWaitlist asks the waitlist matcher to run without waiting for its interval.
+func (t *Ticket) wakeWaitlist() {
+	select {
+	case t.waitlistWake <- struct{}{}:
+	default:
+	}
+}
+
+// runWaitlist matches the waitlist every interval and whenever places may have become available
+// until ctx is done.
+func (t *Ticket) runWaitlist(ctx context.Context) error {
+	ticker := time.NewTicker(t.conf.Waitlist.MatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-t.waitlistWake:
+		}
+		ctxMatch, cancel := context.WithTimeout(ctx, 10*time.Second)
+		offered, err := t.matchWaitlist(ctxMatch, time.Now())
+		cancel()
+		// Holds and offers made by the match are dispatched to subscribers.
+		t.wakeOutbox()
+		if err != nil {
+			t.log.Errorf("failed to match waitlist:%v\n", err)
+		}
+		if offered > 0 {
+			t.log.Debugf("offered places to %d waitlisted customers\n", offered)
+		}
+	}
+}
+
+// matchWaitlist offers available places to waiting customers in the order they joined by holding
+// the best places matching each entry for the offer TTL. An entry that can't be served doesn't block
+// later entries that can, e.g. smaller parties. It returns the number of entries offered places.
+func (t *Ticket) matchWaitlist(ctx context.Context, now time.Time) (int, error) {
+	entries, err := t.storage.GetWaitingEntries(ctx)
+	if err != nil {
+		return 0, err
+	}
+	var eventIDs []int64
+	byEvent := make(map[int64][]models.WaitlistEntry)
+	for _, e := range entries {
+		if _, ok := byEvent[e.EventID]; !ok {
+			eventIDs = append(eventIDs, e.EventID)
+		}
+		byEvent[e.EventID] = append(byEvent[e.EventID], e)
+	}
+
+	offered := 0
+	for _, eventID := range eventIDs {
+		n, err := t.matchEventWaitlist(ctx, eventID, byEvent[eventID], now)
+		offered += n
+		if err != nil {
+			return offered, err
+		}
+	}
+	return offered, nil
+}
+
+func (t *Ticket) matchEventWaitlist(ctx context.Context, eventID int64, entries []models.WaitlistEntry,
+	now time.Time,
+) (int, error) {
+	places, err := t.storage.GetPlaces(ctx, models.PlaceFilter{EventID: eventID})
+	if err != nil {
+		return 0, err
+	}
+	categories, err := t.storage.GetPriceCategories(ctx, eventID)
+	if err != nil {
+		return 0, err
+	}
+	prices := pricing.NewCategories(categories)
+	taken := make(map[int64]bool)
+	available := func(p models.Place) bool { return !taken[p.ID] }
+
+	offered := 0
+	for _, e := range entries {
+		best, err := seating.Best(places, seating.Preferences{
+			Quantity: e.Quantity,
+			MaxPrice: e.MaxPrice,
+			Prices:   prices,
+			Filter:   available,
+		})
+		if errors.Is(err, seating.ErrNoSeats) {
+			continue
+		}
+		if err != nil {
+			return offered, err
+		}
+		ids := make([]int64, 0, len(best))
+		for _, p := range best {
+			ids = append(ids, p.ID)
+		}
+		hold, err := t.storage.CreateHold(ctx, models.Hold{
+			EventID:    eventID,
+			PlaceIDs:   ids,
+			CustomerID: sql.NullInt64{Int64: e.CustomerID, Valid: true},
+			CreatedAt:  now,
+			ExpiresAt:  now.Add(t.conf.Waitlist.OfferTTL),
+		})
+		if errors.Is(err, model.ErrUnavailable) {
+			// Sold concurrently, the next run sees fresh availability.
+			return offered, nil
+		}
+		if err != nil {
+			return offered, err
+		}
+		for _, id := range ids {
+			taken[id] = true
+		}
+		entry, err := t.storage.OfferWaitlistEntry(ctx, e.ID, hold.ID, now)
+		if err != nil {
+			// The entry was cancelled meanwhile.
+			if _, err := t.storage.ReleaseHold(ctx, hold.ID, now); err != nil {
+				return offered, err
+			}
+			continue
+		}
+		offered++
+		if err := t.notifier.Notify(ctx, models.Notification{
+			Type:       models.NotificationWaitlistOffer,
+			CustomerID: entry.CustomerID,
+			Data: map[string]any{
+				"waitlistEntryId": entry.ID,
+				"eventId":         entry.EventID,
+				"holdId":          hold.ID,
+				"placeIds":        hold.PlaceIDs,
+				"expiresAt":       hold.ExpiresAt.UTC(),
+			},
+			CreatedAt: now,
+		}); err != nil {
+			t.log.Errorf("failed to notify customer %d:%v\n", entry.CustomerID, err)
+		}
+	}
+	return offered, nil
+}
+
+// releaseExpiredHolds periodically puts places of expired holds back on sale until ctx is done.
+func (t *Ticket) releaseExpiredHolds(ctx context.Context) error {
+	ticker := time.NewTicker(t.conf.Holds.ReleaseInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			ctxRelease, cancel := context.WithTimeout(ctx, 5*time.Second)
+			holds, err := t.storage.ReleaseExpiredHolds(ctxRelease, now)
+			cancel()
+			if err != nil {
+				t.log.Errorf("failed to release expired holds:%v\n", err)
+				continue
+			}
+			if len(holds) > 0 {
+				t.log.Debugf("released %d expired holds\n", len(holds))
+				t.wakeOutbox()
+				t.wakeWaitlist()
+			}
+		}
+	}
+}
+
+// purgeIdempotencyKeys periodically removes expired idempotency keys until ctx is done.
+func (t *Ticket) purgeIdempotencyKeys(ctx context.Context) error {
+	ticker := time.NewTicker(t.conf.Idempotency.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			ctxPurge, cancel := context.WithTimeout(ctx, 5*time.Second)
+			n, err := t.storage.DeleteExpiredIdempotencyKeys(ctxPurge, now)
+			cancel()
+			if err != nil {
+				t.log.Errorf("failed to purge idempotency keys:%v\n", err)
+				continue
+			}
+			if n > 0 {
+				t.log.Debugf("purged %d expired idempotency keys\n", n)
+			}
+		}
+	}
 }
 
 func NewTicket(log server.Logger, conf TicketConf, storage Storage) (*Ticket, error) {
@@ -112,30 +1572,108 @@ func NewTicket(log server.Logger, conf TicketConf, storage Storage) (*Ticket, er
 		server.Exitfail(fmt.Sprintf("Can't connect to storage:%v", err))
 	}
 
-	return &Ticket{log: log, conf: conf, storage: storage}, nil
+	if conf.Idempotency.TTL <= 0 {
+		conf.Idempotency.TTL = defaultIdempotencyTTL
+	}
+	if conf.Idempotency.PurgeInterval <= 0 {
+		conf.Idempotency.PurgeInterval = defaultIdempotencyPurgeInterval
+	}
+	if conf.Holds.TTL <= 0 {
+		conf.Holds.TTL = defaultHoldTTL
+	}
+	if conf.Holds.ReleaseInterval <= 0 {
+		conf.Holds.ReleaseInterval = defaultHoldReleaseInterval
+	}
+	if conf.Waitlist.OfferTTL <= 0 {
+		conf.Waitlist.OfferTTL = defaultWaitlistOfferTTL
+	}
+	if conf.Waitlist.MatchInterval <= 0 {
+		conf.Waitlist.MatchInterval = defaultWaitlistMatchInterval
+	}
+	if conf.Calendar.EventDuration <= 0 {
+		conf.Calendar.EventDuration = defaultCalendarEventDuration
+	}
+	if conf.Calendar.UIDDomain == "" {
+		conf.Calendar.UIDDomain = defaultCalendarUIDDomain
+	}
+	if conf.Webhooks.MaxAttempts <= 0 {
+		conf.Webhooks.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if conf.Webhooks.RetryBase <= 0 {
+		conf.Webhooks.RetryBase = defaultWebhookRetryBase
+	}
+	if conf.Webhooks.RetryMax < conf.Webhooks.RetryBase {
+		conf.Webhooks.RetryMax = max(defaultWebhookRetryMax, conf.Webhooks.RetryBase)
+	}
+	if conf.Webhooks.DispatchInterval <= 0 {
+		conf.Webhooks.DispatchInterval = defaultWebhookDispatchInterval
+	}
+	if conf.Webhooks.Timeout <= 0 {
+		conf.Webhooks.Timeout = defaultWebhookTimeout
+	}
+	if conf.Outbox.DispatchInterval <= 0 {
+		conf.Outbox.DispatchInterval = defaultOutboxDispatchInterval
+	}
+	if conf.Outbox.Retention <= 0 {
+		conf.Outbox.Retention = defaultOutboxRetention
+	}
+	if conf.Outbox.PurgeInterval <= 0 {
+		conf.Outbox.PurgeInterval = defaultOutboxPurgeInterval
+	}
+	signer, err := tickets.NewSigner(conf.Tickets.Conf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tickets config: %w", err)
+	}
+	if conf.Tickets.SigningKey == "" {
+		log.Warningf("tickets are signed with an ephemeral key, their codes won't verify after restart\n")
+	}
+	pdfTemplate, err := ticketpdf.Load(conf.Tickets.PDFTemplate)
+	if err != nil {
+		return nil, err
+	}
+	publisher, err := broker.New(conf.Broker)
+	if err != nil {
+		return nil, fmt.Errorf("invalid broker config: %w", err)
+	}
+
+	t := &Ticket{
+		log:          log,
+		conf:         conf,
+		storage:      storage,
+		notifier:     logNotifier{log: log},
+		signer:       signer,
+		pdfTemplate:  pdfTemplate,
+		waitlistWake: make(chan struct{}, 1),
+		availability: availability.NewBroker(0),
+		webhookWake:  make(chan struct{}, 1),
+		sender:       webhooks.NewSender(conf.Webhooks.Timeout, conf.Webhooks.AllowPrivate),
+		bus:          domain.NewBus(),
+		outboxWake:   make(chan struct{}, 1),
+		publisher:    publisher,
+	}
+	t.subscribe()
+	return t, nil
 }
 
-func (t Ticket) Run(httpsrv Server) {
+func (t Ticket) Run(servers ...Server) {
 	ctx, cancel := signal.NotifyContext(context.Background(),
 		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 	defer cancel()
 
 	g, ctxEG := errgroup.WithContext(ctx)
 
-	func1 := func() error {
-		return httpsrv.Start(ctxEG)
-	}
-
 	go func() {
 		<-ctx.Done()
 
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
 		defer cancel()
 
-		if err := httpsrv.Stop(ctx); err != nil {
-			if !errors.Is(err, http.ErrServerClosed) &&
-				!errors.Is(err, context.Canceled) {
-				t.log.Errorf("failed to stop HTTP-server:%v\n", err)
+		for _, srv := range servers {
+			if err := srv.Stop(ctx); err != nil {
+				if !errors.Is(err, http.ErrServerClosed) &&
+					!errors.Is(err, context.Canceled) {
+					t.log.Errorf("failed to stop server:%v\n", err)
+				}
 			}
 		}
 
@@ -144,7 +1682,26 @@ func (t Ticket) Run(httpsrv Server) {
 		}
 	}()
 
-	g.Go(func1)
+	for _, srv := range servers {
+		g.Go(func() error {
+			return srv.Start(ctxEG)
+		})
+	}
+	g.Go(func() error {
+		return t.purgeIdempotencyKeys(ctxEG)
+	})
+	g.Go(func() error {
+		return t.releaseExpiredHolds(ctxEG)
+	})
+	g.Go(func() error {
+		return t.runWaitlist(ctxEG)
+	})
+	g.Go(func() error {
+		return t.dispatchWebhooks(ctxEG)
+	})
+	g.Go(func() error {
+		return t.dispatchOutbox(ctxEG)
+	})
 
 	if err := g.Wait(); err != nil {
 		if !errors.Is(err, http.ErrServerClosed) &&
@@ -152,4 +1709,9 @@ func (t Ticket) Run(httpsrv Server) {
 			t.log.Errorf("%v\n", err)
 		}
 	}
+	if t.publisher != nil {
+		if err := t.publisher.Close(); err != nil {
+			t.log.Errorf("failed to close broker publisher:%v\n", err)
+		}
+	}
 }
@@ -0,0 +1,226 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/cronnoss/tk-api/internal/webhooks"
+)
+
+// webhookBatchSize is how many due deliveries are sent at once.
+const webhookBatchSize = 20
+
+// CreateWebhook registers an endpoint for the event types, a secret is generated unless one is given.
+func (t *Ticket) CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if webhook.Secret == "" {
+		secret, err := webhooks.NewSecret()
+		if err != nil {
+			return models.Webhook{}, err
+		}
+		webhook.Secret = secret
+	}
+	webhook.EventTypes = slices.Clone(webhook.EventTypes)
+	slices.Sort(webhook.EventTypes)
+	webhook.EventTypes = slices.Compact(webhook.EventTypes)
+	webhook.CreatedAt = time.Now()
+	return t.storage.CreateWebhook(ctx, webhook)
+}
+
+func (t *Ticket) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetWebhooks(ctx)
+}
+
+func (t *Ticket) GetWebhook(ctx context.Context, id int64) (models.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetWebhook(ctx, id)
+}
+
+// DeleteWebhook unregisters an endpoint, its pending deliveries are dropped.
+func (t *Ticket) DeleteWebhook(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.DeleteWebhook(ctx, id)
+}
+
+func (t *Ticket) GetWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter,
+) ([]models.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	return t.storage.GetWebhookDeliveries(ctx, filter)
+}
+
+// GetWebhookDelivery returns a delivery with the log of its attempts.
+func (t *Ticket) GetWebhookDelivery(ctx context.Context, id int64) (models.WebhookDelivery, []models.WebhookAttempt,
+	error,
+) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	delivery, err := t.storage.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		return delivery, nil, err
+	}
+	attempts, err := t.storage.GetWebhookAttempts(ctx, id)
+	return delivery, attempts, err
+}
+
+// RedeliverWebhookDelivery sends a delivery again now, e.g. a dead one once its endpoint is fixed.
+func (t *Ticket) RedeliverWebhookDelivery(ctx context.Context, id int64) (models.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	delivery, err := t.storage.RedeliverWebhookDelivery(ctx, id, time.Now())
+	if err != nil {
+		return delivery, err
+	}
+	t.wakeWebhooks()
+	return delivery, nil
+}
+
+// queueWebhooks queues a domain event for the webhooks receiving its type. The envelope ID is derived from
+// the event, so an event dispatched again doesn't queue more deliveries.
+func (t *Ticket) queueWebhooks(ctx context.Context, e models.DomainEvent) error {
+	env := webhooks.NewEnvelope(e)
+	payload, err := env.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", e.Type, err)
+	}
+	now := time.Now()
+	deliveries, err := t.storage.CreateWebhookDeliveries(ctx, models.WebhookDelivery{
+		EventID:       env.ID,
+		EventType:     e.Type,
+		Payload:       payload,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+	if err != nil {
+		return err
+	}
+	if len(deliveries) > 0 {
+		t.wakeWebhooks()
+	}
+	return nil
+}
+
+// wakeWebhooks asks the dispatcher to send due deliveries without waiting for its interval.
+func (t *Ticket) wakeWebhooks() {
+	select {
+	case t.webhookWake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatchWebhooks sends due webhook deliveries every interval and whenever some are queued until ctx is done.
+func (t *Ticket) dispatchWebhooks(ctx context.Context) error {
+	ticker := time.NewTicker(t.conf.Webhooks.DispatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-t.webhookWake:
+		}
+		// Keep going while full batches are due.
+		for ctx.Err() == nil {
+			n, err := t.deliverWebhooks(ctx, time.Now())
+			if err != nil {
+				t.log.Errorf("failed to deliver webhooks:%v\n", err)
+				break
+			}
+			if n < webhookBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// deliverWebhooks sends a batch of due deliveries concurrently and returns how many were due.
+func (t *Ticket) deliverWebhooks(ctx context.Context, now time.Time) (int, error) {
+	ctxClaim, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	// A claim outlasts the attempt, so another dispatcher doesn't send the delivery meanwhile.
+	deliveries, err := t.storage.ClaimWebhookDeliveries(ctxClaim, now, now.Add(2*t.conf.Webhooks.Timeout),
+		webhookBatchSize)
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+	hooks, err := t.storage.GetWebhooks(ctxClaim)
+	if err != nil {
+		return len(deliveries), err
+	}
+	byID := make(map[int64]models.Webhook, len(hooks))
+	for _, hook := range hooks {
+		byID[hook.ID] = hook
+	}
+
+	var wg sync.WaitGroup
+	for _, d := range deliveries {
+		hook, ok := byID[d.WebhookID]
+		if !ok {
+			// Deleted meanwhile with its deliveries.
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			t.deliverWebhook(ctx, hook, d)
+		}()
+	}
+	wg.Wait()
+	return len(deliveries), nil
+}
+
+// deliverWebhook makes an attempt to send a delivery and records the outcome: delivered, retried after
+// a backoff or dead once out of attempts.
+func (t *Ticket) deliverWebhook(ctx context.Context, hook models.Webhook, d models.WebhookDelivery) {
+	res := t.sender.Send(ctx, hook.URL, hook.Secret, d.ID, d.EventType, d.Payload)
+	if ctx.Err() != nil {
+		// Shutting down, the claim runs out and the delivery is retried after restart.
+		return
+	}
+	now := time.Now()
+	attempt := models.WebhookAttempt{
+		StatusCode: res.StatusCode,
+		DurationMS: res.Duration.Milliseconds(),
+		CreatedAt:  now,
+	}
+	d.Attempts++
+	if res.OK() {
+		d.Status = models.DeliveryDelivered
+		d.DeliveredAt = sql.NullTime{Time: now, Valid: true}
+		d.LastError = ""
+	} else {
+		if res.Err != nil {
+			attempt.Error = res.Err.Error()
+		} else {
+			attempt.Error = fmt.Sprintf("unexpected status %d", res.StatusCode)
+		}
+		d.LastError = attempt.Error
+		if d.Attempts >= t.conf.Webhooks.MaxAttempts {
+			d.Status = models.DeliveryDead
+			t.log.Warningf("webhook delivery %d to %s is dead after %d attempts: %s\n",
+				d.ID, hook.URL, d.Attempts, d.LastError)
+		} else {
+			d.NextAttemptAt = now.Add(t.conf.Webhooks.Backoff(d.Attempts))
+		}
+	}
+
+	ctxRecord, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if _, err := t.storage.RecordWebhookAttempt(ctxRecord, d, attempt); err != nil &&
+		!errors.Is(err, model.ErrNotFound) {
+		t.log.Errorf("failed to record webhook delivery %d:%v\n", d.ID, err)
+	}
+}
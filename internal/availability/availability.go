@@ -0,0 +1,171 @@
+// Package availability fans place status changes of events out to live subscribers.
+package availability
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+type Kind string
+
+const (
+	Held     Kind = "held"
+	Released Kind = "released"
+	Sold     Kind = "sold"
+	// Synced is a status read from the upstream API.
+	Synced Kind = "synced"
+)
+
+const (
+	defaultHistory = 1000
+	// subscriberBuffer bounds changes queued for a subscriber, slower ones are dropped and resume.
+	subscriberBuffer = 256
+)
+
+// Change is a status change of a place. IDs grow with every change, also across restarts,
+// so a client can resume after the last change it has seen.
+type Change struct {
+	ID      int64
+	EventID int64
+	PlaceID int64
+	Kind    Kind
+	Status  models.PlaceStatus
+	At      time.Time
+}
+
+type Broker struct {
+	mu      sync.Mutex
+	history int
+	// start is the first ID of this process, older IDs are from a previous one.
+	start  int64
+	lastID int64
+	topics map[int64]*topic
+}
+
+// topic holds the recent changes and the subscribers of an event.
+type topic struct {
+	changes []Change
+	// evicted is the ID of the newest change dropped from changes.
+	evicted int64
+	status  map[int64]models.PlaceStatus
+	subs    map[*Subscription]struct{}
+}
+
+// NewBroker returns a broker keeping up to history recent changes of every event for resuming.
+func NewBroker(history int) *Broker {
+	if history <= 0 {
+		history = defaultHistory
+	}
+	start := time.Now().UnixMicro()
+	return &Broker{history: history, start: start, lastID: start - 1, topics: make(map[int64]*topic)}
+}
+
+func (b *Broker) topic(eventID int64) *topic {
+	t, ok := b.topics[eventID]
+	if !ok {
+		t = &topic{status: make(map[int64]models.PlaceStatus), subs: make(map[*Subscription]struct{})}
+		b.topics[eventID] = t
+	}
+	return t
+}
+
+// Publish records the changes and sends them to the subscribers of their events. A synced status
+// is only published when it differs from the known one, the first one seen is just recorded.
+func (b *Broker) Publish(changes ...Change) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, c := range changes {
+		t := b.topic(c.EventID)
+		prev, known := t.status[c.PlaceID]
+		t.status[c.PlaceID] = c.Status
+		if c.Kind == Synced && (!known || prev == c.Status) {
+			continue
+		}
+
+		b.lastID++
+		c.ID = b.lastID
+		if len(t.changes) == b.history {
+			t.evicted = t.changes[0].ID
+			t.changes = append(t.changes[:0], t.changes[1:]...)
+		}
+		t.changes = append(t.changes, c)
+
+		for s := range t.subs {
+			select {
+			case s.ch <- c:
+			default:
+				// The subscriber can't keep up, it resumes from the history after reconnecting.
+				b.drop(t, s)
+			}
+		}
+	}
+}
+
+// Subscription receives changes of an event until closed.
+type Subscription struct {
+	// C delivers changes, it is closed when the subscriber is dropped for falling behind.
+	C <-chan Change
+	// Replay are the changes after the resume point.
+	Replay []Change
+	// Missed reports that changes after the resume point are gone, the client has to reload the places.
+	Missed bool
+	// LastID is the ID of the latest change when subscribing, it resumes after a reload.
+	LastID int64
+
+	ch      chan Change
+	b       *Broker
+	eventID int64
+}
+
+// Subscribe subscribes to changes of an event after lastID, only to new ones if lastID is 0.
+func (b *Broker) Subscribe(eventID, lastID int64) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t := b.topic(eventID)
+	ch := make(chan Change, subscriberBuffer)
+	s := &Subscription{C: ch, LastID: b.lastID, ch: ch, b: b, eventID: eventID}
+	if lastID != 0 {
+		if lastID < b.start-1 || lastID > b.lastID || lastID < t.evicted {
+			s.Missed = true
+		} else {
+			for _, c := range t.changes {
+				if c.ID > lastID {
+					s.Replay = append(s.Replay, c)
+				}
+			}
+		}
+	}
+	t.subs[s] = struct{}{}
+	return s
+}
+
+// Close unsubscribes.
+func (s *Subscription) Close() {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	if t, ok := s.b.topics[s.eventID]; ok {
+		if _, ok := t.subs[s]; ok {
+			s.b.drop(t, s)
+		}
+	}
+}
+
+func (b *Broker) drop(t *topic, s *Subscription) {
+	delete(t.subs, s)
+	close(s.ch)
+}
+
+// Subscribed returns the events with subscribers.
+func (b *Broker) Subscribed() []int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var ids []int64
+	for id, t := range b.topics {
+		if len(t.subs) != 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,84 @@
+package availability
+
+import (
+	"testing"
+
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishSubscribe(t *testing.T) {
+	b := NewBroker(0)
+	s := b.Subscribe(1, 0)
+	defer s.Close()
+	require.Equal(t, []int64{1}, b.Subscribed())
+
+	b.Publish(
+		Change{EventID: 1, PlaceID: 10, Kind: Held, Status: models.PlaceHeld},
+		Change{EventID: 2, PlaceID: 20, Kind: Held, Status: models.PlaceHeld},
+	)
+	c := <-s.C
+	require.Equal(t, int64(10), c.PlaceID)
+	require.NotZero(t, c.ID)
+	require.Empty(t, s.C, "changes of other events are not delivered")
+
+	s.Close()
+	_, ok := <-s.C
+	require.False(t, ok)
+	require.Empty(t, b.Subscribed())
+}
+
+func TestResume(t *testing.T) {
+	b := NewBroker(2)
+	var ids []int64
+	for place := int64(1); place <= 3; place++ {
+		s := b.Subscribe(1, 0)
+		b.Publish(Change{EventID: 1, PlaceID: place, Kind: Sold, Status: models.PlaceSold})
+		ids = append(ids, (<-s.C).ID)
+		s.Close()
+	}
+
+	s := b.Subscribe(1, ids[1])
+	require.False(t, s.Missed)
+	require.Len(t, s.Replay, 1)
+	require.Equal(t, int64(3), s.Replay[0].PlaceID)
+
+	s = b.Subscribe(1, ids[2])
+	require.False(t, s.Missed)
+	require.Empty(t, s.Replay, "up to date")
+
+	require.False(t, b.Subscribe(1, ids[0]).Missed, "only seen changes are evicted")
+	require.True(t, b.Subscribe(1, ids[0]-1).Missed, "evicted from history")
+	require.True(t, b.Subscribe(1, 1).Missed, "from a previous process")
+	require.True(t, b.Subscribe(1, ids[2]+100).Missed, "from the future")
+}
+
+func TestSyncedDedup(t *testing.T) {
+	b := NewBroker(0)
+	s := b.Subscribe(1, 0)
+	defer s.Close()
+
+	synced := Change{EventID: 1, PlaceID: 10, Kind: Synced, Status: models.PlaceAvailable}
+	b.Publish(synced)
+	require.Empty(t, s.C, "the first synced status is the baseline")
+	b.Publish(synced)
+	require.Empty(t, s.C, "unchanged")
+
+	synced.Status = models.PlaceSold
+	b.Publish(synced)
+	require.Equal(t, models.PlaceSold, (<-s.C).Status)
+}
+
+func TestSlowSubscriberDropped(t *testing.T) {
+	b := NewBroker(0)
+	s := b.Subscribe(1, 0)
+	for i := 0; i <= subscriberBuffer; i++ {
+		b.Publish(Change{EventID: 1, PlaceID: int64(i), Kind: Held, Status: models.PlaceHeld})
+	}
+	n := 0
+	for range s.C {
+		n++
+	}
+	require.Equal(t, subscriberBuffer, n, "the channel is closed after the buffered changes")
+	s.Close()
+}
@@ -0,0 +1,115 @@
+// Package broker publishes domain events to an external message broker, so consumers such as analytics
+// get a stream of changes without polling the database. Events are published as webhook envelopes.
+package broker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/domain"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/cronnoss/tk-api/internal/webhooks"
+)
+
+// Adapters.
+const (
+	File  = "file"
+	NATS  = "nats"
+	Kafka = "kafka"
+)
+
+const (
+	defaultSubject  = "tk.events"
+	defaultTopic    = "tk.events"
+	defaultClientID = "tk-api"
+	defaultTimeout  = 5 * time.Second
+)
+
+type Conf struct {
+	// Adapter is File, NATS or Kafka, events aren't published to a broker if empty.
+	Adapter string `toml:"adapter"`
+	// Types are the event types published, all of them if empty.
+	Types []string `toml:"types"`
+	// Timeout bounds publishing an event.
+	Timeout time.Duration `toml:"timeout"`
+	File    FileConf      `toml:"file"`
+	NATS    NATSConf      `toml:"nats"`
+	Kafka   KafkaConf     `toml:"kafka"`
+}
+
+type FileConf struct {
+	// Path of the file events are appended to as JSON lines, standard output if empty or "-".
+	Path string `toml:"path"`
+}
+
+type NATSConf struct {
+	URL string `toml:"url"`
+	// Subject is prefixed to event types: events of type order.paid go to <subject>.order.paid.
+	Subject string `toml:"subject"`
+}
+
+type KafkaConf struct {
+	// Brokers are host:port addresses the cluster is discovered from.
+	Brokers []string `toml:"brokers"`
+	// Topic has to exist, it isn't created.
+	Topic string `toml:"topic"`
+	// Partition events are produced to. Keeping to one partition keeps them in dispatch order.
+	Partition int32  `toml:"partition"`
+	ClientID  string `toml:"client-id"`
+}
+
+// Publisher sends domain events to a broker. Publish returns once the broker has taken the event,
+// an event it fails to publish is dispatched again from the outbox.
+type Publisher interface {
+	Publish(ctx context.Context, e models.DomainEvent) error
+	Close() error
+}
+
+// New returns the publisher of the configured adapter, nil if there is none.
+func New(conf Conf) (Publisher, error) {
+	for _, t := range conf.Types {
+		if !slices.Contains(domain.Types, t) {
+			return nil, fmt.Errorf("unknown event type %q", t)
+		}
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = defaultTimeout
+	}
+
+	switch conf.Adapter {
+	case "":
+		return nil, nil
+	case File:
+		return newFilePublisher(conf.File)
+	case NATS:
+		if conf.NATS.Subject == "" {
+			conf.NATS.Subject = defaultSubject
+		}
+		return newNATSPublisher(conf.NATS, conf.Timeout)
+	case Kafka:
+		if len(conf.Kafka.Brokers) == 0 {
+			return nil, fmt.Errorf("no kafka brokers")
+		}
+		if conf.Kafka.Topic == "" {
+			conf.Kafka.Topic = defaultTopic
+		}
+		if conf.Kafka.ClientID == "" {
+			conf.Kafka.ClientID = defaultClientID
+		}
+		return newKafkaPublisher(conf.Kafka, conf.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown broker adapter %q", conf.Adapter)
+	}
+}
+
+// message returns the body an event is published with.
+func message(e models.DomainEvent) (webhooks.Envelope, []byte, error) {
+	env := webhooks.NewEnvelope(e)
+	body, err := env.Marshal()
+	if err != nil {
+		return env, nil, fmt.Errorf("failed to marshal %s event %d: %w", e.Type, e.ID, err)
+	}
+	return env, body, nil
+}
@@ -0,0 +1,182 @@
+package broker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/domain"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/cronnoss/tk-api/internal/webhooks"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func testEvent(t *testing.T, id int64, typ string) models.DomainEvent {
+	t.Helper()
+	e, err := domain.New(typ, domain.PlacesData{EventID: 1, PlaceIDs: []int64{2, 3}, OrderID: 4}, time.Unix(1_800_000_000, 0))
+	require.NoError(t, err)
+	e.ID = id
+	return e
+}
+
+func TestNew(t *testing.T) {
+	p, err := New(Conf{})
+	require.NoError(t, err)
+	require.Nil(t, p, "no adapter")
+	_, err = New(Conf{Adapter: "carrier-pigeon"})
+	require.ErrorContains(t, err, "unknown broker adapter")
+	_, err = New(Conf{Adapter: File, Types: []string{"order.lost"}})
+	require.ErrorContains(t, err, "unknown event type")
+	_, err = New(Conf{Adapter: Kafka})
+	require.ErrorContains(t, err, "no kafka brokers")
+}
+
+func TestFilePublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	p, err := New(Conf{Adapter: File, File: FileConf{Path: path}})
+	require.NoError(t, err)
+	require.NoError(t, p.Publish(context.Background(), testEvent(t, 1, domain.PlaceSold)))
+	require.NoError(t, p.Publish(context.Background(), testEvent(t, 2, domain.OrderPaid)))
+	require.NoError(t, p.Close())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Len(t, lines, 2)
+	var env webhooks.Envelope
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &env))
+	require.Equal(t, "evt_2", env.ID)
+	require.Equal(t, domain.OrderPaid, env.Type)
+	require.JSONEq(t, `{"eventId":1,"placeIds":[2,3],"orderId":4}`, string(env.Data))
+}
+
+// natsServer is an in-process stand-in of a NATS server that records published messages.
+type natsServer struct {
+	ln   net.Listener
+	mu   sync.Mutex
+	msgs map[string][]string
+}
+
+func newNATSServer(t *testing.T) *natsServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &natsServer{ln: ln, msgs: make(map[string][]string)}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *natsServer) serve(conn net.Conn) {
+	defer conn.Close()
+	_, _ = conn.Write([]byte(`INFO {"server_id":"test","version":"2.10.0","proto":1,"max_payload":1048576}` + "\r\n"))
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "PING":
+			_, _ = conn.Write([]byte("PONG\r\n"))
+		case "PUB":
+			n, _ := strconv.Atoi(fields[len(fields)-1])
+			payload := make([]byte, n+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.msgs[fields[1]] = append(s.msgs[fields[1]], string(payload[:n]))
+			s.mu.Unlock()
+		}
+	}
+}
+
+func (s *natsServer) messages(subject string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.msgs[subject]
+}
+
+func TestNATSPublisher(t *testing.T) {
+	srv := newNATSServer(t)
+	p, err := New(Conf{Adapter: NATS, NATS: NATSConf{URL: "nats://" + srv.ln.Addr().String()}})
+	require.NoError(t, err)
+	defer p.Close()
+
+	require.NoError(t, p.Publish(context.Background(), testEvent(t, 7, domain.PlaceSold)))
+	msgs := srv.messages("tk.events.place.sold")
+	require.Len(t, msgs, 1, "published before Publish returns")
+	var env webhooks.Envelope
+	require.NoError(t, json.Unmarshal([]byte(msgs[0]), &env))
+	require.Equal(t, "evt_7", env.ID)
+}
+
+func TestKafkaPublisher(t *testing.T) {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, "sales"))
+	require.NoError(t, err)
+	defer cluster.Close()
+	addr := cluster.ListenAddrs()[0]
+
+	p, err := New(Conf{Adapter: Kafka, Timeout: 2 * time.Second, Kafka: KafkaConf{
+		Brokers: []string{"127.0.0.1:1", addr},
+		Topic:   "sales",
+	}})
+	require.NoError(t, err)
+	defer p.Close()
+
+	require.NoError(t, p.Publish(context.Background(), testEvent(t, 7, domain.PlaceSold)), "first broker is down")
+	require.NoError(t, p.Publish(context.Background(), testEvent(t, 8, domain.OrderPaid)))
+
+	consumer, err := kgo.NewClient(kgo.SeedBrokers(addr), kgo.ConsumeTopics("sales"),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()))
+	require.NoError(t, err)
+	defer consumer.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var recs []*kgo.Record
+	for len(recs) < 2 && ctx.Err() == nil {
+		fetches := consumer.PollFetches(ctx)
+		recs = append(recs, fetches.Records()...)
+	}
+	require.Len(t, recs, 2)
+	rec := recs[1]
+	require.Equal(t, "evt_8", string(rec.Key))
+	require.Equal(t, []kgo.RecordHeader{{Key: webhooks.EventHeader, Value: []byte(domain.OrderPaid)}}, rec.Headers)
+	require.Equal(t, int64(1_800_000_000_000), rec.Timestamp.UnixMilli())
+	var env webhooks.Envelope
+	require.NoError(t, json.Unmarshal(rec.Value, &env))
+	require.Equal(t, domain.OrderPaid, env.Type)
+
+	other, err := New(Conf{Adapter: Kafka, Timeout: 2 * time.Second, Kafka: KafkaConf{
+		Brokers: []string{addr}, Topic: "other",
+	}})
+	require.NoError(t, err)
+	defer other.Close()
+	err = other.Publish(context.Background(), testEvent(t, 9, domain.OrderPaid))
+	require.ErrorIs(t, err, kerr.UnknownTopicOrPartition)
+}
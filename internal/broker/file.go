@@ -0,0 +1,51 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+// filePublisher appends events to a file or standard output, one JSON line each. It stands in for
+// a broker in local runs.
+type filePublisher struct {
+	mu sync.Mutex
+	w  io.Writer
+	// f is the file opened for the publisher, nil for standard output.
+	f *os.File
+}
+
+func newFilePublisher(conf FileConf) (*filePublisher, error) {
+	if conf.Path == "" || conf.Path == "-" {
+		return &filePublisher{w: os.Stdout}, nil
+	}
+	f, err := os.OpenFile(conf.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open events file: %w", err)
+	}
+	return &filePublisher{w: f, f: f}, nil
+}
+
+func (p *filePublisher) Publish(_ context.Context, e models.DomainEvent) error {
+	_, body, err := message(e)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.w.Write(append(body, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s event %d: %w", e.Type, e.ID, err)
+	}
+	return nil
+}
+
+func (p *filePublisher) Close() error {
+	if p.f == nil {
+		return nil
+	}
+	return p.f.Close()
+}
@@ -0,0 +1,63 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/cronnoss/tk-api/internal/webhooks"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// kafkaPublisher produces events to one partition of a topic with franz-go. The producer is idempotent,
+// so retries of the client don't duplicate or reorder events.
+type kafkaPublisher struct {
+	client    *kgo.Client
+	partition int32
+	timeout   time.Duration
+}
+
+func newKafkaPublisher(conf KafkaConf, timeout time.Duration) (*kafkaPublisher, error) {
+	// Brokers may come up after us, events wait in the outbox until they do.
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(conf.Brokers...),
+		kgo.ClientID(conf.ClientID),
+		kgo.DefaultProduceTopic(conf.Topic),
+		kgo.RecordPartitioner(kgo.ManualPartitioner()),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+		kgo.RecordDeliveryTimeout(timeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+	return &kafkaPublisher{client: client, partition: conf.Partition, timeout: timeout}, nil
+}
+
+// Publish produces an event keyed by its envelope ID with its type in the Tk-Event header and returns
+// once all in-sync replicas have it.
+func (p *kafkaPublisher) Publish(ctx context.Context, e models.DomainEvent) error {
+	env, body, err := message(e)
+	if err != nil {
+		return err
+	}
+	rec := &kgo.Record{
+		Key:       []byte(env.ID),
+		Value:     body,
+		Headers:   []kgo.RecordHeader{{Key: webhooks.EventHeader, Value: []byte(e.Type)}},
+		Timestamp: e.CreatedAt,
+		Partition: p.partition,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	if err := p.client.ProduceSync(ctx, rec).FirstErr(); err != nil {
+		return fmt.Errorf("failed to produce %s event %d: %w", e.Type, e.ID, err)
+	}
+	return nil
+}
+
+func (p *kafkaPublisher) Close() error {
+	p.client.Close()
+	return nil
+}
@@ -0,0 +1,51 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/nats-io/nats.go"
+)
+
+// natsPublisher publishes events to core NATS subjects named after their types.
+type natsPublisher struct {
+	conn    *nats.Conn
+	subject string
+	timeout time.Duration
+}
+
+func newNATSPublisher(conf NATSConf, timeout time.Duration) (*natsPublisher, error) {
+	// The server may come up after us, events wait in the outbox until it does.
+	conn, err := nats.Connect(conf.URL,
+		nats.Name(defaultClientID),
+		nats.Timeout(timeout),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+	return &natsPublisher{conn: conn, subject: conf.Subject, timeout: timeout}, nil
+}
+
+// Publish flushes every event, so it returns only after the server has read it.
+func (p *natsPublisher) Publish(ctx context.Context, e models.DomainEvent) error {
+	_, body, err := message(e)
+	if err != nil {
+		return err
+	}
+	if err := p.conn.Publish(p.subject+"."+e.Type, body); err != nil {
+		return fmt.Errorf("failed to publish %s event %d: %w", e.Type, e.ID, err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	if err := p.conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("failed to flush %s event %d: %w", e.Type, e.ID, err)
+	}
+	return nil
+}
+
+func (p *natsPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,185 @@
+// Package cache keeps shows, events and places read from storage and responses of the upstream API in an
+// in-process LRU or in Redis. Storage and Upstream decorate their sources, reading through the cache
+// and dropping entries changed by writes.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Backends.
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+const (
+	defaultMaxEntries  = 10000
+	defaultShowsTTL    = 5 * time.Minute
+	defaultEventsTTL   = time.Minute
+	defaultPlacesTTL   = 5 * time.Second
+	defaultStaleTTL    = time.Hour
+	defaultRedisPrefix = "tk:cache:"
+)
+
+type Conf struct {
+	// Backend is BackendMemory or BackendRedis, nothing is cached if empty.
+	Backend string `toml:"backend"`
+	// MaxEntries bounds the in-process LRU.
+	MaxEntries int       `toml:"max-entries"`
+	Redis      RedisConf `toml:"redis"`
+	TTL        TTLs      `toml:"ttl"`
+}
+
+type RedisConf struct {
+	Addr     string `toml:"addr"`
+	Password string `toml:"password"`
+	DB       int    `toml:"db"`
+	// Prefix namespaces the keys of the cache.
+	Prefix string `toml:"prefix"`
+}
+
+// TTLs are how long entries of each entity are kept, defaults apply if zero and a negative TTL turns
+// caching of the entity off. Places change on every hold and sale, so their TTL bounds how stale
+// availability read through another instance may be.
+type TTLs struct {
+	Shows  time.Duration `toml:"shows"`
+	Events time.Duration `toml:"events"`
+	Places time.Duration `toml:"places"`
+	// Stale is how much longer upstream responses with validators are kept to be revalidated once
+	// they expire.
+	Stale time.Duration `toml:"stale"`
+}
+
+// Backend keeps encoded entries until their TTL runs out.
+type Backend interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	Close() error
+}
+
+// metrics counts hits, misses and backend errors by entity, it is published with the other expvars.
+var metrics = expvar.NewMap("cache")
+
+// Cache reads values through a backend. Concurrent misses of a key share one load.
+type Cache struct {
+	backend Backend
+	ttl     TTLs
+	group   singleflight.Group
+	// epoch counts invalidations. Loads overlapping one aren't kept, they may have read what it dropped.
+	epoch atomic.Uint64
+}
+
+// New returns a cache with the configured backend, nil if there is none.
+func New(conf Conf) (*Cache, error) {
+	switch conf.Backend {
+	case "":
+		return nil, nil
+	case BackendMemory:
+		if conf.MaxEntries <= 0 {
+			conf.MaxEntries = defaultMaxEntries
+		}
+		return NewCache(NewLRU(conf.MaxEntries), conf.TTL), nil
+	case BackendRedis:
+		if conf.Redis.Prefix == "" {
+			conf.Redis.Prefix = defaultRedisPrefix
+		}
+		return NewCache(NewRedis(conf.Redis), conf.TTL), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", conf.Backend)
+	}
+}
+
+func NewCache(backend Backend, ttl TTLs) *Cache {
+	if ttl.Shows == 0 {
+		ttl.Shows = defaultShowsTTL
+	}
+	if ttl.Events == 0 {
+		ttl.Events = defaultEventsTTL
+	}
+	if ttl.Places == 0 {
+		ttl.Places = defaultPlacesTTL
+	}
+	if ttl.Stale == 0 {
+		ttl.Stale = defaultStaleTTL
+	}
+	return &Cache{backend: backend, ttl: ttl}
+}
+
+func (c *Cache) Close() error {
+	return c.backend.Close()
+}
+
+// entry names a cached value: the entity it is counted under, its key and how long it is kept.
+type entry struct {
+	entity string
+	key    string
+	ttl    time.Duration
+}
+
+// get returns the value cached for e, calling load on a miss and keeping what it returns if keep
+// allows it, nil keeps everything. A failing backend is read through.
+func get[T any](ctx context.Context, c *Cache, e entry,
+	load func(context.Context) (T, error), keep func(T) bool,
+) (T, error) {
+	var v T
+	if e.ttl < 0 {
+		return load(ctx)
+	}
+	b, ok, err := c.backend.Get(ctx, e.key)
+	if err != nil {
+		metrics.Add(e.entity+" errors", 1)
+	}
+	if ok && json.Unmarshal(b, &v) == nil {
+		metrics.Add(e.entity+" hits", 1)
+		return v, nil
+	}
+	metrics.Add(e.entity+" misses", 1)
+
+	epoch := c.epoch.Load()
+	// Callers arriving after an invalidation don't join loads started before it.
+	shared, err, _ := c.group.Do(e.key+"@"+strconv.FormatUint(epoch, 10), func() (any, error) {
+		v, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", e.entity, err)
+		}
+		if (keep == nil || keep(v)) && c.epoch.Load() == epoch {
+			if err := c.backend.Set(ctx, e.key, b, e.ttl); err != nil {
+				metrics.Add(e.entity+" errors", 1)
+			}
+		}
+		return b, nil
+	})
+	if err != nil {
+		return v, err
+	}
+	// Every caller decodes its own copy, values aren't shared.
+	if err := json.Unmarshal(shared.([]byte), &v); err != nil {
+		return v, fmt.Errorf("failed to decode %s: %w", e.entity, err)
+	}
+	return v, nil
+}
+
+// drop deletes the keys after a write, loads running meanwhile aren't kept.
+func (c *Cache) drop(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	c.epoch.Add(1)
+	if err := c.backend.Delete(ctx, keys...); err != nil {
+		metrics.Add("delete errors", 1)
+	}
+}
@@ -0,0 +1,294 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/cronnoss/tk-api/internal/domain"
+	"github.com/cronnoss/tk-api/internal/storage"
+	memorystorage "github.com/cronnoss/tk-api/internal/storage/memory"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/cronnoss/tk-api/internal/upstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1_800_000_000, 0)
+	l := NewLRU(2)
+	l.now = func() time.Time { return now }
+
+	require.NoError(t, l.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, l.Set(ctx, "b", []byte("2"), time.Minute))
+	_, ok, _ := l.Get(ctx, "a")
+	require.True(t, ok)
+	require.NoError(t, l.Set(ctx, "c", []byte("3"), time.Second))
+	_, ok, _ = l.Get(ctx, "b")
+	require.False(t, ok, "least recently used is evicted")
+	require.Equal(t, 2, l.Len())
+
+	now = now.Add(time.Second)
+	_, ok, _ = l.Get(ctx, "c")
+	require.False(t, ok, "expired")
+	v, ok, _ := l.Get(ctx, "a")
+	require.True(t, ok)
+	require.Equal(t, "1", string(v))
+	require.NoError(t, l.Delete(ctx, "a"))
+	require.Zero(t, l.Len())
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+	r := NewRedis(RedisConf{Addr: srv.Addr(), Prefix: "test:"})
+	defer r.Close()
+
+	_, ok, err := r.Get(ctx, "a")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NoError(t, r.Set(ctx, "a", []byte("1"), time.Minute))
+	require.True(t, srv.Exists("test:a"))
+	v, ok, err := r.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "1", string(v))
+
+	srv.FastForward(time.Minute)
+	_, ok, _ = r.Get(ctx, "a")
+	require.False(t, ok, "expired")
+	require.NoError(t, r.Set(ctx, "b", []byte("2"), time.Minute))
+	require.NoError(t, r.Delete(ctx, "b", "c"))
+	require.False(t, srv.Exists("test:b"))
+}
+
+// countingStorage counts reads of shows and places.
+type countingStorage struct {
+	storage.Storage
+	shows, places atomic.Int32
+	// block holds reads of shows until it is closed, if set.
+	block chan struct{}
+}
+
+func (s *countingStorage) GetShows(ctx context.Context) ([]models.Show, error) {
+	s.shows.Add(1)
+	if s.block != nil {
+		<-s.block
+	}
+	return s.Storage.GetShows(ctx)
+}
+
+func (s *countingStorage) GetPlaces(ctx context.Context, filter models.PlaceFilter) ([]models.Place, error) {
+	s.places.Add(1)
+	return s.Storage.GetPlaces(ctx, filter)
+}
+
+func newTestStorage(t *testing.T) (*Storage, *countingStorage) {
+	t.Helper()
+	mem := memorystorage.New()
+	require.NoError(t, mem.Connect(context.Background()))
+	counting := &countingStorage{Storage: mem}
+	return NewStorage(counting, NewCache(NewLRU(100), TTLs{})), counting
+}
+
+func TestStorage(t *testing.T) {
+	ctx := context.Background()
+	s, counting := newTestStorage(t)
+
+	_, err := s.CreateShow(ctx, models.Show{ID: 1, Name: "Show #1"})
+	require.NoError(t, err)
+	shows, err := s.GetShows(ctx)
+	require.NoError(t, err)
+	require.Len(t, shows, 1)
+	_, err = s.GetShows(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), counting.shows.Load(), "second read is cached")
+
+	shows[0].Name = "changed"
+	shows, err = s.GetShows(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "Show #1", shows[0].Name, "callers get copies")
+
+	_, err = s.CreateShow(ctx, models.Show{ID: 2, Name: "Show #2"})
+	require.NoError(t, err)
+	shows, err = s.GetShows(ctx)
+	require.NoError(t, err)
+	require.Len(t, shows, 2, "writes drop entries")
+	require.Equal(t, int32(2), counting.shows.Load())
+
+	// Holds change places of their event.
+	event, err := s.CreateEvent(ctx, models.Event{ID: 10, ShowID: 1, Date: time.Now()})
+	require.NoError(t, err)
+	filter := models.PlaceFilter{EventID: event.ID}
+	place, err := s.CreatePlace(ctx, models.Place{EventID: sql.NullInt64{Int64: event.ID, Valid: true}, IsAvailable: true})
+	require.NoError(t, err)
+	places, err := s.GetPlaces(ctx, filter)
+	require.NoError(t, err)
+	require.True(t, places[0].IsAvailable)
+	hold := models.Hold{EventID: event.ID, PlaceIDs: []int64{place.ID}, ExpiresAt: time.Now().Add(time.Minute)}
+	_, err = s.CreateHold(ctx, hold)
+	require.NoError(t, err)
+	places, err = s.GetPlaces(ctx, filter)
+	require.NoError(t, err)
+	require.False(t, places[0].IsAvailable)
+	_, err = s.GetPlaces(ctx, models.PlaceFilter{EventIDs: []int64{event.ID}})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), counting.places.Load(), "lists of several events aren't cached")
+}
+
+func TestStorageSingleflight(t *testing.T) {
+	ctx := context.Background()
+	s, counting := newTestStorage(t)
+	counting.block = make(chan struct{})
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.GetShows(ctx)
+			assert.NoError(t, err)
+		}()
+	}
+	require.Eventually(t, func() bool { return counting.shows.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(counting.block)
+	wg.Wait()
+	require.Equal(t, int32(1), counting.shows.Load(), "concurrent misses load once")
+}
+
+func TestStorageDropDuringLoad(t *testing.T) {
+	ctx := context.Background()
+	s, counting := newTestStorage(t)
+	counting.block = make(chan struct{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := s.GetShows(ctx)
+		assert.NoError(t, err)
+	}()
+	require.Eventually(t, func() bool { return counting.shows.Load() == 1 }, time.Second, time.Millisecond)
+	require.NoError(t, s.Invalidate(ctx, models.DomainEvent{Type: domain.ShowCreated, Payload: []byte(`{}`)}))
+	close(counting.block)
+	<-done
+
+	counting.block = nil
+	_, err := s.GetShows(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), counting.shows.Load(), "a load overlapping a drop isn't kept")
+}
+
+func TestInvalidate(t *testing.T) {
+	ctx := context.Background()
+	s, counting := newTestStorage(t)
+	_, err := s.GetPlaces(ctx, models.PlaceFilter{EventID: 10})
+	require.NoError(t, err)
+
+	e, err := domain.New(domain.PlaceSold, domain.PlacesData{EventID: 10, PlaceIDs: []int64{100}}, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, s.Invalidate(ctx, e))
+	_, err = s.GetPlaces(ctx, models.PlaceFilter{EventID: 10})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), counting.places.Load())
+}
+
+// fakeProvider serves a canned response and counts fetches.
+type fakeProvider struct {
+	status  int
+	fetches atomic.Int32
+}
+
+func (p *fakeProvider) Shows(context.Context) (upstream.Response, error) {
+	p.fetches.Add(1)
+	return upstream.Response{URL: "http://upstream/shows", StatusCode: p.status, Body: []byte(`{"response":[]}`)}, nil
+}
+
+func (p *fakeProvider) Events(ctx context.Context, _ int64) (upstream.Response, error) {
+	return p.Shows(ctx)
+}
+
+func (p *fakeProvider) Places(ctx context.Context, _ int64) (upstream.Response, error) {
+	return p.Shows(ctx)
+}
+
+func TestUpstream(t *testing.T) {
+	ctx := context.Background()
+	p := &fakeProvider{status: http.StatusOK}
+	u := NewUpstream(p, NewCache(NewLRU(100), TTLs{Events: -1}))
+
+	res, err := u.Shows(ctx)
+	require.NoError(t, err)
+	require.False(t, res.Cached)
+	res, err = u.Shows(ctx)
+	require.NoError(t, err)
+	require.True(t, res.Cached)
+	require.Equal(t, "http://upstream/shows", res.URL)
+	require.JSONEq(t, `{"response":[]}`, string(res.Body))
+	require.Equal(t, int32(1), p.fetches.Load())
+
+	for range 2 {
+		res, err = u.Events(ctx, 1)
+		require.NoError(t, err)
+		require.False(t, res.Cached, "caching of events is off")
+	}
+	p.status = http.StatusBadGateway
+	for range 2 {
+		res, err = u.Places(ctx, 1)
+		require.NoError(t, err)
+		require.False(t, res.Cached, "failures aren't cached")
+	}
+	require.Equal(t, int32(5), p.fetches.Load())
+}
+
+func TestUpstreamRevalidate(t *testing.T) {
+	ctx := context.Background()
+	var requests, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte(`{"response":[]}`))
+	}))
+	defer srv.Close()
+
+	now := time.Unix(1_800_000_000, 0)
+	l := NewLRU(100)
+	l.now = func() time.Time { return now }
+	u := NewUpstream(upstream.NewClient(srv.URL, srv.Client()), NewCache(l, TTLs{Shows: time.Minute}))
+
+	res, err := u.Shows(ctx)
+	require.NoError(t, err)
+	require.False(t, res.Cached)
+	require.Equal(t, `"v1"`, res.ETag)
+
+	now = now.Add(time.Minute)
+	res, err = u.Shows(ctx)
+	require.NoError(t, err)
+	require.True(t, res.Cached, "revalidated responses were stored when fetched")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.JSONEq(t, `{"response":[]}`, string(res.Body))
+	require.Equal(t, int32(2), requests.Load())
+	require.Equal(t, int32(1), notModified.Load())
+
+	_, err = u.Shows(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), requests.Load(), "a revalidated response is fresh again")
+
+	now = now.Add(time.Minute + defaultStaleTTL)
+	res, err = u.Shows(ctx)
+	require.NoError(t, err)
+	require.False(t, res.Cached, "stale copies expire too")
+	require.Equal(t, int32(1), notModified.Load())
+}
@@ -0,0 +1,268 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/domain"
+	"github.com/cronnoss/tk-api/internal/storage"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+// Storage is a storage.Storage reading shows, events and places through a cache. Only plain reads are
+// cached: all shows, events of no or one show, an event, and places of an event or a hall layout.
+//
+// Writes through Storage drop the entries they change. Invalidate drops entries on domain events, which
+// catches changes made elsewhere while the cache is shared. Writes that name neither, such as deleting a
+// price category, show up once entries expire.
+type Storage struct {
+	storage.Storage
+	c *Cache
+}
+
+func NewStorage(s storage.Storage, c *Cache) *Storage {
+	return &Storage{Storage: s, c: c}
+}
+
+const showsKey = "shows"
+
+// eventsKey is the key of events of a show, of all events if showID is 0.
+func eventsKey(showID int64) string {
+	return "events:show:" + strconv.FormatInt(showID, 10)
+}
+
+func eventKey(id int64) string {
+	return "event:" + strconv.FormatInt(id, 10)
+}
+
+func eventPlacesKey(eventID int64) string {
+	return "places:event:" + strconv.FormatInt(eventID, 10)
+}
+
+func hallPlacesKey(hallID int64) string {
+	return "places:hall:" + strconv.FormatInt(hallID, 10)
+}
+
+func (s *Storage) Close(ctx context.Context) error {
+	if err := s.Storage.Close(ctx); err != nil {
+		return err
+	}
+	return s.c.Close()
+}
+
+func (s *Storage) GetShows(ctx context.Context) ([]models.Show, error) {
+	return get(ctx, s.c, entry{"shows", showsKey, s.c.ttl.Shows}, s.Storage.GetShows, nil)
+}
+
+func (s *Storage) GetEvents(ctx context.Context, filter models.EventFilter) ([]models.Event, error) {
+	if !filter.From.IsZero() || !filter.To.IsZero() || len(filter.ShowIDs) > 0 {
+		return s.Storage.GetEvents(ctx, filter)
+	}
+	return get(ctx, s.c, entry{"events", eventsKey(filter.ShowID), s.c.ttl.Events},
+		func(ctx context.Context) ([]models.Event, error) {
+			return s.Storage.GetEvents(ctx, filter)
+		}, nil)
+}
+
+func (s *Storage) GetEvent(ctx context.Context, id int64) (models.Event, error) {
+	return get(ctx, s.c, entry{"events", eventKey(id), s.c.ttl.Events},
+		func(ctx context.Context) (models.Event, error) {
+			return s.Storage.GetEvent(ctx, id)
+		}, nil)
+}
+
+func (s *Storage) GetPlaces(ctx context.Context, filter models.PlaceFilter) ([]models.Place, error) {
+	var key string
+	switch {
+	// Only filters by either one event or one hall are cached.
+	case len(filter.EventIDs) > 0 || (filter.EventID != 0) == (filter.HallID != 0):
+		return s.Storage.GetPlaces(ctx, filter)
+	case filter.EventID != 0:
+		key = eventPlacesKey(filter.EventID)
+	default:
+		key = hallPlacesKey(filter.HallID)
+	}
+	return get(ctx, s.c, entry{"places", key, s.c.ttl.Places},
+		func(ctx context.Context) ([]models.Place, error) {
+			return s.Storage.GetPlaces(ctx, filter)
+		}, nil)
+}
+
+func (s *Storage) CreateShows(ctx context.Context, shows []models.Show) ([]models.Show, error) {
+	defer s.c.drop(ctx, showsKey)
+	return s.Storage.CreateShows(ctx, shows)
+}
+
+func (s *Storage) CreateShow(ctx context.Context, show models.Show) (models.Show, error) {
+	defer s.c.drop(ctx, showsKey)
+	return s.Storage.CreateShow(ctx, show)
+}
+
+func (s *Storage) CreateEvents(ctx context.Context, events []models.Event) ([]models.Event, error) {
+	defer s.dropEvents(ctx, events...)
+	return s.Storage.CreateEvents(ctx, events)
+}
+
+func (s *Storage) CreateEvent(ctx context.Context, event models.Event) (models.Event, error) {
+	defer s.dropEvents(ctx, event)
+	return s.Storage.CreateEvent(ctx, event)
+}
+
+func (s *Storage) CancelEvent(ctx context.Context, id int64, now time.Time, check models.Check[models.Event],
+) (models.Event, error) {
+	event, err := s.Storage.CancelEvent(ctx, id, now, check)
+	if err == nil {
+		s.dropEvents(ctx, event)
+	}
+	return event, err
+}
+
+// AttachEventToHall copies the hall layout to the event, so its places change too.
+func (s *Storage) AttachEventToHall(ctx context.Context, eventID, hallID int64, check models.Check[models.Event],
+) (models.Event, error) {
+	event, err := s.Storage.AttachEventToHall(ctx, eventID, hallID, check)
+	if err == nil {
+		s.dropEvents(ctx, event)
+		s.c.drop(ctx, eventPlacesKey(eventID))
+	}
+	return event, err
+}
+
+func (s *Storage) CreatePlaces(ctx context.Context, places []models.Place) ([]models.Place, error) {
+	defer s.dropPlaces(ctx, places...)
+	return s.Storage.CreatePlaces(ctx, places)
+}
+
+func (s *Storage) CreatePlace(ctx context.Context, place models.Place) (models.Place, error) {
+	defer s.dropPlaces(ctx, place)
+	return s.Storage.CreatePlace(ctx, place)
+}
+
+func (s *Storage) CreateHallPlaces(ctx context.Context, hallID int64, places []models.Place) ([]models.Place, error) {
+	defer s.c.drop(ctx, hallPlacesKey(hallID))
+	return s.Storage.CreateHallPlaces(ctx, hallID, places)
+}
+
+func (s *Storage) AssignPrices(ctx context.Context, eventID int64,
+	assignments []models.PriceAssignment, check models.Check[models.Event],
+) (int64, error) {
+	defer s.c.drop(ctx, eventPlacesKey(eventID))
+	return s.Storage.AssignPrices(ctx, eventID, assignments, check)
+}
+
+func (s *Storage) CreateHold(ctx context.Context, hold models.Hold) (models.Hold, error) {
+	hold, err := s.Storage.CreateHold(ctx, hold)
+	if err == nil {
+		s.c.drop(ctx, eventPlacesKey(hold.EventID))
+	}
+	return hold, err
+}
+
+func (s *Storage) ReleaseHold(ctx context.Context, id int64, now time.Time) (models.Hold, error) {
+	hold, err := s.Storage.ReleaseHold(ctx, id, now)
+	if err == nil {
+		s.c.drop(ctx, eventPlacesKey(hold.EventID))
+	}
+	return hold, err
+}
+
+func (s *Storage) ReleaseExpiredHolds(ctx context.Context, now time.Time) ([]models.Hold, error) {
+	holds, err := s.Storage.ReleaseExpiredHolds(ctx, now)
+	keys := make([]string, 0, len(holds))
+	for _, h := range holds {
+		keys = append(keys, eventPlacesKey(h.EventID))
+	}
+	s.c.drop(ctx, keys...)
+	return holds, err
+}
+
+func (s *Storage) CreateOrder(ctx context.Context, order models.Order) (models.Order, error) {
+	order, err := s.Storage.CreateOrder(ctx, order)
+	if err == nil {
+		s.dropOrderPlaces(ctx, order)
+	}
+	return order, err
+}
+
+func (s *Storage) RefundOrder(ctx context.Context, refund models.Refund) (models.Refund, error) {
+	refund, err := s.Storage.RefundOrder(ctx, refund)
+	if err == nil {
+		s.dropOrderPlacesByID(ctx, refund.OrderID)
+	}
+	return refund, err
+}
+
+func (s *Storage) ExchangeOrderItems(ctx context.Context, exchange models.Exchange) (models.Exchange, error) {
+	exchange, err := s.Storage.ExchangeOrderItems(ctx, exchange)
+	if err == nil {
+		s.dropOrderPlacesByID(ctx, exchange.OrderID)
+	}
+	return exchange, err
+}
+
+// Invalidate drops the entries changed by a domain event, it is a domain.Handler.
+func (s *Storage) Invalidate(ctx context.Context, e models.DomainEvent) error {
+	switch e.Type {
+	case domain.ShowCreated:
+		s.c.drop(ctx, showsKey)
+	case domain.EventCreated, domain.EventUpdated, domain.EventCancelled:
+		var data domain.EventData
+		if err := domain.Decode(e, &data); err != nil {
+			return err
+		}
+		s.dropEvents(ctx, models.Event{ID: data.ID, ShowID: data.ShowID})
+		if data.HallID != 0 {
+			s.c.drop(ctx, eventPlacesKey(data.ID))
+		}
+	case domain.PlaceHeld, domain.PlaceReleased, domain.PlaceSold:
+		var data domain.PlacesData
+		if err := domain.Decode(e, &data); err != nil {
+			return err
+		}
+		s.c.drop(ctx, eventPlacesKey(data.EventID))
+	}
+	return nil
+}
+
+// dropEvents drops the events and the lists they are in.
+func (s *Storage) dropEvents(ctx context.Context, events ...models.Event) {
+	keys := []string{eventsKey(0)}
+	for _, e := range events {
+		keys = append(keys, eventKey(e.ID), eventsKey(e.ShowID))
+	}
+	s.c.drop(ctx, keys...)
+}
+
+// dropPlaces drops the lists the places are in.
+func (s *Storage) dropPlaces(ctx context.Context, places ...models.Place) {
+	keys := make([]string, 0, len(places))
+	for _, p := range places {
+		if p.EventID.Valid {
+			keys = append(keys, eventPlacesKey(p.EventID.Int64))
+		} else if p.HallID.Valid {
+			keys = append(keys, hallPlacesKey(p.HallID.Int64))
+		}
+	}
+	s.c.drop(ctx, keys...)
+}
+
+// dropOrderPlaces drops places of the events of the order items.
+func (s *Storage) dropOrderPlaces(ctx context.Context, order models.Order) {
+	keys := []string{eventPlacesKey(order.EventID)}
+	for _, item := range order.Items {
+		keys = append(keys, eventPlacesKey(item.EventID))
+	}
+	s.c.drop(ctx, keys...)
+}
+
+// dropOrderPlacesByID looks the order up to drop places of its events. Without the order its place
+// events still drop them once they are dispatched.
+func (s *Storage) dropOrderPlacesByID(ctx context.Context, orderID int64) {
+	order, err := s.Storage.GetOrder(ctx, orderID)
+	if err != nil {
+		metrics.Add("delete errors", 1)
+		return
+	}
+	s.dropOrderPlaces(ctx, order)
+}
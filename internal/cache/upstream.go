@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/cronnoss/tk-api/internal/upstream"
+)
+
+// Upstream is an upstream.Provider keeping successful responses of another one in a cache. Responses
+// served from the cache and to callers that joined a running fetch are marked Cached, their items are
+// stored by whoever fetched them, so one fetch is stored once.
+//
+// Responses with an ETag or Last-Modified are also kept as stale copies for TTLs.Stale longer. Once the
+// fresh entry expires, a provider that is an upstream.Revalidator is asked whether the stale copy is
+// still current, which costs the remote API no body when it is.
+type Upstream struct {
+	p upstream.Provider
+	c *Cache
+}
+
+func NewUpstream(p upstream.Provider, c *Cache) *Upstream {
+	return &Upstream{p: p, c: c}
+}
+
+func (u *Upstream) Shows(ctx context.Context) (upstream.Response, error) {
+	return u.fetch(ctx, entry{"upstream shows", "upstream:shows", u.c.ttl.Shows}, u.p.Shows)
+}
+
+func (u *Upstream) Events(ctx context.Context, showID int64) (upstream.Response, error) {
+	return u.fetch(ctx, entry{"upstream events", "upstream:events:" + strconv.FormatInt(showID, 10), u.c.ttl.Events},
+		func(ctx context.Context) (upstream.Response, error) {
+			return u.p.Events(ctx, showID)
+		})
+}
+
+func (u *Upstream) Places(ctx context.Context, eventID int64) (upstream.Response, error) {
+	return u.fetch(ctx, entry{"upstream places", "upstream:places:" + strconv.FormatInt(eventID, 10), u.c.ttl.Places},
+		func(ctx context.Context) (upstream.Response, error) {
+			return u.p.Places(ctx, eventID)
+		})
+}
+
+func (u *Upstream) fetch(ctx context.Context, e entry,
+	load func(context.Context) (upstream.Response, error),
+) (upstream.Response, error) {
+	fetched, unchanged := false, false
+	res, err := get(ctx, u.c, e, func(ctx context.Context) (upstream.Response, error) {
+		fetched = true
+		res, err := u.revalidate(ctx, e, load)
+		unchanged = res.Cached
+		return res, err
+	}, func(res upstream.Response) bool {
+		return res.StatusCode == http.StatusOK
+	})
+	res.Cached = err == nil && (!fetched || unchanged)
+	return res, err
+}
+
+// revalidate revalidates the stale copy of the entry if there is one, else it loads the response.
+// Successful responses with validators become the new stale copy.
+func (u *Upstream) revalidate(ctx context.Context, e entry,
+	load func(context.Context) (upstream.Response, error),
+) (upstream.Response, error) {
+	rv, ok := u.p.(upstream.Revalidator)
+	if !ok || e.ttl < 0 || u.c.ttl.Stale < 0 {
+		return load(ctx)
+	}
+	key := e.key + ":stale"
+
+	var res upstream.Response
+	b, found, err := u.c.backend.Get(ctx, key)
+	if err != nil {
+		metrics.Add(e.entity+" errors", 1)
+	}
+	if found && json.Unmarshal(b, &res) == nil {
+		res, err = rv.Revalidate(ctx, res)
+		if err == nil && res.Cached {
+			metrics.Add(e.entity+" revalidations", 1)
+		}
+	} else {
+		res, err = load(ctx)
+	}
+	if err != nil || res.StatusCode != http.StatusOK || (res.ETag == "" && res.LastModified == "") {
+		return res, err
+	}
+	if b, err := json.Marshal(res); err == nil {
+		if err := u.c.backend.Set(ctx, key, b, e.ttl+u.c.ttl.Stale); err != nil {
+			metrics.Add(e.entity+" errors", 1)
+		}
+	}
+	return res, nil
+}
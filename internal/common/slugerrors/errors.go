@@ -1,23 +1,62 @@
 package slugerrors
 
+import "net/http"
+
+// ErrorType classifies slug errors, it holds the HTTP status and title of problem responses for them.
 type ErrorType struct {
-	t string
+	t      string
+	status int
+	title  string
 }
 
 var (
-	ErrorTypeUnknown       = ErrorType{"unknown"}
-	ErrorTypeAuthorization = ErrorType{"authorization"}
-	ErrorTypeBadRequest    = ErrorType{"bad-request"}
-	ErrorTypeNotFound      = ErrorType{"not-found"}
+	ErrorTypeUnknown       = ErrorType{"unknown", http.StatusInternalServerError, "Internal server error"}
+	ErrorTypeAuthorization = ErrorType{"authorization", http.StatusUnauthorized, "Unauthorised"}
+	ErrorTypeBadRequest    = ErrorType{"bad-request", http.StatusBadRequest, "Bad request"}
+	ErrorTypeNotFound      = ErrorType{"not-found", http.StatusNotFound, "Not found"}
+	ErrorTypeConflict      = ErrorType{"conflict", http.StatusConflict, "Conflict"}
+	ErrorTypeValidation    = ErrorType{"validation", http.StatusUnprocessableEntity, "Unprocessable entity"}
+	ErrorTypeBadGateway    = ErrorType{"bad-gateway", http.StatusBadGateway, "Bad gateway"}
+	ErrorTypePrecondition  = ErrorType{"precondition-failed", http.StatusPreconditionFailed, "Precondition failed"}
+	ErrorTypeTooLarge      = ErrorType{"too-large", http.StatusRequestEntityTooLarge, "Request entity too large"}
 )
 
+// Status returns the HTTP status of errors of the type, the zero type is an unknown one.
+func (t ErrorType) Status() int {
+	if t.status == 0 {
+		return ErrorTypeUnknown.status
+	}
+	return t.status
+}
+
+// Title returns the summary of problem responses for errors of the type.
+func (t ErrorType) Title() string {
+	if t.title == "" {
+		return ErrorTypeUnknown.title
+	}
+	return t.title
+}
+
 type SlugError struct {
 	message   string
 	slug      string
 	errorType ErrorType
+	err       error
 }
 
 func (s SlugError) Error() string {
+	if s.err != nil {
+		return s.message + ": " + s.err.Error()
+	}
+	return s.message
+}
+
+func (s SlugError) Unwrap() error {
+	return s.err
+}
+
+// Message returns the client-facing message without the wrapped cause.
+func (s SlugError) Message() string {
 	return s.message
 }
 
@@ -29,6 +68,12 @@ func (s SlugError) ErrorType() ErrorType {
 	return s.errorType
 }
 
+// Wrap attaches the underlying cause, e.g. validation.Errors, to the slug error.
+func (s SlugError) Wrap(err error) SlugError {
+	s.err = err
+	return s
+}
+
 func NewSlugError(errMsg string, slug string) SlugError {
 	return SlugError{
 		message:   errMsg,
@@ -60,3 +105,43 @@ func NewNotFoundError(errMsg string, slug string) SlugError {
 		errorType: ErrorTypeNotFound,
 	}
 }
+
+func NewConflictError(errMsg string, slug string) SlugError {
+	return SlugError{
+		message:   errMsg,
+		slug:      slug,
+		errorType: ErrorTypeConflict,
+	}
+}
+
+func NewValidationError(errMsg string, slug string) SlugError {
+	return SlugError{
+		message:   errMsg,
+		slug:      slug,
+		errorType: ErrorTypeValidation,
+	}
+}
+
+func NewBadGatewayError(errMsg string, slug string) SlugError {
+	return SlugError{
+		message:   errMsg,
+		slug:      slug,
+		errorType: ErrorTypeBadGateway,
+	}
+}
+
+func NewPreconditionFailedError(errMsg string, slug string) SlugError {
+	return SlugError{
+		message:   errMsg,
+		slug:      slug,
+		errorType: ErrorTypePrecondition,
+	}
+}
+
+func NewTooLargeError(errMsg string, slug string) SlugError {
+	return SlugError{
+		message:   errMsg,
+		slug:      slug,
+		errorType: ErrorTypeTooLarge,
+	}
+}
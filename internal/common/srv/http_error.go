@@ -8,63 +8,105 @@ import (
 	"os"
 
 	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/validation"
+)
+
+const (
+	ContentTypeProblemJSON = "application/problem+json"
+	problemTypePrefix      = "urn:tk-api:problem:"
 )
 
 func InternalError(slug string, err error, w http.ResponseWriter, r *http.Request) {
-	httpRespondWithError(err, slug, w, r, "Internal server error", http.StatusInternalServerError)
+	httpRespondWithError(err, slug, w, r, slugerrors.ErrorTypeUnknown)
 }
 
 func Unauthorised(slug string, err error, w http.ResponseWriter, r *http.Request) {
-	httpRespondWithError(err, slug, w, r, "Unauthorised", http.StatusUnauthorized)
+	httpRespondWithError(err, slug, w, r, slugerrors.ErrorTypeAuthorization)
 }
 
 func BadRequest(slug string, err error, w http.ResponseWriter, r *http.Request) {
-	httpRespondWithError(err, slug, w, r, "Bad request", http.StatusBadRequest)
+	httpRespondWithError(err, slug, w, r, slugerrors.ErrorTypeBadRequest)
 }
 
 func NotFound(slug string, err error, w http.ResponseWriter, r *http.Request) {
-	httpRespondWithError(err, slug, w, r, "Not found", http.StatusBadRequest)
+	httpRespondWithError(err, slug, w, r, slugerrors.ErrorTypeNotFound)
+}
+
+func Conflict(slug string, err error, w http.ResponseWriter, r *http.Request) {
+	httpRespondWithError(err, slug, w, r, slugerrors.ErrorTypeConflict)
+}
+
+func UnprocessableEntity(slug string, err error, w http.ResponseWriter, r *http.Request) {
+	httpRespondWithError(err, slug, w, r, slugerrors.ErrorTypeValidation)
+}
+
+func BadGateway(slug string, err error, w http.ResponseWriter, r *http.Request) {
+	httpRespondWithError(err, slug, w, r, slugerrors.ErrorTypeBadGateway)
 }
 
 func RespondWithError(err error, w http.ResponseWriter, r *http.Request) {
+	writeProblem(err, Problem(err, r.URL.RequestURI()), w)
+}
+
+// Problem describes err for clients, e.g. over other transports than plain HTTP responses.
+// The status and title come from the type of the slug error, other errors are internal ones.
+func Problem(err error, instance string) ErrorResponse {
 	var slugError slugerrors.SlugError
 	if !errors.As(err, &slugError) {
-		InternalError("internal-server-error", err, w, r)
-		return
+		return newProblem(err, "internal-server-error", instance, slugerrors.ErrorTypeUnknown)
 	}
+	return newProblem(err, slugError.Slug(), instance, slugError.ErrorType())
+}
 
-	switch slugError.ErrorType() {
-	case slugerrors.ErrorTypeAuthorization:
-		Unauthorised(slugError.Slug(), slugError, w, r)
-	case slugerrors.ErrorTypeBadRequest:
-		BadRequest(slugError.Slug(), slugError, w, r)
-	case slugerrors.ErrorTypeNotFound:
-		NotFound(slugError.Slug(), slugError, w, r)
-	default:
-		InternalError(slugError.Slug(), slugError, w, r)
-	}
+func httpRespondWithError(err error, slug string, w http.ResponseWriter, r *http.Request, t slugerrors.ErrorType) {
+	writeProblem(err, newProblem(err, slug, r.URL.RequestURI(), t), w)
+}
+
+func writeProblem(err error, resp ErrorResponse, w http.ResponseWriter) {
+	log.Printf("error: %s, slug: %s, msg: %s", err, resp.Slug, resp.Title)
+
+	w.Header().Set("Content-Type", ContentTypeProblemJSON)
+	w.WriteHeader(resp.Status)
+	_ = json.NewEncoder(w).Encode(resp)
 }
 
-func httpRespondWithError(err error, slug string, w http.ResponseWriter, _ *http.Request, msg string, status int) {
-	log.Printf("error: %s, slug: %s, msg: %s", err, slug, msg)
+func newProblem(err error, slug, instance string, t slugerrors.ErrorType) ErrorResponse {
+	resp := ErrorResponse{
+		Type:     problemTypePrefix + slug,
+		Title:    t.Title(),
+		Status:   t.Status(),
+		Instance: instance,
+		Slug:     slug,
+	}
 
-	resp := ErrorResponse{Slug: slug, httpStatus: status}
+	// Slug error messages are written for clients, anything else may leak internals.
+	var slugError slugerrors.SlugError
+	if errors.As(err, &slugError) {
+		resp.Detail = slugError.Message()
+	}
+	var fieldErrors validation.Errors
+	if errors.As(err, &fieldErrors) {
+		resp.Errors = fieldErrors
+	}
 	if os.Getenv("DEBUG_ERRORS") != "" && err != nil {
 		resp.Error = err.Error()
 	}
-
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(resp)
+	return resp
 }
 
+// ErrorResponse is an RFC 7807 problem details document.
 type ErrorResponse struct {
-	Slug       string `json:"slug"`
-	Error      string `json:"error,omitempty"`
-	httpStatus int
+	Type     string                  `json:"type"`
+	Title    string                  `json:"title"`
+	Status   int                     `json:"status"`
+	Detail   string                  `json:"detail,omitempty"`
+	Instance string                  `json:"instance,omitempty"`
+	Slug     string                  `json:"slug"`
+	Errors   []validation.FieldError `json:"errors,omitempty"`
+	Error    string                  `json:"error,omitempty"`
 }
 
 func (e ErrorResponse) Render(w http.ResponseWriter, _ *http.Request) error {
-	w.WriteHeader(e.httpStatus)
+	w.WriteHeader(e.Status)
 	return nil
 }
@@ -0,0 +1,94 @@
+package srv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/validation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblem(t *testing.T) {
+	tests := []struct {
+		err    error
+		slug   string
+		title  string
+		status int
+	}{
+		{slugerrors.NewAuthorizationError("no token", "unauthorized"), "unauthorized", "Unauthorised", 401},
+		{slugerrors.NewBadRequestError("bad id", "invalid-id"), "invalid-id", "Bad request", 400},
+		{slugerrors.NewNotFoundError("no event", "event-not-found"), "event-not-found", "Not found", 404},
+		{slugerrors.NewConflictError("taken", "place-taken"), "place-taken", "Conflict", 409},
+		{slugerrors.NewValidationError("invalid", "invalid-event"), "invalid-event", "Unprocessable entity", 422},
+		{slugerrors.NewBadGatewayError("upstream", "upstream-failed"), "upstream-failed", "Bad gateway", 502},
+		{slugerrors.NewPreconditionFailedError("stale", "stale-etag"), "stale-etag", "Precondition failed", 412},
+		{slugerrors.NewTooLargeError("large", "request-too-large"), "request-too-large", "Request entity too large", 413},
+		{slugerrors.NewSlugError("unknown", "unknown"), "unknown", "Internal server error", 500},
+		{slugerrors.SlugError{}.Wrap(errors.New("zero")), "", "Internal server error", 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			// Wrapped slug errors are found too.
+			p := Problem(fmt.Errorf("handler: %w", tt.err), "/events/1")
+			require.Equal(t, "urn:tk-api:problem:"+tt.slug, p.Type)
+			require.Equal(t, tt.title, p.Title)
+			require.Equal(t, tt.status, p.Status)
+			require.Equal(t, "/events/1", p.Instance)
+			require.Equal(t, tt.slug, p.Slug)
+			require.Equal(t, tt.err.(slugerrors.SlugError).Message(), p.Detail)
+		})
+	}
+
+	p := Problem(errors.New("pq: connection refused"), "/events/1")
+	require.Equal(t, "urn:tk-api:problem:internal-server-error", p.Type)
+	require.Equal(t, http.StatusInternalServerError, p.Status)
+	require.Empty(t, p.Detail, "other errors don't leak to clients")
+	require.Empty(t, p.Error)
+}
+
+func TestRespondWithError(t *testing.T) {
+	var v validation.Validator
+	v.Add(validation.Pointer("date"), errors.New("is required"))
+	v.Add(validation.Pointer("places", 0, "x"), errors.New("must not be negative"))
+	v.Add(validation.Pointer("a/b"), errors.New("is invalid"))
+	err := slugerrors.NewValidationError("invalid event", "invalid-event").Wrap(v.Err())
+
+	w := httptest.NewRecorder()
+	RespondWithError(err, w, httptest.NewRequest(http.MethodPost, "/events?source=test", nil))
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.Equal(t, ContentTypeProblemJSON, w.Header().Get("Content-Type"))
+	var p ErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+	require.Equal(t, ErrorResponse{
+		Type:     "urn:tk-api:problem:invalid-event",
+		Title:    "Unprocessable entity",
+		Status:   http.StatusUnprocessableEntity,
+		Detail:   "invalid event",
+		Instance: "/events?source=test",
+		Slug:     "invalid-event",
+		Errors: []validation.FieldError{
+			{Pointer: "/date", Detail: "is required"},
+			{Pointer: "/places/0/x", Detail: "must not be negative"},
+			{Pointer: "/a~1b", Detail: "is invalid"},
+		},
+	}, p)
+}
+
+func TestNotFound(t *testing.T) {
+	w := httptest.NewRecorder()
+	NotFound("show-not-found", errors.New("no show"), w, httptest.NewRequest(http.MethodGet, "/shows/1", nil))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Equal(t, ContentTypeProblemJSON, w.Header().Get("Content-Type"))
+	var p ErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+	require.Equal(t, http.StatusNotFound, p.Status)
+	require.Equal(t, "Not found", p.Title)
+	require.Equal(t, "/shows/1", p.Instance)
+}
@@ -0,0 +1,87 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError is a single violated constraint located by a JSON pointer (RFC 6901).
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Detail  string `json:"detail"`
+	err     error
+}
+
+func (e FieldError) Error() string {
+	return e.Pointer + ": " + e.Detail
+}
+
+func (e FieldError) Unwrap() error {
+	return e.err
+}
+
+// Errors is a list of violations collected by a Validator.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e Errors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, fe := range e {
+		errs = append(errs, fe)
+	}
+	return errs
+}
+
+// Validator collects every violation instead of stopping at the first one.
+type Validator struct {
+	errs Errors
+}
+
+// Check records err at pointer unless ok holds.
+func (v *Validator) Check(ok bool, pointer string, err error) {
+	if !ok {
+		v.Add(pointer, err)
+	}
+}
+
+// Add records err at pointer.
+func (v *Validator) Add(pointer string, err error) {
+	v.errs = append(v.errs, FieldError{Pointer: pointer, Detail: err.Error(), err: err})
+}
+
+// Valid reports whether no violation has been recorded.
+func (v *Validator) Valid() bool {
+	return len(v.errs) == 0
+}
+
+// Errors returns the collected violations.
+func (v *Validator) Errors() Errors {
+	return v.errs
+}
+
+// Err returns the collected violations as Errors, or nil.
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// Pointer builds a JSON pointer from reference tokens, e.g. Pointer("response", 0, "id") is "/response/0/id".
+func Pointer(tokens ...any) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteByte('/')
+		b.WriteString(pointerEscaper.Replace(fmt.Sprint(t)))
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errTest = errors.New("test error")
+
+func TestPointer(t *testing.T) {
+	require.Equal(t, "", Pointer())
+	require.Equal(t, "/response/0/id", Pointer("response", 0, "id"))
+	require.Equal(t, "/a~1b/m~0n", Pointer("a/b", "m~n"))
+}
+
+func TestValidator(t *testing.T) {
+	var v Validator
+	require.True(t, v.Valid())
+	require.NoError(t, v.Err())
+
+	v.Check(true, "/ok", errTest)
+	v.Check(false, "/response/0/id", errTest)
+	v.Add("/response/1/name", errTest)
+	require.False(t, v.Valid())
+
+	err := v.Err()
+	require.ErrorIs(t, err, errTest)
+	require.EqualError(t, err, "/response/0/id: test error; /response/1/name: test error")
+
+	var verrs Errors
+	require.ErrorAs(t, err, &verrs)
+	require.Len(t, verrs, 2)
+	require.Equal(t, "/response/1/name", verrs[1].Pointer)
+}
@@ -0,0 +1,230 @@
+// Package domain describes changes of stored data as events. Storage writes them to an outbox in the same
+// transaction as the change and a dispatcher hands them to in-process subscribers through a Bus.
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+// Event types.
+const (
+	ShowCreated  = "show.created"
+	EventCreated = "event.created"
+	// EventUpdated is an event stored again with another date or time zone.
+	EventUpdated    = "event.updated"
+	EventCancelled  = "event.cancelled"
+	PlaceHeld       = "place.held"
+	PlaceReleased   = "place.released"
+	PlaceSold       = "place.sold"
+	OrderPaid       = "order.paid"
+	OrderRefunded   = "order.refunded"
+	OrderExchanged  = "order.exchanged"
+	WaitlistOffered = "waitlist.offered"
+)
+
+// Types are all event types.
+var Types = []string{
+	ShowCreated, EventCreated, EventUpdated, EventCancelled, PlaceHeld, PlaceReleased, PlaceSold,
+	OrderPaid, OrderRefunded, OrderExchanged, WaitlistOffered,
+}
+
+// ShowData is the data of show events.
+type ShowData struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func Show(s models.Show) ShowData {
+	return ShowData{ID: s.ID, Name: s.Name}
+}
+
+// EventData is the data of event.* events.
+type EventData struct {
+	ID          int64      `json:"id"`
+	ShowID      int64      `json:"showId"`
+	Date        time.Time  `json:"date"`
+	TimeZone    string     `json:"timeZone"`
+	HallID      int64      `json:"hallId,omitempty"`
+	Sequence    int        `json:"sequence"`
+	CancelledAt *time.Time `json:"cancelledAt,omitempty"`
+}
+
+func Event(e models.Event) EventData {
+	data := EventData{
+		ID:       e.ID,
+		ShowID:   e.ShowID,
+		Date:     e.Date.UTC(),
+		TimeZone: e.TimeZone,
+		HallID:   e.HallID.Int64,
+		Sequence: e.Sequence,
+	}
+	if e.CancelledAt.Valid {
+		cancelledAt := e.CancelledAt.Time.UTC()
+		data.CancelledAt = &cancelledAt
+	}
+	return data
+}
+
+// PlacesData is the data of place.* events: places of an event that changed status together,
+// with the hold or order that changed them.
+type PlacesData struct {
+	EventID  int64   `json:"eventId"`
+	PlaceIDs []int64 `json:"placeIds"`
+	HoldID   int64   `json:"holdId,omitempty"`
+	OrderID  int64   `json:"orderId,omitempty"`
+}
+
+// ItemPlaces returns the places of order items by event in the order the events first appear.
+func ItemPlaces(orderID int64, items []models.OrderItem) []PlacesData {
+	var places []PlacesData
+	index := make(map[int64]int)
+	for _, item := range items {
+		i, ok := index[item.EventID]
+		if !ok {
+			i = len(places)
+			index[item.EventID] = i
+			places = append(places, PlacesData{EventID: item.EventID, OrderID: orderID})
+		}
+		places[i].PlaceIDs = append(places[i].PlaceIDs, item.PlaceID)
+	}
+	return places
+}
+
+// OrderData is the data of order.* events. Amounts are in minor units of Currency.
+type OrderData struct {
+	ID         int64   `json:"id"`
+	EventID    int64   `json:"eventId"`
+	CustomerID int64   `json:"customerId,omitempty"`
+	Status     string  `json:"status"`
+	Currency   string  `json:"currency"`
+	Total      int64   `json:"total"`
+	Refunded   int64   `json:"refunded"`
+	ItemIDs    []int64 `json:"itemIds"`
+}
+
+func Order(o models.Order) OrderData {
+	data := OrderData{
+		ID:         o.ID,
+		EventID:    o.EventID,
+		CustomerID: o.CustomerID.Int64,
+		Status:     string(o.Status),
+		Currency:   o.Currency,
+		Total:      o.Total,
+		Refunded:   o.Refunded,
+		ItemIDs:    make([]int64, 0, len(o.Items)),
+	}
+	for _, item := range o.Items {
+		data.ItemIDs = append(data.ItemIDs, item.ID)
+	}
+	return data
+}
+
+// WaitlistData is the data of waitlist events.
+type WaitlistData struct {
+	EntryID    int64   `json:"entryId"`
+	EventID    int64   `json:"eventId"`
+	CustomerID int64   `json:"customerId"`
+	HoldID     int64   `json:"holdId"`
+	PlaceIDs   []int64 `json:"placeIds"`
+	// ExpiresAt is when the places offered stop being held for the customer.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// New returns an event of the type with data as its payload, it gets an ID when written to the outbox.
+func New(typ string, data any, now time.Time) (models.DomainEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return models.DomainEvent{}, fmt.Errorf("failed to marshal %s event: %w", typ, err)
+	}
+	return models.DomainEvent{Type: typ, Payload: payload, CreatedAt: now}, nil
+}
+
+// Decode unmarshals the payload of an event into data.
+func Decode(e models.DomainEvent, data any) error {
+	if err := json.Unmarshal(e.Payload, data); err != nil {
+		return fmt.Errorf("failed to unmarshal %s event %d: %w", e.Type, e.ID, err)
+	}
+	return nil
+}
+
+// Handler handles a dispatched event. Events are dispatched at least once: an event is dispatched again
+// to subscribers that failed it, and to all of them after a restart, so handlers have to tolerate duplicates.
+type Handler func(ctx context.Context, e models.DomainEvent) error
+
+type subscriber struct {
+	name  string
+	types []string
+	h     Handler
+}
+
+// Bus fans dispatched events out to in-process subscribers in the order they subscribed.
+type Bus struct {
+	mu   sync.RWMutex
+	subs []subscriber
+
+	handledMu sync.Mutex
+	// handled are the subscribers that handled outbox events others failed, they are skipped when
+	// the events are published again.
+	handled map[int64]map[string]bool
+}
+
+func NewBus() *Bus {
+	return &Bus{handled: make(map[int64]map[string]bool)}
+}
+
+// Subscribe adds a handler of the event types, of all types if none are given.
+func (b *Bus) Subscribe(name string, h Handler, types ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, subscriber{name: name, types: types, h: h})
+}
+
+// Publish hands an event to its subscribers, all of them see it even if some fail.
+// The errors are returned together, each prefixed with the name of its subscriber. When an event
+// from the outbox is published again only the subscribers that failed it see it.
+func (b *Bus) Publish(ctx context.Context, e models.DomainEvent) error {
+	b.mu.RLock()
+	subs := b.subs
+	b.mu.RUnlock()
+	b.handledMu.Lock()
+	handled := b.handled[e.ID]
+	b.handledMu.Unlock()
+
+	var errs []error
+	var done []string
+	for _, sub := range subs {
+		if (len(sub.types) > 0 && !slices.Contains(sub.types, e.Type)) || handled[sub.name] {
+			continue
+		}
+		if err := sub.h(ctx, e); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+			continue
+		}
+		done = append(done, sub.name)
+	}
+	if e.ID == 0 {
+		return errors.Join(errs...)
+	}
+
+	b.handledMu.Lock()
+	defer b.handledMu.Unlock()
+	if len(errs) == 0 {
+		delete(b.handled, e.ID)
+		return nil
+	}
+	if b.handled[e.ID] == nil {
+		b.handled[e.ID] = make(map[string]bool)
+	}
+	for _, name := range done {
+		b.handled[e.ID][name] = true
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBus(t *testing.T) {
+	b := NewBus()
+	var all, places []string
+	b.Subscribe("all", func(_ context.Context, e models.DomainEvent) error {
+		all = append(all, e.Type)
+		return nil
+	})
+	b.Subscribe("failing", func(context.Context, models.DomainEvent) error {
+		return errors.New("boom")
+	}, OrderPaid)
+	b.Subscribe("places", func(_ context.Context, e models.DomainEvent) error {
+		places = append(places, e.Type)
+		return nil
+	}, PlaceHeld, PlaceSold)
+
+	held, err := New(PlaceHeld, PlacesData{EventID: 1, PlaceIDs: []int64{2, 3}, HoldID: 4}, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, b.Publish(context.Background(), held))
+	var data PlacesData
+	require.NoError(t, Decode(held, &data))
+	require.Equal(t, []int64{2, 3}, data.PlaceIDs)
+
+	paid := models.DomainEvent{ID: 5, Type: OrderPaid, Payload: []byte(`{}`)}
+	err = b.Publish(context.Background(), paid)
+	require.ErrorContains(t, err, "failing: boom")
+	require.Equal(t, []string{PlaceHeld, OrderPaid}, all, "a failing subscriber doesn't stop the others")
+	require.Equal(t, []string{PlaceHeld}, places)
+
+	err = b.Publish(context.Background(), paid)
+	require.ErrorContains(t, err, "failing: boom")
+	require.Equal(t, []string{PlaceHeld, OrderPaid}, all, "only failed subscribers see a retried event")
+}
+
+func TestItemPlaces(t *testing.T) {
+	places := ItemPlaces(9, []models.OrderItem{
+		{EventID: 2, PlaceID: 20},
+		{EventID: 1, PlaceID: 10},
+		{EventID: 2, PlaceID: 21},
+	})
+	require.Equal(t, []PlacesData{
+		{EventID: 2, PlaceIDs: []int64{20, 21}, OrderID: 9},
+		{EventID: 1, PlaceIDs: []int64{10}, OrderID: 9},
+	}, places)
+	require.Empty(t, ItemPlaces(9, nil))
+}
@@ -0,0 +1,246 @@
+// Package ical writes RFC 5545 calendars of events.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	prodID = "-//cronnoss//tk-api//EN"
+	// maxLine is the longest content line in octets, longer ones are folded.
+	maxLine = 75
+
+	utcLayout   = "20060102T150405Z"
+	localLayout = "20060102T150405"
+)
+
+// Event is a calendar event. Start is in the time zone the event is shown in,
+// a VTIMEZONE is written for every zone other than UTC.
+type Event struct {
+	// UID is stable across revisions of the event.
+	UID         string
+	Start       time.Time
+	Duration    time.Duration
+	Summary     string
+	Location    string
+	Description string
+	// Sequence orders revisions, it grows with every change of the date or place.
+	Sequence  int
+	Cancelled bool
+	// Modified is the last change, zero if never changed.
+	Modified time.Time
+}
+
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Write writes the calendar stamped at now.
+func Write(w io.Writer, cal Calendar, now time.Time) error {
+	var b builder
+	b.line("BEGIN:VCALENDAR")
+	b.line("VERSION:2.0")
+	b.line("PRODID:" + prodID)
+	b.line("CALSCALE:GREGORIAN")
+	b.line("METHOD:PUBLISH")
+	if cal.Name != "" {
+		b.line("X-WR-CALNAME:" + escape(cal.Name))
+	}
+
+	for _, z := range zones(cal.Events) {
+		z.write(&b)
+	}
+	for _, e := range cal.Events {
+		e.write(&b, now)
+	}
+
+	b.line("END:VCALENDAR")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (e Event) write(b *builder, now time.Time) {
+	b.line("BEGIN:VEVENT")
+	b.line("UID:" + escape(e.UID))
+	b.line("DTSTAMP:" + now.UTC().Format(utcLayout))
+	if isUTC(e.Start.Location()) {
+		b.line("DTSTART:" + e.Start.UTC().Format(utcLayout))
+	} else {
+		b.line("DTSTART;TZID=" + e.Start.Location().String() + ":" + e.Start.Format(localLayout))
+	}
+	b.line("DURATION:" + duration(e.Duration))
+	b.line("SUMMARY:" + escape(e.Summary))
+	if e.Location != "" {
+		b.line("LOCATION:" + escape(e.Location))
+	}
+	if e.Description != "" {
+		b.line("DESCRIPTION:" + escape(e.Description))
+	}
+	b.line(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+	if e.Cancelled {
+		b.line("STATUS:CANCELLED")
+	} else {
+		b.line("STATUS:CONFIRMED")
+	}
+	if !e.Modified.IsZero() {
+		b.line("LAST-MODIFIED:" + e.Modified.UTC().Format(utcLayout))
+	}
+	b.line("END:VEVENT")
+}
+
+// zone is a time zone with the span of the events in it.
+type zone struct {
+	loc      *time.Location
+	from, to time.Time
+}
+
+// zones returns the time zones of the events other than UTC ordered by name.
+func zones(events []Event) []zone {
+	byName := make(map[string]*zone)
+	for _, e := range events {
+		loc := e.Start.Location()
+		if isUTC(loc) {
+			continue
+		}
+		z, ok := byName[loc.String()]
+		if !ok {
+			z = &zone{loc: loc, from: e.Start, to: e.Start}
+			byName[loc.String()] = z
+		}
+		if e.Start.Before(z.from) {
+			z.from = e.Start
+		}
+		if end := e.Start.Add(e.Duration); end.After(z.to) {
+			z.to = end
+		}
+	}
+	res := make([]zone, 0, len(byName))
+	for _, z := range byName {
+		res = append(res, *z)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].loc.String() < res[j].loc.String() })
+	return res
+}
+
+// write writes a VTIMEZONE with the offset in effect a year before the first event
+// and every transition until a year after the last one.
+func (z zone) write(b *builder) {
+	from := z.from.AddDate(-1, 0, 0).In(z.loc)
+	to := z.to.AddDate(1, 0, 0)
+
+	b.line("BEGIN:VTIMEZONE")
+	b.line("TZID:" + z.loc.String())
+	_, offset := from.Zone()
+	observance(b, from, offset)
+	for t := from; t.Before(to); {
+		next := t.Add(24 * time.Hour)
+		if _, o := next.Zone(); o != offset {
+			at := transition(t, next)
+			observance(b, at, offset)
+			offset = o
+		}
+		t = next
+	}
+	b.line("END:VTIMEZONE")
+}
+
+// transition returns the first instant in (before, after] with the offset of after.
+func transition(before, after time.Time) time.Time {
+	_, target := after.Zone()
+	for after.Sub(before) > time.Second {
+		mid := before.Add(after.Sub(before) / 2)
+		if _, o := mid.Zone(); o == target {
+			after = mid
+		} else {
+			before = mid
+		}
+	}
+	return after
+}
+
+// observance writes the STANDARD or DAYLIGHT observance starting at the instant, changing from offsetFrom.
+func observance(b *builder, at time.Time, offsetFrom int) {
+	name, offsetTo := at.Zone()
+	kind := "STANDARD"
+	if at.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	b.line("BEGIN:" + kind)
+	// DTSTART is the local time of the onset in the offset before it.
+	b.line("DTSTART:" + at.In(time.FixedZone("", offsetFrom)).Format(localLayout))
+	b.line("TZOFFSETFROM:" + utcOffset(offsetFrom))
+	b.line("TZOFFSETTO:" + utcOffset(offsetTo))
+	b.line("TZNAME:" + escape(name))
+	b.line("END:" + kind)
+}
+
+func isUTC(loc *time.Location) bool {
+	return loc == time.UTC || loc.String() == "UTC"
+}
+
+// utcOffset formats seconds east of UTC as ±hhmm, with seconds if any.
+func utcOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	s := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+	if seconds%60 != 0 {
+		s += fmt.Sprintf("%02d", seconds%60)
+	}
+	return s
+}
+
+// duration formats d as a DURATION value such as PT1H30M.
+func duration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d <= 0 {
+		return "PT0S"
+	}
+	s := "PT"
+	if h := d / time.Hour; h > 0 {
+		s += fmt.Sprintf("%dH", h)
+	}
+	if m := d % time.Hour / time.Minute; m > 0 {
+		s += fmt.Sprintf("%dM", m)
+	}
+	if sec := d % time.Minute / time.Second; sec > 0 {
+		s += fmt.Sprintf("%dS", sec)
+	}
+	return s
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// builder collects content lines ended by CRLF and folded at maxLine octets.
+type builder struct {
+	strings.Builder
+}
+
+func (b *builder) line(s string) {
+	limit := maxLine
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with the space.
+		limit = maxLine - 1
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,85 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata" // nolint: revive
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func write(t *testing.T, cal Calendar) string {
+	t.Helper()
+	var b strings.Builder
+	require.NoError(t, Write(&b, cal, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)))
+	return b.String()
+}
+
+func TestWrite(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	out := write(t, Calendar{Name: "Shows", Events: []Event{{
+		UID:      "event-1@example.com",
+		Start:    time.Date(2026, 12, 31, 20, 0, 0, 0, berlin),
+		Duration: 90 * time.Minute,
+		Summary:  "Swan Lake; Act I, II",
+		Location: "Opera\nBerlin",
+		Sequence: 2,
+	}, {
+		UID:       "event-2@example.com",
+		Start:     time.Date(2026, 7, 1, 18, 0, 0, 0, time.UTC),
+		Summary:   "Matinee",
+		Cancelled: true,
+	}}})
+
+	require.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	for _, want := range []string{
+		"DTSTART;TZID=Europe/Berlin:20261231T200000\r\n",
+		"DURATION:PT1H30M\r\n",
+		`SUMMARY:Swan Lake\; Act I\, II` + "\r\n",
+		`LOCATION:Opera\nBerlin` + "\r\n",
+		"SEQUENCE:2\r\n",
+		"STATUS:CONFIRMED\r\n",
+		"DTSTART:20260701T180000Z\r\n",
+		"STATUS:CANCELLED\r\n",
+		"DTSTAMP:20261019T120000Z\r\n",
+	} {
+		require.Contains(t, out, want)
+	}
+
+	require.Equal(t, 1, strings.Count(out, "BEGIN:VTIMEZONE"), "no VTIMEZONE for UTC")
+	// Berlin switches to summer time on the last Sunday of March at 02:00 CET.
+	require.Contains(t, out, "BEGIN:DAYLIGHT\r\nDTSTART:20260329T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\nTZNAME:CEST\r\n")
+	require.Contains(t, out, "BEGIN:STANDARD\r\nDTSTART:20261025T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\nTZNAME:CET\r\n")
+}
+
+func TestWriteZoneWithoutTransitions(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	out := write(t, Calendar{Events: []Event{{UID: "1", Start: time.Date(2026, 3, 1, 19, 0, 0, 0, moscow)}}})
+	require.Equal(t, 1, strings.Count(out, "BEGIN:STANDARD"))
+	require.Contains(t, out, "TZOFFSETFROM:+0300\r\nTZOFFSETTO:+0300\r\n")
+}
+
+func TestFolding(t *testing.T) {
+	summary := strings.Repeat("Щелкунчик ", 20)
+	out := write(t, Calendar{Events: []Event{{UID: "1", Start: time.Now().UTC(), Summary: summary}}})
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		require.LessOrEqual(t, len(line), maxLine)
+		require.True(t, utf8.ValidString(line), "folds keep characters whole")
+	}
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	require.Contains(t, unfolded, "SUMMARY:"+summary+"\r\n")
+}
+
+func TestDuration(t *testing.T) {
+	require.Equal(t, "PT0S", duration(0))
+	require.Equal(t, "PT2H", duration(2*time.Hour))
+	require.Equal(t, "PT1M5S", duration(65*time.Second))
+}
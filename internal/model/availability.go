@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/common/validation"
+)
+
+// AvailabilityChangeResponse is the data of a place status change streamed as a server-sent event.
+// The event type is held, released, sold or synced, synced changes come from the upstream API.
+type AvailabilityChangeResponse struct {
+	EventID int64 `json:"eventId"`
+	PlaceID int64 `json:"placeId"`
+	// Status is available, held or sold.
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// Seat selection WebSocket command types.
+const (
+	SeatCommandHold    = "hold"
+	SeatCommandRelease = "release"
+)
+
+// SeatCommand is a command sent by a seat selection WebSocket client.
+type SeatCommand struct {
+	// Type is hold or release.
+	Type string `json:"type"`
+	// ID is echoed in the reply to match it with the command.
+	ID string `json:"id,omitempty"`
+	// PlaceIDs are the places to hold.
+	PlaceIDs []int64 `json:"placeIds,omitempty"`
+	// HoldID is the hold to release.
+	HoldID int64 `json:"holdId,omitempty"`
+}
+
+func (c SeatCommand) Validate() error {
+	switch c.Type {
+	case SeatCommandHold:
+		return HoldRequest{PlaceIDs: c.PlaceIDs}.Validate()
+	case SeatCommandRelease:
+		var v validation.Validator
+		v.Check(c.HoldID > 0, validation.Pointer("holdId"), ErrInvalidValue)
+		return v.Err()
+	default:
+		var v validation.Validator
+		v.Check(false, validation.Pointer("type"), ErrInvalidValue)
+		return v.Err()
+	}
+}
+
+// Seat selection WebSocket message types.
+const (
+	SeatMessageChange   = "change"
+	SeatMessageReset    = "reset"
+	SeatMessageHeld     = "held"
+	SeatMessageReleased = "released"
+	SeatMessageError    = "error"
+)
+
+// SeatMessage is a message sent to seat selection WebSocket clients.
+type SeatMessage struct {
+	// Type is change, reset (changes were missed, reload the places), held, released or error.
+	Type string `json:"type"`
+	// ID is the ID of the command replied to.
+	ID string `json:"id,omitempty"`
+	// Kind is the change kind: held, released, sold or synced.
+	Kind   string                      `json:"kind,omitempty"`
+	Change *AvailabilityChangeResponse `json:"change,omitempty"`
+	Hold   *HoldResponse               `json:"hold,omitempty"`
+	Error  *srv.ErrorResponse          `json:"error,omitempty"`
+}
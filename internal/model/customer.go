@@ -0,0 +1,44 @@
+package model
+
+import (
+	"net/mail"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/validation"
+)
+
+type CustomerRequest struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	Phone string `json:"phone,omitempty"`
+	// MarketingEmail and MarketingSMS record consent to marketing by email and by SMS.
+	MarketingEmail bool `json:"marketingEmail"`
+	MarketingSMS   bool `json:"marketingSms"`
+}
+
+func (c CustomerRequest) Validate() error {
+	var v validation.Validator
+	if c.Email == "" {
+		v.Add(validation.Pointer("email"), ErrRequired)
+	} else if a, err := mail.ParseAddress(c.Email); err != nil || a.Address != c.Email {
+		v.Add(validation.Pointer("email"), ErrInvalidEmail)
+	}
+	v.Check(c.Name != "", validation.Pointer("name"), ErrRequired)
+	v.Check(!c.MarketingSMS || c.Phone != "", validation.Pointer("phone"), ErrRequired)
+	return v.Err()
+}
+
+type CustomerResponse struct {
+	CustomerRequest
+	ID        int64      `json:"id"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ErasedAt  *time.Time `json:"erasedAt,omitempty"`
+}
+
+// CustomerExportResponse is everything stored about a customer.
+type CustomerExportResponse struct {
+	Customer   CustomerResponse `json:"customer"`
+	Holds      []HoldResponse   `json:"holds"`
+	Orders     []OrderResponse  `json:"orders"`
+	ExportedAt time.Time        `json:"exportedAt"`
+}
@@ -5,8 +5,21 @@ import "errors"
 var (
 	ErrRequired        = errors.New("required value")
 	ErrNotFound        = errors.New("not found")
+	ErrEmpty           = errors.New("empty value")
+	ErrAlreadyExists   = errors.New("already exists")
+	ErrUnavailable     = errors.New("unavailable")
+	ErrLimitReached    = errors.New("limit reached")
+	ErrAlreadyUsed     = errors.New("already used")
+	ErrWrongEvent      = errors.New("wrong event")
+	ErrPlacesAvailable = errors.New("places are available")
+	ErrPlacesInUse     = errors.New("places are held or sold")
 	ErrNil             = errors.New("nil data")
 	ErrNegative        = errors.New("negative value")
+	ErrInvalidDate     = errors.New("invalid date")
+	ErrInvalidValue    = errors.New("invalid value")
+	ErrInvalidTimeZone = errors.New("invalid time zone")
+	ErrInvalidCurrency = errors.New("invalid currency")
+	ErrInvalidEmail    = errors.New("invalid email")
 	ErrInvalidUserID   = errors.New("invalid user ID")
 	ErrInvalidShowIDs  = errors.New("invalid show IDs")
 	ErrNoUserInContext = errors.New("no user in context")
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/validation"
+)
+
+// MaxPartySize bounds the number of places picked or held at once.
+const MaxPartySize = 50
+
+type PointRequest struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type BestSeatsRequest struct {
+	Quantity   int   `json:"quantity"`
+	SectionID  int64 `json:"sectionId,omitempty"`
+	Accessible bool  `json:"accessible,omitempty"`
+	// MaxPrice limits places to price categories up to this amount in minor units.
+	MaxPrice int64 `json:"maxPrice,omitempty"`
+	// Stage overrides the stage position, the top centre of the seat map by default.
+	Stage *PointRequest `json:"stage,omitempty"`
+	// Hold holds the picked places in the same request.
+	Hold bool `json:"hold,omitempty"`
+	// CustomerID is the customer the places are held for.
+	CustomerID int64 `json:"customerId,omitempty"`
+}
+
+func (b BestSeatsRequest) Validate() error {
+	var v validation.Validator
+	v.Check(b.Quantity > 0 && b.Quantity <= MaxPartySize, validation.Pointer("quantity"), ErrInvalidValue)
+	v.Check(b.SectionID >= 0, validation.Pointer("sectionId"), ErrNegative)
+	v.Check(b.MaxPrice >= 0, validation.Pointer("maxPrice"), ErrNegative)
+	v.Check(b.CustomerID >= 0, validation.Pointer("customerId"), ErrNegative)
+	return v.Err()
+}
+
+type BestSeatsResponse struct {
+	Places []PlaceResponse `json:"places"`
+	Hold   *HoldResponse   `json:"hold,omitempty"`
+}
+
+type HoldRequest struct {
+	PlaceIDs   []int64 `json:"placeIds"`
+	CustomerID int64   `json:"customerId,omitempty"`
+}
+
+func (h HoldRequest) Validate() error {
+	var v validation.Validator
+	v.Check(len(h.PlaceIDs) != 0, validation.Pointer("placeIds"), ErrEmpty)
+	v.Check(len(h.PlaceIDs) <= MaxPartySize, validation.Pointer("placeIds"), ErrInvalidValue)
+	seen := make(map[int64]bool, len(h.PlaceIDs))
+	for i, id := range h.PlaceIDs {
+		v.Check(id > 0 && !seen[id], validation.Pointer("placeIds", i), ErrInvalidValue)
+		seen[id] = true
+	}
+	v.Check(h.CustomerID >= 0, validation.Pointer("customerId"), ErrNegative)
+	return v.Err()
+}
+
+type HoldResponse struct {
+	ID         int64      `json:"id"`
+	EventID    int64      `json:"eventId"`
+	PlaceIDs   []int64    `json:"placeIds"`
+	CustomerID int64      `json:"customerId,omitempty"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	ReleasedAt *time.Time `json:"releasedAt,omitempty"`
+	Active     bool       `json:"active"`
+}
@@ -1,86 +1,190 @@
 package model
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/validation"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
 
 type ShowResponse struct {
 	ID   int64  `json:"id"`
 	Name string `json:"name"`
 }
 
+// Validate records violations of the show located at pointer.
+func (s ShowResponse) Validate(v *validation.Validator, pointer string) {
+	v.Check(s.ID != 0, pointer+"/id", ErrRequired)
+	v.Check(s.Name != "", pointer+"/name", ErrRequired)
+}
+
 type ShowListResponse struct {
 	Response []ShowResponse `json:"response"`
+	// Invalid are the items that failed validation, responses only report their number in a header.
+	Invalid []InvalidItem `json:"-"`
+}
+
+// Partition validates every show and splits them into valid and invalid ones.
+func (s *ShowListResponse) Partition() ([]ShowResponse, []InvalidItem) {
+	return partition(s.Response)
 }
 
 func (s *ShowListResponse) ShowListResponseValidate() error {
-	if len(s.Response) == 0 {
-		return fmt.Errorf("empty response")
+	var v validation.Validator
+	v.Check(len(s.Response) != 0, validation.Pointer("response"), ErrEmpty)
+	for i, show := range s.Response {
+		show.Validate(&v, validation.Pointer("response", i))
 	}
-	if s.Response[0].ID == 0 {
-		return fmt.Errorf("%w: id", ErrRequired)
+	return v.Err()
+}
+
+type EventResponse struct {
+	ID       int64     `json:"id"`
+	ShowID   int64     `json:"showId"`
+	HallID   int64     `json:"hallId,omitempty"`
+	Date     time.Time `json:"date"`
+	TimeZone string    `json:"timeZone,omitempty"`
+	// Sequence counts changes of the date and hall.
+	Sequence    int        `json:"sequence,omitempty"`
+	CancelledAt *time.Time `json:"cancelledAt,omitempty"`
+
+	// rawDate keeps the upstream date until it is resolved in the venue time zone.
+	rawDate string
+}
+
+func (e *EventResponse) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		ID       int64  `json:"id"`
+		ShowID   int64  `json:"showId"`
+		Date     string `json:"date"`
+		TimeZone string `json:"timeZone"`
 	}
-	if s.Response[0].Name == "" {
-		return fmt.Errorf("%w: name", ErrRequired)
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
 	}
+	*e = EventResponse{ID: raw.ID, ShowID: raw.ShowID, TimeZone: raw.TimeZone, rawDate: raw.Date}
+	e.Date, _ = ParseEventDate(raw.Date, time.UTC)
 	return nil
 }
 
-type EventResponse struct {
-	ID     int64  `json:"id"`
-	ShowID int64  `json:"showId"`
-	Date   string `json:"date"`
+// Localize resolves the date in loc: dates without a UTC offset are taken as venue wall clock,
+// and the result is rendered with the venue offset.
+func (e *EventResponse) Localize(loc *time.Location) {
+	if e.rawDate != "" {
+		if d, err := ParseEventDate(e.rawDate, loc); err == nil {
+			e.Date = d
+		}
+	}
+	e.Date = e.Date.In(loc)
+	e.TimeZone = loc.String()
+}
+
+// Validate records violations of the event located at pointer.
+func (e EventResponse) Validate(v *validation.Validator, pointer string) {
+	v.Check(e.ID != 0, pointer+"/id", ErrRequired)
+	v.Check(e.ShowID != 0, pointer+"/showId", ErrRequired)
+	switch {
+	case e.rawDate != "":
+		if _, err := ParseEventDate(e.rawDate, time.UTC); err != nil {
+			v.Add(pointer+"/date", err)
+		}
+	case e.Date.IsZero():
+		v.Add(pointer+"/date", ErrRequired)
+	}
+}
+
+// eventDateLayouts are the date formats accepted from upstream, the first one carries a UTC offset.
+var eventDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+}
+
+// ParseEventDate parses an upstream event date, dates without a UTC offset are taken in loc.
+func ParseEventDate(s string, loc *time.Location) (time.Time, error) {
+	for _, layout := range eventDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidDate, s)
 }
 
 type EventListResponse struct {
 	Response []EventResponse `json:"response"`
+	// Invalid are the items that failed validation, responses only report their number in a header.
+	Invalid []InvalidItem `json:"-"`
+}
+
+// Partition validates every event and splits them into valid and invalid ones.
+func (e *EventListResponse) Partition() ([]EventResponse, []InvalidItem) {
+	return partition(e.Response)
 }
 
 func (e *EventListResponse) EventListResponseValidate() error {
-	if len(e.Response) == 0 {
-		return fmt.Errorf("empty response")
-	}
-	if e.Response[0].ID == 0 {
-		return fmt.Errorf("%w: id", ErrRequired)
-	}
-	if e.Response[0].ShowID == 0 {
-		return fmt.Errorf("%w: showId", ErrRequired)
+	var v validation.Validator
+	v.Check(len(e.Response) != 0, validation.Pointer("response"), ErrEmpty)
+	for i, event := range e.Response {
+		event.Validate(&v, validation.Pointer("response", i))
 	}
-	if e.Response[0].Date == "" {
-		return fmt.Errorf("%w: date", ErrRequired)
-	}
-	return nil
+	return v.Err()
 }
 
 type PlaceResponse struct {
 	ID          int64   `json:"id"`
+	EventID     int64   `json:"eventId,omitempty"`
+	HallID      int64   `json:"hallId,omitempty"`
+	SectionID   int64   `json:"sectionId,omitempty"`
+	Row         string  `json:"row,omitempty"`
+	Seat        int     `json:"seat,omitempty"`
+	Label       string  `json:"label,omitempty"`
 	X           float64 `json:"x"`
 	Y           float64 `json:"y"`
 	Width       float64 `json:"width"`
 	Height      float64 `json:"height"`
 	IsAvailable bool    `json:"is_available"` // nolint: tagliatelle
+	Accessible  bool    `json:"accessible,omitempty"`
+	HoldID      int64   `json:"holdId,omitempty"`
+	// Price is the price category of a local place.
+	Price *PlacePriceResponse `json:"price,omitempty"`
+}
+
+// Validate records violations of the place located at pointer.
+func (p PlaceResponse) Validate(v *validation.Validator, pointer string) {
+	v.Check(p.ID != 0, pointer+"/id", ErrRequired)
+	v.Check(p.ID < models.FirstLocalPlaceID, pointer+"/id", ErrInvalidValue)
+	v.Check(p.X >= 0, pointer+"/x", ErrNegative)
+	v.Check(p.Y >= 0, pointer+"/y", ErrNegative)
+	v.Check(p.Width >= 0, pointer+"/width", ErrNegative)
+	v.Check(p.Height >= 0, pointer+"/height", ErrNegative)
 }
 
 type PlaceListResponse struct {
 	Response []PlaceResponse `json:"response"`
+	// Invalid are the items that failed validation, responses only report their number in a header.
+	Invalid []InvalidItem `json:"-"`
+}
+
+// Partition validates every place and splits them into valid and invalid ones.
+func (p *PlaceListResponse) Partition() ([]PlaceResponse, []InvalidItem) {
+	return partition(p.Response)
 }
 
 func (p *PlaceListResponse) PlaceListResponseValidate() error {
-	if len(p.Response) == 0 {
-		return fmt.Errorf("empty response")
-	}
-	if p.Response[0].ID == 0 {
-		return fmt.Errorf("%w: id", ErrRequired)
-	}
-	if p.Response[0].X < 0 {
-		return fmt.Errorf("%w: x", ErrNegative)
-	}
-	if p.Response[0].Y < 0 {
-		return fmt.Errorf("%w: y", ErrNegative)
-	}
-	if p.Response[0].Width < 0 {
-		return fmt.Errorf("%w: width", ErrNegative)
+	var v validation.Validator
+	v.Check(len(p.Response) != 0, validation.Pointer("response"), ErrEmpty)
+	for i, place := range p.Response {
+		place.Validate(&v, validation.Pointer("response", i))
 	}
-	if p.Response[0].Height < 0 {
-		return fmt.Errorf("%w: height", ErrNegative)
-	}
-	return nil
+	return v.Err()
+}
+
+// AdjacencyResponse links two neighbouring seats of a row, left to right.
+type AdjacencyResponse struct {
+	LeftID  int64 `json:"leftId"`
+	RightID int64 `json:"rightId"`
 }
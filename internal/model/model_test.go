@@ -0,0 +1,74 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventListResponsePartition(t *testing.T) {
+	var list EventListResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"response": [
+		{"id": 1, "showId": 1, "date": "2024-09-12T19:00:00+03:00"},
+		{"id": 2, "showId": 0, "date": "not a date"},
+		{"id": 3, "showId": 1, "date": "2024-09-13 19:00:00"}
+	]}`), &list))
+
+	valid, invalid := list.Partition()
+	require.Len(t, valid, 2)
+	require.Equal(t, int64(1), valid[0].ID)
+	require.Equal(t, int64(3), valid[1].ID)
+
+	require.Len(t, invalid, 1)
+	require.Equal(t, 1, invalid[0].Index)
+	require.Len(t, invalid[0].Errors, 2)
+	require.Equal(t, "/response/1/showId", invalid[0].Errors[0].Pointer)
+	require.ErrorIs(t, invalid[0].Errors[0], ErrRequired)
+	require.Equal(t, "/response/1/date", invalid[0].Errors[1].Pointer)
+	require.ErrorIs(t, invalid[0].Errors[1], ErrInvalidDate)
+}
+
+func TestEventResponseLocalize(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	var events []EventResponse
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"id": 1, "showId": 1, "date": "2024-09-12 19:00:00"},
+		{"id": 2, "showId": 1, "date": "2024-09-12T19:00:00Z"}
+	]`), &events))
+	for i := range events {
+		events[i].Localize(loc)
+	}
+
+	require.Equal(t, time.Date(2024, 9, 12, 16, 0, 0, 0, time.UTC), events[0].Date.UTC())
+	require.Equal(t, time.Date(2024, 9, 12, 19, 0, 0, 0, time.UTC), events[1].Date.UTC())
+
+	b, err := json.Marshal(events[0])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id":1,"showId":1,"date":"2024-09-12T19:00:00+03:00","timeZone":"Europe/Moscow"}`, string(b))
+}
+
+func TestPlaceListResponseValidate(t *testing.T) {
+	list := PlaceListResponse{Response: []PlaceResponse{
+		{ID: 1, X: 1, Y: 1, Width: 1, Height: 1},
+		{ID: 2, X: -1, Y: 1, Width: 1, Height: -1},
+	}}
+
+	err := list.PlaceListResponseValidate()
+	require.ErrorIs(t, err, ErrNegative)
+	require.EqualError(t, err, "/response/1/x: negative value; /response/1/height: negative value")
+
+	require.ErrorIs(t, (&PlaceListResponse{}).PlaceListResponseValidate(), ErrEmpty)
+}
+
+func TestInvalidItemPolicyUnmarshalText(t *testing.T) {
+	var p InvalidItemPolicy
+	require.NoError(t, p.UnmarshalText([]byte("quarantine")))
+	require.Equal(t, PolicyQuarantine, p)
+	require.NoError(t, p.UnmarshalText(nil))
+	require.Equal(t, PolicyReject, p)
+	require.Error(t, p.UnmarshalText([]byte("ignore")))
+}
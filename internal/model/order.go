@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/validation"
+)
+
+type OrderRequest struct {
+	HoldID int64 `json:"holdId"`
+	QuoteRequest
+}
+
+func (o OrderRequest) Validate() error {
+	var v validation.Validator
+	v.Check(o.HoldID > 0, validation.Pointer("holdId"), ErrRequired)
+	o.QuoteRequest.check(&v)
+	return v.Err()
+}
+
+// OrderResponse amounts are in minor units of Currency.
+type OrderResponse struct {
+	ID         int64               `json:"id"`
+	EventID    int64               `json:"eventId"`
+	HoldID     int64               `json:"holdId,omitempty"`
+	CustomerID int64               `json:"customerId,omitempty"`
+	Status     string              `json:"status"`
+	Currency   string              `json:"currency"`
+	Subtotal   int64               `json:"subtotal"`
+	Total      int64               `json:"total"`
+	Refunded   int64               `json:"refunded"`
+	Items      []OrderItemResponse `json:"items"`
+	Discounts  []DiscountResponse  `json:"discounts"`
+	CreatedAt  time.Time           `json:"createdAt"`
+}
+
+// OrderItemResponse status is issued, refunded or exchanged. ExchangedFrom is the item it replaced.
+type OrderItemResponse struct {
+	ID              int64  `json:"id"`
+	EventID         int64  `json:"eventId"`
+	PlaceID         int64  `json:"placeId"`
+	PriceCategoryID int64  `json:"priceCategoryId,omitempty"`
+	Amount          int64  `json:"amount"`
+	Discount        int64  `json:"discount"`
+	Status          string `json:"status"`
+	ExchangedFrom   int64  `json:"exchangedFrom,omitempty"`
+	// UsedAt is when the ticket was scanned at the door.
+	UsedAt *time.Time `json:"usedAt,omitempty"`
+}
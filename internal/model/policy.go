@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/cronnoss/tk-api/internal/common/validation"
+)
+
+// InvalidItemPolicy decides what happens to upstream items that fail validation.
+type InvalidItemPolicy string
+
+const (
+	// PolicyReject fails the whole batch if any item is invalid.
+	PolicyReject InvalidItemPolicy = "reject"
+	// PolicySkip stores the valid items and reports the number of invalid ones in logs and a response header.
+	PolicySkip InvalidItemPolicy = "skip"
+	// PolicyQuarantine is PolicySkip plus saving the invalid items for review.
+	PolicyQuarantine InvalidItemPolicy = "quarantine"
+)
+
+func (p *InvalidItemPolicy) UnmarshalText(text []byte) error {
+	switch v := InvalidItemPolicy(text); v {
+	case PolicyReject, PolicySkip, PolicyQuarantine:
+		*p = v
+		return nil
+	case "":
+		*p = PolicyReject
+		return nil
+	}
+	return fmt.Errorf("unknown invalid item policy %q", text)
+}
+
+// InvalidItem is an upstream item rejected by validation.
+type InvalidItem struct {
+	Index  int               `json:"index"`
+	Item   any               `json:"item"`
+	Errors validation.Errors `json:"errors"`
+}
+
+type validatable interface {
+	Validate(v *validation.Validator, pointer string)
+}
+
+// partition validates every item and splits them into valid and invalid ones.
+func partition[T validatable](items []T) ([]T, []InvalidItem) {
+	valid := make([]T, 0, len(items))
+	var invalid []InvalidItem
+	for i, item := range items {
+		var v validation.Validator
+		item.Validate(&v, validation.Pointer("response", i))
+		if v.Valid() {
+			valid = append(valid, item)
+			continue
+		}
+		invalid = append(invalid, InvalidItem{Index: i, Item: item, Errors: v.Errors()})
+	}
+	return valid, invalid
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"github.com/cronnoss/tk-api/internal/common/validation"
+	"github.com/cronnoss/tk-api/internal/money"
+)
+
+// PriceCategoryRequest defines a price tier. Amount is in minor units of Currency, an ISO 4217 code.
+type PriceCategoryRequest struct {
+	Name     string `json:"name"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (p PriceCategoryRequest) Validate() error {
+	var v validation.Validator
+	v.Check(p.Name != "", validation.Pointer("name"), ErrRequired)
+	v.Check(p.Amount >= 0, validation.Pointer("amount"), ErrNegative)
+	v.Check(money.Valid(p.Currency), validation.Pointer("currency"), ErrInvalidCurrency)
+	return v.Err()
+}
+
+type PriceCategoryResponse struct {
+	ID       int64  `json:"id"`
+	EventID  int64  `json:"eventId"`
+	Name     string `json:"name"`
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	// Display is the amount formatted for people, e.g. "1500.00 RUB".
+	Display string `json:"display"`
+}
+
+// PlacePriceResponse is the price of a place.
+type PlacePriceResponse struct {
+	CategoryID int64  `json:"categoryId"`
+	Name       string `json:"name"`
+	Amount     int64  `json:"amount"`
+	Currency   string `json:"currency"`
+}
+
+// PriceAssignmentRequest puts the listed places and all places of the listed sections
+// into a price category, categoryId 0 removes them from their category.
+type PriceAssignmentRequest struct {
+	CategoryID int64   `json:"categoryId"`
+	PlaceIDs   []int64 `json:"placeIds,omitempty"`
+	SectionIDs []int64 `json:"sectionIds,omitempty"`
+}
+
+// PriceMapRequest is applied in order, later assignments override earlier ones.
+type PriceMapRequest []PriceAssignmentRequest
+
+func (m PriceMapRequest) Validate() error {
+	var v validation.Validator
+	v.Check(len(m) != 0, "", ErrEmpty)
+	for i, a := range m {
+		v.Check(a.CategoryID >= 0, validation.Pointer(i, "categoryId"), ErrNegative)
+		v.Check(len(a.PlaceIDs)+len(a.SectionIDs) != 0, validation.Pointer(i), ErrEmpty)
+		for j, id := range a.PlaceIDs {
+			v.Check(id > 0, validation.Pointer(i, "placeIds", j), ErrInvalidValue)
+		}
+		for j, id := range a.SectionIDs {
+			v.Check(id > 0, validation.Pointer(i, "sectionIds", j), ErrInvalidValue)
+		}
+	}
+	return v.Err()
+}
+
+type PriceMapResponse struct {
+	// Updated is the number of place assignments applied.
+	Updated int64 `json:"updated"`
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/validation"
+	"github.com/cronnoss/tk-api/internal/money"
+)
+
+const (
+	PromotionPercent  = "percent"
+	PromotionFixed    = "fixed"
+	PromotionBuyNGetM = "buy_n_get_m"
+)
+
+// PromotionRequest defines a discount rule. A promotion without a code applies to every order in its scope.
+// Scope IDs left out match everything, limits of 0 are unlimited.
+type PromotionRequest struct {
+	Code string `json:"code,omitempty"`
+	Name string `json:"name"`
+	// Kind is percent, fixed or buy_n_get_m.
+	Kind string `json:"kind"`
+	// Percent is taken off every place in scope by percent promotions.
+	Percent int `json:"percent,omitempty"`
+	// Amount in minor units of Currency is taken off the places in scope in total by fixed promotions.
+	Amount   int64  `json:"amount,omitempty"`
+	Currency string `json:"currency,omitempty"`
+	// BuyN and GetM: buy_n_get_m promotions make the GetM cheapest of every BuyN+GetM places in scope free.
+	BuyN               int        `json:"buyN,omitempty"`
+	GetM               int        `json:"getM,omitempty"`
+	ShowID             int64      `json:"showId,omitempty"`
+	EventID            int64      `json:"eventId,omitempty"`
+	PriceCategoryID    int64      `json:"priceCategoryId,omitempty"`
+	ValidFrom          *time.Time `json:"validFrom,omitempty"`
+	ValidTo            *time.Time `json:"validTo,omitempty"`
+	MaxUses            int        `json:"maxUses,omitempty"`
+	MaxUsesPerCustomer int        `json:"maxUsesPerCustomer,omitempty"`
+}
+
+func (p PromotionRequest) Validate() error {
+	var v validation.Validator
+	v.Check(p.Name != "", validation.Pointer("name"), ErrRequired)
+	switch p.Kind {
+	case PromotionPercent:
+		v.Check(p.Percent > 0 && p.Percent <= 100, validation.Pointer("percent"), ErrInvalidValue)
+	case PromotionFixed:
+		v.Check(p.Amount > 0, validation.Pointer("amount"), ErrInvalidValue)
+		v.Check(money.Valid(p.Currency), validation.Pointer("currency"), ErrInvalidCurrency)
+	case PromotionBuyNGetM:
+		v.Check(p.BuyN > 0, validation.Pointer("buyN"), ErrInvalidValue)
+		v.Check(p.GetM > 0, validation.Pointer("getM"), ErrInvalidValue)
+	default:
+		v.Add(validation.Pointer("kind"), ErrInvalidValue)
+	}
+	v.Check(p.ShowID >= 0, validation.Pointer("showId"), ErrNegative)
+	v.Check(p.EventID >= 0, validation.Pointer("eventId"), ErrNegative)
+	v.Check(p.PriceCategoryID >= 0, validation.Pointer("priceCategoryId"), ErrNegative)
+	v.Check(p.ValidFrom == nil || p.ValidTo == nil || p.ValidFrom.Before(*p.ValidTo),
+		validation.Pointer("validTo"), ErrInvalidDate)
+	v.Check(p.MaxUses >= 0, validation.Pointer("maxUses"), ErrNegative)
+	v.Check(p.MaxUsesPerCustomer >= 0, validation.Pointer("maxUsesPerCustomer"), ErrNegative)
+	return v.Err()
+}
+
+type PromotionResponse struct {
+	PromotionRequest
+	ID        int64     `json:"id"`
+	Uses      int       `json:"uses"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// QuoteRequest asks to price a hold with promo codes. CustomerID is needed by codes limited per customer.
+type QuoteRequest struct {
+	PromoCodes []string `json:"promoCodes,omitempty"`
+	CustomerID int64    `json:"customerId,omitempty"`
+}
+
+func (q QuoteRequest) Validate() error {
+	var v validation.Validator
+	q.check(&v)
+	return v.Err()
+}
+
+func (q QuoteRequest) check(v *validation.Validator) {
+	for i, code := range q.PromoCodes {
+		v.Check(strings.TrimSpace(code) != "", validation.Pointer("promoCodes", i), ErrEmpty)
+	}
+	v.Check(q.CustomerID >= 0, validation.Pointer("customerId"), ErrNegative)
+}
+
+// QuoteResponse amounts are in minor units of Currency.
+type QuoteResponse struct {
+	Currency  string              `json:"currency"`
+	Subtotal  int64               `json:"subtotal"`
+	Total     int64               `json:"total"`
+	Lines     []QuoteLineResponse `json:"lines"`
+	Discounts []DiscountResponse  `json:"discounts"`
+}
+
+type QuoteLineResponse struct {
+	PlaceID         int64 `json:"placeId"`
+	PriceCategoryID int64 `json:"priceCategoryId"`
+	Amount          int64 `json:"amount"`
+	Discount        int64 `json:"discount"`
+}
+
+// DiscountResponse explains a promotion applied to an order.
+type DiscountResponse struct {
+	PromotionID int64  `json:"promotionId"`
+	Code        string `json:"code,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Amount      int64  `json:"amount"`
+}
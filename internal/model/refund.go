@@ -0,0 +1,83 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/validation"
+)
+
+// checkItemIDs reports duplicate or invalid order item IDs.
+func checkItemIDs(v *validation.Validator, ids []int64) {
+	v.Check(len(ids) <= MaxPartySize, validation.Pointer("itemIds"), ErrInvalidValue)
+	seen := make(map[int64]bool, len(ids))
+	for i, id := range ids {
+		v.Check(id > 0 && !seen[id], validation.Pointer("itemIds", i), ErrInvalidValue)
+		seen[id] = true
+	}
+}
+
+// RefundRequest refunds all issued items of the order if ItemIDs is left out.
+type RefundRequest struct {
+	ItemIDs []int64 `json:"itemIds,omitempty"`
+	Reason  string  `json:"reason,omitempty"`
+}
+
+func (r RefundRequest) Validate() error {
+	var v validation.Validator
+	checkItemIDs(&v, r.ItemIDs)
+	v.Check(len(r.Reason) <= 1000, validation.Pointer("reason"), ErrInvalidValue)
+	return v.Err()
+}
+
+// RefundResponse amounts are in minor units of the order currency, Amount is paid back and Fee is kept.
+type RefundResponse struct {
+	ID        int64     `json:"id"`
+	OrderID   int64     `json:"orderId"`
+	ItemIDs   []int64   `json:"itemIds"`
+	Amount    int64     `json:"amount"`
+	Fee       int64     `json:"fee"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ExchangeRequest exchanges every item for the place at the same position of PlaceIDs.
+type ExchangeRequest struct {
+	ItemIDs  []int64 `json:"itemIds"`
+	EventID  int64   `json:"eventId"`
+	PlaceIDs []int64 `json:"placeIds"`
+}
+
+func (e ExchangeRequest) Validate() error {
+	var v validation.Validator
+	v.Check(len(e.ItemIDs) != 0, validation.Pointer("itemIds"), ErrEmpty)
+	checkItemIDs(&v, e.ItemIDs)
+	v.Check(e.EventID > 0, validation.Pointer("eventId"), ErrRequired)
+	v.Check(len(e.PlaceIDs) == len(e.ItemIDs), validation.Pointer("placeIds"), ErrInvalidValue)
+	seen := make(map[int64]bool, len(e.PlaceIDs))
+	for i, id := range e.PlaceIDs {
+		v.Check(id > 0 && !seen[id], validation.Pointer("placeIds", i), ErrInvalidValue)
+		seen[id] = true
+	}
+	return v.Err()
+}
+
+// ExchangeResponse amounts are in minor units of the order currency. The customer pays Difference
+// plus Fee, a negative sum is owed to the customer.
+type ExchangeResponse struct {
+	ID         int64               `json:"id"`
+	OrderID    int64               `json:"orderId"`
+	EventID    int64               `json:"eventId"`
+	ItemIDs    []int64             `json:"itemIds"`
+	Items      []OrderItemResponse `json:"items"`
+	Difference int64               `json:"difference"`
+	Fee        int64               `json:"fee"`
+	CreatedAt  time.Time           `json:"createdAt"`
+}
+
+type AuditRecordResponse struct {
+	ID        int64           `json:"id"`
+	Action    string          `json:"action"`
+	Details   json.RawMessage `json:"details" swaggertype:"object"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/validation"
+)
+
+// ScanRequest is a ticket code read at the door of an event.
+type ScanRequest struct {
+	Code string `json:"code"`
+	// EventID is the event being admitted, tickets of other events are rejected. Any event if left out.
+	EventID int64 `json:"eventId,omitempty"`
+}
+
+func (s ScanRequest) Validate() error {
+	var v validation.Validator
+	v.Check(s.Code != "", validation.Pointer("code"), ErrRequired)
+	v.Check(s.EventID >= 0, validation.Pointer("eventId"), ErrNegative)
+	return v.Err()
+}
+
+// ScanResponse is an admitted ticket.
+type ScanResponse struct {
+	TicketID int64     `json:"ticketId"`
+	OrderID  int64     `json:"orderId"`
+	EventID  int64     `json:"eventId"`
+	PlaceID  int64     `json:"placeId"`
+	UsedAt   time.Time `json:"usedAt"`
+}
+
+// TicketKeysResponse lists the base64 public keys ticket codes are verified with, the current one first.
+// A code is URL-safe base64 of a version byte, varints of the ticket, order, event and place IDs
+// and the signature of those.
+type TicketKeysResponse struct {
+	Algorithm string   `json:"algorithm"`
+	Keys      []string `json:"keys"`
+}
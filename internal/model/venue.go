@@ -0,0 +1,109 @@
+package model
+
+import (
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/validation"
+)
+
+type VenueRequest struct {
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	TimeZone string `json:"timeZone"`
+}
+
+func (v VenueRequest) Validate() error {
+	var val validation.Validator
+	val.Check(v.Name != "", validation.Pointer("name"), ErrRequired)
+	if v.TimeZone == "" {
+		val.Add(validation.Pointer("timeZone"), ErrRequired)
+	} else if _, err := time.LoadLocation(v.TimeZone); err != nil {
+		val.Add(validation.Pointer("timeZone"), ErrInvalidTimeZone)
+	}
+	return val.Err()
+}
+
+type VenueResponse struct {
+	ID       int64          `json:"id"`
+	Name     string         `json:"name"`
+	Address  string         `json:"address,omitempty"`
+	TimeZone string         `json:"timeZone"`
+	Halls    []HallResponse `json:"halls,omitempty"`
+}
+
+type HallRequest struct {
+	Name string `json:"name"`
+}
+
+func (h HallRequest) Validate() error {
+	var v validation.Validator
+	v.Check(h.Name != "", validation.Pointer("name"), ErrRequired)
+	return v.Err()
+}
+
+type HallResponse struct {
+	ID       int64             `json:"id"`
+	VenueID  int64             `json:"venueId"`
+	Name     string            `json:"name"`
+	Sections []SectionResponse `json:"sections,omitempty"`
+}
+
+type SectionRequest struct {
+	Name string `json:"name"`
+}
+
+func (s SectionRequest) Validate() error {
+	var v validation.Validator
+	v.Check(s.Name != "", validation.Pointer("name"), ErrRequired)
+	return v.Err()
+}
+
+type SectionResponse struct {
+	ID     int64  `json:"id"`
+	HallID int64  `json:"hallId"`
+	Name   string `json:"name"`
+}
+
+// HallPlaceRequest is a seat of a hall layout.
+type HallPlaceRequest struct {
+	SectionID int64   `json:"sectionId,omitempty"`
+	Row       string  `json:"row"`
+	Seat      int     `json:"seat"`
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+	Width     float64 `json:"width"`
+	Height    float64 `json:"height"`
+	// Accessible marks a wheelchair or companion place.
+	Accessible bool `json:"accessible,omitempty"`
+}
+
+// Validate records violations of the place located at pointer.
+func (p HallPlaceRequest) Validate(v *validation.Validator, pointer string) {
+	v.Check(p.SectionID >= 0, pointer+"/sectionId", ErrNegative)
+	v.Check(p.Seat >= 0, pointer+"/seat", ErrNegative)
+	v.Check(p.X >= 0, pointer+"/x", ErrNegative)
+	v.Check(p.Y >= 0, pointer+"/y", ErrNegative)
+	v.Check(p.Width >= 0, pointer+"/width", ErrNegative)
+	v.Check(p.Height >= 0, pointer+"/height", ErrNegative)
+}
+
+type HallPlaceListRequest []HallPlaceRequest
+
+func (l HallPlaceListRequest) Validate() error {
+	var v validation.Validator
+	v.Check(len(l) != 0, "", ErrEmpty)
+	for i, p := range l {
+		p.Validate(&v, validation.Pointer(i))
+	}
+	return v.Err()
+}
+
+type AttachHallRequest struct {
+	HallID int64 `json:"hallId"`
+}
+
+func (a AttachHallRequest) Validate() error {
+	var v validation.Validator
+	v.Check(a.HallID > 0, validation.Pointer("hallId"), ErrRequired)
+	return v.Err()
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/validation"
+)
+
+type WaitlistRequest struct {
+	CustomerID int64 `json:"customerId"`
+	Quantity   int   `json:"quantity"`
+	// MaxPrice is the price ceiling per place in minor units, any price goes if left out.
+	MaxPrice int64 `json:"maxPrice,omitempty"`
+}
+
+func (w WaitlistRequest) Validate() error {
+	var v validation.Validator
+	v.Check(w.CustomerID > 0, validation.Pointer("customerId"), ErrRequired)
+	v.Check(w.Quantity > 0 && w.Quantity <= MaxPartySize, validation.Pointer("quantity"), ErrInvalidValue)
+	v.Check(w.MaxPrice >= 0, validation.Pointer("maxPrice"), ErrNegative)
+	return v.Err()
+}
+
+// WaitlistEntryResponse status is waiting, offered or cancelled. Offered entries have a hold
+// of the places offered to the customer.
+type WaitlistEntryResponse struct {
+	ID         int64      `json:"id"`
+	EventID    int64      `json:"eventId"`
+	CustomerID int64      `json:"customerId"`
+	Quantity   int        `json:"quantity"`
+	MaxPrice   int64      `json:"maxPrice,omitempty"`
+	Status     string     `json:"status"`
+	HoldID     int64      `json:"holdId,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	OfferedAt  *time.Time `json:"offeredAt,omitempty"`
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/validation"
+	"github.com/cronnoss/tk-api/internal/webhooks"
+)
+
+// minWebhookSecret is the shortest secret accepted from integrators.
+const minWebhookSecret = 16
+
+// WebhookRequest registers an endpoint for event types, see domain.Types. A secret is generated
+// if none is given.
+type WebhookRequest struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"eventTypes"`
+}
+
+func (w WebhookRequest) Validate() error {
+	var v validation.Validator
+	u, err := url.Parse(w.URL)
+	v.Check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+		validation.Pointer("url"), ErrInvalidValue)
+	v.Check(w.Secret == "" || len(w.Secret) >= minWebhookSecret, validation.Pointer("secret"), ErrInvalidValue)
+	v.Check(len(w.EventTypes) > 0, validation.Pointer("eventTypes"), ErrEmpty)
+	for i, t := range w.EventTypes {
+		v.Check(webhooks.ValidType(t), validation.Pointer("eventTypes", i), ErrInvalidValue)
+	}
+	return v.Err()
+}
+
+// WebhookResponse has the secret only when the webhook is created.
+type WebhookResponse struct {
+	ID         int64     `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret,omitempty"`
+	EventTypes []string  `json:"eventTypes"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// WebhookDeliveryResponse status is pending, delivered or dead. Dead deliveries ran out of attempts
+// and are only sent again by redelivery. Payload and Log are set for a single delivery.
+type WebhookDeliveryResponse struct {
+	ID            int64                    `json:"id"`
+	WebhookID     int64                    `json:"webhookId"`
+	EventID       string                   `json:"eventId"`
+	EventType     string                   `json:"eventType"`
+	Status        string                   `json:"status"`
+	Attempts      int                      `json:"attempts"`
+	NextAttemptAt *time.Time               `json:"nextAttemptAt,omitempty"`
+	LastError     string                   `json:"lastError,omitempty"`
+	CreatedAt     time.Time                `json:"createdAt"`
+	DeliveredAt   *time.Time               `json:"deliveredAt,omitempty"`
+	Payload       json.RawMessage          `json:"payload,omitempty" swaggertype:"object"`
+	Log           []WebhookAttemptResponse `json:"log,omitempty"`
+}
+
+// WebhookAttemptResponse status code is left out if the endpoint didn't respond.
+type WebhookAttemptResponse struct {
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMS int64     `json:"durationMs"`
+	At         time.Time `json:"at"`
+}
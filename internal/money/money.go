@@ -0,0 +1,42 @@
+// Package money handles amounts stored in minor currency units with ISO 4217 codes.
+package money
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// exponents maps supported ISO 4217 codes to the number of minor unit digits.
+var exponents = map[string]int{
+	"AED": 2, "AMD": 2, "AUD": 2, "AZN": 2, "BGN": 2, "BHD": 3, "BRL": 2, "BYN": 2,
+	"CAD": 2, "CHF": 2, "CLP": 0, "CNY": 2, "CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2,
+	"GEL": 2, "HKD": 2, "HUF": 2, "IDR": 2, "ILS": 2, "INR": 2, "ISK": 0, "JOD": 3,
+	"JPY": 0, "KGS": 2, "KRW": 0, "KWD": 3, "KZT": 2, "MDL": 2, "MXN": 2, "NOK": 2,
+	"NZD": 2, "OMR": 3, "PLN": 2, "RON": 2, "RSD": 2, "RUB": 2, "SAR": 2, "SEK": 2,
+	"SGD": 2, "THB": 2, "TJS": 2, "TND": 3, "TRY": 2, "UAH": 2, "USD": 2, "UZS": 2,
+	"VND": 0, "ZAR": 2,
+}
+
+// Valid reports whether code is a supported ISO 4217 currency code.
+func Valid(code string) bool {
+	_, ok := exponents[code]
+	return ok
+}
+
+// Format renders amount minor units of the currency as a decimal with its code, e.g. "1500.00 RUB".
+func Format(amount int64, code string) string {
+	exp := exponents[code]
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	s := strconv.FormatInt(amount, 10)
+	if exp > 0 {
+		if len(s) <= exp {
+			s = strings.Repeat("0", exp-len(s)+1) + s
+		}
+		s = s[:len(s)-exp] + "." + s[len(s)-exp:]
+	}
+	return fmt.Sprintf("%s%s %s", sign, s, code)
+}
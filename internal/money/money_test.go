@@ -0,0 +1,20 @@
+package money
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	require.Equal(t, "1500.00 RUB", Format(150000, "RUB"))
+	require.Equal(t, "0.05 EUR", Format(5, "EUR"))
+	require.Equal(t, "-1.250 KWD", Format(-1250, "KWD"))
+	require.Equal(t, "700 JPY", Format(700, "JPY"))
+}
+
+func TestValid(t *testing.T) {
+	require.True(t, Valid("USD"))
+	require.False(t, Valid("usd"))
+	require.False(t, Valid("XXX"))
+}
@@ -0,0 +1,218 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/money"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+var (
+	ErrPromoUnknown          = errors.New("unknown promo code")
+	ErrPromoExpired          = errors.New("promo code is not valid now")
+	ErrPromoLimit            = errors.New("promo code usage limit reached")
+	ErrPromoCustomerRequired = errors.New("promo code requires a customer")
+	ErrPromoNotApplicable    = errors.New("promo code does not apply to the order")
+)
+
+// PromoError tells which promo code was rejected and why.
+type PromoError struct {
+	Code string
+	Err  error
+}
+
+func (e *PromoError) Error() string {
+	return fmt.Sprintf("promo code %s: %v", e.Code, e.Err)
+}
+
+func (e *PromoError) Unwrap() error {
+	return e.Err
+}
+
+// NormalizeCode returns the canonical form of a promo code.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Discount explains a promotion applied to a quote.
+type Discount struct {
+	PromotionID int64
+	Code        string
+	Name        string
+	Description string
+	Amount      int64
+	PlaceIDs    []int64
+}
+
+// Checkout is what promotions are evaluated against besides the quote.
+type Checkout struct {
+	ShowID  int64
+	EventID int64
+	// Codes are the promo codes entered by the buyer.
+	Codes []string
+	Now   time.Time
+	// CustomerID identifies the buyer for per-customer limits, 0 if anonymous.
+	CustomerID int64
+	// Redeemed counts earlier redemptions of promotions by the customer.
+	Redeemed map[int64]int
+}
+
+// Apply applies promotions to the quote. Promotions are evaluated one by one in ID order,
+// each on the amounts left by the previous ones, so the result doesn't depend on the order of codes.
+// Automatic promotions that don't apply are skipped, a rejected code fails with a PromoError.
+func Apply(q Quote, promotions []models.Promotion, c Checkout) (Quote, error) {
+	codes := make(map[string]bool, len(c.Codes))
+	for _, code := range c.Codes {
+		codes[NormalizeCode(code)] = true
+	}
+	byCode := make(map[string]bool)
+	for _, p := range promotions {
+		if p.Code != "" {
+			byCode[p.Code] = true
+		}
+	}
+	for code := range codes {
+		if !byCode[code] {
+			return Quote{}, &PromoError{Code: code, Err: ErrPromoUnknown}
+		}
+	}
+
+	sorted := append([]models.Promotion(nil), promotions...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	q.Lines = append([]Line(nil), q.Lines...)
+	q.Discounts = append([]Discount(nil), q.Discounts...)
+	for _, p := range sorted {
+		if p.Code != "" && !codes[p.Code] {
+			continue
+		}
+		d, err := apply(&q, p, c)
+		if err != nil {
+			if p.Code == "" {
+				continue
+			}
+			return Quote{}, &PromoError{Code: p.Code, Err: err}
+		}
+		q.Discounts = append(q.Discounts, d)
+		q.Total -= d.Amount
+	}
+	return q, nil
+}
+
+// apply takes the promotion discount off the quote lines.
+func apply(q *Quote, p models.Promotion, c Checkout) (Discount, error) {
+	switch {
+	case !p.IsValid(c.Now):
+		return Discount{}, ErrPromoExpired
+	case p.MaxUses > 0 && p.Uses >= p.MaxUses:
+		return Discount{}, ErrPromoLimit
+	case p.MaxUsesPerCustomer > 0 && c.CustomerID == 0:
+		return Discount{}, ErrPromoCustomerRequired
+	case p.MaxUsesPerCustomer > 0 && c.Redeemed[p.ID] >= p.MaxUsesPerCustomer:
+		return Discount{}, ErrPromoLimit
+	case p.ShowID.Valid && p.ShowID.Int64 != c.ShowID, p.EventID.Valid && p.EventID.Int64 != c.EventID:
+		return Discount{}, ErrPromoNotApplicable
+	}
+
+	var eligible []int
+	for i, l := range q.Lines {
+		if (!p.PriceCategoryID.Valid || p.PriceCategoryID.Int64 == l.CategoryID) && l.Net() > 0 {
+			eligible = append(eligible, i)
+		}
+	}
+
+	var off map[int]int64
+	var description string
+	switch p.Kind {
+	case models.PromotionPercent:
+		off, description = percentOff(q.Lines, eligible, p.Percent)
+	case models.PromotionFixed:
+		if p.Currency != q.Currency {
+			return Discount{}, ErrPromoNotApplicable
+		}
+		off, description = fixedOff(q.Lines, eligible, p.Amount, p.Currency)
+	case models.PromotionBuyNGetM:
+		off, description = buyNGetM(q.Lines, eligible, p.BuyN, p.GetM)
+	}
+
+	d := Discount{PromotionID: p.ID, Code: p.Code, Name: p.Name, Description: description}
+	for _, i := range eligible {
+		if off[i] > 0 {
+			q.Lines[i].Discount += off[i]
+			d.Amount += off[i]
+			d.PlaceIDs = append(d.PlaceIDs, q.Lines[i].PlaceID)
+		}
+	}
+	if d.Amount == 0 {
+		return Discount{}, ErrPromoNotApplicable
+	}
+	return d, nil
+}
+
+func percentOff(lines []Line, eligible []int, percent int) (map[int]int64, string) {
+	off := make(map[int]int64, len(eligible))
+	for _, i := range eligible {
+		off[i] = lines[i].Net() * int64(percent) / 100
+	}
+	return off, fmt.Sprintf("%d%% off %s", percent, plural(len(eligible)))
+}
+
+// fixedOff spreads amount over the eligible lines in proportion to their net amounts,
+// the rounding remainder goes to the first lines.
+func fixedOff(lines []Line, eligible []int, amount int64, currency string) (map[int]int64, string) {
+	var net int64
+	for _, i := range eligible {
+		net += lines[i].Net()
+	}
+	amount = min(amount, net)
+	off := make(map[int]int64, len(eligible))
+	left := amount
+	for _, i := range eligible {
+		off[i] = amount * lines[i].Net() / net
+		left -= off[i]
+	}
+	for _, i := range eligible {
+		if left == 0 {
+			break
+		}
+		if off[i] < lines[i].Net() {
+			off[i]++
+			left--
+		}
+	}
+	return off, fmt.Sprintf("%s off %s", money.Format(amount, currency), plural(len(eligible)))
+}
+
+// buyNGetM orders eligible lines from the most expensive and makes the last m of every n+m free.
+func buyNGetM(lines []Line, eligible []int, n, m int) (map[int]int64, string) {
+	sorted := append([]int(nil), eligible...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		la, lb := lines[sorted[a]], lines[sorted[b]]
+		if la.Net() != lb.Net() {
+			return la.Net() > lb.Net()
+		}
+		return la.PlaceID < lb.PlaceID
+	})
+	off := make(map[int]int64)
+	free := 0
+	if group := n + m; n > 0 && m > 0 {
+		for start := 0; start+group <= len(sorted); start += group {
+			for _, i := range sorted[start+n : start+group] {
+				off[i] = lines[i].Net()
+				free++
+			}
+		}
+	}
+	return off, fmt.Sprintf("buy %d get %d free: %s free", n, m, plural(free))
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return "1 place"
+	}
+	return fmt.Sprintf("%d places", n)
+}
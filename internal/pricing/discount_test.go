@@ -0,0 +1,127 @@
+package pricing
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/stretchr/testify/require"
+)
+
+func quote(amounts ...int64) Quote {
+	q := Quote{Currency: "RUB"}
+	for i, a := range amounts {
+		q.Lines = append(q.Lines, Line{PlaceID: int64(i + 1), CategoryID: 1, Amount: a})
+		q.Subtotal += a
+	}
+	q.Total = q.Subtotal
+	return q
+}
+
+func discounts(q Quote) []int64 {
+	res := make([]int64, 0, len(q.Lines))
+	for _, l := range q.Lines {
+		res = append(res, l.Discount)
+	}
+	return res
+}
+
+func TestApply(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	percent := models.Promotion{ID: 1, Code: "TEN", Kind: models.PromotionPercent, Percent: 10, Active: true}
+	fixed := models.Promotion{ID: 2, Code: "FIXED", Kind: models.PromotionFixed, Amount: 1000, Currency: "RUB", Active: true}
+	bogo := models.Promotion{ID: 3, Kind: models.PromotionBuyNGetM, BuyN: 1, GetM: 1, Active: true}
+
+	t.Run("percent", func(t *testing.T) {
+		q, err := Apply(quote(1005, 2000), []models.Promotion{percent}, Checkout{Codes: []string{" ten "}, Now: now})
+		require.NoError(t, err)
+		require.Equal(t, []int64{100, 200}, discounts(q))
+		require.Equal(t, int64(2705), q.Total)
+		require.Equal(t, "10% off 2 places", q.Discounts[0].Description)
+	})
+
+	t.Run("fixed is split in proportion", func(t *testing.T) {
+		q, err := Apply(quote(1000, 1000, 1000), []models.Promotion{fixed}, Checkout{Codes: []string{"FIXED"}, Now: now})
+		require.NoError(t, err)
+		require.Equal(t, []int64{334, 333, 333}, discounts(q))
+		require.Equal(t, int64(2000), q.Total)
+	})
+
+	t.Run("fixed is capped", func(t *testing.T) {
+		q, err := Apply(quote(300, 200), []models.Promotion{fixed}, Checkout{Codes: []string{"FIXED"}, Now: now})
+		require.NoError(t, err)
+		require.Equal(t, []int64{300, 200}, discounts(q))
+		require.Equal(t, int64(0), q.Total)
+	})
+
+	t.Run("buy one get one makes the cheaper free", func(t *testing.T) {
+		q, err := Apply(quote(500, 900, 700, 300, 100), []models.Promotion{bogo}, Checkout{Now: now})
+		require.NoError(t, err)
+		require.Equal(t, []int64{0, 0, 700, 300, 0}, discounts(q))
+		require.Equal(t, int64(1500), q.Total)
+	})
+
+	t.Run("stacking is in promotion order", func(t *testing.T) {
+		promos := []models.Promotion{bogo, fixed, percent}
+		a, err := Apply(quote(1000, 1000), promos, Checkout{Codes: []string{"FIXED", "TEN"}, Now: now})
+		require.NoError(t, err)
+		b, err := Apply(quote(1000, 1000), promos, Checkout{Codes: []string{"TEN", "FIXED"}, Now: now})
+		require.NoError(t, err)
+		require.Equal(t, a, b)
+		// 10% off 2000, then 1000 off 1800, then the cheaper of 400 and 400 free.
+		require.Equal(t, int64(400), a.Total)
+		require.Len(t, a.Discounts, 3)
+		require.Equal(t, int64(1), a.Discounts[0].PromotionID)
+	})
+
+	t.Run("scope", func(t *testing.T) {
+		scoped := percent
+		scoped.EventID = sql.NullInt64{Int64: 7, Valid: true}
+		_, err := Apply(quote(1000), []models.Promotion{scoped}, Checkout{EventID: 8, Codes: []string{"TEN"}, Now: now})
+		require.ErrorIs(t, err, ErrPromoNotApplicable)
+
+		q, err := Apply(quote(1000), []models.Promotion{scoped}, Checkout{EventID: 7, Codes: []string{"TEN"}, Now: now})
+		require.NoError(t, err)
+		require.Equal(t, int64(900), q.Total)
+	})
+
+	t.Run("automatic promotions are skipped when they don't apply", func(t *testing.T) {
+		q, err := Apply(quote(1000), []models.Promotion{bogo}, Checkout{Now: now})
+		require.NoError(t, err)
+		require.Empty(t, q.Discounts)
+		require.Equal(t, q.Subtotal, q.Total)
+	})
+
+	t.Run("rejected codes", func(t *testing.T) {
+		_, err := Apply(quote(1000), []models.Promotion{percent}, Checkout{Codes: []string{"NOPE"}, Now: now})
+		var perr *PromoError
+		require.ErrorAs(t, err, &perr)
+		require.Equal(t, "NOPE", perr.Code)
+		require.ErrorIs(t, err, ErrPromoUnknown)
+
+		expired := percent
+		expired.ValidTo = sql.NullTime{Time: now, Valid: true}
+		_, err = Apply(quote(1000), []models.Promotion{expired}, Checkout{Codes: []string{"TEN"}, Now: now})
+		require.ErrorIs(t, err, ErrPromoExpired)
+
+		limited := percent
+		limited.MaxUses, limited.Uses = 5, 5
+		_, err = Apply(quote(1000), []models.Promotion{limited}, Checkout{Codes: []string{"TEN"}, Now: now})
+		require.ErrorIs(t, err, ErrPromoLimit)
+
+		perCustomer := percent
+		perCustomer.MaxUsesPerCustomer = 1
+		_, err = Apply(quote(1000), []models.Promotion{perCustomer}, Checkout{Codes: []string{"TEN"}, Now: now})
+		require.ErrorIs(t, err, ErrPromoCustomerRequired)
+		_, err = Apply(quote(1000), []models.Promotion{perCustomer}, Checkout{
+			Codes: []string{"TEN"}, Now: now, CustomerID: 4, Redeemed: map[int64]int{1: 1},
+		})
+		require.ErrorIs(t, err, ErrPromoLimit)
+
+		eur := fixed
+		eur.Currency = "EUR"
+		_, err = Apply(quote(1000), []models.Promotion{eur}, Checkout{Codes: []string{"FIXED"}, Now: now})
+		require.ErrorIs(t, err, ErrPromoNotApplicable)
+	})
+}
@@ -0,0 +1,76 @@
+// Package pricing prices places by their event price categories.
+package pricing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+var (
+	ErrUnpriced        = errors.New("place has no price")
+	ErrMixedCurrencies = errors.New("places are priced in different currencies")
+)
+
+// Line is the price of one place, Discount of the Amount is taken off by promotions.
+type Line struct {
+	PlaceID    int64
+	CategoryID int64
+	Amount     int64
+	Discount   int64
+}
+
+// Net returns the amount due for the place.
+func (l Line) Net() int64 {
+	return l.Amount - l.Discount
+}
+
+// Quote is the price of a set of places. Amounts are in minor units of Currency.
+type Quote struct {
+	Lines     []Line
+	Currency  string
+	Subtotal  int64
+	Total     int64
+	Discounts []Discount
+}
+
+// Categories indexes price categories by ID.
+type Categories map[int64]models.PriceCategory
+
+func NewCategories(categories []models.PriceCategory) Categories {
+	c := make(Categories, len(categories))
+	for _, cat := range categories {
+		c[cat.ID] = cat
+	}
+	return c
+}
+
+// Of returns the price category of the place.
+func (c Categories) Of(p models.Place) (models.PriceCategory, bool) {
+	if !p.PriceCategoryID.Valid {
+		return models.PriceCategory{}, false
+	}
+	cat, ok := c[p.PriceCategoryID.Int64]
+	return cat, ok
+}
+
+// Price quotes places. Every place must be in a category and all categories must share a currency.
+func Price(places []models.Place, categories Categories) (Quote, error) {
+	var q Quote
+	for _, p := range places {
+		cat, ok := categories.Of(p)
+		if !ok {
+			return Quote{}, fmt.Errorf("place %d: %w", p.ID, ErrUnpriced)
+		}
+		if q.Currency == "" {
+			q.Currency = cat.Currency
+		} else if q.Currency != cat.Currency {
+			return Quote{}, ErrMixedCurrencies
+		}
+		q.Lines = append(q.Lines, Line{PlaceID: p.ID, CategoryID: cat.ID, Amount: cat.Amount})
+		q.Subtotal += cat.Amount
+	}
+	q.Total = q.Subtotal
+	return q, nil
+}
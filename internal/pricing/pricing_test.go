@@ -0,0 +1,37 @@
+package pricing
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/stretchr/testify/require"
+)
+
+func place(id, categoryID int64) models.Place {
+	return models.Place{ID: id, PriceCategoryID: sql.NullInt64{Int64: categoryID, Valid: categoryID != 0}}
+}
+
+func TestPrice(t *testing.T) {
+	categories := NewCategories([]models.PriceCategory{
+		{ID: 1, Name: "Stalls", Amount: 250000, Currency: "RUB"},
+		{ID: 2, Name: "Balcony", Amount: 120000, Currency: "RUB"},
+		{ID: 3, Name: "Promo", Amount: 1000, Currency: "EUR"},
+	})
+
+	q, err := Price([]models.Place{place(10, 1), place(11, 1), place(12, 2)}, categories)
+	require.NoError(t, err)
+	require.Equal(t, "RUB", q.Currency)
+	require.Equal(t, int64(620000), q.Subtotal)
+	require.Equal(t, q.Subtotal, q.Total)
+	require.Equal(t, Line{PlaceID: 12, CategoryID: 2, Amount: 120000}, q.Lines[2])
+
+	_, err = Price([]models.Place{place(10, 1), place(13, 0)}, categories)
+	require.ErrorIs(t, err, ErrUnpriced)
+
+	_, err = Price([]models.Place{place(10, 1), place(14, 99)}, categories)
+	require.ErrorIs(t, err, ErrUnpriced)
+
+	_, err = Price([]models.Place{place(10, 1), place(15, 3)}, categories)
+	require.ErrorIs(t, err, ErrMixedCurrencies)
+}
@@ -0,0 +1,33 @@
+// Package refunds decides whether and at what cost issued tickets can be refunded or exchanged.
+package refunds
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrDeadlinePassed = errors.New("refund deadline has passed")
+
+// Policy is the refund and exchange policy. Fixed fees are in minor units of the order currency.
+type Policy struct {
+	// Deadline closes refunds and exchanges this long before the event starts.
+	Deadline time.Duration `toml:"deadline"`
+	// FeePercent of the paid amount plus FeeFixed is kept for every refunded ticket.
+	FeePercent int   `toml:"fee-percent"`
+	FeeFixed   int64 `toml:"fee-fixed"`
+	// ExchangeFee is charged for every exchanged ticket.
+	ExchangeFee int64 `toml:"exchange-fee"`
+}
+
+// Open returns ErrDeadlinePassed unless tickets of an event starting at eventDate can be refunded at now.
+func (p Policy) Open(eventDate, now time.Time) error {
+	if !now.Before(eventDate.Add(-p.Deadline)) {
+		return ErrDeadlinePassed
+	}
+	return nil
+}
+
+// Fee returns the part of paid kept when a ticket is refunded, never more than paid.
+func (p Policy) Fee(paid int64) int64 {
+	return min(paid, paid*int64(p.FeePercent)/100+p.FeeFixed)
+}
@@ -0,0 +1,21 @@
+package refunds
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy(t *testing.T) {
+	p := Policy{Deadline: 24 * time.Hour, FeePercent: 10, FeeFixed: 5000}
+	event := time.Date(2026, 12, 31, 19, 0, 0, 0, time.UTC)
+
+	require.NoError(t, p.Open(event, event.Add(-25*time.Hour)))
+	require.ErrorIs(t, p.Open(event, event.Add(-24*time.Hour)), ErrDeadlinePassed)
+	require.ErrorIs(t, p.Open(event, event.Add(time.Hour)), ErrDeadlinePassed)
+
+	require.Equal(t, int64(15000), p.Fee(100000))
+	require.Equal(t, int64(3000), p.Fee(3000), "the fee is capped at the paid amount")
+	require.Equal(t, int64(0), Policy{}.Fee(100000))
+}
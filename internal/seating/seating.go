@@ -0,0 +1,185 @@
+// Package seating picks the best group of adjacent available places for a party.
+package seating
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/cronnoss/tk-api/internal/pricing"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+var ErrNoSeats = errors.New("no contiguous seats available")
+
+type Preferences struct {
+	// Quantity is the party size.
+	Quantity int
+	// SectionID limits the search to one section if set.
+	SectionID int64
+	// Accessible requires at least one accessible place in the group.
+	Accessible bool
+	// MaxPrice limits the search to places priced at most MaxPrice minor units if set.
+	MaxPrice int64
+	// Prices are the price categories of the event, needed with MaxPrice.
+	Prices pricing.Categories
+	// Filter limits the search to places it accepts if set.
+	Filter func(models.Place) bool
+	// Stage is where the stage is, the top centre of the seat map if nil.
+	Stage *models.Point
+}
+
+func (prefs Preferences) affordable(p models.Place) bool {
+	if prefs.MaxPrice <= 0 {
+		return true
+	}
+	cat, ok := prefs.Prices.Of(p)
+	return ok && cat.Amount <= prefs.MaxPrice
+}
+
+// candidate is a group of places with its score, lower is better.
+type candidate struct {
+	places   []models.Place
+	orphans  int
+	distance float64
+}
+
+func (c candidate) better(o candidate) bool {
+	if c.orphans != o.orphans {
+		return c.orphans < o.orphans
+	}
+	if c.distance != o.distance {
+		return c.distance < o.distance
+	}
+	return c.places[0].ID < o.places[0].ID
+}
+
+// Best returns the best group of prefs.Quantity adjacent available places in one row.
+// Groups leaving fewer single orphan places next to them win, then groups closer to the stage.
+func Best(places []models.Place, prefs Preferences) ([]models.Place, error) {
+	if prefs.Quantity <= 0 || len(places) == 0 {
+		return nil, ErrNoSeats
+	}
+	stage := stagePoint(places, prefs.Stage)
+
+	var best *candidate
+	for _, row := range Rows(places) {
+		if prefs.SectionID != 0 && row[0].SectionID.Int64 != prefs.SectionID {
+			continue
+		}
+		for _, run := range Runs(row) {
+			usable := make([]bool, len(run))
+			for i, p := range run {
+				usable[i] = p.IsAvailable && prefs.affordable(p) && (prefs.Filter == nil || prefs.Filter(p))
+			}
+			for i := 0; i+prefs.Quantity <= len(run); i++ {
+				c, ok := evaluate(run, usable, i, prefs, stage)
+				if ok && (best == nil || c.better(*best)) {
+					best = &c
+				}
+			}
+		}
+	}
+	if best == nil {
+		return nil, ErrNoSeats
+	}
+	return best.places, nil
+}
+
+func evaluate(run []models.Place, usable []bool, start int, prefs Preferences, stage models.Point,
+) (candidate, bool) {
+	end := start + prefs.Quantity
+	accessible := false
+	for i := start; i < end; i++ {
+		if !usable[i] {
+			return candidate{}, false
+		}
+		accessible = accessible || run[i].Accessible
+	}
+	if prefs.Accessible && !accessible {
+		return candidate{}, false
+	}
+
+	// A free place squeezed between the group and a taken place or the row end can't be sold to a pair.
+	free := func(i int) bool { return i >= 0 && i < len(run) && run[i].IsAvailable }
+	orphans := 0
+	if free(start-1) && !free(start-2) {
+		orphans++
+	}
+	if free(end) && !free(end+1) {
+		orphans++
+	}
+
+	group := run[start:end]
+	var centroid models.Point
+	for _, p := range group {
+		c := p.Center()
+		centroid.X += c.X / float64(len(group))
+		centroid.Y += c.Y / float64(len(group))
+	}
+	return candidate{
+		places:   append([]models.Place(nil), group...),
+		orphans:  orphans,
+		distance: centroid.Distance(stage),
+	}, true
+}
+
+func stagePoint(places []models.Place, stage *models.Point) models.Point {
+	if stage != nil {
+		return *stage
+	}
+	minX, minY, maxX := math.Inf(1), math.Inf(1), math.Inf(-1)
+	for _, p := range places {
+		minX = math.Min(minX, p.X)
+		maxX = math.Max(maxX, p.X+p.Width)
+		minY = math.Min(minY, p.Y)
+	}
+	return models.Point{X: (minX + maxX) / 2, Y: minY}
+}
+
+// Rows groups places into rows: places of one section whose vertical centres are within
+// half a place height of the row's first place. Rows are ordered top to bottom, places left to right.
+func Rows(places []models.Place) [][]models.Place {
+	sorted := append([]models.Place(nil), places...)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.SectionID.Int64 != b.SectionID.Int64 {
+			return a.SectionID.Int64 < b.SectionID.Int64
+		}
+		if ca, cb := a.Center().Y, b.Center().Y; ca != cb {
+			return ca < cb
+		}
+		return a.X < b.X
+	})
+
+	var rows [][]models.Place
+	for _, p := range sorted {
+		if n := len(rows); n > 0 {
+			first := rows[n-1][0]
+			sameSection := first.SectionID.Int64 == p.SectionID.Int64
+			tolerance := math.Max(first.Height, p.Height) / 2
+			if sameSection && math.Abs(p.Center().Y-first.Center().Y) <= tolerance {
+				rows[n-1] = append(rows[n-1], p)
+				continue
+			}
+		}
+		rows = append(rows, []models.Place{p})
+	}
+	for _, row := range rows {
+		sort.Slice(row, func(i, j int) bool { return row[i].X < row[j].X })
+	}
+	return rows
+}
+
+// Runs splits a row into runs of adjacent places regardless of their availability.
+func Runs(row []models.Place) [][]models.Place {
+	var runs [][]models.Place
+	start := 0
+	for i := 1; i <= len(row); i++ {
+		if i == len(row) || !row[i-1].Precedes(row[i]) {
+			runs = append(runs, row[start:i])
+			start = i
+		}
+	}
+	return runs
+}
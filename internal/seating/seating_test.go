@@ -0,0 +1,148 @@
+package seating
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/cronnoss/tk-api/internal/pricing"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/stretchr/testify/require"
+)
+
+// hall builds a seat map from rows of '.' (available), 'x' (taken), 'a' (accessible) and ' ' (aisle).
+// Place IDs are row*100 + column + 1, the stage is above the first row.
+func hall(rows ...string) []models.Place {
+	var places []models.Place
+	for r, row := range rows {
+		for c, ch := range row {
+			if ch == ' ' {
+				continue
+			}
+			places = append(places, models.Place{
+				ID:          int64(r*100 + c + 1),
+				SectionID:   sql.NullInt64{Int64: 1, Valid: true},
+				X:           float64(c) * 10,
+				Y:           float64(r) * 12,
+				Width:       9,
+				Height:      10,
+				IsAvailable: ch != 'x',
+				Accessible:  ch == 'a',
+			})
+		}
+	}
+	return places
+}
+
+func ids(places []models.Place) []int64 {
+	res := make([]int64, 0, len(places))
+	for _, p := range places {
+		res = append(res, p.ID)
+	}
+	return res
+}
+
+func TestRowsAndRuns(t *testing.T) {
+	rows := Rows(hall("... ..", "xx.x"))
+	require.Len(t, rows, 2)
+	require.Equal(t, []int64{1, 2, 3, 5, 6}, ids(rows[0]))
+
+	runs := Runs(rows[0])
+	require.Len(t, runs, 2)
+	require.Equal(t, []int64{1, 2, 3}, ids(runs[0]))
+	require.Equal(t, []int64{5, 6}, ids(runs[1]))
+}
+
+func TestBest(t *testing.T) {
+	tests := []struct {
+		name  string
+		hall  []string
+		prefs Preferences
+		want  []int64
+	}{
+		{
+			name:  "closest to stage centre",
+			hall:  []string{"xx.xx", "....."},
+			prefs: Preferences{Quantity: 1},
+			want:  []int64{3},
+		},
+		{
+			name:  "group must be contiguous",
+			hall:  []string{"..x..", "....."},
+			prefs: Preferences{Quantity: 3},
+			want:  []int64{101, 102, 103},
+		},
+		{
+			name:  "aisle breaks adjacency",
+			hall:  []string{".. ..", "xxxx."},
+			prefs: Preferences{Quantity: 3},
+			want:  nil,
+		},
+		{
+			name: "avoid single orphan",
+			// The centred pair 3-4 would strand places 2 and 5 next to taken ones.
+			hall:  []string{"x....x"},
+			prefs: Preferences{Quantity: 2},
+			want:  []int64{2, 3},
+		},
+		{
+			name:  "orphan accepted when nothing else fits",
+			hall:  []string{"x...x"},
+			prefs: Preferences{Quantity: 2},
+			want:  []int64{2, 3},
+		},
+		{
+			name:  "accessible",
+			hall:  []string{".....", "a...."},
+			prefs: Preferences{Quantity: 2, Accessible: true},
+			want:  []int64{101, 102},
+		},
+		{
+			name:  "custom stage",
+			hall:  []string{"...", "..."},
+			prefs: Preferences{Quantity: 1, Stage: &models.Point{X: 25, Y: 30}},
+			want:  []int64{103},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Best(hall(tc.hall...), tc.prefs)
+			if tc.want == nil {
+				require.ErrorIs(t, err, ErrNoSeats)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, ids(got))
+		})
+	}
+}
+
+func TestBestSection(t *testing.T) {
+	places := hall("....")
+	places = append(places, models.Place{
+		ID: 900, SectionID: sql.NullInt64{Int64: 2, Valid: true}, X: 100, Y: 100, Width: 9, Height: 10, IsAvailable: true,
+	})
+
+	got, err := Best(places, Preferences{Quantity: 1, SectionID: 2})
+	require.NoError(t, err)
+	require.Equal(t, []int64{900}, ids(got))
+}
+
+func TestBestMaxPrice(t *testing.T) {
+	places := hall("...", "...")
+	for i := range places {
+		// The first row is expensive.
+		places[i].PriceCategoryID = sql.NullInt64{Int64: 1 + int64(i/3), Valid: true}
+	}
+	prices := pricing.NewCategories([]models.PriceCategory{
+		{ID: 1, Amount: 500000, Currency: "RUB"},
+		{ID: 2, Amount: 150000, Currency: "RUB"},
+	})
+
+	got, err := Best(places, Preferences{Quantity: 2, MaxPrice: 200000, Prices: prices})
+	require.NoError(t, err)
+	require.Equal(t, []int64{101, 102}, ids(got))
+
+	_, err = Best(places, Preferences{Quantity: 2, MaxPrice: 100000, Prices: prices})
+	require.ErrorIs(t, err, ErrNoSeats)
+}
@@ -0,0 +1,45 @@
+package seatmap
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+// RenderPNG writes the seat map as a PNG image.
+func RenderPNG(w io.Writer, seats []Seat, opts Options) error {
+	l := newLayout(seats, opts)
+	img := image.NewRGBA(image.Rect(0, 0, l.width, l.height))
+	fill(img, img.Bounds(), colorBackground)
+
+	for _, s := range l.seats {
+		fill(img, s.rect, l.seatColor(s.Seat))
+		if s.selected {
+			outline(img, s.rect.Inset(-2), 3, colorSelected)
+		}
+	}
+
+	if len(l.legend) > 0 {
+		x, y := padding, l.mapRect.Max.Y+(legendHeight-legendSwatch)/2
+		for _, item := range l.legend {
+			fill(img, image.Rect(x, y, x+legendSwatch, y+legendSwatch), item.color)
+			drawText(img, x+legendSwatch+6, y+(legendSwatch-glyphHeight)/2, item.title, colorText)
+			x += legendSwatch + 6 + textWidth(item.title) + 16
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+func fill(img draw.Image, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+func outline(img draw.Image, r image.Rectangle, width int, c color.Color) {
+	fill(img, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+width), c)
+	fill(img, image.Rect(r.Min.X, r.Max.Y-width, r.Max.X, r.Max.Y), c)
+	fill(img, image.Rect(r.Min.X, r.Min.Y, r.Min.X+width, r.Max.Y), c)
+	fill(img, image.Rect(r.Max.X-width, r.Min.Y, r.Max.X, r.Max.Y), c)
+}
@@ -0,0 +1,224 @@
+// Package seatmap draws event seat maps from place geometry.
+package seatmap
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/cronnoss/tk-api/internal/money"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+const (
+	DefaultSize = 800
+
+	padding      = 10
+	legendHeight = 30
+	legendSwatch = 14
+)
+
+// Seat is a place drawn on the map.
+type Seat struct {
+	ID     int64
+	Label  string
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+	Status models.PlaceStatus
+	// Zone is the price category of the seat, 0 if unpriced.
+	Zone int64
+	// Price is the formatted seat price shown in its tooltip.
+	Price string
+}
+
+// SeatsFromPlaces converts places to seats.
+func SeatsFromPlaces(places []models.Place) []Seat {
+	seats := make([]Seat, 0, len(places))
+	for _, p := range places {
+		seats = append(seats, Seat{
+			ID:     p.ID,
+			Label:  p.Label(),
+			X:      p.X,
+			Y:      p.Y,
+			Width:  p.Width,
+			Height: p.Height,
+			Status: p.Status(),
+			Zone:   p.PriceCategoryID.Int64,
+		})
+	}
+	return seats
+}
+
+// Zone is a price category drawn on the map.
+type Zone struct {
+	ID    int64
+	Title string
+}
+
+// PriceZones sets prices of seats from the categories and returns zones of the categories,
+// most expensive first. Seats must be converted from places in their order.
+func PriceZones(seats []Seat, categories []models.PriceCategory) []Zone {
+	byID := make(map[int64]models.PriceCategory, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+	for i := range seats {
+		if c, ok := byID[seats[i].Zone]; ok {
+			seats[i].Price = money.Format(c.Amount, c.Currency)
+		}
+	}
+
+	sorted := append([]models.PriceCategory(nil), categories...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Amount > sorted[j].Amount })
+	zones := make([]Zone, 0, len(sorted))
+	for _, c := range sorted {
+		zones = append(zones, Zone{ID: c.ID, Title: strings.ToUpper(c.Name) + " " + money.Format(c.Amount, c.Currency)})
+	}
+	return zones
+}
+
+type Options struct {
+	// Size is the larger dimension of the map area in pixels, DefaultSize if zero.
+	Size int
+	// Legend adds a status legend below the map.
+	Legend bool
+	// Selected seats are outlined.
+	Selected map[int64]bool
+	// Zones colour available seats by price category instead of status if set.
+	Zones []Zone
+}
+
+var (
+	colorBackground = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	colorSelected   = color.RGBA{R: 0x15, G: 0x65, B: 0xc0, A: 0xff}
+	colorText       = color.RGBA{R: 0x21, G: 0x21, B: 0x21, A: 0xff}
+
+	statusColors = map[models.PlaceStatus]color.RGBA{
+		models.PlaceAvailable: {R: 0x4c, G: 0xaf, B: 0x50, A: 0xff},
+		models.PlaceHeld:      {R: 0xff, G: 0xb3, B: 0x00, A: 0xff},
+		models.PlaceSold:      {R: 0x9e, G: 0x9e, B: 0x9e, A: 0xff},
+	}
+
+	// zoneColors are picked for price zones in turn.
+	zoneColors = []color.RGBA{
+		{R: 0x4c, G: 0xaf, B: 0x50, A: 0xff},
+		{R: 0x21, G: 0x96, B: 0xf3, A: 0xff},
+		{R: 0x9c, G: 0x27, B: 0xb0, A: 0xff},
+		{R: 0x00, G: 0x96, B: 0x88, A: 0xff},
+		{R: 0xe9, G: 0x1e, B: 0x63, A: 0xff},
+		{R: 0x3f, G: 0x51, B: 0xb5, A: 0xff},
+		{R: 0xcd, G: 0xdc, B: 0x39, A: 0xff},
+		{R: 0x79, G: 0x55, B: 0x48, A: 0xff},
+	}
+)
+
+type legendItem struct {
+	color color.RGBA
+	title string
+}
+
+func statusColor(s models.PlaceStatus) color.RGBA {
+	if c, ok := statusColors[s]; ok {
+		return c
+	}
+	return statusColors[models.PlaceSold]
+}
+
+// placedSeat is a seat in pixel coordinates.
+type placedSeat struct {
+	Seat
+	rect     image.Rectangle
+	selected bool
+}
+
+type layout struct {
+	width   int
+	height  int
+	mapRect image.Rectangle
+	seats   []placedSeat
+	legend  []legendItem
+	zones   map[int64]color.RGBA
+}
+
+// seatColor returns the fill of a seat: its zone colour if available and priced, its status colour otherwise.
+func (l layout) seatColor(s Seat) color.RGBA {
+	if c, ok := l.zones[s.Zone]; ok && s.Status == models.PlaceAvailable {
+		return c
+	}
+	return statusColor(s.Status)
+}
+
+// newLayout scales seat geometry so the map fits into opts.Size pixels with padding.
+func newLayout(seats []Seat, opts Options) layout {
+	size := opts.Size
+	if size <= 0 {
+		size = DefaultSize
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, s := range seats {
+		minX, minY = math.Min(minX, s.X), math.Min(minY, s.Y)
+		maxX, maxY = math.Max(maxX, s.X+s.Width), math.Max(maxY, s.Y+s.Height)
+	}
+	if len(seats) == 0 {
+		minX, minY, maxX, maxY = 0, 0, 1, 1
+	}
+	bw, bh := math.Max(maxX-minX, 1e-9), math.Max(maxY-minY, 1e-9)
+	scale := float64(size) / math.Max(bw, bh)
+
+	l := layout{
+		width:  int(math.Ceil(bw*scale)) + 2*padding,
+		height: int(math.Ceil(bh*scale)) + 2*padding,
+		zones:  make(map[int64]color.RGBA, len(opts.Zones)),
+	}
+	for i, z := range opts.Zones {
+		l.zones[z.ID] = zoneColors[i%len(zoneColors)]
+	}
+	l.mapRect = image.Rect(0, 0, l.width, l.height)
+	if opts.Legend {
+		l.legend = legendItems(opts.Zones)
+		l.height += legendHeight
+		l.width = max(l.width, legendWidth(l.legend))
+	}
+
+	l.seats = make([]placedSeat, 0, len(seats))
+	for _, s := range seats {
+		x0 := padding + int(math.Round((s.X-minX)*scale))
+		y0 := padding + int(math.Round((s.Y-minY)*scale))
+		x1 := padding + int(math.Round((s.X+s.Width-minX)*scale))
+		y1 := padding + int(math.Round((s.Y+s.Height-minY)*scale))
+		// Leave a pixel gap between adjacent seats and keep tiny seats visible.
+		r := image.Rect(x0, y0, max(x1-1, x0+1), max(y1-1, y0+1))
+		l.seats = append(l.seats, placedSeat{Seat: s, rect: r, selected: opts.Selected[s.ID]})
+	}
+	return l
+}
+
+// legendItems lists price zones if any, otherwise the available status, then the other statuses.
+func legendItems(zones []Zone) []legendItem {
+	var items []legendItem
+	for i, z := range zones {
+		items = append(items, legendItem{color: zoneColors[i%len(zoneColors)], title: z.Title})
+	}
+	if len(items) == 0 {
+		items = append(items, legendItem{color: statusColor(models.PlaceAvailable), title: "AVAILABLE"})
+	}
+	return append(items,
+		legendItem{color: statusColor(models.PlaceHeld), title: "HELD"},
+		legendItem{color: statusColor(models.PlaceSold), title: "SOLD"},
+	)
+}
+
+// legendWidth is the width taken by the legend items.
+func legendWidth(items []legendItem) int {
+	w := padding
+	for _, item := range items {
+		w += legendSwatch + 6 + textWidth(item.title) + 16
+	}
+	return w
+}
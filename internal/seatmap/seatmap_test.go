@@ -0,0 +1,83 @@
+package seatmap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/stretchr/testify/require"
+)
+
+var testSeats = []Seat{
+	{ID: 1, Label: "Row 1, Seat 1", X: 0, Y: 0, Width: 10, Height: 10, Status: models.PlaceAvailable},
+	{ID: 2, Label: "Row 1, Seat 2", X: 10, Y: 0, Width: 10, Height: 10, Status: models.PlaceHeld},
+	{ID: 3, Label: "<Row 2>", X: 0, Y: 10, Width: 10, Height: 10, Status: models.PlaceSold},
+}
+
+func TestRenderSVG(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, RenderSVG(&b, testSeats, Options{Size: 200, Legend: true, Selected: map[int64]bool{2: true}}))
+
+	// The document must be well-formed XML.
+	dec := xml.NewDecoder(bytes.NewReader(b.Bytes()))
+	for {
+		if _, err := dec.Token(); err != nil {
+			require.Equal(t, "EOF", err.Error())
+			break
+		}
+	}
+
+	svg := b.String()
+	require.Contains(t, svg, `id="place-1" class="seat available" x="10" y="10" width="99" height="99" fill="#4caf50">`)
+	require.Contains(t, svg, `id="place-2" class="seat held" x="110" y="10" width="99" height="99" fill="#ffb300" stroke=`)
+	require.Contains(t, svg, `<title>&lt;Row 2&gt;</title>`)
+	require.Contains(t, svg, `class="legend"`)
+	require.Equal(t, 1, strings.Count(svg, "stroke="))
+}
+
+func TestRenderPNG(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, RenderPNG(&b, testSeats, Options{Size: 200, Legend: true, Selected: map[int64]bool{2: true}}))
+
+	img, err := png.Decode(&b)
+	require.NoError(t, err)
+	require.Equal(t, 220, img.Bounds().Dx())
+	require.Equal(t, 220+legendHeight, img.Bounds().Dy())
+
+	colorAt := func(x, y int) [3]uint32 {
+		r, g, b, _ := img.At(x, y).RGBA()
+		return [3]uint32{r >> 8, g >> 8, b >> 8}
+	}
+	require.Equal(t, [3]uint32{0x4c, 0xaf, 0x50}, colorAt(50, 50))
+	require.Equal(t, [3]uint32{0xff, 0xb3, 0x00}, colorAt(150, 50))
+	require.Equal(t, [3]uint32{0x9e, 0x9e, 0x9e}, colorAt(50, 150))
+	require.Equal(t, [3]uint32{0x15, 0x65, 0xc0}, colorAt(109, 9), "selected seat outline")
+	require.Equal(t, [3]uint32{0xff, 0xff, 0xff}, colorAt(150, 150))
+}
+
+func TestRenderEmpty(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, RenderPNG(&b, nil, Options{}))
+	require.NoError(t, RenderSVG(&b, nil, Options{}))
+}
+
+func TestPriceZones(t *testing.T) {
+	seats := append([]Seat(nil), testSeats...)
+	seats[0].Zone, seats[1].Zone = 7, 7
+	zones := PriceZones(seats, []models.PriceCategory{
+		{ID: 6, Name: "Balcony", Amount: 90000, Currency: "RUB"},
+		{ID: 7, Name: "Stalls", Amount: 250000, Currency: "RUB"},
+	})
+	require.Equal(t, []Zone{{ID: 7, Title: "STALLS 2500.00 RUB"}, {ID: 6, Title: "BALCONY 900.00 RUB"}}, zones)
+
+	var b bytes.Buffer
+	require.NoError(t, RenderSVG(&b, seats, Options{Size: 200, Legend: true, Zones: zones}))
+	svg := b.String()
+	require.Contains(t, svg, `fill="#4caf50"><title>Row 1, Seat 1 - 2500.00 RUB</title>`)
+	require.Contains(t, svg, `fill="#ffb300"><title>Row 1, Seat 2 - 2500.00 RUB</title>`, "held seats keep the status colour")
+	require.Contains(t, svg, `>BALCONY 900.00 RUB</text>`)
+	require.NotContains(t, svg, "AVAILABLE")
+}
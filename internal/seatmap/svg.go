@@ -0,0 +1,57 @@
+package seatmap
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+)
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// RenderSVG writes the seat map as an SVG document.
+func RenderSVG(w io.Writer, seats []Seat, opts Options) error {
+	l := newLayout(seats, opts)
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		l.width, l.height, l.width, l.height)
+	fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hexColor(colorBackground))
+
+	for _, s := range l.seats {
+		stroke := ""
+		if s.selected {
+			stroke = fmt.Sprintf(` stroke="%s" stroke-width="3"`, hexColor(colorSelected))
+		}
+		title := s.Label
+		if title == "" {
+			title = fmt.Sprintf("Place %d", s.ID)
+		}
+		if s.Price != "" {
+			title += " - " + s.Price
+		}
+		fmt.Fprintf(bw, `<rect id="place-%d" class="seat %s" x="%d" y="%d" width="%d" height="%d" fill="%s"%s>`+
+			`<title>%s</title></rect>`+"\n",
+			s.ID, s.Status, s.rect.Min.X, s.rect.Min.Y, s.rect.Dx(), s.rect.Dy(),
+			hexColor(l.seatColor(s.Seat)), stroke, html.EscapeString(title))
+	}
+
+	if len(l.legend) > 0 {
+		x, y := padding, l.mapRect.Max.Y+(legendHeight-legendSwatch)/2
+		fmt.Fprintln(bw, `<g class="legend" font-family="sans-serif" font-size="12">`)
+		for _, item := range l.legend {
+			fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+
+				`<text x="%d" y="%d" fill="%s">%s</text>`+"\n",
+				x, y, legendSwatch, legendSwatch, hexColor(item.color),
+				x+legendSwatch+6, y+legendSwatch-2, hexColor(colorText), html.EscapeString(item.title))
+			x += legendSwatch + 6 + textWidth(item.title) + 16
+		}
+		fmt.Fprintln(bw, `</g>`)
+	}
+
+	fmt.Fprintln(bw, `</svg>`)
+	return bw.Flush()
+}
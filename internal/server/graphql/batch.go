@@ -0,0 +1,27 @@
+package internalgraphql
+
+import (
+	"context"
+	"sync"
+)
+
+// batch loads values for all items of a resolved list with one storage call when the first item asks,
+// e.g. the events of every show in a list of shows, instead of a call per item.
+type batch[V any] struct {
+	ids    []int64
+	load   func(ctx context.Context, ids []int64) (map[int64]V, error)
+	once   sync.Once
+	values map[int64]V
+	err    error
+}
+
+func newBatch[V any](ids []int64, load func(ctx context.Context, ids []int64) (map[int64]V, error)) *batch[V] {
+	return &batch[V]{ids: ids, load: load}
+}
+
+func (b *batch[V]) get(ctx context.Context, id int64) (V, error) {
+	b.once.Do(func() {
+		b.values, b.err = b.load(ctx, b.ids)
+	})
+	return b.values[id], b.err
+}
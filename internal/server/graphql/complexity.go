@@ -0,0 +1,47 @@
+package internalgraphql
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// listSizes are the expected lengths of list fields, the cost of their selections is multiplied by them.
+var listSizes = map[string]int{
+	"Query.shows":  20,
+	"Show.events":  20,
+	"Event.places": 500,
+}
+
+// complexity estimates the number of values the operation resolves: every field costs one
+// and selections of list fields are counted once per expected item. Introspection is free.
+func complexity(doc *ast.QueryDocument, operationName string) int {
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		return 0
+	}
+	return selectionCost(op.SelectionSet)
+}
+
+func selectionCost(set ast.SelectionSet) int {
+	cost := 0
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if sel.ObjectDefinition == nil || sel.Name == "__typename" ||
+				sel.Name == "__schema" || sel.Name == "__type" {
+				continue
+			}
+			size, ok := listSizes[sel.ObjectDefinition.Name+"."+sel.Name]
+			if !ok {
+				size = 1
+			}
+			cost += 1 + size*selectionCost(sel.SelectionSet)
+		case *ast.InlineFragment:
+			cost += selectionCost(sel.SelectionSet)
+		case *ast.FragmentSpread:
+			if sel.Definition != nil {
+				cost += selectionCost(sel.Definition.SelectionSet)
+			}
+		}
+	}
+	return cost
+}
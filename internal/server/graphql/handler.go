@@ -0,0 +1,116 @@
+package internalgraphql
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/server"
+	"github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	// defaultMaxDepth leaves room for the introspection query of GraphiQL.
+	defaultMaxDepth = 15
+	// defaultMaxComplexity lets a query list the places of an event but not of every event of every show.
+	defaultMaxComplexity = 20000
+	maxRequestSize       = 1 << 20
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+//go:embed graphiql.html
+var graphiQLPage []byte
+
+// Handler serves GraphQL queries over stored shows, events and places.
+type Handler struct {
+	app           server.Application
+	log           server.Logger
+	schema        *graphql.Schema
+	ast           *ast.Schema
+	maxDepth      int
+	maxComplexity int
+}
+
+type Option func(*Handler)
+
+// WithLimits rejects queries nested deeper than maxDepth or more complex than maxComplexity,
+// zero keeps the default.
+func WithLimits(maxDepth, maxComplexity int) Option {
+	return func(h *Handler) {
+		if maxDepth > 0 {
+			h.maxDepth = maxDepth
+		}
+		if maxComplexity > 0 {
+			h.maxComplexity = maxComplexity
+		}
+	}
+}
+
+func NewHandler(log server.Logger, app server.Application, opts ...Option) *Handler {
+	h := &Handler{app: app, log: log, maxDepth: defaultMaxDepth, maxComplexity: defaultMaxComplexity}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.schema = graphql.MustParseSchema(schemaSDL, &resolver{},
+		graphql.UseStringDescriptions(), graphql.MaxDepth(h.maxDepth))
+	h.ast = gqlparser.MustLoadSchema(&ast.Source{Name: "schema.graphql", Input: schemaSDL})
+	return h
+}
+
+type params struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// ServeHTTP runs queries sent as JSON by POST or as URL parameters by GET, a GET without a query gets GraphiQL.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var p params
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		if q.Get("query") == "" {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(graphiQLPage)
+			return
+		}
+		p.Query = q.Get("query")
+		p.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &p.Variables); err != nil {
+				srv.BadRequest("invalid-graphql-request", err, w, r)
+				return
+			}
+		}
+	} else if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&p); err != nil {
+		srv.BadRequest("invalid-graphql-request", err, w, r)
+		return
+	}
+
+	resp := h.exec(r.Context(), p)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.log.Errorf("failed to write graphql response:%v\n", err)
+	}
+}
+
+func (h *Handler) exec(ctx context.Context, p params) *graphql.Response {
+	// Invalid queries are reported by Exec, it validates them as well.
+	if doc, errs := gqlparser.LoadQuery(h.ast, p.Query); errs == nil {
+		if c := complexity(doc, p.OperationName); c > h.maxComplexity {
+			return &graphql.Response{Errors: []*gqlerrors.QueryError{
+				gqlerrors.Errorf("query complexity %d exceeds the limit of %d", c, h.maxComplexity),
+			}}
+		}
+	}
+	ctx = context.WithValue(ctx, requestKey{}, &request{app: h.app, log: h.log})
+	return h.schema.Exec(ctx, p.Query, p.OperationName, p.Variables)
+}
@@ -0,0 +1,146 @@
+package internalgraphql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/logger"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/server/mocks"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type result struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func post(t *testing.T, h http.Handler, query string, variables map[string]any) result {
+	t.Helper()
+	body, err := json.Marshal(params{Query: query, Variables: variables})
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+	require.Equal(t, http.StatusOK, w.Code)
+	var res result
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	return res
+}
+
+func newHandler(app *mocks.Application, opts ...Option) *Handler {
+	return NewHandler(logger.NewLogger("error", io.Discard), app, opts...)
+}
+
+func TestNestedQueryIsBatched(t *testing.T) {
+	app := mocks.NewApplication(t)
+	date := time.Date(2026, 11, 1, 19, 0, 0, 0, time.UTC)
+	app.On("GetShows", mock.Anything).Return([]models.Show{{ID: 1, Name: "Show #1"}, {ID: 2, Name: "Show #2"}}, nil).Once()
+	app.On("GetEvents", mock.Anything, models.EventFilter{ShowIDs: []int64{1, 2}}).Return([]models.Event{
+		{ID: 10, ShowID: 1, Date: date, TimeZone: "UTC"},
+		{ID: 11, ShowID: 1, Date: date.Add(24 * time.Hour), TimeZone: "UTC"},
+		{ID: 20, ShowID: 2, Date: date, TimeZone: "UTC"},
+	}, nil).Once()
+	app.On("GetPlaces", mock.Anything, models.PlaceFilter{EventIDs: []int64{10, 11, 20}}).Return([]models.Place{
+		{ID: 100, EventID: sql.NullInt64{Int64: 10, Valid: true}, Row: "A", Seat: 1, IsAvailable: true},
+		{ID: 101, EventID: sql.NullInt64{Int64: 10, Valid: true}, Row: "A", Seat: 2},
+		{ID: 200, EventID: sql.NullInt64{Int64: 20, Valid: true}, Row: "B", Seat: 1, IsAvailable: true},
+	}, nil).Once()
+
+	// Places of every event of every show are over the default complexity limit.
+	res := post(t, newHandler(app, WithLimits(0, 1000000)), `{
+		shows { id name events { id date show { name } places(status: AVAILABLE) { id row seat status } } }
+	}`, nil)
+	require.Empty(t, res.Errors)
+	require.JSONEq(t, `{"shows": [
+		{"id": "1", "name": "Show #1", "events": [
+			{"id": "10", "date": "2026-11-01T19:00:00Z", "show": {"name": "Show #1"},
+				"places": [{"id": "100", "row": "A", "seat": 1, "status": "AVAILABLE"}]},
+			{"id": "11", "date": "2026-11-02T19:00:00Z", "show": {"name": "Show #1"}, "places": []}
+		]},
+		{"id": "2", "name": "Show #2", "events": [
+			{"id": "20", "date": "2026-11-01T19:00:00Z", "show": {"name": "Show #2"},
+				"places": [{"id": "200", "row": "B", "seat": 1, "status": "AVAILABLE"}]}
+		]}
+	]}`, string(res.Data))
+}
+
+func TestEvent(t *testing.T) {
+	app := mocks.NewApplication(t)
+	app.On("GetEvent", mock.Anything, int64(1)).Return(models.Event{
+		ID: 1, ShowID: 2, HallID: sql.NullInt64{Int64: 3, Valid: true}, TimeZone: "Europe/Moscow",
+	}, nil)
+	app.On("GetEvent", mock.Anything, int64(2)).Return(models.Event{}, model.ErrNotFound)
+	h := newHandler(app)
+
+	res := post(t, h, `query($id: ID!) { event(id: $id) { id hallId cancelledAt timeZone } }`,
+		map[string]any{"id": "1"})
+	require.Empty(t, res.Errors)
+	require.JSONEq(t, `{"event": {"id": "1", "hallId": "3", "cancelledAt": null, "timeZone": "Europe/Moscow"}}`,
+		string(res.Data))
+
+	res = post(t, h, `{ event(id: 2) { id } }`, nil)
+	require.Empty(t, res.Errors)
+	require.JSONEq(t, `{"event": null}`, string(res.Data))
+
+	res = post(t, h, `{ event(id: "x") { id } }`, nil)
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, errInvalidID.Error(), res.Errors[0].Message)
+}
+
+func TestStorageErrorsAreHidden(t *testing.T) {
+	app := mocks.NewApplication(t)
+	app.On("GetShows", mock.Anything).Return(nil, io.ErrUnexpectedEOF)
+
+	res := post(t, newHandler(app), `{ shows { id } }`, nil)
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, "failed to load shows", res.Errors[0].Message)
+}
+
+func TestLimits(t *testing.T) {
+	app := mocks.NewApplication(t)
+	h := newHandler(app, WithLimits(4, 1000))
+
+	// 1 + 20 * (1 + 20 * (1 + 500)) is far over the limit, nothing is loaded.
+	res := post(t, h, `{ shows { events { places { id } } } }`, nil)
+	require.Len(t, res.Errors, 1)
+	require.Contains(t, res.Errors[0].Message, "exceeds the limit of 1000")
+
+	res = post(t, h, `{ event(id: 1) { show { events { show { name } } } } }`, nil)
+	require.NotEmpty(t, res.Errors)
+	require.Contains(t, res.Errors[0].Message, "depth")
+}
+
+func TestDefaultComplexityLimit(t *testing.T) {
+	h := newHandler(mocks.NewApplication(t))
+
+	// 1 + 20 * (1 + 20 * (1 + 500 * 3)), nothing is loaded.
+	res := post(t, h, `{ shows { events { places { id x y } } } }`, nil)
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, "query complexity 600421 exceeds the limit of 20000", res.Errors[0].Message)
+}
+
+func TestGraphiQL(t *testing.T) {
+	h := newHandler(mocks.NewApplication(t))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	require.Contains(t, w.Body.String(), "GraphiQL")
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("{ __typename }"), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"data": {"__typename": "Query"}}`, w.Body.String())
+}
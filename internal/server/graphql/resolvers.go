@@ -0,0 +1,287 @@
+package internalgraphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/server"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/graph-gophers/graphql-go"
+)
+
+var errInvalidID = errors.New("invalid id")
+
+func parseID(id graphql.ID) (int64, error) {
+	n, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil || n <= 0 {
+		return 0, errInvalidID
+	}
+	return n, nil
+}
+
+func formatID(id int64) graphql.ID {
+	return graphql.ID(strconv.FormatInt(id, 10))
+}
+
+func nullID(id int64, valid bool) *graphql.ID {
+	if !valid {
+		return nil
+	}
+	gid := formatID(id)
+	return &gid
+}
+
+// requestKey is the context key of the per-request state.
+type requestKey struct{}
+
+// request is state shared by the resolvers of a query.
+type request struct {
+	app       server.Application
+	log       server.Logger
+	showsOnce sync.Once
+	shows     []models.Show
+	showsErr  error
+}
+
+func requestFrom(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+// fail hides storage failures from clients, they are logged instead.
+func (r *request) fail(entity string, err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	r.log.Errorf("failed to load %s:%v\n", entity, err)
+	return fmt.Errorf("failed to load %s", entity)
+}
+
+// getShows loads the shows once per query, there are few of them.
+func (r *request) getShows(ctx context.Context) ([]models.Show, error) {
+	r.showsOnce.Do(func() {
+		r.shows, r.showsErr = r.app.GetShows(ctx)
+		if r.showsErr != nil {
+			r.showsErr = r.fail("shows", r.showsErr)
+		}
+	})
+	return r.shows, r.showsErr
+}
+
+type resolver struct{}
+
+func (*resolver) Shows(ctx context.Context) ([]*showResolver, error) {
+	shows, err := requestFrom(ctx).getShows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newShowResolvers(shows), nil
+}
+
+func (*resolver) Show(ctx context.Context, args struct{ ID graphql.ID }) (*showResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	shows, err := requestFrom(ctx).getShows(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, show := range shows {
+		if show.ID == id {
+			return newShowResolvers([]models.Show{show})[0], nil
+		}
+	}
+	return nil, nil
+}
+
+func (*resolver) Event(ctx context.Context, args struct{ ID graphql.ID }) (*eventResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	req := requestFrom(ctx)
+	event, err := req.app.GetEvent(ctx, id)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, req.fail("event", err)
+	}
+	return newEventResolvers([]models.Event{event})[0], nil
+}
+
+type showResolver struct {
+	show   models.Show
+	events *batch[[]*eventResolver]
+}
+
+// newShowResolvers resolves shows whose events are loaded together.
+func newShowResolvers(shows []models.Show) []*showResolver {
+	ids := make([]int64, 0, len(shows))
+	for _, show := range shows {
+		ids = append(ids, show.ID)
+	}
+	events := newBatch(ids, loadEvents)
+	res := make([]*showResolver, 0, len(shows))
+	for _, show := range shows {
+		res = append(res, &showResolver{show: show, events: events})
+	}
+	return res
+}
+
+// loadEvents loads events of the shows, places of all of them are loaded together.
+func loadEvents(ctx context.Context, showIDs []int64) (map[int64][]*eventResolver, error) {
+	req := requestFrom(ctx)
+	events, err := req.app.GetEvents(ctx, models.EventFilter{ShowIDs: showIDs})
+	if err != nil {
+		return nil, req.fail("events", err)
+	}
+	byShow := make(map[int64][]*eventResolver)
+	for _, e := range newEventResolvers(events) {
+		byShow[e.event.ShowID] = append(byShow[e.event.ShowID], e)
+	}
+	return byShow, nil
+}
+
+func (s *showResolver) ID() graphql.ID {
+	return formatID(s.show.ID)
+}
+
+func (s *showResolver) Name() string {
+	return s.show.Name
+}
+
+func (s *showResolver) Events(ctx context.Context) ([]*eventResolver, error) {
+	events, err := s.events.get(ctx, s.show.ID)
+	if events == nil {
+		events = []*eventResolver{}
+	}
+	return events, err
+}
+
+type eventResolver struct {
+	event  models.Event
+	places *batch[[]models.Place]
+}
+
+// newEventResolvers resolves events whose places are loaded together.
+func newEventResolvers(events []models.Event) []*eventResolver {
+	ids := make([]int64, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	places := newBatch(ids, loadPlaces)
+	res := make([]*eventResolver, 0, len(events))
+	for _, e := range events {
+		res = append(res, &eventResolver{event: e, places: places})
+	}
+	return res
+}
+
+func loadPlaces(ctx context.Context, eventIDs []int64) (map[int64][]models.Place, error) {
+	req := requestFrom(ctx)
+	places, err := req.app.GetPlaces(ctx, models.PlaceFilter{EventIDs: eventIDs})
+	if err != nil {
+		return nil, req.fail("places", err)
+	}
+	byEvent := make(map[int64][]models.Place)
+	for _, p := range places {
+		byEvent[p.EventID.Int64] = append(byEvent[p.EventID.Int64], p)
+	}
+	return byEvent, nil
+}
+
+func (e *eventResolver) ID() graphql.ID {
+	return formatID(e.event.ID)
+}
+
+func (e *eventResolver) Show(ctx context.Context) (*showResolver, error) {
+	return (&resolver{}).Show(ctx, struct{ ID graphql.ID }{formatID(e.event.ShowID)})
+}
+
+func (e *eventResolver) Date() graphql.Time {
+	return graphql.Time{Time: e.event.Date.UTC()}
+}
+
+func (e *eventResolver) TimeZone() string {
+	return e.event.TimeZone
+}
+
+func (e *eventResolver) HallID() *graphql.ID {
+	return nullID(e.event.HallID.Int64, e.event.HallID.Valid)
+}
+
+func (e *eventResolver) CancelledAt() *graphql.Time {
+	if !e.event.CancelledAt.Valid {
+		return nil
+	}
+	return &graphql.Time{Time: e.event.CancelledAt.Time.UTC()}
+}
+
+func (e *eventResolver) Places(ctx context.Context, args struct{ Status *string }) ([]*placeResolver, error) {
+	places, err := e.places.get(ctx, e.event.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*placeResolver, 0, len(places))
+	for _, p := range places {
+		if args.Status != nil && string(p.Status()) != strings.ToLower(*args.Status) {
+			continue
+		}
+		res = append(res, &placeResolver{place: p})
+	}
+	return res, nil
+}
+
+type placeResolver struct {
+	place models.Place
+}
+
+func (p *placeResolver) ID() graphql.ID {
+	return formatID(p.place.ID)
+}
+
+func (p *placeResolver) Row() string {
+	return p.place.Row
+}
+
+func (p *placeResolver) Seat() int32 {
+	return int32(p.place.Seat)
+}
+
+func (p *placeResolver) X() float64 {
+	return p.place.X
+}
+
+func (p *placeResolver) Y() float64 {
+	return p.place.Y
+}
+
+func (p *placeResolver) Width() float64 {
+	return p.place.Width
+}
+
+func (p *placeResolver) Height() float64 {
+	return p.place.Height
+}
+
+func (p *placeResolver) Accessible() bool {
+	return p.place.Accessible
+}
+
+func (p *placeResolver) SectionID() *graphql.ID {
+	return nullID(p.place.SectionID.Int64, p.place.SectionID.Valid)
+}
+
+func (p *placeResolver) PriceCategoryID() *graphql.ID {
+	return nullID(p.place.PriceCategoryID.Int64, p.place.PriceCategoryID.Valid)
+}
+
+func (p *placeResolver) Status() string {
+	return strings.ToUpper(string(p.place.Status()))
+}
@@ -0,0 +1,86 @@
+package internalgrpc
+
+import (
+	"context"
+	"net"
+
+	ticketv1 "github.com/cronnoss/tk-api/api/ticket/v1"
+	"github.com/cronnoss/tk-api/internal/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+type Server struct {
+	srv    *grpc.Server
+	health *health.Server
+	app    server.Application
+	log    server.Logger
+	host   string
+	port   string
+	tokens []string
+	// done ends availability streams when the server is stopping.
+	done <-chan struct{}
+}
+
+type Option func(*Server)
+
+// WithTokens lets in only clients presenting one of the bearer tokens, anyone if there are none.
+func WithTokens(tokens []string) Option {
+	return func(s *Server) {
+		s.tokens = tokens
+	}
+}
+
+func NewServer(log server.Logger, app server.Application, host, port string, opts ...Option) *Server {
+	s := &Server{log: log, app: app, host: host, port: port, health: health.NewServer()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if len(s.tokens) == 0 {
+		log.Warningf("gRPC server accepts unauthenticated clients, no tokens are configured\n")
+	}
+
+	s.srv = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.logUnary, s.authUnary, metricsUnary),
+		grpc.ChainStreamInterceptor(s.logStream, s.authStream, metricsStream),
+	)
+	ticketv1.RegisterTicketServiceServer(s.srv, &ticketService{s: s})
+	grpc_health_v1.RegisterHealthServer(s.srv, s.health)
+	reflection.Register(s.srv)
+	return s
+}
+
+func (s *Server) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", net.JoinHostPort(s.host, s.port))
+	if err != nil {
+		return err
+	}
+	s.log.Infof("grpc server started on %s:%s\n", s.host, s.port)
+	return s.serve(ctx, lis)
+}
+
+func (s *Server) serve(ctx context.Context, lis net.Listener) error {
+	s.done = ctx.Done()
+	s.health.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
+	s.health.SetServingStatus(ticketv1.TicketService_ServiceDesc.ServiceName, grpc_health_v1.HealthCheckResponse_SERVING)
+	return s.srv.Serve(lis)
+}
+
+// Stop waits for running calls until ctx is done and cancels the rest.
+func (s *Server) Stop(ctx context.Context) error {
+	s.health.Shutdown()
+	stopped := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.srv.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,135 @@
+package internalgrpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	ticketv1 "github.com/cronnoss/tk-api/api/ticket/v1"
+	"github.com/cronnoss/tk-api/internal/availability"
+	"github.com/cronnoss/tk-api/internal/logger"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/server/mocks"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial serves app in-process and connects a client to it.
+func dial(t *testing.T, app *mocks.Application, opts ...Option) *grpc.ClientConn {
+	t.Helper()
+	s := NewServer(logger.NewLogger("error", io.Discard), app, "", "", opts...)
+	lis := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { _ = s.serve(ctx, lis) }()
+	t.Cleanup(func() {
+		cancel()
+		_ = s.Stop(context.Background())
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestListShowsAndEvents(t *testing.T) {
+	app := mocks.NewApplication(t)
+	date := time.Date(2026, 11, 1, 19, 0, 0, 0, time.UTC)
+	app.On("GetShows", mock.Anything).Return([]models.Show{{ID: 1, Name: "Show #1"}}, nil)
+	app.On("GetEvents", mock.Anything, models.EventFilter{ShowID: 1}).
+		Return([]models.Event{{ID: 2, ShowID: 1, Date: date, TimeZone: "Europe/Moscow"}}, nil)
+	app.On("GetEvent", mock.Anything, int64(3)).Return(models.Event{}, model.ErrNotFound)
+	client := ticketv1.NewTicketServiceClient(dial(t, app))
+	ctx := context.Background()
+
+	shows, err := client.ListShows(ctx, &ticketv1.ListShowsRequest{})
+	require.NoError(t, err)
+	require.Len(t, shows.GetShows(), 1)
+	require.Equal(t, "Show #1", shows.GetShows()[0].GetName())
+
+	events, err := client.ListEvents(ctx, &ticketv1.ListEventsRequest{ShowId: 1})
+	require.NoError(t, err)
+	require.Len(t, events.GetEvents(), 1)
+	require.Equal(t, date, events.GetEvents()[0].GetDate().AsTime())
+	require.Nil(t, events.GetEvents()[0].GetCancelledAt())
+
+	_, err = client.GetEvent(ctx, &ticketv1.GetEventRequest{Id: 3})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetEvent(ctx, &ticketv1.GetEventRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListPlaces(t *testing.T) {
+	app := mocks.NewApplication(t)
+	app.On("GetEvent", mock.Anything, int64(1)).Return(models.Event{ID: 1}, nil)
+	app.On("GetPlaces", mock.Anything, models.PlaceFilter{EventID: 1}).Return([]models.Place{
+		{ID: 1, IsAvailable: true},
+		{ID: 2},
+	}, nil)
+	client := ticketv1.NewTicketServiceClient(dial(t, app))
+
+	resp, err := client.ListPlaces(context.Background(), &ticketv1.ListPlacesRequest{EventId: 1})
+	require.NoError(t, err)
+	require.Len(t, resp.GetPlaces(), 2)
+	require.Equal(t, ticketv1.PlaceStatus_PLACE_STATUS_AVAILABLE, resp.GetPlaces()[0].GetStatus())
+	require.Equal(t, ticketv1.PlaceStatus_PLACE_STATUS_SOLD, resp.GetPlaces()[1].GetStatus())
+}
+
+func TestStreamAvailability(t *testing.T) {
+	app := mocks.NewApplication(t)
+	broker := availability.NewBroker(0)
+	subscribed := make(chan struct{})
+	app.On("GetEvent", mock.Anything, int64(1)).Return(models.Event{ID: 1}, nil)
+	app.On("SubscribeAvailability", int64(1), int64(0)).Return(broker.Subscribe).
+		Run(func(mock.Arguments) { close(subscribed) })
+	client := ticketv1.NewTicketServiceClient(dial(t, app))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.StreamAvailability(ctx, &ticketv1.StreamAvailabilityRequest{EventId: 1})
+	require.NoError(t, err)
+	<-subscribed
+	broker.Publish(availability.Change{EventID: 1, PlaceID: 5, Kind: availability.Sold, Status: models.PlaceSold})
+
+	change, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, ticketv1.ChangeKind_CHANGE_KIND_SOLD, change.GetKind())
+	require.Equal(t, ticketv1.PlaceStatus_PLACE_STATUS_SOLD, change.GetStatus())
+	require.Equal(t, int64(5), change.GetPlaceId())
+	require.NotZero(t, change.GetId())
+}
+
+func TestAuth(t *testing.T) {
+	app := mocks.NewApplication(t)
+	app.On("GetShows", mock.Anything).Return(nil, nil).Once()
+	conn := dial(t, app, WithTokens([]string{"secret"}))
+	client := ticketv1.NewTicketServiceClient(conn)
+
+	_, err := client.ListShows(context.Background(), &ticketv1.ListShowsRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	_, err = client.ListShows(ctx, &ticketv1.ListShowsRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	_, err = client.ListShows(ctx, &ticketv1.ListShowsRequest{})
+	require.NoError(t, err)
+
+	// Probes don't authenticate.
+	health, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(),
+		&grpc_health_v1.HealthCheckRequest{Service: ticketv1.TicketService_ServiceDesc.ServiceName})
+	require.NoError(t, err)
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, health.GetStatus())
+}
@@ -0,0 +1,218 @@
+package internalgrpc
+
+import (
+	"context"
+	"errors"
+
+	ticketv1 "github.com/cronnoss/tk-api/api/ticket/v1"
+	"github.com/cronnoss/tk-api/internal/availability"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ticketService serves the stored shows, events and places, upstream data is fetched by the HTTP API.
+type ticketService struct {
+	ticketv1.UnimplementedTicketServiceServer
+	s *Server
+}
+
+// statusError turns model.ErrNotFound into a not-found status named after entity and hides other causes.
+func (t *ticketService) statusError(entity string, err error) error {
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return status.Errorf(codes.NotFound, "%s not found", entity)
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		t.s.log.Errorf("failed to process %s:%v\n", entity, err)
+		return status.Errorf(codes.Internal, "failed to process %s", entity)
+	}
+}
+
+func newEvent(e models.Event) *ticketv1.Event {
+	resp := &ticketv1.Event{
+		Id:       e.ID,
+		ShowId:   e.ShowID,
+		Date:     timestamppb.New(e.Date),
+		TimeZone: e.TimeZone,
+		HallId:   e.HallID.Int64,
+		Sequence: int64(e.Sequence),
+	}
+	if e.CancelledAt.Valid {
+		resp.CancelledAt = timestamppb.New(e.CancelledAt.Time)
+	}
+	return resp
+}
+
+var placeStatuses = map[models.PlaceStatus]ticketv1.PlaceStatus{
+	models.PlaceAvailable: ticketv1.PlaceStatus_PLACE_STATUS_AVAILABLE,
+	models.PlaceHeld:      ticketv1.PlaceStatus_PLACE_STATUS_HELD,
+	models.PlaceSold:      ticketv1.PlaceStatus_PLACE_STATUS_SOLD,
+}
+
+var changeKinds = map[availability.Kind]ticketv1.ChangeKind{
+	availability.Held:     ticketv1.ChangeKind_CHANGE_KIND_HELD,
+	availability.Released: ticketv1.ChangeKind_CHANGE_KIND_RELEASED,
+	availability.Sold:     ticketv1.ChangeKind_CHANGE_KIND_SOLD,
+	availability.Synced:   ticketv1.ChangeKind_CHANGE_KIND_SYNCED,
+}
+
+func newPlace(p models.Place) *ticketv1.Place {
+	return &ticketv1.Place{
+		Id:              p.ID,
+		EventId:         p.EventID.Int64,
+		SectionId:       p.SectionID.Int64,
+		PriceCategoryId: p.PriceCategoryID.Int64,
+		Row:             p.Row,
+		Seat:            int32(p.Seat),
+		X:               p.X,
+		Y:               p.Y,
+		Width:           p.Width,
+		Height:          p.Height,
+		Accessible:      p.Accessible,
+		Status:          placeStatuses[p.Status()],
+	}
+}
+
+func newChange(c availability.Change) *ticketv1.StreamAvailabilityResponse {
+	return &ticketv1.StreamAvailabilityResponse{
+		Id:      c.ID,
+		Kind:    changeKinds[c.Kind],
+		EventId: c.EventID,
+		PlaceId: c.PlaceID,
+		Status:  placeStatuses[c.Status],
+		At:      timestamppb.New(c.At),
+	}
+}
+
+func (t *ticketService) ListShows(ctx context.Context, _ *ticketv1.ListShowsRequest,
+) (*ticketv1.ListShowsResponse, error) {
+	shows, err := t.s.app.GetShows(ctx)
+	if err != nil {
+		return nil, t.statusError("show", err)
+	}
+	resp := &ticketv1.ListShowsResponse{Shows: make([]*ticketv1.Show, 0, len(shows))}
+	for _, show := range shows {
+		resp.Shows = append(resp.Shows, &ticketv1.Show{Id: show.ID, Name: show.Name})
+	}
+	return resp, nil
+}
+
+func (t *ticketService) ListEvents(ctx context.Context, req *ticketv1.ListEventsRequest,
+) (*ticketv1.ListEventsResponse, error) {
+	if req.GetShowId() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "show_id is negative")
+	}
+	filter := models.EventFilter{ShowID: req.GetShowId()}
+	if req.From != nil {
+		filter.From = req.GetFrom().AsTime()
+	}
+	if req.To != nil {
+		filter.To = req.GetTo().AsTime()
+	}
+	events, err := t.s.app.GetEvents(ctx, filter)
+	if err != nil {
+		return nil, t.statusError("event", err)
+	}
+	resp := &ticketv1.ListEventsResponse{Events: make([]*ticketv1.Event, 0, len(events))}
+	for _, e := range events {
+		resp.Events = append(resp.Events, newEvent(e))
+	}
+	return resp, nil
+}
+
+func (t *ticketService) GetEvent(ctx context.Context, req *ticketv1.GetEventRequest,
+) (*ticketv1.GetEventResponse, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+	event, err := t.s.app.GetEvent(ctx, req.GetId())
+	if err != nil {
+		return nil, t.statusError("event", err)
+	}
+	return &ticketv1.GetEventResponse{Event: newEvent(event)}, nil
+}
+
+func (t *ticketService) ListPlaces(ctx context.Context, req *ticketv1.ListPlacesRequest,
+) (*ticketv1.ListPlacesResponse, error) {
+	if req.GetEventId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid event_id")
+	}
+	if _, err := t.s.app.GetEvent(ctx, req.GetEventId()); err != nil {
+		return nil, t.statusError("event", err)
+	}
+	places, err := t.s.app.GetPlaces(ctx, models.PlaceFilter{EventID: req.GetEventId()})
+	if err != nil {
+		return nil, t.statusError("place", err)
+	}
+	resp := &ticketv1.ListPlacesResponse{Places: make([]*ticketv1.Place, 0, len(places))}
+	for _, p := range places {
+		resp.Places = append(resp.Places, newPlace(p))
+	}
+	return resp, nil
+}
+
+// StreamAvailability streams changes like the HTTP availability stream. A client falling behind is resumed
+// after the last change sent to it.
+func (t *ticketService) StreamAvailability(req *ticketv1.StreamAvailabilityRequest,
+	stream ticketv1.TicketService_StreamAvailabilityServer,
+) error {
+	ctx := stream.Context()
+	if req.GetEventId() <= 0 || req.GetLastId() < 0 {
+		return status.Error(codes.InvalidArgument, "invalid event_id or last_id")
+	}
+	if _, err := t.s.app.GetEvent(ctx, req.GetEventId()); err != nil {
+		return t.statusError("event", err)
+	}
+
+	lastID := req.GetLastId()
+	for {
+		sub := t.s.app.SubscribeAvailability(req.GetEventId(), lastID)
+		err := func() error {
+			defer sub.Close()
+			if sub.Missed {
+				lastID = sub.LastID
+				if err := stream.Send(&ticketv1.StreamAvailabilityResponse{
+					Id:      lastID,
+					Kind:    ticketv1.ChangeKind_CHANGE_KIND_RESET,
+					EventId: req.GetEventId(),
+				}); err != nil {
+					return err
+				}
+			}
+			if lastID == 0 {
+				lastID = sub.LastID
+			}
+			for _, c := range sub.Replay {
+				if err := stream.Send(newChange(c)); err != nil {
+					return err
+				}
+				lastID = c.ID
+			}
+			for {
+				select {
+				case <-ctx.Done():
+					return status.FromContextError(ctx.Err()).Err()
+				case <-t.s.done:
+					return status.Error(codes.Unavailable, "server is shutting down")
+				case c, ok := <-sub.C:
+					if !ok {
+						return nil
+					}
+					if err := stream.Send(newChange(c)); err != nil {
+						return err
+					}
+					lastID = c.ID
+				}
+			}
+		}()
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package internalhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/availability"
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// streamHeartbeat keeps idle streams from being closed by proxies.
+	streamHeartbeat = 15 * time.Second
+	// streamRetry is how long clients wait before reconnecting, in milliseconds.
+	streamRetry = 3000
+)
+
+func (s *Server) registerAvailabilityRoutes(router *mux.Router, midLogger *MiddlewareLogger) {
+	handle := func(path string, h http.HandlerFunc, method string) {
+		router.Handle(path, midLogger.setCommonHeadersMiddleware(
+			midLogger.loggingMiddleware(h))).Methods(method)
+	}
+
+	handle("/events/{id:[0-9]+}/availability/stream", s.StreamAvailability, http.MethodGet)
+}
+
+func writeChange(w io.Writer, c availability.Change) error {
+	data, err := json.Marshal(model.AvailabilityChangeResponse{
+		EventID: c.EventID,
+		PlaceID: c.PlaceID,
+		Status:  string(c.Status),
+		At:      c.At.UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", c.ID, c.Kind, data)
+	return err
+}
+
+// @Summary Stream availability
+// @Tags places
+// @Description Server-sent events of place status changes of the event: held, released, sold and synced
+// @Description (read from the upstream API), with model.AvailabilityChangeResponse data. A reconnecting client
+// @Description resumes after its Last-Event-ID. If those changes are gone a reset event is sent first
+// @Description and the places have to be reloaded.
+// @ID stream-availability
+// @Produce  text/event-stream
+// @Param id path int true "event ID"
+// @Param Last-Event-ID header int false "ID of the last change received"
+// @Success 200 {object} model.AvailabilityChangeResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/availability/stream [get].
+func (s *Server) StreamAvailability(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var lastID int64
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id < 0 {
+			srv.RespondWithError(slugerrors.NewBadRequestError("invalid Last-Event-ID", "invalid-last-event-id"), w, r)
+			return
+		}
+		lastID = id
+	}
+	if _, err := s.app.GetEvent(r.Context(), eventID); err != nil {
+		respondStorageError("event", err, w, r)
+		return
+	}
+
+	sub := s.app.SubscribeAvailability(eventID, lastID)
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	err := func() error {
+		if _, err := fmt.Fprintf(w, "retry: %d\n\n", streamRetry); err != nil {
+			return err
+		}
+		if sub.Missed {
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: reset\ndata: {}\n\n", sub.LastID); err != nil {
+				return err
+			}
+		}
+		for _, c := range sub.Replay {
+			if err := writeChange(w, c); err != nil {
+				return err
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return err
+		}
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return nil
+			case <-heartbeat.C:
+				if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+					return err
+				}
+			case c, ok := <-sub.C:
+				if !ok {
+					// Fell behind, the client reconnects and resumes.
+					return nil
+				}
+				if err := writeChange(w, c); err != nil {
+					return err
+				}
+			}
+			if err := rc.Flush(); err != nil {
+				return err
+			}
+		}
+	}()
+	if err != nil && !errors.Is(err, context.Canceled) {
+		s.log.Debugf("availability stream of event %d closed:%v\n", eventID, err)
+	}
+}
+
+// syncPlaces refetches places of upstream events with availability subscribers every interval until ctx is done.
+// Changed statuses reach the subscribers as synced changes.
+func (s *Server) syncPlaces(ctx context.Context) {
+	ticker := time.NewTicker(s.placesSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, eventID := range s.app.AvailabilitySubscribed() {
+			ctxSync, cancel := context.WithTimeout(ctx, 10*time.Second)
+			err := s.syncEventPlaces(ctxSync, eventID)
+			cancel()
+			if err != nil {
+				s.log.Errorf("failed to sync places of event %d:%v\n", eventID, err)
+			}
+		}
+	}
+}
+
+func (s *Server) syncEventPlaces(ctx context.Context, eventID int64) error {
+	event, err := s.app.GetEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	// Places of events in local halls only change here.
+	if event.HallID.Valid {
+		return nil
+	}
+	_, _, err = s.fetchPlaces(ctx, eventID)
+	return err
+}
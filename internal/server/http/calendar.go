@@ -0,0 +1,119 @@
+package internalhttp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/ical"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) registerCalendarRoutes(router *mux.Router, midLogger *MiddlewareLogger) {
+	handle := func(path string, h http.HandlerFunc, method string) {
+		router.Handle(path, midLogger.setCommonHeadersMiddleware(
+			midLogger.loggingMiddleware(h))).Methods(method)
+	}
+
+	handle("/shows/{id:[0-9]+}/events.ics", s.GetShowCalendar, http.MethodGet)
+	handle("/orders/{id:[0-9]+}/calendar.ics", s.GetOrderCalendar, http.MethodGet)
+	handle("/events/{id:[0-9]+}/cancel", s.CancelEvent, http.MethodPost)
+}
+
+func respondCalendar(cal ical.Calendar, filename string, w http.ResponseWriter, r *http.Request) {
+	var b bytes.Buffer
+	if err := ical.Write(&b, cal, time.Now()); err != nil {
+		srv.RespondWithError(fmt.Errorf("failed to write calendar: %w", err), w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, filename))
+	w.Header().Set("Content-Length", strconv.Itoa(b.Len()))
+	w.WriteHeader(http.StatusOK)
+	_, _ = b.WriteTo(w)
+}
+
+// @Summary Get show calendar
+// @Tags events
+// @Description iCalendar feed of the show events in their venue time zones. Event UIDs are stable,
+// @Description rescheduled events have a higher SEQUENCE and cancelled ones STATUS:CANCELLED.
+// @ID get-show-calendar
+// @Produce  text/calendar
+// @Param id path int true "show ID"
+// @Success 200 {file} file
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /shows/{id}/events.ics [get].
+func (s *Server) GetShowCalendar(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	cal, err := s.app.ShowCalendar(r.Context(), id)
+	if err != nil {
+		respondStorageError("show", err, w, r)
+		return
+	}
+	respondCalendar(cal, fmt.Sprintf("show-%d.ics", id), w, r)
+}
+
+// @Summary Get order calendar
+// @Tags orders
+// @Description iCalendar with the events the order has issued tickets for and the seats in the description
+// @ID get-order-calendar
+// @Produce  text/calendar
+// @Param id path int true "order ID"
+// @Success 200 {file} file
+// @Failure 400,404,409 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /orders/{id}/calendar.ics [get].
+func (s *Server) GetOrderCalendar(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	cal, err := s.app.OrderCalendar(r.Context(), id)
+	if errors.Is(err, model.ErrUnavailable) {
+		srv.RespondWithError(slugerrors.NewConflictError("order has no issued tickets", "no-tickets"), w, r)
+		return
+	}
+	if err != nil {
+		respondStorageError("order", err, w, r)
+		return
+	}
+	respondCalendar(cal, fmt.Sprintf("order-%d.ics", id), w, r)
+}
+
+// @Summary Cancel event
+// @Tags events
+// @Description Mark the event as cancelled: its places can't be held any more and calendars show it cancelled
+// @ID cancel-event
+// @Produce  json
+// @Param id path int true "event ID"
+// @Param If-Match header string false "ETag of the event read last"
+// @Success 200 {object} model.EventResponse
+// @Failure 400,404,412 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/cancel [post].
+func (s *Server) CancelEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	event, err := s.app.CancelEvent(r.Context(), id, ifMatch(r, newEventResponse))
+	if err != nil {
+		respondStorageError("event", err, w, r)
+		return
+	}
+	respondConditional(newEventResponse(event), event.ModifiedAt(), w, r)
+}
@@ -0,0 +1,135 @@
+package internalhttp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+// modifier is a stored record knowing when it was last written.
+type modifier interface {
+	ModifiedAt() time.Time
+}
+
+// lastModified returns when the latest of the records was written, zero if there are none.
+func lastModified[T modifier](records ...T) time.Time {
+	var latest time.Time
+	for _, r := range records {
+		if t := r.ModifiedAt(); t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+// encode returns the JSON body of data as srv.RespondOK writes it and its strong ETag, a digest of the body.
+func encode(data any) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		return nil, "", fmt.Errorf("failed to encode response: %w", err)
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return buf.Bytes(), `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// respondConditional responds with data and its validators: the ETag and, unless modified is zero,
+// Last-Modified. GET and HEAD requests whose validators match get 304 Not Modified without a body.
+func respondConditional(data any, modified time.Time, w http.ResponseWriter, r *http.Request) {
+	body, etag, err := encode(data)
+	if err != nil {
+		srv.RespondWithError(err, w, r)
+		return
+	}
+
+	h := w.Header()
+	h.Set("ETag", etag)
+	// Clients may keep responses but have to revalidate them before reuse.
+	h.Set("Cache-Control", "no-cache")
+	if !modified.IsZero() {
+		h.Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(r, etag, modified) {
+		h.Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since without it, of a GET or HEAD request.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if inm := r.Header.Values("If-None-Match"); len(inm) > 0 {
+		return matchETag(inm, etag, false)
+	}
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || modified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	// HTTP dates have a resolution of seconds.
+	return err == nil && !modified.Truncate(time.Second).After(since)
+}
+
+// setETag sets the ETag of data, e.g. on a created resource, so clients can update it with If-Match.
+func setETag(data any, w http.ResponseWriter) {
+	if _, etag, err := encode(data); err == nil {
+		w.Header().Set("ETag", etag)
+	}
+}
+
+// ifMatch returns the check of If-Match of an update, nil without If-Match. The stored record, rendered
+// by response as GET responds with it, must have one of the listed ETags. Storage runs the check within
+// the write, so an update racing another one fails instead of overwriting it.
+func ifMatch[T, R any](r *http.Request, response func(T) R) models.Check[T] {
+	im := r.Header.Values("If-Match")
+	if len(im) == 0 {
+		return nil
+	}
+	return func(record T) error {
+		_, etag, err := encode(response(record))
+		if err != nil {
+			return err
+		}
+		if !matchETag(im, etag, true) {
+			return slugerrors.NewPreconditionFailedError("the resource was changed since it was read", "etag-mismatch")
+		}
+		return nil
+	}
+}
+
+// matchETag reports whether any tag listed in the header values matches etag. Weak tags only match
+// in the weak comparison.
+func matchETag(values []string, etag string, strong bool) bool {
+	for _, v := range values {
+		for _, tag := range strings.Split(v, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" {
+				return true
+			}
+			if weak, ok := strings.CutPrefix(tag, "W/"); ok {
+				if strong {
+					continue
+				}
+				tag = weak
+			}
+			if tag == etag {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,259 @@
+package internalhttp
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) registerCustomerRoutes(router *mux.Router, midLogger *MiddlewareLogger) {
+	handle := func(path string, h http.Handler, method string) {
+		router.Handle(path, midLogger.setCommonHeadersMiddleware(
+			midLogger.loggingMiddleware(h))).Methods(method)
+	}
+	// Customer IDs are sequential, personal data is only served to administrators.
+	admin := func(h http.HandlerFunc) http.Handler {
+		return s.adminMiddleware(h)
+	}
+
+	handle("/customers", http.HandlerFunc(s.CreateCustomer), http.MethodPost)
+	handle("/customers/{id:[0-9]+}", admin(s.GetCustomer), http.MethodGet)
+	handle("/customers/{id:[0-9]+}", admin(s.UpdateCustomer), http.MethodPut)
+	handle("/customers/{id:[0-9]+}", admin(s.EraseCustomer), http.MethodDelete)
+	handle("/customers/{id:[0-9]+}/orders", admin(s.ListCustomerOrders), http.MethodGet)
+	handle("/customers/{id:[0-9]+}/export", admin(s.ExportCustomer), http.MethodGet)
+}
+
+func newCustomerResponse(c models.Customer) model.CustomerResponse {
+	resp := model.CustomerResponse{
+		CustomerRequest: model.CustomerRequest{
+			Email:          c.Email,
+			Name:           c.Name,
+			Phone:          c.Phone,
+			MarketingEmail: c.MarketingEmail,
+			MarketingSMS:   c.MarketingSMS,
+		},
+		ID:        c.ID,
+		CreatedAt: c.CreatedAt.UTC(),
+	}
+	if c.ErasedAt.Valid {
+		erasedAt := c.ErasedAt.Time.UTC()
+		resp.ErasedAt = &erasedAt
+	}
+	return resp
+}
+
+func newCustomer(req model.CustomerRequest) models.Customer {
+	return models.Customer{
+		Email:          req.Email,
+		Name:           req.Name,
+		Phone:          req.Phone,
+		MarketingEmail: req.MarketingEmail,
+		MarketingSMS:   req.MarketingSMS,
+	}
+}
+
+// customerError reports taken emails as conflicts and unknown or erased buyers as invalid.
+func customerError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrAlreadyExists):
+		return slugerrors.NewConflictError("email is taken", "email-taken")
+	case errors.Is(err, model.ErrInvalidUserID):
+		return slugerrors.NewValidationError("customer is unknown, erased or not the holder",
+			"invalid-customer").Wrap(err)
+	default:
+		return storageError("customer", err)
+	}
+}
+
+func respondCustomerError(err error, w http.ResponseWriter, r *http.Request) {
+	srv.RespondWithError(customerError(err), w, r)
+}
+
+// @Summary Create customer
+// @Tags customers
+// @ID create-customer
+// @Accept  json
+// @Produce  json
+// @Param customer body model.CustomerRequest true "customer"
+// @Success 201 {object} model.CustomerResponse
+// @Failure 400,409,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /customers [post].
+func (s *Server) CreateCustomer(w http.ResponseWriter, r *http.Request) {
+	var req model.CustomerRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	customer, err := s.app.CreateCustomer(r.Context(), newCustomer(req))
+	if err != nil {
+		respondCustomerError(err, w, r)
+		return
+	}
+	setETag(newCustomerResponse(customer), w)
+	srv.RespondCreated(newCustomerResponse(customer), w, r)
+}
+
+// @Summary Get customer
+// @Tags customers
+// @Security BearerAuth
+// @ID get-customer
+// @Produce  json
+// @Param id path int true "customer ID"
+// @Param If-None-Match header string false "ETag of a kept response"
+// @Param If-Modified-Since header string false "Last-Modified of a kept response"
+// @Success 200 {object} model.CustomerResponse
+// @Success 304 "not modified"
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 401 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /customers/{id} [get].
+func (s *Server) GetCustomer(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	customer, err := s.app.GetCustomer(r.Context(), id)
+	if err != nil {
+		respondCustomerError(err, w, r)
+		return
+	}
+	respondConditional(newCustomerResponse(customer), customer.ModifiedAt(), w, r)
+}
+
+// @Summary Update customer
+// @Tags customers
+// @Security BearerAuth
+// @Description Change contacts and consents of a customer, erased customers can't be changed
+// @ID update-customer
+// @Accept  json
+// @Produce  json
+// @Param id path int true "customer ID"
+// @Param customer body model.CustomerRequest true "customer"
+// @Param If-Match header string false "ETag of the customer read last"
+// @Success 200 {object} model.CustomerResponse
+// @Failure 400,404,409,412,422 {object} server.ErrorResponse
+// @Failure 401 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /customers/{id} [put].
+func (s *Server) UpdateCustomer(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req model.CustomerRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	c := newCustomer(req)
+	c.ID = id
+	customer, err := s.app.UpdateCustomer(r.Context(), c, ifMatch(r, newCustomerResponse))
+	if err != nil {
+		respondCustomerError(err, w, r)
+		return
+	}
+	respondConditional(newCustomerResponse(customer), customer.ModifiedAt(), w, r)
+}
+
+// @Summary Erase customer
+// @Tags customers
+// @Security BearerAuth
+// @Description Anonymise a customer: personal data and consents are dropped, orders keep their totals
+// @ID erase-customer
+// @Produce  json
+// @Param id path int true "customer ID"
+// @Success 200 {object} model.CustomerResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 401 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /customers/{id} [delete].
+func (s *Server) EraseCustomer(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	customer, err := s.app.EraseCustomer(r.Context(), id)
+	if err != nil {
+		respondCustomerError(err, w, r)
+		return
+	}
+	srv.RespondOK(newCustomerResponse(customer), w, r)
+}
+
+// @Summary List customer orders
+// @Tags customers
+// @Security BearerAuth
+// @ID list-customer-orders
+// @Produce  json
+// @Param id path int true "customer ID"
+// @Success 200 {array} model.OrderResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 401 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /customers/{id}/orders [get].
+func (s *Server) ListCustomerOrders(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	orders, err := s.app.GetCustomerOrders(r.Context(), id)
+	if err != nil {
+		respondCustomerError(err, w, r)
+		return
+	}
+	resp := make([]model.OrderResponse, 0, len(orders))
+	for _, o := range orders {
+		resp = append(resp, newOrderResponse(o))
+	}
+	srv.RespondOK(resp, w, r)
+}
+
+// @Summary Export customer data
+// @Tags customers
+// @Security BearerAuth
+// @Description Everything stored about a customer: profile, consents, holds and orders
+// @ID export-customer
+// @Produce  json
+// @Param id path int true "customer ID"
+// @Success 200 {object} model.CustomerExportResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 401 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /customers/{id}/export [get].
+func (s *Server) ExportCustomer(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	export, err := s.app.ExportCustomer(r.Context(), id)
+	if err != nil {
+		respondCustomerError(err, w, r)
+		return
+	}
+	resp := model.CustomerExportResponse{
+		Customer:   newCustomerResponse(export.Customer),
+		Holds:      make([]model.HoldResponse, 0, len(export.Holds)),
+		Orders:     make([]model.OrderResponse, 0, len(export.Orders)),
+		ExportedAt: time.Now().UTC(),
+	}
+	for _, h := range export.Holds {
+		resp.Holds = append(resp.Holds, newHoldResponse(h))
+	}
+	for _, o := range export.Orders {
+		resp.Orders = append(resp.Orders, newOrderResponse(o))
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="customer-export.json"`)
+	srv.RespondOK(resp, w, r)
+}
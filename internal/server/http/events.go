@@ -0,0 +1,120 @@
+package internalhttp
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/common/validation"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/server"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+func parseEventFilter(r *http.Request) (models.EventFilter, error) {
+	var (
+		filter models.EventFilter
+		v      validation.Validator
+		err    error
+	)
+	q := r.URL.Query()
+	if showID := q.Get("showId"); showID != "" {
+		filter.ShowID, err = strconv.ParseInt(showID, 10, 64)
+		v.Check(err == nil && filter.ShowID > 0, validation.Pointer("showId"), server.ErrID)
+	}
+	if from := q.Get("from"); from != "" {
+		filter.From, err = time.Parse(time.RFC3339, from)
+		v.Check(err == nil, validation.Pointer("from"), model.ErrInvalidDate)
+	}
+	if to := q.Get("to"); to != "" {
+		filter.To, err = time.Parse(time.RFC3339, to)
+		v.Check(err == nil, validation.Pointer("to"), model.ErrInvalidDate)
+	}
+	if err := v.Err(); err != nil {
+		return filter, slugerrors.NewBadRequestError("invalid event filter", "invalid-event-filter").Wrap(err)
+	}
+	return filter, nil
+}
+
+// @Summary List events
+// @Tags events
+// @Description List stored events, optionally by show and date range [from, to) in RFC 3339
+// @ID list-events
+// @Produce  json
+// @Param showId query int false "show ID"
+// @Param from query string false "range start, RFC 3339"
+// @Param to query string false "range end, RFC 3339"
+// @Param If-None-Match header string false "ETag of a kept response"
+// @Param If-Modified-Since header string false "Last-Modified of a kept response"
+// @Success 200 {array} model.EventResponse
+// @Success 304 "not modified"
+// @Failure 400 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events [get].
+func (s *Server) ListEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseEventFilter(r)
+	if err != nil {
+		srv.RespondWithError(err, w, r)
+		return
+	}
+
+	events, err := s.app.GetEvents(r.Context(), filter)
+	if err != nil {
+		srv.RespondWithError(fmt.Errorf("failed to get events: %w", err), w, r)
+		return
+	}
+
+	resp := make([]model.EventResponse, 0, len(events))
+	for _, event := range events {
+		resp = append(resp, newEventResponse(event))
+	}
+	respondConditional(resp, lastModified(events...), w, r)
+}
+
+// @Summary Get event
+// @Tags events
+// @Description Get a stored event, its ETag guards updates of the event sent with If-Match
+// @ID get-event
+// @Produce  json
+// @Param id path int true "event ID"
+// @Param If-None-Match header string false "ETag of a kept response"
+// @Param If-Modified-Since header string false "Last-Modified of a kept response"
+// @Success 200 {object} model.EventResponse
+// @Success 304 "not modified"
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id} [get].
+func (s *Server) GetEvent(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	event, err := s.app.GetEvent(r.Context(), id)
+	if err != nil {
+		respondStorageError("event", err, w, r)
+		return
+	}
+	respondConditional(newEventResponse(event), event.ModifiedAt(), w, r)
+}
+
+// newEventResponse renders a stored event with its date in the venue time zone.
+func newEventResponse(e models.Event) model.EventResponse {
+	date := e.LocalDate()
+	resp := model.EventResponse{
+		ID:       e.ID,
+		ShowID:   e.ShowID,
+		HallID:   e.HallID.Int64,
+		Date:     date,
+		TimeZone: date.Location().String(),
+		Sequence: e.Sequence,
+	}
+	if e.CancelledAt.Valid {
+		cancelledAt := e.CancelledAt.Time.UTC()
+		resp.CancelledAt = &cancelledAt
+	}
+	return resp
+}
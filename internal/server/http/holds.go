@@ -0,0 +1,193 @@
+package internalhttp
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/seating"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) registerHoldRoutes(router *mux.Router, midLogger *MiddlewareLogger) {
+	handle := func(path string, h http.HandlerFunc, method string) {
+		router.Handle(path, midLogger.setCommonHeadersMiddleware(
+			midLogger.loggingMiddleware(h))).Methods(method)
+	}
+
+	handle("/events/{id:[0-9]+}/best-seats", s.BestSeats, http.MethodPost)
+	handle("/events/{id:[0-9]+}/holds", s.CreateHold, http.MethodPost)
+	handle("/holds/{id:[0-9]+}", s.GetHold, http.MethodGet)
+	handle("/holds/{id:[0-9]+}", s.ReleaseHold, http.MethodDelete)
+}
+
+func newHoldResponse(h models.Hold) model.HoldResponse {
+	resp := model.HoldResponse{
+		ID:         h.ID,
+		EventID:    h.EventID,
+		PlaceIDs:   h.PlaceIDs,
+		CustomerID: h.CustomerID.Int64,
+		ExpiresAt:  h.ExpiresAt.UTC(),
+		Active:     h.IsActive(time.Now()),
+	}
+	if resp.PlaceIDs == nil {
+		resp.PlaceIDs = []int64{}
+	}
+	if h.ReleasedAt.Valid {
+		releasedAt := h.ReleasedAt.Time.UTC()
+		resp.ReleasedAt = &releasedAt
+	}
+	return resp
+}
+
+// holdError reports places taken by someone else as a conflict.
+func holdError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrUnavailable):
+		return slugerrors.NewConflictError("places are not available", "places-unavailable")
+	case errors.Is(err, seating.ErrNoSeats):
+		return slugerrors.NewConflictError("no seats match the request", "no-seats-available")
+	case errors.Is(err, model.ErrInvalidUserID):
+		return customerError(err)
+	default:
+		return storageError("hold", err)
+	}
+}
+
+func respondHoldError(err error, w http.ResponseWriter, r *http.Request) {
+	srv.RespondWithError(holdError(err), w, r)
+}
+
+// @Summary Pick best available seats
+// @Tags holds
+// @Description Pick the best group of adjacent available places of the event: places in one row,
+// @Description leaving no single stranded places where possible, closest to the stage, optionally
+// @Description within a section, a price limit or with an accessible place. Optionally hold them.
+// @ID best-seats
+// @Accept  json
+// @Produce  json
+// @Param id path int true "event ID"
+// @Param request body model.BestSeatsRequest true "party size and preferences"
+// @Success 200 {object} model.BestSeatsResponse
+// @Failure 400,404,409,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/best-seats [post].
+func (s *Server) BestSeats(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req model.BestSeatsRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	prefs := seating.Preferences{
+		Quantity:   req.Quantity,
+		SectionID:  req.SectionID,
+		Accessible: req.Accessible,
+		MaxPrice:   req.MaxPrice,
+	}
+	if req.Stage != nil {
+		prefs.Stage = &models.Point{X: req.Stage.X, Y: req.Stage.Y}
+	}
+
+	places, hold, err := s.app.BestSeats(r.Context(), id, prefs, req.Hold, req.CustomerID)
+	if err != nil {
+		respondHoldError(err, w, r)
+		return
+	}
+
+	priced, err := s.pricedPlaceResponses(r.Context(), id, places)
+	if err != nil {
+		respondStorageError("price-category", err, w, r)
+		return
+	}
+	resp := model.BestSeatsResponse{Places: priced}
+	if hold != nil {
+		h := newHoldResponse(*hold)
+		resp.Hold = &h
+	}
+	srv.RespondOK(resp, w, r)
+}
+
+// @Summary Hold places
+// @Tags holds
+// @Description Take places of the event off sale until the hold expires or is released. All places are held or none.
+// @ID create-hold
+// @Accept  json
+// @Produce  json
+// @Param id path int true "event ID"
+// @Param hold body model.HoldRequest true "places to hold"
+// @Success 201 {object} model.HoldResponse
+// @Failure 400,404,409,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/holds [post].
+func (s *Server) CreateHold(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req model.HoldRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	hold, err := s.app.CreateHold(r.Context(), id, req.CustomerID, req.PlaceIDs)
+	if err != nil {
+		respondHoldError(err, w, r)
+		return
+	}
+	srv.RespondCreated(newHoldResponse(hold), w, r)
+}
+
+// @Summary Get hold
+// @Tags holds
+// @ID get-hold
+// @Produce  json
+// @Param id path int true "hold ID"
+// @Success 200 {object} model.HoldResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /holds/{id} [get].
+func (s *Server) GetHold(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	hold, err := s.app.GetHold(r.Context(), id)
+	if err != nil {
+		respondStorageError("hold", err, w, r)
+		return
+	}
+	srv.RespondOK(newHoldResponse(hold), w, r)
+}
+
+// @Summary Release hold
+// @Tags holds
+// @Description Put held places back on sale before the hold expires
+// @ID release-hold
+// @Produce  json
+// @Param id path int true "hold ID"
+// @Success 200 {object} model.HoldResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /holds/{id} [delete].
+func (s *Server) ReleaseHold(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	hold, err := s.app.ReleaseHold(r.Context(), id)
+	if err != nil {
+		respondStorageError("hold", err, w, r)
+		return
+	}
+	srv.RespondOK(newHoldResponse(hold), w, r)
+}
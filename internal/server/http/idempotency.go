@@ -0,0 +1,188 @@
+package internalhttp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotentReplayed  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotencyRequestBody = 1 << 20
+)
+
+// replayedHeaders are the response headers stored along with the body, Content-Type is kept on its own.
+var replayedHeaders = []string{"Location", "ETag", "Last-Modified"}
+
+// responseRecorder passes the response through and keeps a copy of it.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// requestFingerprint covers the credentials too, so a response is only replayed to whoever was let in.
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n%s\n", r.Method, r.URL.RequestURI(), r.Header.Get("Authorization"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotencyMiddleware makes mutating requests carrying an Idempotency-Key header safe to retry:
+// the first response is stored and replayed for repeats of the same request.
+func (s *Server) idempotencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(HeaderIdempotencyKey)
+		if key == "" || !isMutatingMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			srv.RespondWithError(slugerrors.NewBadRequestError(
+				"idempotency key is too long", "invalid-idempotency-key"), w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotencyRequestBody))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			srv.RespondWithError(slugerrors.NewTooLargeError(
+				fmt.Sprintf("request body is larger than %d bytes", tooLarge.Limit), "request-too-large"), w, r)
+			return
+		}
+		if err != nil {
+			srv.RespondWithError(slugerrors.NewBadRequestError(
+				fmt.Sprintf("failed to read request body: %v", err), "invalid-request-body"), w, r)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := requestFingerprint(r, body)
+
+		reserved, err := s.app.CreateIdempotencyKey(r.Context(), models.IdempotencyKey{
+			Key:         key,
+			Method:      r.Method,
+			Path:        r.URL.RequestURI(),
+			Fingerprint: fingerprint,
+		})
+		if errors.Is(err, model.ErrAlreadyExists) {
+			s.replayIdempotentResponse(key, fingerprint, w, r)
+			return
+		}
+		if err != nil {
+			srv.RespondWithError(fmt.Errorf("failed to create idempotency key: %w", err), w, r)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		// The response must be stored even if the client has gone away.
+		ctx := context.WithoutCancel(r.Context())
+		if rec.status == 0 || rec.status >= http.StatusInternalServerError || rec.status == http.StatusUnauthorized {
+			// Server errors are not cached so the client may retry with the same key, nor are rejected
+			// credentials.
+			if err := s.app.DeleteIdempotencyKey(ctx, key); err != nil {
+				s.log.Errorf("failed to release idempotency key %q:%v\n", key, err)
+			}
+			return
+		}
+
+		reserved.StatusCode = rec.status
+		reserved.ContentType = rec.Header().Get("Content-Type")
+		reserved.ResponseHeaders = storedHeaders(rec.Header())
+		reserved.ResponseBody = rec.body.Bytes()
+		if err := s.app.CompleteIdempotencyKey(ctx, reserved); err != nil {
+			s.log.Errorf("failed to store idempotent response for key %q:%v\n", key, err)
+		}
+	})
+}
+
+func (s *Server) replayIdempotentResponse(key, fingerprint string, w http.ResponseWriter, r *http.Request) {
+	stored, err := s.app.GetIdempotencyKey(r.Context(), key)
+	if errors.Is(err, model.ErrNotFound) {
+		// The key expired or was released between the reservation attempt and now.
+		srv.RespondWithError(slugerrors.NewConflictError(
+			"idempotency key is being processed, retry the request", "idempotency-key-in-progress"), w, r)
+		return
+	}
+	if err != nil {
+		srv.RespondWithError(fmt.Errorf("failed to get idempotency key: %w", err), w, r)
+		return
+	}
+
+	if stored.Fingerprint != fingerprint {
+		srv.RespondWithError(slugerrors.NewConflictError(
+			"idempotency key was already used for a different request", "idempotency-key-reused"), w, r)
+		return
+	}
+	if !stored.IsCompleted() {
+		srv.RespondWithError(slugerrors.NewConflictError(
+			"a request with this idempotency key is still in progress", "idempotency-key-in-progress"), w, r)
+		return
+	}
+
+	if stored.ContentType != "" {
+		w.Header().Set("Content-Type", stored.ContentType)
+	}
+	var headers map[string]string
+	if len(stored.ResponseHeaders) > 0 {
+		if err := json.Unmarshal(stored.ResponseHeaders, &headers); err != nil {
+			srv.RespondWithError(fmt.Errorf("failed to decode stored response headers: %w", err), w, r)
+			return
+		}
+	}
+	for name, value := range headers {
+		w.Header().Set(name, value)
+	}
+	w.Header().Set(HeaderIdempotentReplayed, "true")
+	w.WriteHeader(stored.StatusCode)
+	_, _ = w.Write(stored.ResponseBody)
+}
+
+func storedHeaders(h http.Header) []byte {
+	headers := make(map[string]string, len(replayedHeaders))
+	for _, name := range replayedHeaders {
+		if value := h.Get(name); value != "" {
+			headers[name] = value
+		}
+	}
+	b, _ := json.Marshal(headers)
+	return b
+}
@@ -0,0 +1,115 @@
+package internalhttp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cronnoss/tk-api/internal/app"
+	"github.com/cronnoss/tk-api/internal/logger"
+	memorystorage "github.com/cronnoss/tk-api/internal/storage/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func newIdempotencyTestServer(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+	log := logger.NewLogger("ERROR", io.Discard)
+	var conf app.TicketConf
+	conf.Tickets.EphemeralKey = true
+	ticket, err := app.NewTicket(log, conf, memorystorage.New())
+	require.NoError(t, err)
+	s := NewServer(log, ticket, "", "")
+	ts := httptest.NewServer(s.idempotencyMiddleware(handler))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func doIdempotent(t *testing.T, url, key, body string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set(HeaderIdempotencyKey, key)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(b)
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	var calls int32
+	ts := newIdempotencyTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		b, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", fmt.Sprintf("/orders/%d", n))
+		w.Header().Set("ETag", fmt.Sprintf(`"%d"`, n))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"call":%d,"body":%s}`, n, b)
+	}))
+
+	resp, body := doIdempotent(t, ts.URL, "key-1", `{"seats":[1,2]}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, `{"call":1,"body":{"seats":[1,2]}}`, body)
+	require.Empty(t, resp.Header.Get(HeaderIdempotentReplayed))
+
+	resp, body = doIdempotent(t, ts.URL, "key-1", `{"seats":[1,2]}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, `{"call":1,"body":{"seats":[1,2]}}`, body)
+	require.Equal(t, "true", resp.Header.Get(HeaderIdempotentReplayed))
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	require.Equal(t, "/orders/1", resp.Header.Get("Location"))
+	require.Equal(t, `"1"`, resp.Header.Get("ETag"))
+	require.Empty(t, resp.Header.Get("Last-Modified"))
+
+	resp, _ = doIdempotent(t, ts.URL, "key-1", `{"seats":[3]}`)
+	require.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp, body = doIdempotent(t, ts.URL, "key-2", `{"seats":[3]}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	require.Equal(t, `{"call":2,"body":{"seats":[3]}}`, body)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotencyMiddlewareReleasesKeyOnServerError(t *testing.T) {
+	var calls int32
+	ts := newIdempotencyTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	resp, _ := doIdempotent(t, ts.URL, "key", `{}`)
+	require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	resp, _ = doIdempotent(t, ts.URL, "key", `{}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Empty(t, resp.Header.Get(HeaderIdempotentReplayed))
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestIdempotencyMiddlewareRejectsLargeBody(t *testing.T) {
+	var calls int32
+	ts := newIdempotencyTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	resp, body := doIdempotent(t, ts.URL, "key", strings.Repeat("a", maxIdempotencyRequestBody+1))
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	require.Contains(t, body, "request-too-large")
+	require.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	// The key wasn't reserved, a request within the limit goes through.
+	resp, _ = doIdempotent(t, ts.URL, "key", strings.Repeat("a", maxIdempotencyRequestBody))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
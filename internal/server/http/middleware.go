@@ -1,8 +1,15 @@
 package internalhttp
 
 import (
+	"bufio"
+	"crypto/subtle"
+	"net"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
 )
 
 type statusWriter struct {
@@ -15,6 +22,17 @@ func (w *statusWriter) WriteHeader(status int) {
 	w.ResponseWriter.WriteHeader(status)
 }
 
+// Unwrap lets http.ResponseController flush streamed responses.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Hijack lets WebSocket connections take over, they are logged as switching protocols.
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.status = http.StatusSwitchingProtocols
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
 type MiddlewareLogger struct{}
 
 func NewMiddlewareLogger() *MiddlewareLogger {
@@ -49,3 +67,20 @@ func (a *MiddlewareLogger) setCommonHeadersMiddleware(next http.Handler) http.Ha
 		next.ServeHTTP(w, r)
 	})
 }
+
+// adminMiddleware lets in requests with the bearer token of an administrator, none without tokens.
+func (s *Server) adminMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if ok {
+			for _, t := range s.adminTokens {
+				if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+		}
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		srv.RespondWithError(slugerrors.NewAuthorizationError("missing or unknown bearer token", "unauthorized"), w, r)
+	})
+}
@@ -0,0 +1,143 @@
+package internalhttp
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/pricing"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) registerOrderRoutes(router *mux.Router, midLogger *MiddlewareLogger) {
+	handle := func(path string, h http.HandlerFunc, method string) {
+		router.Handle(path, midLogger.setCommonHeadersMiddleware(
+			midLogger.loggingMiddleware(h))).Methods(method)
+	}
+
+	handle("/orders", s.CreateOrder, http.MethodPost)
+	handle("/orders/{id:[0-9]+}", s.GetOrder, http.MethodGet)
+}
+
+func newOrderResponse(o models.Order) model.OrderResponse {
+	resp := model.OrderResponse{
+		ID:         o.ID,
+		EventID:    o.EventID,
+		HoldID:     o.HoldID.Int64,
+		CustomerID: o.CustomerID.Int64,
+		Status:     string(o.Status),
+		Currency:   o.Currency,
+		Subtotal:   o.Subtotal,
+		Total:      o.Total,
+		Refunded:   o.Refunded,
+		Items:      make([]model.OrderItemResponse, 0, len(o.Items)),
+		Discounts:  make([]model.DiscountResponse, 0, len(o.Discounts)),
+		CreatedAt:  o.CreatedAt.UTC(),
+	}
+	for _, item := range o.Items {
+		resp.Items = append(resp.Items, newOrderItemResponse(item))
+	}
+	for _, d := range o.Discounts {
+		resp.Discounts = append(resp.Discounts, model.DiscountResponse{
+			PromotionID: d.PromotionID,
+			Code:        d.Code,
+			Name:        d.Name,
+			Description: d.Description,
+			Amount:      d.Amount,
+		})
+	}
+	return resp
+}
+
+func newOrderItemResponse(item models.OrderItem) model.OrderItemResponse {
+	resp := model.OrderItemResponse{
+		ID:              item.ID,
+		EventID:         item.EventID,
+		PlaceID:         item.PlaceID,
+		PriceCategoryID: item.PriceCategoryID.Int64,
+		Amount:          item.Amount,
+		Discount:        item.Discount,
+		Status:          string(item.Status),
+		ExchangedFrom:   item.ExchangedFrom.Int64,
+	}
+	if item.UsedAt.Valid {
+		usedAt := item.UsedAt.Time.UTC()
+		resp.UsedAt = &usedAt
+	}
+	return resp
+}
+
+// respondOrderError reports holds that can't be sold as conflicts.
+func respondOrderError(err error, w http.ResponseWriter, r *http.Request) {
+	if respondPromoError(err, w, r) {
+		return
+	}
+	switch {
+	case errors.Is(err, model.ErrUnavailable):
+		srv.RespondWithError(slugerrors.NewConflictError("hold is released or expired", "hold-unavailable"), w, r)
+	case errors.Is(err, pricing.ErrUnpriced):
+		srv.RespondWithError(slugerrors.NewConflictError("held places have no price", "places-unpriced").Wrap(err), w, r)
+	case errors.Is(err, model.ErrInvalidUserID):
+		respondCustomerError(err, w, r)
+	case errors.Is(err, pricing.ErrMixedCurrencies):
+		srv.RespondWithError(slugerrors.NewConflictError("held places are priced in different currencies",
+			"mixed-currencies"), w, r)
+	default:
+		respondStorageError("hold", err, w, r)
+	}
+}
+
+// @Summary Create order
+// @Tags orders
+// @Description Sell the places of an active hold at the prices of their categories less the promotions
+// @ID create-order
+// @Accept  json
+// @Produce  json
+// @Param order body model.OrderRequest true "hold to sell and promo codes"
+// @Success 201 {object} model.OrderResponse
+// @Failure 400,404,409,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /orders [post].
+func (s *Server) CreateOrder(w http.ResponseWriter, r *http.Request) {
+	var req model.OrderRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	order, err := s.app.CreateOrder(r.Context(), models.Checkout{
+		HoldID:     req.HoldID,
+		PromoCodes: req.PromoCodes,
+		CustomerID: req.CustomerID,
+	})
+	if err != nil {
+		respondOrderError(err, w, r)
+		return
+	}
+	srv.RespondCreated(newOrderResponse(order), w, r)
+}
+
+// @Summary Get order
+// @Tags orders
+// @ID get-order
+// @Produce  json
+// @Param id path int true "order ID"
+// @Success 200 {object} model.OrderResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /orders/{id} [get].
+func (s *Server) GetOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	order, err := s.app.GetOrder(r.Context(), id)
+	if err != nil {
+		respondStorageError("order", err, w, r)
+		return
+	}
+	srv.RespondOK(newOrderResponse(order), w, r)
+}
@@ -0,0 +1,219 @@
+package internalhttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/money"
+	"github.com/cronnoss/tk-api/internal/pricing"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) registerPriceRoutes(router *mux.Router, midLogger *MiddlewareLogger) {
+	handle := func(path string, h http.HandlerFunc, method string) {
+		router.Handle(path, midLogger.setCommonHeadersMiddleware(
+			midLogger.loggingMiddleware(h))).Methods(method)
+	}
+
+	handle("/events/{id:[0-9]+}/prices", s.CreatePriceCategory, http.MethodPost)
+	handle("/events/{id:[0-9]+}/prices", s.ListPriceCategories, http.MethodGet)
+	handle("/events/{id:[0-9]+}/price-map", s.AssignPrices, http.MethodPut)
+	handle("/prices/{id:[0-9]+}", s.UpdatePriceCategory, http.MethodPut)
+	handle("/prices/{id:[0-9]+}", s.DeletePriceCategory, http.MethodDelete)
+}
+
+func newPriceCategoryResponse(c models.PriceCategory) model.PriceCategoryResponse {
+	return model.PriceCategoryResponse{
+		ID:       c.ID,
+		EventID:  c.EventID,
+		Name:     c.Name,
+		Amount:   c.Amount,
+		Currency: c.Currency,
+		Display:  money.Format(c.Amount, c.Currency),
+	}
+}
+
+// pricedPlaceResponses converts places of the event adding their prices.
+func (s *Server) pricedPlaceResponses(ctx context.Context, eventID int64, places []models.Place,
+) ([]model.PlaceResponse, error) {
+	categories, err := s.app.GetPriceCategories(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	byID := pricing.NewCategories(categories)
+	resp := newPlaceResponses(places)
+	for i, p := range places {
+		resp[i].Price = newPlacePriceResponse(p, byID)
+	}
+	return resp, nil
+}
+
+func newPlacePriceResponse(p models.Place, categories pricing.Categories) *model.PlacePriceResponse {
+	c, ok := categories.Of(p)
+	if !ok {
+		return nil
+	}
+	return &model.PlacePriceResponse{CategoryID: c.ID, Name: c.Name, Amount: c.Amount, Currency: c.Currency}
+}
+
+// @Summary Create price category
+// @Tags prices
+// @Description Create a price tier of the event, the amount is in minor units of the ISO 4217 currency
+// @ID create-price-category
+// @Accept  json
+// @Produce  json
+// @Param id path int true "event ID"
+// @Param category body model.PriceCategoryRequest true "price category"
+// @Success 201 {object} model.PriceCategoryResponse
+// @Failure 400,404,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/prices [post].
+func (s *Server) CreatePriceCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req model.PriceCategoryRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	category, err := s.app.CreatePriceCategory(r.Context(), models.PriceCategory{
+		EventID:  id,
+		Name:     req.Name,
+		Amount:   req.Amount,
+		Currency: req.Currency,
+	})
+	if err != nil {
+		respondStorageError("event", err, w, r)
+		return
+	}
+	setETag(newPriceCategoryResponse(category), w)
+	srv.RespondCreated(newPriceCategoryResponse(category), w, r)
+}
+
+// @Summary List price categories
+// @Tags prices
+// @ID list-price-categories
+// @Produce  json
+// @Param id path int true "event ID"
+// @Success 200 {array} model.PriceCategoryResponse
+// @Failure 400 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/prices [get].
+func (s *Server) ListPriceCategories(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	categories, err := s.app.GetPriceCategories(r.Context(), id)
+	if err != nil {
+		respondStorageError("price-category", err, w, r)
+		return
+	}
+	resp := make([]model.PriceCategoryResponse, 0, len(categories))
+	for _, c := range categories {
+		resp = append(resp, newPriceCategoryResponse(c))
+	}
+	srv.RespondOK(resp, w, r)
+}
+
+// @Summary Update price category
+// @Tags prices
+// @ID update-price-category
+// @Accept  json
+// @Produce  json
+// @Param id path int true "price category ID"
+// @Param category body model.PriceCategoryRequest true "price category"
+// @Param If-Match header string false "ETag of the price category read last"
+// @Success 200 {object} model.PriceCategoryResponse
+// @Failure 400,404,412,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /prices/{id} [put].
+func (s *Server) UpdatePriceCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req model.PriceCategoryRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	category, err := s.app.UpdatePriceCategory(r.Context(), models.PriceCategory{
+		ID:       id,
+		Name:     req.Name,
+		Amount:   req.Amount,
+		Currency: req.Currency,
+	}, ifMatch(r, newPriceCategoryResponse))
+	if err != nil {
+		respondStorageError("price-category", err, w, r)
+		return
+	}
+	respondConditional(newPriceCategoryResponse(category), category.ModifiedAt(), w, r)
+}
+
+// @Summary Delete price category
+// @Tags prices
+// @Description Delete a price category, its places become unpriced
+// @ID delete-price-category
+// @Param id path int true "price category ID"
+// @Success 204
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /prices/{id} [delete].
+func (s *Server) DeletePriceCategory(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.app.DeletePriceCategory(r.Context(), id); err != nil {
+		respondStorageError("price-category", err, w, r)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Assign prices to places
+// @Tags prices
+// @Description Put places and whole sections of the event into price categories, assignments apply in order
+// @ID assign-prices
+// @Accept  json
+// @Produce  json
+// @Param id path int true "event ID"
+// @Param map body model.PriceMapRequest true "price map"
+// @Param If-Match header string false "ETag of the event read last"
+// @Success 200 {object} model.PriceMapResponse
+// @Failure 400,404,412,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/price-map [put].
+func (s *Server) AssignPrices(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req model.PriceMapRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	assignments := make([]models.PriceAssignment, 0, len(req))
+	for _, a := range req {
+		assignments = append(assignments, models.PriceAssignment{
+			CategoryID: a.CategoryID,
+			PlaceIDs:   a.PlaceIDs,
+			SectionIDs: a.SectionIDs,
+		})
+	}
+	n, err := s.app.AssignPrices(r.Context(), id, assignments, ifMatch(r, newEventResponse))
+	if err != nil {
+		respondStorageError("price-category", err, w, r)
+		return
+	}
+	srv.RespondOK(model.PriceMapResponse{Updated: n}, w, r)
+}
@@ -0,0 +1,266 @@
+package internalhttp
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/pricing"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) registerPromotionRoutes(router *mux.Router, midLogger *MiddlewareLogger) {
+	handle := func(path string, h http.HandlerFunc, method string) {
+		router.Handle(path, midLogger.setCommonHeadersMiddleware(
+			midLogger.loggingMiddleware(h))).Methods(method)
+	}
+
+	handle("/promotions", s.CreatePromotion, http.MethodPost)
+	handle("/promotions", s.ListPromotions, http.MethodGet)
+	handle("/promotions/{id:[0-9]+}", s.GetPromotion, http.MethodGet)
+	handle("/promotions/{id:[0-9]+}", s.DeactivatePromotion, http.MethodDelete)
+	handle("/holds/{id:[0-9]+}/quote", s.QuoteHold, http.MethodPost)
+}
+
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func newPromotionResponse(p models.Promotion) model.PromotionResponse {
+	resp := model.PromotionResponse{
+		PromotionRequest: model.PromotionRequest{
+			Code:               p.Code,
+			Name:               p.Name,
+			Kind:               string(p.Kind),
+			Percent:            p.Percent,
+			Amount:             p.Amount,
+			Currency:           p.Currency,
+			BuyN:               p.BuyN,
+			GetM:               p.GetM,
+			ShowID:             p.ShowID.Int64,
+			EventID:            p.EventID.Int64,
+			PriceCategoryID:    p.PriceCategoryID.Int64,
+			MaxUses:            p.MaxUses,
+			MaxUsesPerCustomer: p.MaxUsesPerCustomer,
+		},
+		ID:        p.ID,
+		Uses:      p.Uses,
+		Active:    p.Active,
+		CreatedAt: p.CreatedAt.UTC(),
+	}
+	if p.ValidFrom.Valid {
+		from := p.ValidFrom.Time.UTC()
+		resp.ValidFrom = &from
+	}
+	if p.ValidTo.Valid {
+		to := p.ValidTo.Time.UTC()
+		resp.ValidTo = &to
+	}
+	return resp
+}
+
+func newDiscountResponses(discounts []pricing.Discount) []model.DiscountResponse {
+	resp := make([]model.DiscountResponse, 0, len(discounts))
+	for _, d := range discounts {
+		resp = append(resp, model.DiscountResponse{
+			PromotionID: d.PromotionID,
+			Code:        d.Code,
+			Name:        d.Name,
+			Description: d.Description,
+			Amount:      d.Amount,
+		})
+	}
+	return resp
+}
+
+func newQuoteResponse(q pricing.Quote) model.QuoteResponse {
+	resp := model.QuoteResponse{
+		Currency:  q.Currency,
+		Subtotal:  q.Subtotal,
+		Total:     q.Total,
+		Lines:     make([]model.QuoteLineResponse, 0, len(q.Lines)),
+		Discounts: newDiscountResponses(q.Discounts),
+	}
+	for _, l := range q.Lines {
+		resp.Lines = append(resp.Lines, model.QuoteLineResponse{
+			PlaceID:         l.PlaceID,
+			PriceCategoryID: l.CategoryID,
+			Amount:          l.Amount,
+			Discount:        l.Discount,
+		})
+	}
+	return resp
+}
+
+// respondPromoError reports rejected promo codes as validation errors and exhausted ones as conflicts.
+func respondPromoError(err error, w http.ResponseWriter, r *http.Request) bool {
+	var perr *pricing.PromoError
+	switch {
+	case errors.Is(err, pricing.ErrPromoLimit), errors.Is(err, model.ErrLimitReached):
+		srv.RespondWithError(slugerrors.NewConflictError("promo code usage limit reached",
+			"promo-limit-reached").Wrap(err), w, r)
+	case errors.As(err, &perr):
+		srv.RespondWithError(slugerrors.NewValidationError(perr.Error(), "promo-code-rejected").Wrap(err), w, r)
+	default:
+		return false
+	}
+	return true
+}
+
+// @Summary Create promotion
+// @Tags promotions
+// @Description Create a discount rule, promotions without a code apply to every order in their scope
+// @ID create-promotion
+// @Accept  json
+// @Produce  json
+// @Param promotion body model.PromotionRequest true "promotion"
+// @Success 201 {object} model.PromotionResponse
+// @Failure 400,409,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /promotions [post].
+func (s *Server) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	var req model.PromotionRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	promotion, err := s.app.CreatePromotion(r.Context(), models.Promotion{
+		Code:               req.Code,
+		Name:               req.Name,
+		Kind:               models.PromotionKind(req.Kind),
+		Percent:            req.Percent,
+		Amount:             req.Amount,
+		Currency:           req.Currency,
+		BuyN:               req.BuyN,
+		GetM:               req.GetM,
+		ShowID:             nullID(req.ShowID),
+		EventID:            nullID(req.EventID),
+		PriceCategoryID:    nullID(req.PriceCategoryID),
+		ValidFrom:          nullTime(req.ValidFrom),
+		ValidTo:            nullTime(req.ValidTo),
+		MaxUses:            req.MaxUses,
+		MaxUsesPerCustomer: req.MaxUsesPerCustomer,
+	})
+	if errors.Is(err, model.ErrAlreadyExists) {
+		srv.RespondWithError(slugerrors.NewConflictError("promo code is taken", "promo-code-taken"), w, r)
+		return
+	}
+	if err != nil {
+		respondStorageError("promotion", err, w, r)
+		return
+	}
+	srv.RespondCreated(newPromotionResponse(promotion), w, r)
+}
+
+// @Summary List promotions
+// @Tags promotions
+// @ID list-promotions
+// @Produce  json
+// @Success 200 {array} model.PromotionResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /promotions [get].
+func (s *Server) ListPromotions(w http.ResponseWriter, r *http.Request) {
+	promotions, err := s.app.GetPromotions(r.Context())
+	if err != nil {
+		respondStorageError("promotion", err, w, r)
+		return
+	}
+	resp := make([]model.PromotionResponse, 0, len(promotions))
+	for _, p := range promotions {
+		resp = append(resp, newPromotionResponse(p))
+	}
+	srv.RespondOK(resp, w, r)
+}
+
+// @Summary Get promotion
+// @Tags promotions
+// @ID get-promotion
+// @Produce  json
+// @Param id path int true "promotion ID"
+// @Success 200 {object} model.PromotionResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /promotions/{id} [get].
+func (s *Server) GetPromotion(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	promotion, err := s.app.GetPromotion(r.Context(), id)
+	if err != nil {
+		respondStorageError("promotion", err, w, r)
+		return
+	}
+	srv.RespondOK(newPromotionResponse(promotion), w, r)
+}
+
+// @Summary Deactivate promotion
+// @Tags promotions
+// @Description Stop a promotion, its redemptions are kept
+// @ID deactivate-promotion
+// @Produce  json
+// @Param id path int true "promotion ID"
+// @Success 200 {object} model.PromotionResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /promotions/{id} [delete].
+func (s *Server) DeactivatePromotion(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	promotion, err := s.app.DeactivatePromotion(r.Context(), id)
+	if err != nil {
+		respondStorageError("promotion", err, w, r)
+		return
+	}
+	srv.RespondOK(newPromotionResponse(promotion), w, r)
+}
+
+// @Summary Quote hold
+// @Tags promotions
+// @Description Price the places of an active hold with promo codes, explaining the discounts applied
+// @ID quote-hold
+// @Accept  json
+// @Produce  json
+// @Param id path int true "hold ID"
+// @Param quote body model.QuoteRequest true "promo codes"
+// @Success 200 {object} model.QuoteResponse
+// @Failure 400,404,409,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /holds/{id}/quote [post].
+func (s *Server) QuoteHold(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req model.QuoteRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	quote, err := s.app.QuoteHold(r.Context(), models.Checkout{
+		HoldID:     id,
+		PromoCodes: req.PromoCodes,
+		CustomerID: req.CustomerID,
+	})
+	if err != nil {
+		respondOrderError(err, w, r)
+		return
+	}
+	srv.RespondOK(newQuoteResponse(quote), w, r)
+}
@@ -0,0 +1,115 @@
+package internalhttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/common/validation"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/cronnoss/tk-api/internal/upstream"
+)
+
+// InvalidItemsHeader reports the number of upstream items left out of a list response.
+const InvalidItemsHeader = "X-Invalid-Items"
+
+// reportInvalidItems sets InvalidItemsHeader if any upstream items were left out.
+func reportInvalidItems(w http.ResponseWriter, invalid []model.InvalidItem) {
+	if len(invalid) > 0 {
+		w.Header().Set(InvalidItemsHeader, strconv.Itoa(len(invalid)))
+	}
+}
+
+// handleInvalidItems applies the invalid item policy to upstream items that failed validation.
+// It returns an error only if the whole batch has to be rejected. Items of cached responses were
+// skipped or quarantined when they were fetched.
+func (s *Server) handleInvalidItems(ctx context.Context, entity string, res upstream.Response,
+	invalid []model.InvalidItem,
+) error {
+	if len(invalid) == 0 || (res.Cached && s.invalidItems != model.PolicyReject) {
+		return nil
+	}
+	source := res.URL
+
+	switch s.invalidItems {
+	case model.PolicySkip:
+		s.log.Warningf("skipped %d invalid %s items from %s\n", len(invalid), entity, source)
+		return nil
+	case model.PolicyQuarantine:
+		items := make([]models.QuarantinedItem, 0, len(invalid))
+		for _, item := range invalid {
+			payload, err := json.Marshal(item.Item)
+			if err != nil {
+				return fmt.Errorf("failed to encode quarantined %s: %w", entity, err)
+			}
+			errs, err := json.Marshal(item.Errors)
+			if err != nil {
+				return fmt.Errorf("failed to encode quarantined %s errors: %w", entity, err)
+			}
+			items = append(items, models.QuarantinedItem{
+				Entity:  entity,
+				Source:  source,
+				Payload: payload,
+				Errors:  errs,
+			})
+		}
+		if _, err := s.app.QuarantineItems(ctx, items); err != nil {
+			return fmt.Errorf("failed to quarantine %s items: %w", entity, err)
+		}
+		s.log.Warningf("quarantined %d invalid %s items from %s\n", len(invalid), entity, source)
+		return nil
+	default:
+		var errs validation.Errors
+		for _, item := range invalid {
+			errs = append(errs, item.Errors...)
+		}
+		return slugerrors.NewBadGatewayError(
+			fmt.Sprintf("upstream returned invalid %ss", entity), "invalid-upstream-response").Wrap(errs)
+	}
+}
+
+type QuarantinedItemResponse struct {
+	ID        int64           `json:"id"`
+	Entity    string          `json:"entity"`
+	Source    string          `json:"source"`
+	Item      json.RawMessage `json:"item"`
+	Errors    json.RawMessage `json:"errors"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// @Summary Get quarantined items
+// @Tags quarantine
+// @Security BearerAuth
+// @Description Get upstream items that failed validation and were quarantined for review
+// @ID get-quarantined-items
+// @Produce  json
+// @Success 200 {array} QuarantinedItemResponse
+// @Failure 401 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /quarantine [get].
+func (s *Server) GetQuarantinedItems(w http.ResponseWriter, r *http.Request) {
+	items, err := s.app.GetQuarantinedItems(r.Context())
+	if err != nil {
+		srv.RespondWithError(fmt.Errorf("failed to get quarantined items: %w", err), w, r)
+		return
+	}
+
+	resp := make([]QuarantinedItemResponse, 0, len(items))
+	for _, item := range items {
+		resp = append(resp, QuarantinedItemResponse{
+			ID:        item.ID,
+			Entity:    item.Entity,
+			Source:    item.Source,
+			Item:      item.Payload,
+			Errors:    item.Errors,
+			CreatedAt: item.CreatedAt,
+		})
+	}
+	srv.RespondOK(resp, w, r)
+}
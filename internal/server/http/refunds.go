@@ -0,0 +1,166 @@
+package internalhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/pricing"
+	"github.com/cronnoss/tk-api/internal/refunds"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) registerRefundRoutes(router *mux.Router, midLogger *MiddlewareLogger) {
+	handle := func(path string, h http.HandlerFunc, method string) {
+		router.Handle(path, midLogger.setCommonHeadersMiddleware(
+			midLogger.loggingMiddleware(h))).Methods(method)
+	}
+
+	handle("/orders/{id:[0-9]+}/refunds", s.RefundOrder, http.MethodPost)
+	handle("/orders/{id:[0-9]+}/exchanges", s.ExchangeOrderItems, http.MethodPost)
+	handle("/orders/{id:[0-9]+}/audit", s.GetOrderAudit, http.MethodGet)
+}
+
+// respondRefundError reports items and places that can't be refunded or exchanged as conflicts.
+func respondRefundError(err error, w http.ResponseWriter, r *http.Request) {
+	switch {
+	case errors.Is(err, refunds.ErrDeadlinePassed):
+		srv.RespondWithError(slugerrors.NewConflictError("refunds and exchanges are closed for the event",
+			"refund-deadline-passed"), w, r)
+	case errors.Is(err, model.ErrUnavailable):
+		srv.RespondWithError(slugerrors.NewConflictError("items are not issued or places are taken",
+			"items-unavailable"), w, r)
+	case errors.Is(err, model.ErrInvalidShowIDs):
+		srv.RespondWithError(slugerrors.NewValidationError("event is of another show",
+			"invalid-show").Wrap(err), w, r)
+	case errors.Is(err, model.ErrInvalidValue):
+		srv.RespondWithError(slugerrors.NewValidationError("every item needs one place",
+			"invalid-places").Wrap(err), w, r)
+	case errors.Is(err, pricing.ErrUnpriced):
+		srv.RespondWithError(slugerrors.NewConflictError("places have no price", "places-unpriced").Wrap(err), w, r)
+	case errors.Is(err, pricing.ErrMixedCurrencies):
+		srv.RespondWithError(slugerrors.NewConflictError("places are priced in another currency",
+			"mixed-currencies"), w, r)
+	default:
+		respondStorageError("order", err, w, r)
+	}
+}
+
+// @Summary Refund order
+// @Tags orders
+// @Description Refund issued tickets of an order, all of them if no items are given, less the policy fees.
+// @Description Their places go back on sale. Refunds close at the policy deadline before the event.
+// @ID refund-order
+// @Accept  json
+// @Produce  json
+// @Param id path int true "order ID"
+// @Param refund body model.RefundRequest true "items to refund"
+// @Success 201 {object} model.RefundResponse
+// @Failure 400,404,409,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /orders/{id}/refunds [post].
+func (s *Server) RefundOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req model.RefundRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	refund, err := s.app.RefundOrder(r.Context(), id, req.ItemIDs, req.Reason)
+	if err != nil {
+		respondRefundError(err, w, r)
+		return
+	}
+	srv.RespondCreated(model.RefundResponse{
+		ID:        refund.ID,
+		OrderID:   refund.OrderID,
+		ItemIDs:   refund.ItemIDs,
+		Amount:    refund.Amount,
+		Fee:       refund.Fee,
+		Reason:    refund.Reason,
+		CreatedAt: refund.CreatedAt.UTC(),
+	}, w, r)
+}
+
+// @Summary Exchange order items
+// @Tags orders
+// @Description Exchange issued tickets of an order for available places of another event of the same show,
+// @Description priced at their categories. Exchanges close at the refund policy deadline.
+// @ID exchange-order-items
+// @Accept  json
+// @Produce  json
+// @Param id path int true "order ID"
+// @Param exchange body model.ExchangeRequest true "items and the places to exchange them for"
+// @Success 201 {object} model.ExchangeResponse
+// @Failure 400,404,409,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /orders/{id}/exchanges [post].
+func (s *Server) ExchangeOrderItems(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req model.ExchangeRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	exchange, err := s.app.ExchangeOrderItems(r.Context(), id, req.ItemIDs, req.EventID, req.PlaceIDs)
+	if err != nil {
+		respondRefundError(err, w, r)
+		return
+	}
+	resp := model.ExchangeResponse{
+		ID:         exchange.ID,
+		OrderID:    exchange.OrderID,
+		EventID:    exchange.EventID,
+		ItemIDs:    exchange.ItemIDs,
+		Items:      make([]model.OrderItemResponse, 0, len(exchange.Items)),
+		Difference: exchange.Difference,
+		Fee:        exchange.Fee,
+		CreatedAt:  exchange.CreatedAt.UTC(),
+	}
+	for _, item := range exchange.Items {
+		resp.Items = append(resp.Items, newOrderItemResponse(item))
+	}
+	srv.RespondCreated(resp, w, r)
+}
+
+// @Summary Get order audit trail
+// @Tags orders
+// @Description Lifecycle of an order oldest first: creation, refunds and exchanges
+// @ID get-order-audit
+// @Produce  json
+// @Param id path int true "order ID"
+// @Success 200 {array} model.AuditRecordResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /orders/{id}/audit [get].
+func (s *Server) GetOrderAudit(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	records, err := s.app.GetOrderAudit(r.Context(), id)
+	if err != nil {
+		respondStorageError("order", err, w, r)
+		return
+	}
+	resp := make([]model.AuditRecordResponse, 0, len(records))
+	for _, rec := range records {
+		resp = append(resp, model.AuditRecordResponse{
+			ID:        rec.ID,
+			Action:    rec.Action,
+			Details:   json.RawMessage(rec.Details),
+			CreatedAt: rec.CreatedAt.UTC(),
+		})
+	}
+	srv.RespondOK(resp, w, r)
+}
@@ -0,0 +1,53 @@
+package internalhttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/gorilla/mux"
+)
+
+type validatedRequest interface {
+	Validate() error
+}
+
+// decodeRequest decodes the JSON request body into req and validates it.
+// It responds to the client and returns false on failure.
+func (s *Server) decodeRequest(w http.ResponseWriter, r *http.Request, req validatedRequest) bool {
+	if err := s.helperDecode(w, r, req); err != nil {
+		return false
+	}
+	if err := req.Validate(); err != nil {
+		srv.RespondWithError(slugerrors.NewValidationError("invalid request", "invalid-request").Wrap(err), w, r)
+		return false
+	}
+	return true
+}
+
+// pathID returns the ID path variable, responding to the client and returning false on failure.
+func pathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		srv.RespondWithError(slugerrors.NewBadRequestError("invalid ID", "invalid-id"), w, r)
+		return 0, false
+	}
+	return id, true
+}
+
+// storageError turns model.ErrNotFound into a not-found error named after entity.
+func storageError(entity string, err error) error {
+	if errors.Is(err, model.ErrNotFound) {
+		return slugerrors.NewNotFoundError(entity+" not found", entity+"-not-found")
+	}
+	return fmt.Errorf("failed to process %s: %w", entity, err)
+}
+
+// respondStorageError turns model.ErrNotFound into a not-found problem named after entity.
+func respondStorageError(entity string, err error, w http.ResponseWriter, r *http.Request) {
+	srv.RespondWithError(storageError(entity, err), w, r)
+}
@@ -0,0 +1,159 @@
+package internalhttp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/common/validation"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/seatmap"
+	"github.com/cronnoss/tk-api/internal/server"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+// maxSeatMapSize bounds the rendered image, a PNG of it is held in memory at 4 bytes per pixel.
+const maxSeatMapSize = 2048
+
+// seatMapQuery is the parsed query of seat map requests.
+type seatMapQuery struct {
+	opts seatmap.Options
+	// bbox limits the map to a viewport if set.
+	bbox *models.Rect
+	// prices colours available places by price category.
+	prices bool
+}
+
+func parseSeatMapOptions(r *http.Request) (seatMapQuery, error) {
+	var (
+		query seatMapQuery
+		opts  = &query.opts
+		v     validation.Validator
+		err   error
+	)
+	q := r.URL.Query()
+	if legend := q.Get("legend"); legend != "" {
+		opts.Legend, err = strconv.ParseBool(legend)
+		v.Check(err == nil, validation.Pointer("legend"), model.ErrInvalidValue)
+	}
+	if size := q.Get("size"); size != "" {
+		opts.Size, err = strconv.Atoi(size)
+		v.Check(err == nil && opts.Size > 0 && opts.Size <= maxSeatMapSize, validation.Pointer("size"), model.ErrInvalidValue)
+	}
+	if selected := q.Get("selected"); selected != "" {
+		opts.Selected = make(map[int64]bool)
+		for i, s := range strings.Split(selected, ",") {
+			id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+			v.Check(err == nil && id > 0, validation.Pointer("selected", i), server.ErrID)
+			opts.Selected[id] = true
+		}
+	}
+	if b := q.Get("bbox"); b != "" {
+		rect, err := parseRect(b)
+		v.Check(err == nil, validation.Pointer("bbox"), model.ErrInvalidValue)
+		query.bbox = &rect
+	}
+	if prices := q.Get("prices"); prices != "" {
+		query.prices, err = strconv.ParseBool(prices)
+		v.Check(err == nil, validation.Pointer("prices"), model.ErrInvalidValue)
+	}
+	if err := v.Err(); err != nil {
+		return query, slugerrors.NewBadRequestError("invalid seat map options", "invalid-seatmap-options").Wrap(err)
+	}
+	return query, nil
+}
+
+// @Summary Get event seat map as SVG
+// @Tags places
+// @Description Draw event places coloured by status, optionally with a legend and outlined selected places
+// @ID get-seatmap-svg
+// @Produce  image/svg+xml
+// @Param id path int true "event ID"
+// @Param legend query bool false "draw legend"
+// @Param selected query string false "comma separated place IDs to outline"
+// @Param size query int false "larger map dimension in pixels, up to 2048"
+// @Param bbox query string false "draw only places intersecting minX,minY,maxX,maxY"
+// @Param prices query bool false "colour available places by price category"
+// @Success 200 {file} file
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/seatmap.svg [get].
+func (s *Server) GetSeatMapSVG(w http.ResponseWriter, r *http.Request) {
+	s.renderSeatMap(w, r, "image/svg+xml", seatmap.RenderSVG)
+}
+
+// @Summary Get event seat map as PNG
+// @Tags places
+// @Description Draw event places coloured by status, optionally with a legend and outlined selected places
+// @ID get-seatmap-png
+// @Produce  image/png
+// @Param id path int true "event ID"
+// @Param legend query bool false "draw legend"
+// @Param selected query string false "comma separated place IDs to outline"
+// @Param size query int false "larger map dimension in pixels, up to 2048"
+// @Param bbox query string false "draw only places intersecting minX,minY,maxX,maxY"
+// @Param prices query bool false "colour available places by price category"
+// @Success 200 {file} file
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/seatmap.png [get].
+func (s *Server) GetSeatMapPNG(w http.ResponseWriter, r *http.Request) {
+	s.renderSeatMap(w, r, "image/png", seatmap.RenderPNG)
+}
+
+func (s *Server) renderSeatMap(w http.ResponseWriter, r *http.Request, contentType string,
+	render func(io.Writer, []seatmap.Seat, seatmap.Options) error,
+) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	query, err := parseSeatMapOptions(r)
+	if err != nil {
+		srv.RespondWithError(err, w, r)
+		return
+	}
+
+	var places []models.Place
+	if query.bbox != nil {
+		places, err = s.app.GetPlacesInRect(r.Context(), id, *query.bbox)
+	} else {
+		places, err = s.app.GetPlaces(r.Context(), models.PlaceFilter{EventID: id})
+	}
+	if err != nil {
+		srv.RespondWithError(fmt.Errorf("failed to get places: %w", err), w, r)
+		return
+	}
+	if len(places) == 0 {
+		srv.RespondWithError(slugerrors.NewNotFoundError("event has no places", "seatmap-not-found"), w, r)
+		return
+	}
+
+	seats := seatmap.SeatsFromPlaces(places)
+	categories, err := s.app.GetPriceCategories(r.Context(), id)
+	if err != nil {
+		srv.RespondWithError(fmt.Errorf("failed to get prices: %w", err), w, r)
+		return
+	}
+	zones := seatmap.PriceZones(seats, categories)
+	if query.prices {
+		query.opts.Zones = zones
+	}
+
+	// Render into a buffer first so a failure can still be reported as a problem.
+	var b bytes.Buffer
+	if err := render(&b, seats, query.opts); err != nil {
+		srv.RespondWithError(fmt.Errorf("failed to render seat map: %w", err), w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(b.Len()))
+	w.WriteHeader(http.StatusOK)
+	_, _ = b.WriteTo(w)
+}
@@ -2,18 +2,23 @@ package internalhttp
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"expvar"
 	"fmt"
-	"io"
 	"net"
 	"net/http"
 	"time"
 
 	_ "github.com/cronnoss/tickets-api/docs" // nolint: revive
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
 	"github.com/cronnoss/tk-api/internal/common/srv"
 	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/pricing"
 	"github.com/cronnoss/tk-api/internal/server"
+	internalgraphql "github.com/cronnoss/tk-api/internal/server/graphql"
 	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/cronnoss/tk-api/internal/upstream"
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
@@ -25,11 +30,61 @@ const (
 )
 
 type Server struct {
-	srv  http.Server
-	app  server.Application
-	log  Logger
-	host string
-	port string
+	srv          http.Server
+	app          server.Application
+	log          Logger
+	host         string
+	port         string
+	invalidItems model.InvalidItemPolicy
+	timeZone     *time.Location
+	// placesSyncInterval is how often places of watched upstream events are refetched.
+	placesSyncInterval time.Duration
+	graphql            []internalgraphql.Option
+	upstream           upstream.Provider
+	// adminTokens let in administrators, admin routes are closed if there are none.
+	adminTokens []string
+}
+
+type Option func(*Server)
+
+// WithInvalidItemPolicy sets how upstream items failing validation are handled.
+func WithInvalidItemPolicy(policy model.InvalidItemPolicy) Option {
+	return func(s *Server) {
+		if policy != "" {
+			s.invalidItems = policy
+		}
+	}
+}
+
+// WithPlacesSyncInterval makes the server refetch places of upstream events with live availability
+// subscribers every interval.
+func WithPlacesSyncInterval(interval time.Duration) Option {
+	return func(s *Server) {
+		s.placesSyncInterval = interval
+	}
+}
+
+// WithUpstream sets the provider of upstream shows, events and places, such as a caching one.
+func WithUpstream(p upstream.Provider) Option {
+	return func(s *Server) {
+		if p != nil {
+			s.upstream = p
+		}
+	}
+}
+
+// WithGraphQLLimits bounds the depth and complexity of /graphql queries, zero keeps the default.
+func WithGraphQLLimits(maxDepth, maxComplexity int) Option {
+	return func(s *Server) {
+		s.graphql = append(s.graphql, internalgraphql.WithLimits(maxDepth, maxComplexity))
+	}
+}
+
+// WithAdminTokens opens admin routes, such as webhooks, to clients presenting one of the bearer tokens.
+func WithAdminTokens(tokens []string) Option {
+	return func(s *Server) {
+		s.adminTokens = tokens
+	}
 }
 
 type Logger interface {
@@ -40,16 +95,32 @@ type Logger interface {
 	Debugf(format string, a ...interface{})
 }
 
-func NewServer(log Logger, app server.Application, host, port string) *Server {
-	return &Server{log: log, app: app, host: host, port: port}
+// WithTimeZone sets the venue time zone for upstream event dates without a UTC offset.
+func WithTimeZone(loc *time.Location) Option {
+	return func(s *Server) {
+		if loc != nil {
+			s.timeZone = loc
+		}
+	}
+}
+
+func NewServer(log Logger, app server.Application, host, port string, opts ...Option) *Server {
+	s := &Server{
+		log: log, app: app, host: host, port: port, invalidItems: model.PolicyReject, timeZone: time.UTC,
+		upstream: upstream.NewClient(upstream.DefaultURL, http.DefaultClient),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-func (s *Server) helperDecode(stream io.ReadCloser, w http.ResponseWriter, data interface{}) error { // nolint: unused
-	decoder := json.NewDecoder(stream)
-	if err := decoder.Decode(&data); err != nil {
+func (s *Server) helperDecode(w http.ResponseWriter, r *http.Request, data interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(data); err != nil {
 		s.log.Errorf("Can't decode json:%v\n", err)
-		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(fmt.Sprintf("{\"error\": \"Can't decode json:%v\"}\n", err)))
+		srv.RespondWithError(slugerrors.NewBadRequestError(
+			fmt.Sprintf("Can't decode json:%v", err), "invalid-json"), w, r)
 		return err
 	}
 	return nil
@@ -61,45 +132,42 @@ func (s *Server) helperDecode(stream io.ReadCloser, w http.ResponseWriter, data
 // @ID get-shows
 // @Accept  json
 // @Produce  json
-// @Success 200 {object} ShowListResponse
+// @Param If-None-Match header string false "ETag of a kept response"
+// @Param If-Modified-Since header string false "Last-Modified of a kept response"
+// @Success 200 {array} model.ShowResponse
+// @Header 200 {integer} X-Invalid-Items "number of invalid upstream items left out"
+// @Success 304 "not modified"
 // @Failure 400,404 {object} server.ErrorResponse
 // @Failure 500 {object} server.ErrorResponse
+// @Failure 502 {object} server.ErrorResponse
 // @Router /shows [get].
 func (s *Server) GetShows(w http.ResponseWriter, r *http.Request) {
-	// Step 1: Make a GET request to the remote API
-	remoteURL := "https://leadbook.ru/test-task-api/shows"
-	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, remoteURL, nil)
+	// Step 1: Fetch shows from the remote API
+	res, err := s.upstream.Shows(r.Context())
 	if err != nil {
-		srv.RespondWithError(fmt.Errorf("failed to create request: %w", err), w, r)
+		srv.RespondWithError(err, w, r)
 		return
 	}
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		srv.RespondWithError(fmt.Errorf("failed to do request: %w", err), w, r)
-		return
-	}
-	defer resp.Body.Close()
 
 	// Step 2: Decode the response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		srv.RespondWithError(fmt.Errorf("failed to read response body: %w", err), w, r)
-		return
-	}
-
 	var showListResponse model.ShowListResponse
-	if err := json.Unmarshal(body, &showListResponse); err != nil {
+	if err := json.Unmarshal(res.Body, &showListResponse); err != nil {
 		srv.RespondWithError(fmt.Errorf("failed to decode response: %w", err), w, r)
 		return
 	}
 
-	// Step 3: Iterate over shows and store them in the local service
-	for _, show := range showListResponse.Response {
-		if err := showListResponse.ShowListResponseValidate(); err != nil {
-			srv.RespondWithError(fmt.Errorf("failed to validate response: %w", err), w, r)
-			return
+	// Step 3: Validate shows and store the valid ones in the local service
+	valid, invalid := showListResponse.Partition()
+	if err := s.handleInvalidItems(r.Context(), "show", res, invalid); err != nil {
+		srv.RespondWithError(err, w, r)
+		return
+	}
+	showListResponse.Response, showListResponse.Invalid = valid, invalid
+	for _, show := range valid {
+		// Shows of a cached response were stored when it was fetched.
+		if res.Cached {
+			break
 		}
-
 		_, err := s.app.CreateShow(r.Context(), models.Show{
 			ID:   show.ID,
 			Name: show.Name,
@@ -110,7 +178,14 @@ func (s *Server) GetShows(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	srv.RespondOK(showListResponse.Response, w, r)
+	// Step 4: Respond, Last-Modified is when the stored shows were last written
+	shows, err := s.app.GetShows(r.Context())
+	if err != nil {
+		srv.RespondWithError(fmt.Errorf("failed to get shows: %w", err), w, r)
+		return
+	}
+	reportInvalidItems(w, invalid)
+	respondConditional(showListResponse.Response, lastModified(shows...), w, r)
 }
 
 // @Summary Get events
@@ -120,51 +195,53 @@ func (s *Server) GetShows(w http.ResponseWriter, r *http.Request) {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "show ID"
-// @Success 200 {object} EventListResponse
+// @Param If-None-Match header string false "ETag of a kept response"
+// @Param If-Modified-Since header string false "Last-Modified of a kept response"
+// @Success 200 {array} model.EventResponse
+// @Header 200 {integer} X-Invalid-Items "number of invalid upstream items left out"
+// @Success 304 "not modified"
 // @Failure 400,404 {object} server.ErrorResponse
 // @Failure 500 {object} server.ErrorResponse
+// @Failure 502 {object} server.ErrorResponse
 // @Router /shows/{id}/events [get].
 func (s *Server) GetEvents(w http.ResponseWriter, r *http.Request) {
-	// Step 1: Make a GET request to the remote API
-	vars := mux.Vars(r)
-	id := vars["id"]
-	remoteURL := "https://leadbook.ru/test-task-api/shows/" + id + "/events"
-	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, remoteURL, nil)
-	if err != nil {
-		srv.RespondWithError(fmt.Errorf("failed to create request: %w", err), w, r)
-		return
-	}
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		srv.RespondWithError(fmt.Errorf("failed to do request: %w", err), w, r)
+	showID, ok := pathID(w, r)
+	if !ok {
 		return
 	}
-	defer resp.Body.Close()
 
-	// Step 2: Decode the response
-	body, err := io.ReadAll(resp.Body)
+	// Step 1: Fetch events of the show from the remote API
+	res, err := s.upstream.Events(r.Context(), showID)
 	if err != nil {
-		srv.RespondWithError(fmt.Errorf("failed to read response body: %w", err), w, r)
+		srv.RespondWithError(err, w, r)
 		return
 	}
 
+	// Step 2: Decode the response
 	var eventListResponse model.EventListResponse
-	if err := json.Unmarshal(body, &eventListResponse); err != nil {
+	if err := json.Unmarshal(res.Body, &eventListResponse); err != nil {
 		srv.RespondWithError(fmt.Errorf("failed to decode response: %w", err), w, r)
 		return
 	}
 
-	// Step 3: Iterate over events and store them in the local service
-	for _, event := range eventListResponse.Response {
-		if err := eventListResponse.EventListResponseValidate(); err != nil {
-			srv.RespondWithError(fmt.Errorf("failed to validate response: %w", err), w, r)
-			return
+	// Step 3: Validate events and store the valid ones in the local service
+	valid, invalid := eventListResponse.Partition()
+	if err := s.handleInvalidItems(r.Context(), "event", res, invalid); err != nil {
+		srv.RespondWithError(err, w, r)
+		return
+	}
+	eventListResponse.Response, eventListResponse.Invalid = valid, invalid
+	for i := range valid {
+		valid[i].Localize(s.timeZone)
+		// Events of a cached response were stored when it was fetched.
+		if res.Cached {
+			continue
 		}
-
 		_, err := s.app.CreateEvent(r.Context(), models.Event{
-			ID:     event.ID,
-			ShowID: event.ShowID,
-			Date:   event.Date,
+			ID:       valid[i].ID,
+			ShowID:   valid[i].ShowID,
+			Date:     valid[i].Date,
+			TimeZone: valid[i].TimeZone,
 		})
 		if err != nil {
 			srv.RespondWithError(fmt.Errorf("failed to create event: %w", err), w, r)
@@ -172,7 +249,14 @@ func (s *Server) GetEvents(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	srv.RespondOK(eventListResponse.Response, w, r)
+	// Step 4: Respond, Last-Modified is when the stored events of the show were last written
+	events, err := s.app.GetEvents(r.Context(), models.EventFilter{ShowID: showID})
+	if err != nil {
+		srv.RespondWithError(fmt.Errorf("failed to get events: %w", err), w, r)
+		return
+	}
+	reportInvalidItems(w, invalid)
+	respondConditional(eventListResponse.Response, lastModified(events...), w, r)
 }
 
 // @Summary Get places
@@ -182,49 +266,102 @@ func (s *Server) GetEvents(w http.ResponseWriter, r *http.Request) {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "event ID"
-// @Success 200 {object} PlaceListResponse
+// @Param If-None-Match header string false "ETag of a kept response"
+// @Param If-Modified-Since header string false "Last-Modified of a kept response"
+// @Success 200 {array} model.PlaceResponse
+// @Header 200 {integer} X-Invalid-Items "number of invalid upstream items left out"
+// @Success 304 "not modified"
 // @Failure 400,404 {object} server.ErrorResponse
 // @Failure 500 {object} server.ErrorResponse
+// @Failure 502 {object} server.ErrorResponse
 // @Router /events/{id}/places [get].
 func (s *Server) GetPlaces(w http.ResponseWriter, r *http.Request) {
-	// Step 1: Make a GET request to the remote API
-	vars := mux.Vars(r)
-	id := vars["id"]
-	remoteURL := "https://leadbook.ru/test-task-api/events/" + id + "/places"
-	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, remoteURL, nil)
-	if err != nil {
-		srv.RespondWithError(fmt.Errorf("failed to create request: %w", err), w, r)
+	eventID, ok := pathID(w, r)
+	if !ok {
 		return
 	}
-	resp, err := http.DefaultClient.Do(req)
+
+	categories, err := s.app.GetPriceCategories(r.Context(), eventID)
 	if err != nil {
-		srv.RespondWithError(fmt.Errorf("failed to do request: %w", err), w, r)
+		srv.RespondWithError(fmt.Errorf("failed to get prices: %w", err), w, r)
 		return
 	}
-	defer resp.Body.Close()
+	prices := pricing.NewCategories(categories)
+
+	// Events held in a local hall have their own seat map instead of the remote one
+	var (
+		placeListResponse model.PlaceListResponse
+		stored            []models.Place
+	)
+	if event, err := s.app.GetEvent(r.Context(), eventID); err == nil && event.HallID.Valid {
+		stored, err = s.app.GetPlaces(r.Context(), models.PlaceFilter{EventID: eventID})
+		if err != nil {
+			srv.RespondWithError(fmt.Errorf("failed to get places: %w", err), w, r)
+			return
+		}
+		placeListResponse.Response = newPlaceResponses(stored)
+	} else {
+		placeListResponse, stored, err = s.fetchPlaces(r.Context(), eventID)
+		if err != nil {
+			srv.RespondWithError(err, w, r)
+			return
+		}
+	}
+	for i, place := range stored {
+		placeListResponse.Response[i].Price = newPlacePriceResponse(place, prices)
+	}
 
-	// Step 2: Decode the response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		srv.RespondWithError(fmt.Errorf("failed to read response body: %w", err), w, r)
-		return
+	// Holds and sales write places, repricing writes places or their categories.
+	modified := lastModified(stored...)
+	if m := lastModified(categories...); m.After(modified) {
+		modified = m
 	}
+	reportInvalidItems(w, placeListResponse.Invalid)
+	respondConditional(placeListResponse.Response, modified, w, r)
+}
 
+// fetchPlaces gets the places of an event from the remote API and stores the valid ones.
+func (s *Server) fetchPlaces(ctx context.Context, eventID int64) (model.PlaceListResponse, []models.Place, error) {
 	var placeListResponse model.PlaceListResponse
-	if err := json.Unmarshal(body, &placeListResponse); err != nil {
-		srv.RespondWithError(fmt.Errorf("failed to decode response: %w", err), w, r)
-		return
+
+	// Step 1: Fetch places of the event from the remote API
+	res, err := s.upstream.Places(ctx, eventID)
+	if err != nil {
+		return placeListResponse, nil, err
 	}
 
-	// Step 3: Iterate over places and store them in the local service
-	for _, place := range placeListResponse.Response {
-		if err := placeListResponse.PlaceListResponseValidate(); err != nil {
-			srv.RespondWithError(fmt.Errorf("failed to validate response: %w", err), w, r)
-			return
-		}
+	// Step 2: Decode the response
+	if err := json.Unmarshal(res.Body, &placeListResponse); err != nil {
+		return placeListResponse, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
 
-		_, err := s.app.CreatePlace(r.Context(), models.Place{
+	// Step 3: Validate places and store the valid ones in the local service
+	valid, invalid := placeListResponse.Partition()
+	if err := s.handleInvalidItems(ctx, "place", res, invalid); err != nil {
+		return placeListResponse, nil, err
+	}
+	placeListResponse.Response, placeListResponse.Invalid = valid, invalid
+	// Places of a cached response were stored when it was fetched, only missing ones are stored again.
+	var known map[int64]models.Place
+	if res.Cached {
+		places, err := s.app.GetPlaces(ctx, models.PlaceFilter{EventID: eventID})
+		if err != nil {
+			return placeListResponse, nil, fmt.Errorf("failed to get places: %w", err)
+		}
+		known = make(map[int64]models.Place, len(places))
+		for _, p := range places {
+			known[p.ID] = p
+		}
+	}
+	stored := make([]models.Place, 0, len(valid))
+	for _, place := range valid {
+		if p, ok := known[place.ID]; ok {
+			stored = append(stored, p)
+			continue
+		}
+		p, err := s.app.CreatePlace(ctx, models.Place{
 			ID:          place.ID,
+			EventID:     sql.NullInt64{Int64: eventID, Valid: true},
 			X:           place.X,
 			Y:           place.Y,
 			Width:       place.Width,
@@ -232,22 +369,48 @@ func (s *Server) GetPlaces(w http.ResponseWriter, r *http.Request) {
 			IsAvailable: place.IsAvailable,
 		})
 		if err != nil {
-			srv.RespondWithError(fmt.Errorf("failed to create place: %w", err), w, r)
-			return
+			return placeListResponse, nil, fmt.Errorf("failed to create place: %w", err)
 		}
+		stored = append(stored, p)
 	}
-
-	srv.RespondOK(placeListResponse.Response, w, r)
+	return placeListResponse, stored, nil
 }
 
 // @title Ticket API
 // @version 1
 // @description API Server for remote Tickets Application.
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func (s *Server) Start(ctx context.Context) error {
 	addr := net.JoinHostPort(s.host, s.port)
+	s.srv = http.Server{
+		Addr:              addr,
+		Handler:           s.router(),
+		ReadHeaderTimeout: 2 * time.Second,
+		BaseContext: func(_ net.Listener) context.Context {
+			bCtx := context.WithValue(ctx, KeyLoggerID, s.log)
+			return bCtx
+		},
+	}
+
+	if s.placesSyncInterval > 0 {
+		go s.syncPlaces(ctx)
+	}
+
+	if len(s.adminTokens) == 0 {
+		s.log.Warningf("http admin routes are closed, no admin tokens are configured\n")
+	}
+	s.log.Infof("http server started on %s:%s\n", s.host, s.port)
+	return s.srv.ListenAndServe()
+}
+
+// router routes the API requests.
+func (s *Server) router() *mux.Router {
 	midLogger := NewMiddlewareLogger()
 
 	router := mux.NewRouter()
+	router.Use(s.idempotencyMiddleware)
 
 	router.Handle("/healthz", midLogger.setCommonHeadersMiddleware(
 		midLogger.loggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -262,26 +425,44 @@ func (s *Server) Start(ctx context.Context) error {
 		}))))
 
 	router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
+	// Metrics include memstats and the command line, only administrators read them.
+	router.Handle("/debug/vars", s.adminMiddleware(expvar.Handler()))
 
 	router.Handle("/shows", midLogger.setCommonHeadersMiddleware(
 		midLogger.loggingMiddleware(http.HandlerFunc(s.GetShows))))
 	router.Handle("/shows/{id:[0-9]+}/events", midLogger.setCommonHeadersMiddleware(
 		midLogger.loggingMiddleware(http.HandlerFunc(s.GetEvents))))
+	router.Handle("/events", midLogger.setCommonHeadersMiddleware(
+		midLogger.loggingMiddleware(http.HandlerFunc(s.ListEvents))))
+	router.Handle("/events/{id:[0-9]+}", midLogger.setCommonHeadersMiddleware(
+		midLogger.loggingMiddleware(http.HandlerFunc(s.GetEvent)))).Methods(http.MethodGet)
 	router.Handle("/events/{id:[0-9]+}/places", midLogger.setCommonHeadersMiddleware(
 		midLogger.loggingMiddleware(http.HandlerFunc(s.GetPlaces))))
-
-	s.srv = http.Server{
-		Addr:              addr,
-		Handler:           router,
-		ReadHeaderTimeout: 2 * time.Second,
-		BaseContext: func(_ net.Listener) context.Context {
-			bCtx := context.WithValue(ctx, KeyLoggerID, s.log)
-			return bCtx
-		},
-	}
-
-	s.log.Infof("http server started on %s:%s\n", s.host, s.port)
-	return s.srv.ListenAndServe()
+	s.registerVenueRoutes(router, midLogger)
+	s.registerHoldRoutes(router, midLogger)
+	s.registerSpatialRoutes(router, midLogger)
+	s.registerPriceRoutes(router, midLogger)
+	s.registerOrderRoutes(router, midLogger)
+	s.registerPromotionRoutes(router, midLogger)
+	s.registerCustomerRoutes(router, midLogger)
+	s.registerWaitlistRoutes(router, midLogger)
+	s.registerRefundRoutes(router, midLogger)
+	s.registerTicketRoutes(router, midLogger)
+	s.registerCalendarRoutes(router, midLogger)
+	s.registerAvailabilityRoutes(router, midLogger)
+	s.registerWebSocketRoutes(router, midLogger)
+	s.registerWebhookRoutes(router, midLogger)
+	router.Handle("/graphql", midLogger.setCommonHeadersMiddleware(
+		midLogger.loggingMiddleware(internalgraphql.NewHandler(s.log, s.app, s.graphql...)))).
+		Methods(http.MethodGet, http.MethodPost)
+	router.Handle("/events/{id:[0-9]+}/seatmap.svg", midLogger.setCommonHeadersMiddleware(
+		midLogger.loggingMiddleware(http.HandlerFunc(s.GetSeatMapSVG))))
+	router.Handle("/events/{id:[0-9]+}/seatmap.png", midLogger.setCommonHeadersMiddleware(
+		midLogger.loggingMiddleware(http.HandlerFunc(s.GetSeatMapPNG))))
+	// Quarantined items are raw upstream payloads, only administrators review them.
+	router.Handle("/quarantine", midLogger.setCommonHeadersMiddleware(
+		midLogger.loggingMiddleware(s.adminMiddleware(http.HandlerFunc(s.GetQuarantinedItems)))))
+	return router
 }
 
 func (s *Server) Stop(ctx context.Context) error {
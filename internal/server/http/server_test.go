@@ -2,15 +2,20 @@ package internalhttp
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/cronnoss/tk-api/internal/logger"
 	"github.com/cronnoss/tk-api/internal/model"
 	"github.com/cronnoss/tk-api/internal/server/mocks"
 	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/cronnoss/tk-api/internal/upstream"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -57,3 +62,216 @@ func TestGetShows(t *testing.T) {
 	require.Equal(t, int64(2), showListResponse.Response[1].ID)
 	require.Equal(t, "Show #2", showListResponse.Response[1].Name)
 }
+
+// stubProvider serves the same shows response every time.
+type stubProvider struct {
+	upstream.Provider
+	res upstream.Response
+}
+
+func (p *stubProvider) Shows(context.Context) (upstream.Response, error) {
+	return p.res, nil
+}
+
+func TestGetShowsStoresFetchedOnly(t *testing.T) {
+	app := mocks.NewApplication(t)
+	app.On("CreateShow", mock.Anything, mock.Anything).Return(models.Show{}, nil).Twice()
+	app.On("GetShows", mock.Anything).Return([]models.Show{}, nil)
+	p := &stubProvider{res: upstream.Response{
+		URL:        "http://upstream/shows",
+		StatusCode: http.StatusOK,
+		Body:       []byte(`{"response":[{"id":1,"name":"Show #1"},{"id":2,"name":"Show #2"}]}`),
+	}}
+	s := NewServer(logger.NewLogger("error", io.Discard), app, "", "", WithUpstream(p))
+
+	for _, cached := range []bool{false, true} {
+		p.res.Cached = cached
+		rec := httptest.NewRecorder()
+		s.GetShows(rec, httptest.NewRequest(http.MethodGet, "/shows", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp []model.ShowResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp, 2)
+	}
+	app.AssertNumberOfCalls(t, "CreateShow", 2)
+}
+
+func TestGetShowsConditional(t *testing.T) {
+	modified := time.Date(2026, 10, 19, 10, 0, 0, 500, time.UTC)
+	app := mocks.NewApplication(t)
+	app.On("GetShows", mock.Anything).Return([]models.Show{
+		{ID: 1, CreatedAt: modified.Add(-time.Hour)},
+		{ID: 2, CreatedAt: modified.Add(-time.Hour), UpdatedAt: sql.NullTime{Time: modified, Valid: true}},
+	}, nil)
+	p := &stubProvider{res: upstream.Response{
+		StatusCode: http.StatusOK,
+		Body:       []byte(`{"response":[{"id":1,"name":"Show #1"}]}`),
+		Cached:     true,
+	}}
+	s := NewServer(logger.NewLogger("error", io.Discard), app, "", "", WithUpstream(p))
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/shows", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		s.GetShows(rec, req)
+		return rec
+	}
+
+	rec := get("", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	require.Equal(t, "Mon, 19 Oct 2026 10:00:00 GMT", rec.Header().Get("Last-Modified"))
+
+	for _, tc := range []struct {
+		header, value string
+		status        int
+	}{
+		{"If-None-Match", etag, http.StatusNotModified},
+		{"If-None-Match", `"other", W/` + etag, http.StatusNotModified},
+		{"If-None-Match", `"other"`, http.StatusOK},
+		{"If-Modified-Since", "Mon, 19 Oct 2026 10:00:00 GMT", http.StatusNotModified},
+		{"If-Modified-Since", "Mon, 19 Oct 2026 09:59:59 GMT", http.StatusOK},
+	} {
+		rec := get(tc.header, tc.value)
+		require.Equal(t, tc.status, rec.Code, "%s: %s", tc.header, tc.value)
+		require.Equal(t, etag, rec.Header().Get("ETag"))
+		if tc.status == http.StatusNotModified {
+			require.Zero(t, rec.Body.Len())
+		}
+	}
+
+	// If-None-Match takes precedence over If-Modified-Since.
+	req := httptest.NewRequest(http.MethodGet, "/shows", nil)
+	req.Header.Set("If-None-Match", `"other"`)
+	req.Header.Set("If-Modified-Since", "Mon, 19 Oct 2026 10:00:00 GMT")
+	rec = httptest.NewRecorder()
+	s.GetShows(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCancelEventIfMatch(t *testing.T) {
+	event := models.Event{ID: 1, ShowID: 1, Date: time.Date(2026, 11, 1, 19, 0, 0, 0, time.UTC), TimeZone: "UTC"}
+	cancelled := event
+	cancelled.CancelledAt = sql.NullTime{Time: time.Now(), Valid: true}
+	cancelled.UpdatedAt = cancelled.CancelledAt
+	app := mocks.NewApplication(t)
+	app.On("GetEvent", mock.Anything, int64(1)).Return(event, nil)
+	// Like storage, the check runs on the stored event.
+	app.On("CancelEvent", mock.Anything, int64(1), mock.Anything).Return(
+		func(_ context.Context, _ int64, check models.Check[models.Event]) (models.Event, error) {
+			if err := check.Run(event); err != nil {
+				return models.Event{}, err
+			}
+			return cancelled, nil
+		})
+	s := NewServer(logger.NewLogger("error", io.Discard), app, "", "")
+	do := func(method, path string, h http.HandlerFunc, ifMatch string) *httptest.ResponseRecorder {
+		req := mux.SetURLVars(httptest.NewRequest(method, path, nil), map[string]string{"id": "1"})
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		h(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodGet, "/events/1", s.GetEvent, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	etag := rec.Header().Get("ETag")
+
+	rec = do(http.MethodPost, "/events/1/cancel", s.CancelEvent, `"stale"`)
+	require.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	rec = do(http.MethodPost, "/events/1/cancel", s.CancelEvent, "W/"+etag)
+	require.Equal(t, http.StatusPreconditionFailed, rec.Code, "If-Match compares strongly")
+
+	rec = do(http.MethodPost, "/events/1/cancel", s.CancelEvent, etag)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEqual(t, etag, rec.Header().Get("ETag"))
+	rec = do(http.MethodPost, "/events/1/cancel", s.CancelEvent, "")
+	require.Equal(t, http.StatusOK, rec.Code, "updates without If-Match aren't checked")
+}
+
+func TestGetShowsSkipsInvalid(t *testing.T) {
+	app := mocks.NewApplication(t)
+	app.On("CreateShow", mock.Anything, mock.Anything).Return(models.Show{}, nil).Once()
+	app.On("GetShows", mock.Anything).Return([]models.Show{}, nil)
+	p := &stubProvider{res: upstream.Response{
+		StatusCode: http.StatusOK,
+		Body:       []byte(`{"response":[{"id":1,"name":"Show #1"},{"id":2}]}`),
+	}}
+	s := NewServer(logger.NewLogger("error", io.Discard), app, "", "",
+		WithUpstream(p), WithInvalidItemPolicy(model.PolicySkip))
+
+	rec := httptest.NewRecorder()
+	s.GetShows(rec, httptest.NewRequest(http.MethodGet, "/shows", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `[{"id":1,"name":"Show #1"}]`, rec.Body.String(), "lists stay bare arrays")
+	require.Equal(t, "1", rec.Header().Get(InvalidItemsHeader))
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	for _, tc := range []struct {
+		name   string
+		tokens []string
+		header string
+		status int
+	}{
+		{"closed without tokens", nil, "Bearer anything", http.StatusUnauthorized},
+		{"missing token", []string{"secret"}, "", http.StatusUnauthorized},
+		{"unknown token", []string{"secret"}, "Bearer other", http.StatusUnauthorized},
+		{"known token", []string{"other", "secret"}, "Bearer secret", http.StatusNoContent},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewServer(logger.NewLogger("error", io.Discard), nil, "", "", WithAdminTokens(tc.tokens))
+			req := httptest.NewRequest(http.MethodGet, "/webhooks", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rec := httptest.NewRecorder()
+			s.adminMiddleware(handler).ServeHTTP(rec, req)
+			require.Equal(t, tc.status, rec.Code)
+		})
+	}
+}
+
+func TestAdminRoutes(t *testing.T) {
+	log := logger.NewLogger("error", io.Discard)
+	s := NewServer(log, mocks.NewApplication(t), "", "", WithAdminTokens([]string{"secret"}))
+	router := s.router()
+	// The logging middleware takes the logger of the server context.
+	ctx := context.WithValue(context.Background(), KeyLoggerID, log)
+	for _, route := range []struct{ method, path string }{
+		{http.MethodGet, "/customers/1"},
+		{http.MethodPut, "/customers/1"},
+		{http.MethodDelete, "/customers/1"},
+		{http.MethodGet, "/customers/1/orders"},
+		{http.MethodGet, "/customers/1/export"},
+		{http.MethodGet, "/webhooks"},
+		{http.MethodGet, "/quarantine"},
+		{http.MethodGet, "/debug/vars"},
+	} {
+		for _, header := range []string{"", "Bearer other"} {
+			req := httptest.NewRequest(route.method, route.path, nil).WithContext(ctx)
+			if header != "" {
+				req.Header.Set("Authorization", header)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			require.Equal(t, http.StatusUnauthorized, rec.Code, "%s %s %q", route.method, route.path, header)
+		}
+	}
+}
+
+func TestParseSeatMapOptionsSize(t *testing.T) {
+	for size, valid := range map[string]bool{"1": true, "2048": true, "2049": false, "4000": false, "0": false} {
+		r := httptest.NewRequest(http.MethodGet, "/events/1/seatmap.png?size="+size, nil)
+		_, err := parseSeatMapOptions(r)
+		require.Equal(t, valid, err == nil, size)
+	}
+}
@@ -0,0 +1,184 @@
+package internalhttp
+
+import (
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/common/validation"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultNearestLimit = 10
+	maxNearestLimit     = 100
+)
+
+func (s *Server) registerSpatialRoutes(router *mux.Router, midLogger *MiddlewareLogger) {
+	handle := func(path string, h http.HandlerFunc, method string) {
+		router.Handle(path, midLogger.setCommonHeadersMiddleware(
+			midLogger.loggingMiddleware(h))).Methods(method)
+	}
+
+	handle("/events/{id:[0-9]+}/places/within", s.GetPlacesInRect, http.MethodGet)
+	handle("/events/{id:[0-9]+}/places/nearest", s.GetNearestPlaces, http.MethodGet)
+	handle("/events/{id:[0-9]+}/places/adjacency", s.GetPlaceAdjacency, http.MethodGet)
+}
+
+// parseFloat parses a finite number.
+func parseFloat(s string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		return f, strconv.ErrRange
+	}
+	return f, err
+}
+
+// parseRect parses a "minX,minY,maxX,maxY" bounding box.
+func parseRect(s string) (models.Rect, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return models.Rect{}, model.ErrInvalidValue
+	}
+	var c [4]float64
+	for i, p := range parts {
+		f, err := parseFloat(p)
+		if err != nil {
+			return models.Rect{}, model.ErrInvalidValue
+		}
+		c[i] = f
+	}
+	r := models.Rect{MinX: c[0], MinY: c[1], MaxX: c[2], MaxY: c[3]}
+	if r.MinX > r.MaxX || r.MinY > r.MaxY {
+		return r, model.ErrInvalidValue
+	}
+	return r, nil
+}
+
+// queryFloat parses the required number query parameter name, recording violations in v.
+func queryFloat(q url.Values, name string, v *validation.Validator) float64 {
+	if q.Get(name) == "" {
+		v.Add(validation.Pointer(name), model.ErrRequired)
+		return 0
+	}
+	f, err := parseFloat(q.Get(name))
+	v.Check(err == nil, validation.Pointer(name), model.ErrInvalidValue)
+	return f
+}
+
+func (s *Server) respondPricedPlaces(eventID int64, places []models.Place, w http.ResponseWriter, r *http.Request) {
+	resp, err := s.pricedPlaceResponses(r.Context(), eventID, places)
+	if err != nil {
+		respondStorageError("price-category", err, w, r)
+		return
+	}
+	srv.RespondOK(resp, w, r)
+}
+
+// @Summary List places in a bounding box
+// @Tags places
+// @Description List places of the event intersecting the bounding box, e.g. the visible part of a zoomed seat map
+// @ID get-places-within
+// @Produce  json
+// @Param id path int true "event ID"
+// @Param bbox query string true "bounding box minX,minY,maxX,maxY"
+// @Success 200 {array} model.PlaceResponse
+// @Failure 400 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/places/within [get].
+func (s *Server) GetPlacesInRect(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var v validation.Validator
+	bbox := r.URL.Query().Get("bbox")
+	rect, err := parseRect(bbox)
+	v.Check(bbox != "", validation.Pointer("bbox"), model.ErrRequired)
+	v.Check(bbox == "" || err == nil, validation.Pointer("bbox"), model.ErrInvalidValue)
+	if err := v.Err(); err != nil {
+		srv.RespondWithError(slugerrors.NewBadRequestError("invalid bounding box", "invalid-bbox").Wrap(err), w, r)
+		return
+	}
+
+	places, err := s.app.GetPlacesInRect(r.Context(), id, rect)
+	if err != nil {
+		respondStorageError("place", err, w, r)
+		return
+	}
+	s.respondPricedPlaces(id, places, w, r)
+}
+
+// @Summary List nearest available places
+// @Tags places
+// @Description List available places of the event closest to a point, nearest first
+// @ID get-places-nearest
+// @Produce  json
+// @Param id path int true "event ID"
+// @Param x query number true "point X"
+// @Param y query number true "point Y"
+// @Param limit query int false "number of places, 10 by default, at most 100"
+// @Success 200 {array} model.PlaceResponse
+// @Failure 400 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/places/nearest [get].
+func (s *Server) GetNearestPlaces(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var v validation.Validator
+	q := r.URL.Query()
+	pt := models.Point{X: queryFloat(q, "x", &v), Y: queryFloat(q, "y", &v)}
+	limit := defaultNearestLimit
+	if l := q.Get("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		v.Check(err == nil && limit > 0 && limit <= maxNearestLimit, validation.Pointer("limit"), model.ErrInvalidValue)
+	}
+	if err := v.Err(); err != nil {
+		srv.RespondWithError(slugerrors.NewBadRequestError("invalid point", "invalid-point").Wrap(err), w, r)
+		return
+	}
+
+	places, err := s.app.GetNearestPlaces(r.Context(), id, pt, limit)
+	if err != nil {
+		respondStorageError("place", err, w, r)
+		return
+	}
+	s.respondPricedPlaces(id, places, w, r)
+}
+
+// @Summary Get seat adjacency
+// @Tags places
+// @Description List links between neighbouring seats of the event rows, derived from the place geometry
+// @ID get-places-adjacency
+// @Produce  json
+// @Param id path int true "event ID"
+// @Success 200 {array} model.AdjacencyResponse
+// @Failure 400 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/places/adjacency [get].
+func (s *Server) GetPlaceAdjacency(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	links, err := s.app.GetPlaceAdjacency(r.Context(), id)
+	if err != nil {
+		respondStorageError("place", err, w, r)
+		return
+	}
+	resp := make([]model.AdjacencyResponse, 0, len(links))
+	for _, l := range links {
+		resp = append(resp, model.AdjacencyResponse{LeftID: l.LeftID, RightID: l.RightID})
+	}
+	srv.RespondOK(resp, w, r)
+}
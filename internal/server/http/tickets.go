@@ -0,0 +1,193 @@
+package internalhttp
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/common/validation"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/cronnoss/tk-api/internal/tickets"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultQRSize = 256
+	maxQRSize     = 1024
+)
+
+func (s *Server) registerTicketRoutes(router *mux.Router, midLogger *MiddlewareLogger) {
+	handle := func(path string, h http.HandlerFunc, method string) {
+		router.Handle(path, midLogger.setCommonHeadersMiddleware(
+			midLogger.loggingMiddleware(h))).Methods(method)
+	}
+
+	handle("/tickets/{id:[0-9]+}/qr.png", s.GetTicketQR, http.MethodGet)
+	handle("/tickets/keys", s.GetTicketKeys, http.MethodGet)
+	handle("/scan", s.ScanTicket, http.MethodPost)
+	handle("/orders/{id:[0-9]+}/tickets.pdf", s.GetOrderTicketsPDF, http.MethodGet)
+}
+
+// @Summary Get ticket QR code
+// @Tags tickets
+// @Description QR code of the signed code of an issued ticket, the order item with the ID
+// @ID get-ticket-qr
+// @Produce  image/png
+// @Param id path int true "order item ID"
+// @Param size query int false "width in pixels, 256 by default"
+// @Success 200 {file} file
+// @Failure 400,404,409 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /tickets/{id}/qr.png [get].
+func (s *Server) GetTicketQR(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	size := defaultQRSize
+	if q := r.URL.Query().Get("size"); q != "" {
+		var (
+			v   validation.Validator
+			err error
+		)
+		size, err = strconv.Atoi(q)
+		v.Check(err == nil && size > 0 && size <= maxQRSize, validation.Pointer("size"), model.ErrInvalidValue)
+		if err := v.Err(); err != nil {
+			srv.RespondWithError(slugerrors.NewBadRequestError("invalid QR code size", "invalid-qr-size").Wrap(err), w, r)
+			return
+		}
+	}
+
+	code, err := s.app.TicketCode(r.Context(), id)
+	if errors.Is(err, model.ErrUnavailable) {
+		srv.RespondWithError(slugerrors.NewConflictError("ticket is refunded or exchanged", "ticket-unavailable"), w, r)
+		return
+	}
+	if err != nil {
+		respondStorageError("ticket", err, w, r)
+		return
+	}
+	b, err := tickets.QR(code, size)
+	if err != nil {
+		srv.RespondWithError(fmt.Errorf("failed to render QR code: %w", err), w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
+
+// @Summary Get ticket keys
+// @Tags tickets
+// @Description Public keys ticket codes are signed with, for scanners verifying codes offline
+// @ID get-ticket-keys
+// @Produce  json
+// @Success 200 {object} model.TicketKeysResponse
+// @Router /tickets/keys [get].
+func (s *Server) GetTicketKeys(w http.ResponseWriter, r *http.Request) {
+	resp := model.TicketKeysResponse{Algorithm: "Ed25519"}
+	for _, k := range s.app.TicketKeys() {
+		resp.Keys = append(resp.Keys, base64.StdEncoding.EncodeToString(k))
+	}
+	srv.RespondOK(resp, w, r)
+}
+
+// respondScanError tells door staff why a ticket is not admitted.
+func respondScanError(item models.OrderItem, err error, w http.ResponseWriter, r *http.Request) {
+	switch {
+	case errors.Is(err, tickets.ErrMalformed):
+		srv.RespondWithError(slugerrors.NewValidationError("not a ticket code", "malformed-ticket-code"), w, r)
+	case errors.Is(err, tickets.ErrSignature):
+		srv.RespondWithError(slugerrors.NewValidationError("ticket code is forged or from another system",
+			"invalid-ticket-signature"), w, r)
+	case errors.Is(err, model.ErrWrongEvent):
+		srv.RespondWithError(slugerrors.NewConflictError(
+			fmt.Sprintf("ticket is for event %d", item.EventID), "wrong-event"), w, r)
+	case errors.Is(err, model.ErrAlreadyUsed):
+		srv.RespondWithError(slugerrors.NewConflictError(
+			"ticket was already used at "+item.UsedAt.Time.UTC().Format("2006-01-02T15:04:05Z"),
+			"ticket-already-used"), w, r)
+	case errors.Is(err, model.ErrUnavailable) && item.Status == models.ItemRefunded:
+		srv.RespondWithError(slugerrors.NewConflictError("ticket was refunded", "ticket-refunded"), w, r)
+	case errors.Is(err, model.ErrUnavailable) && item.Status == models.ItemExchanged:
+		srv.RespondWithError(slugerrors.NewConflictError("ticket was exchanged for another one",
+			"ticket-exchanged"), w, r)
+	case errors.Is(err, model.ErrUnavailable):
+		srv.RespondWithError(slugerrors.NewConflictError("ticket is not valid", "ticket-unavailable"), w, r)
+	default:
+		respondStorageError("ticket", err, w, r)
+	}
+}
+
+// @Summary Scan ticket
+// @Tags tickets
+// @Description Admit a ticket at the door: verify the signature of its code, check it is issued
+// @Description and unused, then mark it as used. Rejections explain the reason in the slug.
+// @ID scan-ticket
+// @Accept  json
+// @Produce  json
+// @Param scan body model.ScanRequest true "ticket code and the event being admitted"
+// @Success 200 {object} model.ScanResponse
+// @Failure 400,404,409,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /scan [post].
+func (s *Server) ScanTicket(w http.ResponseWriter, r *http.Request) {
+	var req model.ScanRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	item, err := s.app.ScanTicket(r.Context(), req.Code, req.EventID)
+	if err != nil {
+		respondScanError(item, err, w, r)
+		return
+	}
+	srv.RespondOK(model.ScanResponse{
+		TicketID: item.ID,
+		OrderID:  item.OrderID,
+		EventID:  item.EventID,
+		PlaceID:  item.PlaceID,
+		UsedAt:   item.UsedAt.Time.UTC(),
+	}, w, r)
+}
+
+// @Summary Get printable order tickets
+// @Tags tickets
+// @Description PDF with a page for every issued ticket of the order: show, event date in the venue time zone,
+// @Description seat, price and the QR code scanned at the door
+// @ID get-order-tickets-pdf
+// @Produce  application/pdf
+// @Param id path int true "order ID"
+// @Success 200 {file} file
+// @Failure 400,404,409 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /orders/{id}/tickets.pdf [get].
+func (s *Server) GetOrderTicketsPDF(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	b, err := s.app.OrderTicketsPDF(r.Context(), id)
+	if errors.Is(err, model.ErrUnavailable) {
+		srv.RespondWithError(slugerrors.NewConflictError("order has no issued tickets", "no-tickets"), w, r)
+		return
+	}
+	if err != nil {
+		respondStorageError("order", err, w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="order-%d-tickets.pdf"`, id))
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
@@ -0,0 +1,387 @@
+package internalhttp
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) registerVenueRoutes(router *mux.Router, midLogger *MiddlewareLogger) {
+	handle := func(path string, h http.HandlerFunc, method string) {
+		router.Handle(path, midLogger.setCommonHeadersMiddleware(
+			midLogger.loggingMiddleware(h))).Methods(method)
+	}
+
+	handle("/venues", s.CreateVenue, http.MethodPost)
+	handle("/venues", s.ListVenues, http.MethodGet)
+	handle("/venues/{id:[0-9]+}", s.GetVenue, http.MethodGet)
+	handle("/venues/{id:[0-9]+}/halls", s.CreateHall, http.MethodPost)
+	handle("/venues/{id:[0-9]+}/halls", s.ListHalls, http.MethodGet)
+	handle("/halls/{id:[0-9]+}", s.GetHall, http.MethodGet)
+	handle("/halls/{id:[0-9]+}/sections", s.CreateSection, http.MethodPost)
+	handle("/halls/{id:[0-9]+}/places", s.CreateHallPlaces, http.MethodPost)
+	handle("/halls/{id:[0-9]+}/places", s.ListHallPlaces, http.MethodGet)
+	handle("/events/{id:[0-9]+}/hall", s.AttachEventToHall, http.MethodPut)
+}
+
+func newVenueResponse(v models.Venue) model.VenueResponse {
+	return model.VenueResponse{ID: v.ID, Name: v.Name, Address: v.Address, TimeZone: v.TimeZone}
+}
+
+func newHallResponse(h models.Hall) model.HallResponse {
+	return model.HallResponse{ID: h.ID, VenueID: h.VenueID, Name: h.Name}
+}
+
+func newSectionResponse(sec models.Section) model.SectionResponse {
+	return model.SectionResponse{ID: sec.ID, HallID: sec.HallID, Name: sec.Name}
+}
+
+func newPlaceResponse(p models.Place) model.PlaceResponse {
+	return model.PlaceResponse{
+		ID:          p.ID,
+		EventID:     p.EventID.Int64,
+		HallID:      p.HallID.Int64,
+		SectionID:   p.SectionID.Int64,
+		Row:         p.Row,
+		Seat:        p.Seat,
+		Label:       p.Label(),
+		X:           p.X,
+		Y:           p.Y,
+		Width:       p.Width,
+		Height:      p.Height,
+		IsAvailable: p.IsAvailable,
+		Accessible:  p.Accessible,
+		HoldID:      p.HoldID.Int64,
+	}
+}
+
+func newPlaceResponses(places []models.Place) []model.PlaceResponse {
+	resp := make([]model.PlaceResponse, 0, len(places))
+	for _, p := range places {
+		resp = append(resp, newPlaceResponse(p))
+	}
+	return resp
+}
+
+// @Summary Create venue
+// @Tags venues
+// @ID create-venue
+// @Accept  json
+// @Produce  json
+// @Param venue body model.VenueRequest true "venue"
+// @Success 201 {object} model.VenueResponse
+// @Failure 400,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /venues [post].
+func (s *Server) CreateVenue(w http.ResponseWriter, r *http.Request) {
+	var req model.VenueRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	venue, err := s.app.CreateVenue(r.Context(), models.Venue{
+		Name:     req.Name,
+		Address:  req.Address,
+		TimeZone: req.TimeZone,
+	})
+	if err != nil {
+		respondStorageError("venue", err, w, r)
+		return
+	}
+	srv.RespondCreated(newVenueResponse(venue), w, r)
+}
+
+// @Summary List venues
+// @Tags venues
+// @ID list-venues
+// @Produce  json
+// @Success 200 {array} model.VenueResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /venues [get].
+func (s *Server) ListVenues(w http.ResponseWriter, r *http.Request) {
+	venues, err := s.app.GetVenues(r.Context())
+	if err != nil {
+		respondStorageError("venue", err, w, r)
+		return
+	}
+
+	resp := make([]model.VenueResponse, 0, len(venues))
+	for _, v := range venues {
+		resp = append(resp, newVenueResponse(v))
+	}
+	srv.RespondOK(resp, w, r)
+}
+
+// @Summary Get venue
+// @Tags venues
+// @Description Get venue with its halls
+// @ID get-venue
+// @Produce  json
+// @Param id path int true "venue ID"
+// @Success 200 {object} model.VenueResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /venues/{id} [get].
+func (s *Server) GetVenue(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	venue, err := s.app.GetVenue(r.Context(), id)
+	if err != nil {
+		respondStorageError("venue", err, w, r)
+		return
+	}
+	halls, err := s.app.GetHalls(r.Context(), id)
+	if err != nil {
+		respondStorageError("hall", err, w, r)
+		return
+	}
+
+	resp := newVenueResponse(venue)
+	for _, h := range halls {
+		resp.Halls = append(resp.Halls, newHallResponse(h))
+	}
+	srv.RespondOK(resp, w, r)
+}
+
+// @Summary Create hall
+// @Tags venues
+// @ID create-hall
+// @Accept  json
+// @Produce  json
+// @Param id path int true "venue ID"
+// @Param hall body model.HallRequest true "hall"
+// @Success 201 {object} model.HallResponse
+// @Failure 400,404,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /venues/{id}/halls [post].
+func (s *Server) CreateHall(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req model.HallRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	hall, err := s.app.CreateHall(r.Context(), models.Hall{VenueID: id, Name: req.Name})
+	if err != nil {
+		respondStorageError("venue", err, w, r)
+		return
+	}
+	srv.RespondCreated(newHallResponse(hall), w, r)
+}
+
+// @Summary List halls
+// @Tags venues
+// @ID list-halls
+// @Produce  json
+// @Param id path int true "venue ID"
+// @Success 200 {array} model.HallResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /venues/{id}/halls [get].
+func (s *Server) ListHalls(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := s.app.GetVenue(r.Context(), id); err != nil {
+		respondStorageError("venue", err, w, r)
+		return
+	}
+	halls, err := s.app.GetHalls(r.Context(), id)
+	if err != nil {
+		respondStorageError("hall", err, w, r)
+		return
+	}
+
+	resp := make([]model.HallResponse, 0, len(halls))
+	for _, h := range halls {
+		resp = append(resp, newHallResponse(h))
+	}
+	srv.RespondOK(resp, w, r)
+}
+
+// @Summary Get hall
+// @Tags venues
+// @Description Get hall with its sections
+// @ID get-hall
+// @Produce  json
+// @Param id path int true "hall ID"
+// @Success 200 {object} model.HallResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /halls/{id} [get].
+func (s *Server) GetHall(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	hall, err := s.app.GetHall(r.Context(), id)
+	if err != nil {
+		respondStorageError("hall", err, w, r)
+		return
+	}
+	sections, err := s.app.GetSections(r.Context(), id)
+	if err != nil {
+		respondStorageError("section", err, w, r)
+		return
+	}
+
+	resp := newHallResponse(hall)
+	for _, sec := range sections {
+		resp.Sections = append(resp.Sections, newSectionResponse(sec))
+	}
+	srv.RespondOK(resp, w, r)
+}
+
+// @Summary Create section
+// @Tags venues
+// @ID create-section
+// @Accept  json
+// @Produce  json
+// @Param id path int true "hall ID"
+// @Param section body model.SectionRequest true "section"
+// @Success 201 {object} model.SectionResponse
+// @Failure 400,404,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /halls/{id}/sections [post].
+func (s *Server) CreateSection(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req model.SectionRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	section, err := s.app.CreateSection(r.Context(), models.Section{HallID: id, Name: req.Name})
+	if err != nil {
+		respondStorageError("hall", err, w, r)
+		return
+	}
+	srv.RespondCreated(newSectionResponse(section), w, r)
+}
+
+// @Summary Add places to hall layout
+// @Tags venues
+// @Description Add seats to the hall layout, sections must belong to the hall
+// @ID create-hall-places
+// @Accept  json
+// @Produce  json
+// @Param id path int true "hall ID"
+// @Param places body model.HallPlaceListRequest true "places"
+// @Success 201 {array} model.PlaceResponse
+// @Failure 400,404,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /halls/{id}/places [post].
+func (s *Server) CreateHallPlaces(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req model.HallPlaceListRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	places := make([]models.Place, 0, len(req))
+	for _, p := range req {
+		places = append(places, models.Place{
+			SectionID:   sql.NullInt64{Int64: p.SectionID, Valid: p.SectionID != 0},
+			Row:         p.Row,
+			Seat:        p.Seat,
+			X:           p.X,
+			Y:           p.Y,
+			Width:       p.Width,
+			Height:      p.Height,
+			IsAvailable: true,
+			Accessible:  p.Accessible,
+		})
+	}
+	places, err := s.app.CreateHallPlaces(r.Context(), id, places)
+	if err != nil {
+		respondStorageError("hall", err, w, r)
+		return
+	}
+	srv.RespondCreated(newPlaceResponses(places), w, r)
+}
+
+// @Summary List hall layout
+// @Tags venues
+// @ID list-hall-places
+// @Produce  json
+// @Param id path int true "hall ID"
+// @Param If-None-Match header string false "ETag of a kept response"
+// @Param If-Modified-Since header string false "Last-Modified of a kept response"
+// @Success 200 {array} model.PlaceResponse
+// @Success 304 "not modified"
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /halls/{id}/places [get].
+func (s *Server) ListHallPlaces(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := s.app.GetHall(r.Context(), id); err != nil {
+		respondStorageError("hall", err, w, r)
+		return
+	}
+	places, err := s.app.GetPlaces(r.Context(), models.PlaceFilter{HallID: id})
+	if err != nil {
+		respondStorageError("place", err, w, r)
+		return
+	}
+	respondConditional(newPlaceResponses(places), lastModified(places...), w, r)
+}
+
+// @Summary Attach event to hall
+// @Tags events
+// @Description The event takes place in the hall: it gets the venue time zone and a copy of the hall seat map
+// @ID attach-event-to-hall
+// @Accept  json
+// @Produce  json
+// @Param id path int true "event ID"
+// @Param hall body model.AttachHallRequest true "hall"
+// @Param If-Match header string false "ETag of the event read last"
+// @Success 200 {object} model.EventResponse
+// @Failure 400,404,409,412,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/hall [put].
+func (s *Server) AttachEventToHall(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req model.AttachHallRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	event, err := s.app.AttachEventToHall(r.Context(), id, req.HallID, ifMatch(r, newEventResponse))
+	if errors.Is(err, model.ErrPlacesInUse) {
+		srv.RespondWithError(slugerrors.NewConflictError(
+			"places of the event are held or sold, it can't move to another seat map", "places-in-use"), w, r)
+		return
+	}
+	if err != nil {
+		respondStorageError("hall", err, w, r)
+		return
+	}
+	respondConditional(newEventResponse(event), event.ModifiedAt(), w, r)
+}
@@ -0,0 +1,162 @@
+package internalhttp
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) registerWaitlistRoutes(router *mux.Router, midLogger *MiddlewareLogger) {
+	handle := func(path string, h http.HandlerFunc, method string) {
+		router.Handle(path, midLogger.setCommonHeadersMiddleware(
+			midLogger.loggingMiddleware(h))).Methods(method)
+	}
+
+	handle("/events/{id:[0-9]+}/waitlist", s.JoinWaitlist, http.MethodPost)
+	handle("/events/{id:[0-9]+}/waitlist", s.ListWaitlist, http.MethodGet)
+	handle("/waitlist/{id:[0-9]+}", s.GetWaitlistEntry, http.MethodGet)
+	handle("/waitlist/{id:[0-9]+}", s.CancelWaitlistEntry, http.MethodDelete)
+}
+
+func newWaitlistEntryResponse(e models.WaitlistEntry) model.WaitlistEntryResponse {
+	resp := model.WaitlistEntryResponse{
+		ID:         e.ID,
+		EventID:    e.EventID,
+		CustomerID: e.CustomerID,
+		Quantity:   e.Quantity,
+		MaxPrice:   e.MaxPrice,
+		Status:     string(e.Status),
+		HoldID:     e.HoldID.Int64,
+		CreatedAt:  e.CreatedAt.UTC(),
+	}
+	if e.OfferedAt.Valid {
+		offeredAt := e.OfferedAt.Time.UTC()
+		resp.OfferedAt = &offeredAt
+	}
+	return resp
+}
+
+// @Summary Join waitlist
+// @Tags waitlist
+// @Description Wait for places of a sold-out event. When places matching the quantity and price ceiling
+// @Description are released they are held for the customer and a notification is sent.
+// @ID join-waitlist
+// @Accept  json
+// @Produce  json
+// @Param id path int true "event ID"
+// @Param entry body model.WaitlistRequest true "customer and wanted places"
+// @Success 201 {object} model.WaitlistEntryResponse
+// @Failure 400,404,409,422 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/waitlist [post].
+func (s *Server) JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var req model.WaitlistRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	entry, err := s.app.JoinWaitlist(r.Context(), models.WaitlistEntry{
+		EventID:    id,
+		CustomerID: req.CustomerID,
+		Quantity:   req.Quantity,
+		MaxPrice:   req.MaxPrice,
+	})
+	switch {
+	case errors.Is(err, model.ErrPlacesAvailable):
+		srv.RespondWithError(slugerrors.NewConflictError("places are available, no need to wait",
+			"places-available"), w, r)
+	case errors.Is(err, model.ErrAlreadyExists):
+		srv.RespondWithError(slugerrors.NewConflictError("customer is already waiting for the event",
+			"already-waiting"), w, r)
+	case errors.Is(err, model.ErrInvalidUserID):
+		respondCustomerError(err, w, r)
+	case err != nil:
+		respondStorageError("event", err, w, r)
+	default:
+		srv.RespondCreated(newWaitlistEntryResponse(entry), w, r)
+	}
+}
+
+// @Summary List waitlist
+// @Tags waitlist
+// @Description Waitlist entries of the event in the order they joined
+// @ID list-waitlist
+// @Produce  json
+// @Param id path int true "event ID"
+// @Success 200 {array} model.WaitlistEntryResponse
+// @Failure 400 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/waitlist [get].
+func (s *Server) ListWaitlist(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	entries, err := s.app.GetWaitlist(r.Context(), id)
+	if err != nil {
+		respondStorageError("waitlist", err, w, r)
+		return
+	}
+	resp := make([]model.WaitlistEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		resp = append(resp, newWaitlistEntryResponse(e))
+	}
+	srv.RespondOK(resp, w, r)
+}
+
+// @Summary Get waitlist entry
+// @Tags waitlist
+// @ID get-waitlist-entry
+// @Produce  json
+// @Param id path int true "waitlist entry ID"
+// @Success 200 {object} model.WaitlistEntryResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /waitlist/{id} [get].
+func (s *Server) GetWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	entry, err := s.app.GetWaitlistEntry(r.Context(), id)
+	if err != nil {
+		respondStorageError("waitlist-entry", err, w, r)
+		return
+	}
+	srv.RespondOK(newWaitlistEntryResponse(entry), w, r)
+}
+
+// @Summary Cancel waitlist entry
+// @Tags waitlist
+// @Description Leave the waitlist, places offered to the entry go back on sale
+// @ID cancel-waitlist-entry
+// @Produce  json
+// @Param id path int true "waitlist entry ID"
+// @Success 200 {object} model.WaitlistEntryResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /waitlist/{id} [delete].
+func (s *Server) CancelWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	entry, err := s.app.CancelWaitlistEntry(r.Context(), id)
+	if err != nil {
+		respondStorageError("waitlist-entry", err, w, r)
+		return
+	}
+	srv.RespondOK(newWaitlistEntryResponse(entry), w, r)
+}
@@ -0,0 +1,275 @@
+package internalhttp
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/common/validation"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) registerWebhookRoutes(router *mux.Router, midLogger *MiddlewareLogger) {
+	handle := func(path string, h http.HandlerFunc, method string) {
+		router.Handle(path, midLogger.setCommonHeadersMiddleware(
+			midLogger.loggingMiddleware(s.adminMiddleware(h)))).Methods(method)
+	}
+
+	handle("/webhooks", s.CreateWebhook, http.MethodPost)
+	handle("/webhooks", s.ListWebhooks, http.MethodGet)
+	handle("/webhooks/{id:[0-9]+}", s.GetWebhook, http.MethodGet)
+	handle("/webhooks/{id:[0-9]+}", s.DeleteWebhook, http.MethodDelete)
+	handle("/webhooks/deliveries", s.ListWebhookDeliveries, http.MethodGet)
+	handle("/webhooks/deliveries/{id:[0-9]+}", s.GetWebhookDelivery, http.MethodGet)
+	handle("/webhooks/deliveries/{id:[0-9]+}/redeliver", s.RedeliverWebhookDelivery, http.MethodPost)
+}
+
+func newWebhookResponse(w models.Webhook) model.WebhookResponse {
+	return model.WebhookResponse{
+		ID:         w.ID,
+		URL:        w.URL,
+		EventTypes: w.EventTypes,
+		CreatedAt:  w.CreatedAt.UTC(),
+	}
+}
+
+func newWebhookDeliveryResponse(d models.WebhookDelivery) model.WebhookDeliveryResponse {
+	resp := model.WebhookDeliveryResponse{
+		ID:        d.ID,
+		WebhookID: d.WebhookID,
+		EventID:   d.EventID,
+		EventType: d.EventType,
+		Status:    string(d.Status),
+		Attempts:  d.Attempts,
+		LastError: d.LastError,
+		CreatedAt: d.CreatedAt.UTC(),
+	}
+	if d.Status == models.DeliveryPending {
+		next := d.NextAttemptAt.UTC()
+		resp.NextAttemptAt = &next
+	}
+	if d.DeliveredAt.Valid {
+		deliveredAt := d.DeliveredAt.Time.UTC()
+		resp.DeliveredAt = &deliveredAt
+	}
+	return resp
+}
+
+// @Summary Create webhook
+// @Tags webhooks
+// @Security BearerAuth
+// @Description Register an endpoint receiving events of the types as signed JSON POSTs. The secret
+// @Description is only returned here, the Tk-Signature header is "t=<unix time>,v1=<hex HMAC-SHA256>"
+// @Description of "<unix time>.<body>" with it. Failed deliveries are retried with exponential backoff.
+// @ID create-webhook
+// @Accept  json
+// @Produce  json
+// @Param webhook body model.WebhookRequest true "endpoint and event types"
+// @Success 201 {object} model.WebhookResponse
+// @Failure 400,422 {object} server.ErrorResponse
+// @Failure 401 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /webhooks [post].
+func (s *Server) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req model.WebhookRequest
+	if !s.decodeRequest(w, r, &req) {
+		return
+	}
+
+	webhook, err := s.app.CreateWebhook(r.Context(), models.Webhook{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+	})
+	if err != nil {
+		respondStorageError("webhook", err, w, r)
+		return
+	}
+	resp := newWebhookResponse(webhook)
+	resp.Secret = webhook.Secret
+	srv.RespondCreated(resp, w, r)
+}
+
+// @Summary List webhooks
+// @Tags webhooks
+// @Security BearerAuth
+// @ID list-webhooks
+// @Produce  json
+// @Success 200 {array} model.WebhookResponse
+// @Failure 401 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /webhooks [get].
+func (s *Server) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := s.app.GetWebhooks(r.Context())
+	if err != nil {
+		respondStorageError("webhook", err, w, r)
+		return
+	}
+	resp := make([]model.WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		resp = append(resp, newWebhookResponse(webhook))
+	}
+	srv.RespondOK(resp, w, r)
+}
+
+// @Summary Get webhook
+// @Tags webhooks
+// @Security BearerAuth
+// @ID get-webhook
+// @Produce  json
+// @Param id path int true "webhook ID"
+// @Success 200 {object} model.WebhookResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 401 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /webhooks/{id} [get].
+func (s *Server) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := s.app.GetWebhook(r.Context(), id)
+	if err != nil {
+		respondStorageError("webhook", err, w, r)
+		return
+	}
+	srv.RespondOK(newWebhookResponse(webhook), w, r)
+}
+
+// @Summary Delete webhook
+// @Tags webhooks
+// @Security BearerAuth
+// @Description Unregister an endpoint, its deliveries and their logs are dropped
+// @ID delete-webhook
+// @Param id path int true "webhook ID"
+// @Success 204
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 401 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /webhooks/{id} [delete].
+func (s *Server) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.app.DeleteWebhook(r.Context(), id); err != nil {
+		respondStorageError("webhook", err, w, r)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary List webhook deliveries
+// @Tags webhooks
+// @Security BearerAuth
+// @Description Deliveries newest first, status=dead lists the dead letters
+// @ID list-webhook-deliveries
+// @Produce  json
+// @Param webhookId query int false "webhook ID"
+// @Param status query string false "pending, delivered or dead"
+// @Success 200 {array} model.WebhookDeliveryResponse
+// @Failure 400 {object} server.ErrorResponse
+// @Failure 401 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /webhooks/deliveries [get].
+func (s *Server) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	var (
+		v      validation.Validator
+		filter models.WebhookDeliveryFilter
+	)
+	q := r.URL.Query()
+	if id := q.Get("webhookId"); id != "" {
+		var err error
+		filter.WebhookID, err = strconv.ParseInt(id, 10, 64)
+		v.Check(err == nil && filter.WebhookID > 0, validation.Pointer("webhookId"), model.ErrInvalidValue)
+	}
+	switch status := models.WebhookDeliveryStatus(q.Get("status")); status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+		filter.Status = status
+	default:
+		v.Add(validation.Pointer("status"), model.ErrInvalidValue)
+	}
+	if err := v.Err(); err != nil {
+		srv.RespondWithError(slugerrors.NewBadRequestError("invalid delivery filter", "invalid-filter").Wrap(err), w, r)
+		return
+	}
+
+	deliveries, err := s.app.GetWebhookDeliveries(r.Context(), filter)
+	if err != nil {
+		respondStorageError("webhook-delivery", err, w, r)
+		return
+	}
+	resp := make([]model.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		resp = append(resp, newWebhookDeliveryResponse(d))
+	}
+	srv.RespondOK(resp, w, r)
+}
+
+// @Summary Get webhook delivery
+// @Tags webhooks
+// @Security BearerAuth
+// @Description A delivery with its payload and the log of its attempts
+// @ID get-webhook-delivery
+// @Produce  json
+// @Param id path int true "delivery ID"
+// @Success 200 {object} model.WebhookDeliveryResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 401 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /webhooks/deliveries/{id} [get].
+func (s *Server) GetWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, attempts, err := s.app.GetWebhookDelivery(r.Context(), id)
+	if err != nil {
+		respondStorageError("webhook-delivery", err, w, r)
+		return
+	}
+	resp := newWebhookDeliveryResponse(delivery)
+	resp.Payload = delivery.Payload
+	resp.Log = make([]model.WebhookAttemptResponse, 0, len(attempts))
+	for _, a := range attempts {
+		resp.Log = append(resp.Log, model.WebhookAttemptResponse{
+			StatusCode: a.StatusCode,
+			Error:      a.Error,
+			DurationMS: a.DurationMS,
+			At:         a.CreatedAt.UTC(),
+		})
+	}
+	srv.RespondOK(resp, w, r)
+}
+
+// @Summary Redeliver webhook delivery
+// @Tags webhooks
+// @Security BearerAuth
+// @Description Send a delivery again now with a fresh set of attempts, e.g. a dead letter once the endpoint is fixed
+// @ID redeliver-webhook-delivery
+// @Produce  json
+// @Param id path int true "delivery ID"
+// @Success 200 {object} model.WebhookDeliveryResponse
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 401 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /webhooks/deliveries/{id}/redeliver [post].
+func (s *Server) RedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := s.app.RedeliverWebhookDelivery(r.Context(), id)
+	if err != nil {
+		respondStorageError("webhook-delivery", err, w, r)
+		return
+	}
+	srv.RespondOK(newWebhookDeliveryResponse(delivery), w, r)
+}
@@ -0,0 +1,258 @@
+package internalhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/availability"
+	"github.com/cronnoss/tk-api/internal/common/slugerrors"
+	"github.com/cronnoss/tk-api/internal/common/srv"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsWriteWait = 10 * time.Second
+	// wsPongWait is how long a client may stay silent, pings are sent well within it.
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = wsPongWait * 9 / 10
+	wsMaxMessage = 4096
+	// wsSendBuffer bounds replies queued for a client, commands aren't read while it is full.
+	wsSendBuffer = 16
+)
+
+var upgrader = websocket.Upgrader{ReadBufferSize: 1024, WriteBufferSize: 1024}
+
+func (s *Server) registerWebSocketRoutes(router *mux.Router, midLogger *MiddlewareLogger) {
+	handle := func(path string, h http.HandlerFunc, method string) {
+		router.Handle(path, midLogger.setCommonHeadersMiddleware(
+			midLogger.loggingMiddleware(h))).Methods(method)
+	}
+
+	handle("/events/{id:[0-9]+}/availability/ws", s.SeatSelection, http.MethodGet)
+}
+
+// seatConn is a seat selection WebSocket connection.
+type seatConn struct {
+	s          *Server
+	conn       *websocket.Conn
+	eventID    int64
+	customerID int64
+	instance   string
+	send       chan model.SeatMessage
+	// holds are the holds made over the connection, they are released when it closes.
+	holds map[int64]bool
+}
+
+// @Summary Seat selection WebSocket
+// @Tags holds
+// @Description WebSocket of an event. The server sends model.SeatMessage: availability changes like the
+// @Description availability stream and replies to model.SeatCommand sent by the client to hold places
+// @Description or release holds made over the same connection. Holds still active when the connection
+// @Description closes are released. Clients have to answer pings.
+// @ID seat-selection
+// @Param id path int true "event ID"
+// @Param customerId query int false "customer the places are held for"
+// @Success 101
+// @Failure 400,404 {object} server.ErrorResponse
+// @Failure 500 {object} server.ErrorResponse
+// @Router /events/{id}/availability/ws [get].
+func (s *Server) SeatSelection(w http.ResponseWriter, r *http.Request) {
+	eventID, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	var customerID int64
+	if v := r.URL.Query().Get("customerId"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			srv.RespondWithError(slugerrors.NewBadRequestError("invalid customerId", "invalid-customer-id"), w, r)
+			return
+		}
+		customerID = id
+	}
+	if _, err := s.app.GetEvent(r.Context(), eventID); err != nil {
+		respondStorageError("event", err, w, r)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has responded to the client.
+		return
+	}
+	c := &seatConn{
+		s:          s,
+		conn:       conn,
+		eventID:    eventID,
+		customerID: customerID,
+		instance:   r.URL.RequestURI(),
+		send:       make(chan model.SeatMessage, wsSendBuffer),
+		holds:      make(map[int64]bool),
+	}
+
+	readDone := make(chan struct{})
+	writeDone := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(writeDone)
+		c.writeLoop(r.Context(), readDone)
+	}()
+	c.readLoop(r.Context(), writeDone)
+	close(readDone)
+	wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 5*time.Second)
+	defer cancel()
+	c.releaseHolds(ctx)
+}
+
+// readLoop handles commands until the connection fails or the writer stops.
+func (c *seatConn) readLoop(ctx context.Context, writeDone <-chan struct{}) {
+	defer c.conn.Close()
+	c.conn.SetReadLimit(wsMaxMessage)
+	_ = c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var reply model.SeatMessage
+		var cmd model.SeatCommand
+		if err := json.Unmarshal(data, &cmd); err != nil {
+			reply = c.errorMessage("", slugerrors.NewBadRequestError("invalid command", "invalid-command").Wrap(err))
+		} else {
+			reply = c.handle(ctx, cmd)
+		}
+		select {
+		case c.send <- reply:
+		case <-writeDone:
+			return
+		}
+	}
+}
+
+func (c *seatConn) handle(ctx context.Context, cmd model.SeatCommand) model.SeatMessage {
+	if err := cmd.Validate(); err != nil {
+		return c.errorMessage(cmd.ID, slugerrors.NewValidationError("invalid command", "invalid-command").Wrap(err))
+	}
+
+	switch cmd.Type {
+	case model.SeatCommandHold:
+		hold, err := c.s.app.CreateHold(ctx, c.eventID, c.customerID, cmd.PlaceIDs)
+		if err != nil {
+			return c.errorMessage(cmd.ID, holdError(err))
+		}
+		c.holds[hold.ID] = true
+		resp := newHoldResponse(hold)
+		return model.SeatMessage{Type: model.SeatMessageHeld, ID: cmd.ID, Hold: &resp}
+	default:
+		// Holds of others can't be released here.
+		if !c.holds[cmd.HoldID] {
+			return c.errorMessage(cmd.ID, storageError("hold", model.ErrNotFound))
+		}
+		delete(c.holds, cmd.HoldID)
+		hold, err := c.s.app.ReleaseHold(ctx, cmd.HoldID)
+		if err != nil {
+			return c.errorMessage(cmd.ID, storageError("hold", err))
+		}
+		resp := newHoldResponse(hold)
+		return model.SeatMessage{Type: model.SeatMessageReleased, ID: cmd.ID, Hold: &resp}
+	}
+}
+
+func (c *seatConn) errorMessage(id string, err error) model.SeatMessage {
+	problem := srv.Problem(err, c.instance)
+	if problem.Status >= http.StatusInternalServerError {
+		c.s.log.Errorf("failed to handle seat command of event %d:%v\n", c.eventID, err)
+	}
+	return model.SeatMessage{Type: model.SeatMessageError, ID: id, Error: &problem}
+}
+
+func changeMessage(ch availability.Change) model.SeatMessage {
+	return model.SeatMessage{
+		Type: model.SeatMessageChange,
+		Kind: string(ch.Kind),
+		Change: &model.AvailabilityChangeResponse{
+			EventID: ch.EventID,
+			PlaceID: ch.PlaceID,
+			Status:  string(ch.Status),
+			At:      ch.At.UTC(),
+		},
+	}
+}
+
+// writeLoop sends availability changes, replies and pings until the reader stops, a write fails or ctx is done.
+// A client falling behind on changes is resubscribed after the last change sent to it.
+func (c *seatConn) writeLoop(ctx context.Context, readDone <-chan struct{}) {
+	defer c.conn.Close()
+	sub := c.s.app.SubscribeAvailability(c.eventID, 0)
+	defer func() { sub.Close() }()
+	lastID := sub.LastID
+
+	ping := time.NewTicker(wsPingPeriod)
+	defer ping.Stop()
+
+	for {
+		var msgs []model.SeatMessage
+		select {
+		case <-readDone:
+			return
+		case <-ctx.Done():
+			_ = c.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server is shutting down"),
+				time.Now().Add(wsWriteWait))
+			return
+		case <-ping.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+			continue
+		case msg := <-c.send:
+			msgs = append(msgs, msg)
+		case ch, ok := <-sub.C:
+			if ok {
+				lastID = ch.ID
+				msgs = append(msgs, changeMessage(ch))
+				break
+			}
+			sub = c.s.app.SubscribeAvailability(c.eventID, lastID)
+			if sub.Missed {
+				lastID = sub.LastID
+				msgs = append(msgs, model.SeatMessage{Type: model.SeatMessageReset})
+			}
+			for _, ch := range sub.Replay {
+				lastID = ch.ID
+				msgs = append(msgs, changeMessage(ch))
+			}
+		}
+
+		for _, msg := range msgs {
+			_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// releaseHolds puts places held over the connection back on sale, holds already sold or released are skipped.
+func (c *seatConn) releaseHolds(ctx context.Context) {
+	for id := range c.holds {
+		if _, err := c.s.app.ReleaseHold(ctx, id); err != nil && !errors.Is(err, model.ErrNotFound) {
+			c.s.log.Errorf("failed to release hold %d:%v\n", id, err)
+		}
+	}
+}
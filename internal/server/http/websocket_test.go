@@ -0,0 +1,125 @@
+package internalhttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/availability"
+	"github.com/cronnoss/tk-api/internal/logger"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/server/mocks"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// dialSeats serves the seat selection WebSocket of event 1 backed by app and broker and connects to it.
+func dialSeats(t *testing.T, app *mocks.Application, broker *availability.Broker) *websocket.Conn {
+	t.Helper()
+	app.On("GetEvent", mock.Anything, int64(1)).Return(models.Event{ID: 1}, nil)
+	app.On("SubscribeAvailability", int64(1), mock.Anything).Return(broker.Subscribe).Maybe()
+
+	log := logger.NewLogger("error", io.Discard)
+	s := NewServer(log, app, "", "")
+	router := mux.NewRouter()
+	s.registerWebSocketRoutes(router, NewMiddlewareLogger())
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		router.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), KeyLoggerID, Logger(log))))
+	}))
+	t.Cleanup(ts.Close)
+
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/events/1/availability/ws?customerId=7"
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readSeatMessage(t *testing.T, conn *websocket.Conn) model.SeatMessage {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var msg model.SeatMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func TestSeatSelection(t *testing.T) {
+	app := mocks.NewApplication(t)
+	broker := availability.NewBroker(0)
+	hold := models.Hold{ID: 3, EventID: 1, PlaceIDs: []int64{10, 11}, ExpiresAt: time.Now().Add(time.Minute)}
+	app.On("CreateHold", mock.Anything, int64(1), int64(7), []int64{10, 11}).Return(hold, nil).Once()
+	app.On("ReleaseHold", mock.Anything, int64(3)).Return(hold, nil).Once()
+	conn := dialSeats(t, app, broker)
+
+	require.NoError(t, conn.WriteJSON(model.SeatCommand{Type: "hold", ID: "a", PlaceIDs: []int64{10, 11}}))
+	msg := readSeatMessage(t, conn)
+	require.Equal(t, model.SeatMessageHeld, msg.Type)
+	require.Equal(t, "a", msg.ID)
+	require.Equal(t, int64(3), msg.Hold.ID)
+
+	broker.Publish(availability.Change{EventID: 1, PlaceID: 10, Kind: availability.Held, Status: models.PlaceHeld})
+	msg = readSeatMessage(t, conn)
+	require.Equal(t, model.SeatMessageChange, msg.Type)
+	require.Equal(t, "held", msg.Kind)
+	require.Equal(t, int64(10), msg.Change.PlaceID)
+
+	require.NoError(t, conn.WriteJSON(model.SeatCommand{Type: "release", ID: "b", HoldID: 3}))
+	msg = readSeatMessage(t, conn)
+	require.Equal(t, model.SeatMessageReleased, msg.Type)
+	require.Equal(t, "b", msg.ID)
+
+	// Released already, it is no longer the connection's to release.
+	require.NoError(t, conn.WriteJSON(model.SeatCommand{Type: "release", ID: "c", HoldID: 3}))
+	msg = readSeatMessage(t, conn)
+	require.Equal(t, model.SeatMessageError, msg.Type)
+	require.Equal(t, "hold-not-found", msg.Error.Slug)
+}
+
+func TestSeatSelectionErrors(t *testing.T) {
+	app := mocks.NewApplication(t)
+	app.On("CreateHold", mock.Anything, int64(1), int64(7), []int64{10}).Return(models.Hold{}, model.ErrUnavailable)
+	conn := dialSeats(t, app, availability.NewBroker(0))
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("{")))
+	msg := readSeatMessage(t, conn)
+	require.Equal(t, model.SeatMessageError, msg.Type)
+	require.Equal(t, "invalid-command", msg.Error.Slug)
+
+	require.NoError(t, conn.WriteJSON(model.SeatCommand{Type: "hold", ID: "a"}))
+	msg = readSeatMessage(t, conn)
+	require.Equal(t, "invalid-command", msg.Error.Slug)
+	require.Equal(t, http.StatusUnprocessableEntity, msg.Error.Status)
+
+	require.NoError(t, conn.WriteJSON(model.SeatCommand{Type: "hold", ID: "b", PlaceIDs: []int64{10}}))
+	msg = readSeatMessage(t, conn)
+	require.Equal(t, "b", msg.ID)
+	require.Equal(t, "places-unavailable", msg.Error.Slug)
+}
+
+func TestSeatSelectionReleasesHoldsOnDisconnect(t *testing.T) {
+	app := mocks.NewApplication(t)
+	hold := models.Hold{ID: 3, EventID: 1, PlaceIDs: []int64{10}}
+	app.On("CreateHold", mock.Anything, int64(1), int64(7), []int64{10}).Return(hold, nil)
+	released := make(chan struct{})
+	app.On("ReleaseHold", mock.Anything, int64(3)).Return(hold, nil).Once().
+		Run(func(mock.Arguments) { close(released) })
+	conn := dialSeats(t, app, availability.NewBroker(0))
+
+	require.NoError(t, conn.WriteJSON(model.SeatCommand{Type: "hold", PlaceIDs: []int64{10}}))
+	require.Equal(t, model.SeatMessageHeld, readSeatMessage(t, conn).Type)
+	require.NoError(t, conn.Close())
+
+	select {
+	case <-released:
+	case <-time.After(5 * time.Second):
+		t.Fatal("hold was not released")
+	}
+}
@@ -1,12 +1,23 @@
-// Code generated by mockery v2.43.2. DO NOT EDIT.
+// Code generated by mockery v2.53.7. DO NOT EDIT.
 
 package mocks
 
 import (
 	context "context"
 
-	models "github.com/cronnoss/tk-api/internal/storage/models"
+	availability "github.com/cronnoss/tk-api/internal/availability"
+
+	ed25519 "crypto/ed25519"
+
+	ical "github.com/cronnoss/tk-api/internal/ical"
+
 	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/cronnoss/tk-api/internal/storage/models"
+
+	pricing "github.com/cronnoss/tk-api/internal/pricing"
+
+	seating "github.com/cronnoss/tk-api/internal/seating"
 )
 
 // Application is an autogenerated mock type for the Application type
@@ -14,35 +25,3654 @@ type Application struct {
 	mock.Mock
 }
 
-type Application_Expecter struct {
-	mock *mock.Mock
+type Application_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Application) EXPECT() *Application_Expecter {
+	return &Application_Expecter{mock: &_m.Mock}
+}
+
+// AssignPrices provides a mock function with given fields: ctx, eventID, assignments, check
+func (_m *Application) AssignPrices(ctx context.Context, eventID int64, assignments []models.PriceAssignment, check models.Check[models.Event]) (int64, error) {
+	ret := _m.Called(ctx, eventID, assignments, check)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignPrices")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []models.PriceAssignment, models.Check[models.Event]) (int64, error)); ok {
+		return rf(ctx, eventID, assignments, check)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []models.PriceAssignment, models.Check[models.Event]) int64); ok {
+		r0 = rf(ctx, eventID, assignments, check)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []models.PriceAssignment, models.Check[models.Event]) error); ok {
+		r1 = rf(ctx, eventID, assignments, check)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_AssignPrices_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AssignPrices'
+type Application_AssignPrices_Call struct {
+	*mock.Call
+}
+
+// AssignPrices is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventID int64
+//   - assignments []models.PriceAssignment
+//   - check models.Check[models.Event]
+func (_e *Application_Expecter) AssignPrices(ctx interface{}, eventID interface{}, assignments interface{}, check interface{}) *Application_AssignPrices_Call {
+	return &Application_AssignPrices_Call{Call: _e.mock.On("AssignPrices", ctx, eventID, assignments, check)}
+}
+
+func (_c *Application_AssignPrices_Call) Run(run func(ctx context.Context, eventID int64, assignments []models.PriceAssignment, check models.Check[models.Event])) *Application_AssignPrices_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].([]models.PriceAssignment), args[3].(models.Check[models.Event]))
+	})
+	return _c
+}
+
+func (_c *Application_AssignPrices_Call) Return(_a0 int64, _a1 error) *Application_AssignPrices_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_AssignPrices_Call) RunAndReturn(run func(context.Context, int64, []models.PriceAssignment, models.Check[models.Event]) (int64, error)) *Application_AssignPrices_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AttachEventToHall provides a mock function with given fields: ctx, eventID, hallID, check
+func (_m *Application) AttachEventToHall(ctx context.Context, eventID int64, hallID int64, check models.Check[models.Event]) (models.Event, error) {
+	ret := _m.Called(ctx, eventID, hallID, check)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachEventToHall")
+	}
+
+	var r0 models.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, models.Check[models.Event]) (models.Event, error)); ok {
+		return rf(ctx, eventID, hallID, check)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, models.Check[models.Event]) models.Event); ok {
+		r0 = rf(ctx, eventID, hallID, check)
+	} else {
+		r0 = ret.Get(0).(models.Event)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, models.Check[models.Event]) error); ok {
+		r1 = rf(ctx, eventID, hallID, check)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_AttachEventToHall_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AttachEventToHall'
+type Application_AttachEventToHall_Call struct {
+	*mock.Call
+}
+
+// AttachEventToHall is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventID int64
+//   - hallID int64
+//   - check models.Check[models.Event]
+func (_e *Application_Expecter) AttachEventToHall(ctx interface{}, eventID interface{}, hallID interface{}, check interface{}) *Application_AttachEventToHall_Call {
+	return &Application_AttachEventToHall_Call{Call: _e.mock.On("AttachEventToHall", ctx, eventID, hallID, check)}
+}
+
+func (_c *Application_AttachEventToHall_Call) Run(run func(ctx context.Context, eventID int64, hallID int64, check models.Check[models.Event])) *Application_AttachEventToHall_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].(models.Check[models.Event]))
+	})
+	return _c
+}
+
+func (_c *Application_AttachEventToHall_Call) Return(_a0 models.Event, _a1 error) *Application_AttachEventToHall_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_AttachEventToHall_Call) RunAndReturn(run func(context.Context, int64, int64, models.Check[models.Event]) (models.Event, error)) *Application_AttachEventToHall_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AvailabilitySubscribed provides a mock function with no fields
+func (_m *Application) AvailabilitySubscribed() []int64 {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for AvailabilitySubscribed")
+	}
+
+	var r0 []int64
+	if rf, ok := ret.Get(0).(func() []int64); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	return r0
+}
+
+// Application_AvailabilitySubscribed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AvailabilitySubscribed'
+type Application_AvailabilitySubscribed_Call struct {
+	*mock.Call
+}
+
+// AvailabilitySubscribed is a helper method to define mock.On call
+func (_e *Application_Expecter) AvailabilitySubscribed() *Application_AvailabilitySubscribed_Call {
+	return &Application_AvailabilitySubscribed_Call{Call: _e.mock.On("AvailabilitySubscribed")}
+}
+
+func (_c *Application_AvailabilitySubscribed_Call) Run(run func()) *Application_AvailabilitySubscribed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Application_AvailabilitySubscribed_Call) Return(_a0 []int64) *Application_AvailabilitySubscribed_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Application_AvailabilitySubscribed_Call) RunAndReturn(run func() []int64) *Application_AvailabilitySubscribed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// BestSeats provides a mock function with given fields: ctx, eventID, prefs, hold, customerID
+func (_m *Application) BestSeats(ctx context.Context, eventID int64, prefs seating.Preferences, hold bool, customerID int64) ([]models.Place, *models.Hold, error) {
+	ret := _m.Called(ctx, eventID, prefs, hold, customerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BestSeats")
+	}
+
+	var r0 []models.Place
+	var r1 *models.Hold
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, seating.Preferences, bool, int64) ([]models.Place, *models.Hold, error)); ok {
+		return rf(ctx, eventID, prefs, hold, customerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, seating.Preferences, bool, int64) []models.Place); ok {
+		r0 = rf(ctx, eventID, prefs, hold, customerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Place)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, seating.Preferences, bool, int64) *models.Hold); ok {
+		r1 = rf(ctx, eventID, prefs, hold, customerID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*models.Hold)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, seating.Preferences, bool, int64) error); ok {
+		r2 = rf(ctx, eventID, prefs, hold, customerID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Application_BestSeats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BestSeats'
+type Application_BestSeats_Call struct {
+	*mock.Call
+}
+
+// BestSeats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventID int64
+//   - prefs seating.Preferences
+//   - hold bool
+//   - customerID int64
+func (_e *Application_Expecter) BestSeats(ctx interface{}, eventID interface{}, prefs interface{}, hold interface{}, customerID interface{}) *Application_BestSeats_Call {
+	return &Application_BestSeats_Call{Call: _e.mock.On("BestSeats", ctx, eventID, prefs, hold, customerID)}
+}
+
+func (_c *Application_BestSeats_Call) Run(run func(ctx context.Context, eventID int64, prefs seating.Preferences, hold bool, customerID int64)) *Application_BestSeats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(seating.Preferences), args[3].(bool), args[4].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_BestSeats_Call) Return(_a0 []models.Place, _a1 *models.Hold, _a2 error) *Application_BestSeats_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *Application_BestSeats_Call) RunAndReturn(run func(context.Context, int64, seating.Preferences, bool, int64) ([]models.Place, *models.Hold, error)) *Application_BestSeats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CancelEvent provides a mock function with given fields: ctx, id, check
+func (_m *Application) CancelEvent(ctx context.Context, id int64, check models.Check[models.Event]) (models.Event, error) {
+	ret := _m.Called(ctx, id, check)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelEvent")
+	}
+
+	var r0 models.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.Check[models.Event]) (models.Event, error)); ok {
+		return rf(ctx, id, check)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.Check[models.Event]) models.Event); ok {
+		r0 = rf(ctx, id, check)
+	} else {
+		r0 = ret.Get(0).(models.Event)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, models.Check[models.Event]) error); ok {
+		r1 = rf(ctx, id, check)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CancelEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelEvent'
+type Application_CancelEvent_Call struct {
+	*mock.Call
+}
+
+// CancelEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - check models.Check[models.Event]
+func (_e *Application_Expecter) CancelEvent(ctx interface{}, id interface{}, check interface{}) *Application_CancelEvent_Call {
+	return &Application_CancelEvent_Call{Call: _e.mock.On("CancelEvent", ctx, id, check)}
+}
+
+func (_c *Application_CancelEvent_Call) Run(run func(ctx context.Context, id int64, check models.Check[models.Event])) *Application_CancelEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(models.Check[models.Event]))
+	})
+	return _c
+}
+
+func (_c *Application_CancelEvent_Call) Return(_a0 models.Event, _a1 error) *Application_CancelEvent_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CancelEvent_Call) RunAndReturn(run func(context.Context, int64, models.Check[models.Event]) (models.Event, error)) *Application_CancelEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CancelWaitlistEntry provides a mock function with given fields: ctx, id
+func (_m *Application) CancelWaitlistEntry(ctx context.Context, id int64) (models.WaitlistEntry, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelWaitlistEntry")
+	}
+
+	var r0 models.WaitlistEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.WaitlistEntry, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.WaitlistEntry); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.WaitlistEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CancelWaitlistEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelWaitlistEntry'
+type Application_CancelWaitlistEntry_Call struct {
+	*mock.Call
+}
+
+// CancelWaitlistEntry is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) CancelWaitlistEntry(ctx interface{}, id interface{}) *Application_CancelWaitlistEntry_Call {
+	return &Application_CancelWaitlistEntry_Call{Call: _e.mock.On("CancelWaitlistEntry", ctx, id)}
+}
+
+func (_c *Application_CancelWaitlistEntry_Call) Run(run func(ctx context.Context, id int64)) *Application_CancelWaitlistEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_CancelWaitlistEntry_Call) Return(_a0 models.WaitlistEntry, _a1 error) *Application_CancelWaitlistEntry_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CancelWaitlistEntry_Call) RunAndReturn(run func(context.Context, int64) (models.WaitlistEntry, error)) *Application_CancelWaitlistEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CompleteIdempotencyKey provides a mock function with given fields: ctx, key
+func (_m *Application) CompleteIdempotencyKey(ctx context.Context, key models.IdempotencyKey) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.IdempotencyKey) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Application_CompleteIdempotencyKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteIdempotencyKey'
+type Application_CompleteIdempotencyKey_Call struct {
+	*mock.Call
+}
+
+// CompleteIdempotencyKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key models.IdempotencyKey
+func (_e *Application_Expecter) CompleteIdempotencyKey(ctx interface{}, key interface{}) *Application_CompleteIdempotencyKey_Call {
+	return &Application_CompleteIdempotencyKey_Call{Call: _e.mock.On("CompleteIdempotencyKey", ctx, key)}
+}
+
+func (_c *Application_CompleteIdempotencyKey_Call) Run(run func(ctx context.Context, key models.IdempotencyKey)) *Application_CompleteIdempotencyKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.IdempotencyKey))
+	})
+	return _c
+}
+
+func (_c *Application_CompleteIdempotencyKey_Call) Return(_a0 error) *Application_CompleteIdempotencyKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Application_CompleteIdempotencyKey_Call) RunAndReturn(run func(context.Context, models.IdempotencyKey) error) *Application_CompleteIdempotencyKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateCustomer provides a mock function with given fields: ctx, customer
+func (_m *Application) CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error) {
+	ret := _m.Called(ctx, customer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCustomer")
+	}
+
+	var r0 models.Customer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Customer) (models.Customer, error)); ok {
+		return rf(ctx, customer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Customer) models.Customer); ok {
+		r0 = rf(ctx, customer)
+	} else {
+		r0 = ret.Get(0).(models.Customer)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Customer) error); ok {
+		r1 = rf(ctx, customer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreateCustomer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateCustomer'
+type Application_CreateCustomer_Call struct {
+	*mock.Call
+}
+
+// CreateCustomer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - customer models.Customer
+func (_e *Application_Expecter) CreateCustomer(ctx interface{}, customer interface{}) *Application_CreateCustomer_Call {
+	return &Application_CreateCustomer_Call{Call: _e.mock.On("CreateCustomer", ctx, customer)}
+}
+
+func (_c *Application_CreateCustomer_Call) Run(run func(ctx context.Context, customer models.Customer)) *Application_CreateCustomer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Customer))
+	})
+	return _c
+}
+
+func (_c *Application_CreateCustomer_Call) Return(_a0 models.Customer, _a1 error) *Application_CreateCustomer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreateCustomer_Call) RunAndReturn(run func(context.Context, models.Customer) (models.Customer, error)) *Application_CreateCustomer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateEvent provides a mock function with given fields: ctx, event
+func (_m *Application) CreateEvent(ctx context.Context, event models.Event) (models.Event, error) {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEvent")
+	}
+
+	var r0 models.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Event) (models.Event, error)); ok {
+		return rf(ctx, event)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Event) models.Event); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Get(0).(models.Event)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Event) error); ok {
+		r1 = rf(ctx, event)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreateEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateEvent'
+type Application_CreateEvent_Call struct {
+	*mock.Call
+}
+
+// CreateEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event models.Event
+func (_e *Application_Expecter) CreateEvent(ctx interface{}, event interface{}) *Application_CreateEvent_Call {
+	return &Application_CreateEvent_Call{Call: _e.mock.On("CreateEvent", ctx, event)}
+}
+
+func (_c *Application_CreateEvent_Call) Run(run func(ctx context.Context, event models.Event)) *Application_CreateEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Event))
+	})
+	return _c
+}
+
+func (_c *Application_CreateEvent_Call) Return(_a0 models.Event, _a1 error) *Application_CreateEvent_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreateEvent_Call) RunAndReturn(run func(context.Context, models.Event) (models.Event, error)) *Application_CreateEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateEvents provides a mock function with given fields: ctx, events
+func (_m *Application) CreateEvents(ctx context.Context, events []models.Event) ([]models.Event, error) {
+	ret := _m.Called(ctx, events)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEvents")
+	}
+
+	var r0 []models.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Event) ([]models.Event, error)); ok {
+		return rf(ctx, events)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Event) []models.Event); ok {
+		r0 = rf(ctx, events)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.Event) error); ok {
+		r1 = rf(ctx, events)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreateEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateEvents'
+type Application_CreateEvents_Call struct {
+	*mock.Call
+}
+
+// CreateEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - events []models.Event
+func (_e *Application_Expecter) CreateEvents(ctx interface{}, events interface{}) *Application_CreateEvents_Call {
+	return &Application_CreateEvents_Call{Call: _e.mock.On("CreateEvents", ctx, events)}
+}
+
+func (_c *Application_CreateEvents_Call) Run(run func(ctx context.Context, events []models.Event)) *Application_CreateEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.Event))
+	})
+	return _c
+}
+
+func (_c *Application_CreateEvents_Call) Return(_a0 []models.Event, _a1 error) *Application_CreateEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreateEvents_Call) RunAndReturn(run func(context.Context, []models.Event) ([]models.Event, error)) *Application_CreateEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateHall provides a mock function with given fields: ctx, hall
+func (_m *Application) CreateHall(ctx context.Context, hall models.Hall) (models.Hall, error) {
+	ret := _m.Called(ctx, hall)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateHall")
+	}
+
+	var r0 models.Hall
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Hall) (models.Hall, error)); ok {
+		return rf(ctx, hall)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Hall) models.Hall); ok {
+		r0 = rf(ctx, hall)
+	} else {
+		r0 = ret.Get(0).(models.Hall)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Hall) error); ok {
+		r1 = rf(ctx, hall)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreateHall_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateHall'
+type Application_CreateHall_Call struct {
+	*mock.Call
+}
+
+// CreateHall is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hall models.Hall
+func (_e *Application_Expecter) CreateHall(ctx interface{}, hall interface{}) *Application_CreateHall_Call {
+	return &Application_CreateHall_Call{Call: _e.mock.On("CreateHall", ctx, hall)}
+}
+
+func (_c *Application_CreateHall_Call) Run(run func(ctx context.Context, hall models.Hall)) *Application_CreateHall_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Hall))
+	})
+	return _c
+}
+
+func (_c *Application_CreateHall_Call) Return(_a0 models.Hall, _a1 error) *Application_CreateHall_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreateHall_Call) RunAndReturn(run func(context.Context, models.Hall) (models.Hall, error)) *Application_CreateHall_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateHallPlaces provides a mock function with given fields: ctx, hallID, places
+func (_m *Application) CreateHallPlaces(ctx context.Context, hallID int64, places []models.Place) ([]models.Place, error) {
+	ret := _m.Called(ctx, hallID, places)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateHallPlaces")
+	}
+
+	var r0 []models.Place
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []models.Place) ([]models.Place, error)); ok {
+		return rf(ctx, hallID, places)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []models.Place) []models.Place); ok {
+		r0 = rf(ctx, hallID, places)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Place)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []models.Place) error); ok {
+		r1 = rf(ctx, hallID, places)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreateHallPlaces_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateHallPlaces'
+type Application_CreateHallPlaces_Call struct {
+	*mock.Call
+}
+
+// CreateHallPlaces is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hallID int64
+//   - places []models.Place
+func (_e *Application_Expecter) CreateHallPlaces(ctx interface{}, hallID interface{}, places interface{}) *Application_CreateHallPlaces_Call {
+	return &Application_CreateHallPlaces_Call{Call: _e.mock.On("CreateHallPlaces", ctx, hallID, places)}
+}
+
+func (_c *Application_CreateHallPlaces_Call) Run(run func(ctx context.Context, hallID int64, places []models.Place)) *Application_CreateHallPlaces_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].([]models.Place))
+	})
+	return _c
+}
+
+func (_c *Application_CreateHallPlaces_Call) Return(_a0 []models.Place, _a1 error) *Application_CreateHallPlaces_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreateHallPlaces_Call) RunAndReturn(run func(context.Context, int64, []models.Place) ([]models.Place, error)) *Application_CreateHallPlaces_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateHold provides a mock function with given fields: ctx, eventID, customerID, placeIDs
+func (_m *Application) CreateHold(ctx context.Context, eventID int64, customerID int64, placeIDs []int64) (models.Hold, error) {
+	ret := _m.Called(ctx, eventID, customerID, placeIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateHold")
+	}
+
+	var r0 models.Hold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, []int64) (models.Hold, error)); ok {
+		return rf(ctx, eventID, customerID, placeIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, []int64) models.Hold); ok {
+		r0 = rf(ctx, eventID, customerID, placeIDs)
+	} else {
+		r0 = ret.Get(0).(models.Hold)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, []int64) error); ok {
+		r1 = rf(ctx, eventID, customerID, placeIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreateHold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateHold'
+type Application_CreateHold_Call struct {
+	*mock.Call
+}
+
+// CreateHold is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventID int64
+//   - customerID int64
+//   - placeIDs []int64
+func (_e *Application_Expecter) CreateHold(ctx interface{}, eventID interface{}, customerID interface{}, placeIDs interface{}) *Application_CreateHold_Call {
+	return &Application_CreateHold_Call{Call: _e.mock.On("CreateHold", ctx, eventID, customerID, placeIDs)}
+}
+
+func (_c *Application_CreateHold_Call) Run(run func(ctx context.Context, eventID int64, customerID int64, placeIDs []int64)) *Application_CreateHold_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int64), args[3].([]int64))
+	})
+	return _c
+}
+
+func (_c *Application_CreateHold_Call) Return(_a0 models.Hold, _a1 error) *Application_CreateHold_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreateHold_Call) RunAndReturn(run func(context.Context, int64, int64, []int64) (models.Hold, error)) *Application_CreateHold_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateIdempotencyKey provides a mock function with given fields: ctx, key
+func (_m *Application) CreateIdempotencyKey(ctx context.Context, key models.IdempotencyKey) (models.IdempotencyKey, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateIdempotencyKey")
+	}
+
+	var r0 models.IdempotencyKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.IdempotencyKey) (models.IdempotencyKey, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.IdempotencyKey) models.IdempotencyKey); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(models.IdempotencyKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.IdempotencyKey) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreateIdempotencyKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateIdempotencyKey'
+type Application_CreateIdempotencyKey_Call struct {
+	*mock.Call
+}
+
+// CreateIdempotencyKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key models.IdempotencyKey
+func (_e *Application_Expecter) CreateIdempotencyKey(ctx interface{}, key interface{}) *Application_CreateIdempotencyKey_Call {
+	return &Application_CreateIdempotencyKey_Call{Call: _e.mock.On("CreateIdempotencyKey", ctx, key)}
+}
+
+func (_c *Application_CreateIdempotencyKey_Call) Run(run func(ctx context.Context, key models.IdempotencyKey)) *Application_CreateIdempotencyKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.IdempotencyKey))
+	})
+	return _c
+}
+
+func (_c *Application_CreateIdempotencyKey_Call) Return(_a0 models.IdempotencyKey, _a1 error) *Application_CreateIdempotencyKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreateIdempotencyKey_Call) RunAndReturn(run func(context.Context, models.IdempotencyKey) (models.IdempotencyKey, error)) *Application_CreateIdempotencyKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateOrder provides a mock function with given fields: ctx, checkout
+func (_m *Application) CreateOrder(ctx context.Context, checkout models.Checkout) (models.Order, error) {
+	ret := _m.Called(ctx, checkout)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateOrder")
+	}
+
+	var r0 models.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Checkout) (models.Order, error)); ok {
+		return rf(ctx, checkout)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Checkout) models.Order); ok {
+		r0 = rf(ctx, checkout)
+	} else {
+		r0 = ret.Get(0).(models.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Checkout) error); ok {
+		r1 = rf(ctx, checkout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreateOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateOrder'
+type Application_CreateOrder_Call struct {
+	*mock.Call
+}
+
+// CreateOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - checkout models.Checkout
+func (_e *Application_Expecter) CreateOrder(ctx interface{}, checkout interface{}) *Application_CreateOrder_Call {
+	return &Application_CreateOrder_Call{Call: _e.mock.On("CreateOrder", ctx, checkout)}
+}
+
+func (_c *Application_CreateOrder_Call) Run(run func(ctx context.Context, checkout models.Checkout)) *Application_CreateOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Checkout))
+	})
+	return _c
+}
+
+func (_c *Application_CreateOrder_Call) Return(_a0 models.Order, _a1 error) *Application_CreateOrder_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreateOrder_Call) RunAndReturn(run func(context.Context, models.Checkout) (models.Order, error)) *Application_CreateOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreatePlace provides a mock function with given fields: ctx, place
+func (_m *Application) CreatePlace(ctx context.Context, place models.Place) (models.Place, error) {
+	ret := _m.Called(ctx, place)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePlace")
+	}
+
+	var r0 models.Place
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Place) (models.Place, error)); ok {
+		return rf(ctx, place)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Place) models.Place); ok {
+		r0 = rf(ctx, place)
+	} else {
+		r0 = ret.Get(0).(models.Place)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Place) error); ok {
+		r1 = rf(ctx, place)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreatePlace_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePlace'
+type Application_CreatePlace_Call struct {
+	*mock.Call
+}
+
+// CreatePlace is a helper method to define mock.On call
+//   - ctx context.Context
+//   - place models.Place
+func (_e *Application_Expecter) CreatePlace(ctx interface{}, place interface{}) *Application_CreatePlace_Call {
+	return &Application_CreatePlace_Call{Call: _e.mock.On("CreatePlace", ctx, place)}
+}
+
+func (_c *Application_CreatePlace_Call) Run(run func(ctx context.Context, place models.Place)) *Application_CreatePlace_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Place))
+	})
+	return _c
+}
+
+func (_c *Application_CreatePlace_Call) Return(_a0 models.Place, _a1 error) *Application_CreatePlace_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreatePlace_Call) RunAndReturn(run func(context.Context, models.Place) (models.Place, error)) *Application_CreatePlace_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreatePlaces provides a mock function with given fields: ctx, places
+func (_m *Application) CreatePlaces(ctx context.Context, places []models.Place) ([]models.Place, error) {
+	ret := _m.Called(ctx, places)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePlaces")
+	}
+
+	var r0 []models.Place
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Place) ([]models.Place, error)); ok {
+		return rf(ctx, places)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Place) []models.Place); ok {
+		r0 = rf(ctx, places)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Place)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.Place) error); ok {
+		r1 = rf(ctx, places)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreatePlaces_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePlaces'
+type Application_CreatePlaces_Call struct {
+	*mock.Call
+}
+
+// CreatePlaces is a helper method to define mock.On call
+//   - ctx context.Context
+//   - places []models.Place
+func (_e *Application_Expecter) CreatePlaces(ctx interface{}, places interface{}) *Application_CreatePlaces_Call {
+	return &Application_CreatePlaces_Call{Call: _e.mock.On("CreatePlaces", ctx, places)}
+}
+
+func (_c *Application_CreatePlaces_Call) Run(run func(ctx context.Context, places []models.Place)) *Application_CreatePlaces_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.Place))
+	})
+	return _c
+}
+
+func (_c *Application_CreatePlaces_Call) Return(_a0 []models.Place, _a1 error) *Application_CreatePlaces_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreatePlaces_Call) RunAndReturn(run func(context.Context, []models.Place) ([]models.Place, error)) *Application_CreatePlaces_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreatePriceCategory provides a mock function with given fields: ctx, category
+func (_m *Application) CreatePriceCategory(ctx context.Context, category models.PriceCategory) (models.PriceCategory, error) {
+	ret := _m.Called(ctx, category)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePriceCategory")
+	}
+
+	var r0 models.PriceCategory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.PriceCategory) (models.PriceCategory, error)); ok {
+		return rf(ctx, category)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.PriceCategory) models.PriceCategory); ok {
+		r0 = rf(ctx, category)
+	} else {
+		r0 = ret.Get(0).(models.PriceCategory)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.PriceCategory) error); ok {
+		r1 = rf(ctx, category)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreatePriceCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePriceCategory'
+type Application_CreatePriceCategory_Call struct {
+	*mock.Call
+}
+
+// CreatePriceCategory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - category models.PriceCategory
+func (_e *Application_Expecter) CreatePriceCategory(ctx interface{}, category interface{}) *Application_CreatePriceCategory_Call {
+	return &Application_CreatePriceCategory_Call{Call: _e.mock.On("CreatePriceCategory", ctx, category)}
+}
+
+func (_c *Application_CreatePriceCategory_Call) Run(run func(ctx context.Context, category models.PriceCategory)) *Application_CreatePriceCategory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.PriceCategory))
+	})
+	return _c
+}
+
+func (_c *Application_CreatePriceCategory_Call) Return(_a0 models.PriceCategory, _a1 error) *Application_CreatePriceCategory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreatePriceCategory_Call) RunAndReturn(run func(context.Context, models.PriceCategory) (models.PriceCategory, error)) *Application_CreatePriceCategory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreatePromotion provides a mock function with given fields: ctx, promotion
+func (_m *Application) CreatePromotion(ctx context.Context, promotion models.Promotion) (models.Promotion, error) {
+	ret := _m.Called(ctx, promotion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePromotion")
+	}
+
+	var r0 models.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Promotion) (models.Promotion, error)); ok {
+		return rf(ctx, promotion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Promotion) models.Promotion); ok {
+		r0 = rf(ctx, promotion)
+	} else {
+		r0 = ret.Get(0).(models.Promotion)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Promotion) error); ok {
+		r1 = rf(ctx, promotion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreatePromotion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePromotion'
+type Application_CreatePromotion_Call struct {
+	*mock.Call
+}
+
+// CreatePromotion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - promotion models.Promotion
+func (_e *Application_Expecter) CreatePromotion(ctx interface{}, promotion interface{}) *Application_CreatePromotion_Call {
+	return &Application_CreatePromotion_Call{Call: _e.mock.On("CreatePromotion", ctx, promotion)}
+}
+
+func (_c *Application_CreatePromotion_Call) Run(run func(ctx context.Context, promotion models.Promotion)) *Application_CreatePromotion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Promotion))
+	})
+	return _c
+}
+
+func (_c *Application_CreatePromotion_Call) Return(_a0 models.Promotion, _a1 error) *Application_CreatePromotion_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreatePromotion_Call) RunAndReturn(run func(context.Context, models.Promotion) (models.Promotion, error)) *Application_CreatePromotion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateSection provides a mock function with given fields: ctx, section
+func (_m *Application) CreateSection(ctx context.Context, section models.Section) (models.Section, error) {
+	ret := _m.Called(ctx, section)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSection")
+	}
+
+	var r0 models.Section
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Section) (models.Section, error)); ok {
+		return rf(ctx, section)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Section) models.Section); ok {
+		r0 = rf(ctx, section)
+	} else {
+		r0 = ret.Get(0).(models.Section)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Section) error); ok {
+		r1 = rf(ctx, section)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreateSection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateSection'
+type Application_CreateSection_Call struct {
+	*mock.Call
+}
+
+// CreateSection is a helper method to define mock.On call
+//   - ctx context.Context
+//   - section models.Section
+func (_e *Application_Expecter) CreateSection(ctx interface{}, section interface{}) *Application_CreateSection_Call {
+	return &Application_CreateSection_Call{Call: _e.mock.On("CreateSection", ctx, section)}
+}
+
+func (_c *Application_CreateSection_Call) Run(run func(ctx context.Context, section models.Section)) *Application_CreateSection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Section))
+	})
+	return _c
+}
+
+func (_c *Application_CreateSection_Call) Return(_a0 models.Section, _a1 error) *Application_CreateSection_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreateSection_Call) RunAndReturn(run func(context.Context, models.Section) (models.Section, error)) *Application_CreateSection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateShow provides a mock function with given fields: ctx, shows
+func (_m *Application) CreateShow(ctx context.Context, shows models.Show) (models.Show, error) {
+	ret := _m.Called(ctx, shows)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateShow")
+	}
+
+	var r0 models.Show
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Show) (models.Show, error)); ok {
+		return rf(ctx, shows)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Show) models.Show); ok {
+		r0 = rf(ctx, shows)
+	} else {
+		r0 = ret.Get(0).(models.Show)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Show) error); ok {
+		r1 = rf(ctx, shows)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreateShow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateShow'
+type Application_CreateShow_Call struct {
+	*mock.Call
+}
+
+// CreateShow is a helper method to define mock.On call
+//   - ctx context.Context
+//   - shows models.Show
+func (_e *Application_Expecter) CreateShow(ctx interface{}, shows interface{}) *Application_CreateShow_Call {
+	return &Application_CreateShow_Call{Call: _e.mock.On("CreateShow", ctx, shows)}
+}
+
+func (_c *Application_CreateShow_Call) Run(run func(ctx context.Context, shows models.Show)) *Application_CreateShow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Show))
+	})
+	return _c
+}
+
+func (_c *Application_CreateShow_Call) Return(_a0 models.Show, _a1 error) *Application_CreateShow_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreateShow_Call) RunAndReturn(run func(context.Context, models.Show) (models.Show, error)) *Application_CreateShow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateShows provides a mock function with given fields: ctx, shows
+func (_m *Application) CreateShows(ctx context.Context, shows []models.Show) ([]models.Show, error) {
+	ret := _m.Called(ctx, shows)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateShows")
+	}
+
+	var r0 []models.Show
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Show) ([]models.Show, error)); ok {
+		return rf(ctx, shows)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.Show) []models.Show); ok {
+		r0 = rf(ctx, shows)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Show)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.Show) error); ok {
+		r1 = rf(ctx, shows)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreateShows_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateShows'
+type Application_CreateShows_Call struct {
+	*mock.Call
+}
+
+// CreateShows is a helper method to define mock.On call
+//   - ctx context.Context
+//   - shows []models.Show
+func (_e *Application_Expecter) CreateShows(ctx interface{}, shows interface{}) *Application_CreateShows_Call {
+	return &Application_CreateShows_Call{Call: _e.mock.On("CreateShows", ctx, shows)}
+}
+
+func (_c *Application_CreateShows_Call) Run(run func(ctx context.Context, shows []models.Show)) *Application_CreateShows_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.Show))
+	})
+	return _c
+}
+
+func (_c *Application_CreateShows_Call) Return(_a0 []models.Show, _a1 error) *Application_CreateShows_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreateShows_Call) RunAndReturn(run func(context.Context, []models.Show) ([]models.Show, error)) *Application_CreateShows_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateVenue provides a mock function with given fields: ctx, venue
+func (_m *Application) CreateVenue(ctx context.Context, venue models.Venue) (models.Venue, error) {
+	ret := _m.Called(ctx, venue)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateVenue")
+	}
+
+	var r0 models.Venue
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Venue) (models.Venue, error)); ok {
+		return rf(ctx, venue)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Venue) models.Venue); ok {
+		r0 = rf(ctx, venue)
+	} else {
+		r0 = ret.Get(0).(models.Venue)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Venue) error); ok {
+		r1 = rf(ctx, venue)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreateVenue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateVenue'
+type Application_CreateVenue_Call struct {
+	*mock.Call
+}
+
+// CreateVenue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - venue models.Venue
+func (_e *Application_Expecter) CreateVenue(ctx interface{}, venue interface{}) *Application_CreateVenue_Call {
+	return &Application_CreateVenue_Call{Call: _e.mock.On("CreateVenue", ctx, venue)}
+}
+
+func (_c *Application_CreateVenue_Call) Run(run func(ctx context.Context, venue models.Venue)) *Application_CreateVenue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Venue))
+	})
+	return _c
+}
+
+func (_c *Application_CreateVenue_Call) Return(_a0 models.Venue, _a1 error) *Application_CreateVenue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreateVenue_Call) RunAndReturn(run func(context.Context, models.Venue) (models.Venue, error)) *Application_CreateVenue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateWebhook provides a mock function with given fields: ctx, webhook
+func (_m *Application) CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	ret := _m.Called(ctx, webhook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhook")
+	}
+
+	var r0 models.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Webhook) (models.Webhook, error)); ok {
+		return rf(ctx, webhook)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Webhook) models.Webhook); ok {
+		r0 = rf(ctx, webhook)
+	} else {
+		r0 = ret.Get(0).(models.Webhook)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Webhook) error); ok {
+		r1 = rf(ctx, webhook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_CreateWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateWebhook'
+type Application_CreateWebhook_Call struct {
+	*mock.Call
+}
+
+// CreateWebhook is a helper method to define mock.On call
+//   - ctx context.Context
+//   - webhook models.Webhook
+func (_e *Application_Expecter) CreateWebhook(ctx interface{}, webhook interface{}) *Application_CreateWebhook_Call {
+	return &Application_CreateWebhook_Call{Call: _e.mock.On("CreateWebhook", ctx, webhook)}
+}
+
+func (_c *Application_CreateWebhook_Call) Run(run func(ctx context.Context, webhook models.Webhook)) *Application_CreateWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Webhook))
+	})
+	return _c
+}
+
+func (_c *Application_CreateWebhook_Call) Return(_a0 models.Webhook, _a1 error) *Application_CreateWebhook_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_CreateWebhook_Call) RunAndReturn(run func(context.Context, models.Webhook) (models.Webhook, error)) *Application_CreateWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeactivatePromotion provides a mock function with given fields: ctx, id
+func (_m *Application) DeactivatePromotion(ctx context.Context, id int64) (models.Promotion, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeactivatePromotion")
+	}
+
+	var r0 models.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Promotion, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Promotion); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Promotion)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_DeactivatePromotion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeactivatePromotion'
+type Application_DeactivatePromotion_Call struct {
+	*mock.Call
+}
+
+// DeactivatePromotion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) DeactivatePromotion(ctx interface{}, id interface{}) *Application_DeactivatePromotion_Call {
+	return &Application_DeactivatePromotion_Call{Call: _e.mock.On("DeactivatePromotion", ctx, id)}
+}
+
+func (_c *Application_DeactivatePromotion_Call) Run(run func(ctx context.Context, id int64)) *Application_DeactivatePromotion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_DeactivatePromotion_Call) Return(_a0 models.Promotion, _a1 error) *Application_DeactivatePromotion_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_DeactivatePromotion_Call) RunAndReturn(run func(context.Context, int64) (models.Promotion, error)) *Application_DeactivatePromotion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteIdempotencyKey provides a mock function with given fields: ctx, key
+func (_m *Application) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteIdempotencyKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Application_DeleteIdempotencyKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteIdempotencyKey'
+type Application_DeleteIdempotencyKey_Call struct {
+	*mock.Call
+}
+
+// DeleteIdempotencyKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *Application_Expecter) DeleteIdempotencyKey(ctx interface{}, key interface{}) *Application_DeleteIdempotencyKey_Call {
+	return &Application_DeleteIdempotencyKey_Call{Call: _e.mock.On("DeleteIdempotencyKey", ctx, key)}
+}
+
+func (_c *Application_DeleteIdempotencyKey_Call) Run(run func(ctx context.Context, key string)) *Application_DeleteIdempotencyKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Application_DeleteIdempotencyKey_Call) Return(_a0 error) *Application_DeleteIdempotencyKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Application_DeleteIdempotencyKey_Call) RunAndReturn(run func(context.Context, string) error) *Application_DeleteIdempotencyKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeletePriceCategory provides a mock function with given fields: ctx, id
+func (_m *Application) DeletePriceCategory(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePriceCategory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Application_DeletePriceCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeletePriceCategory'
+type Application_DeletePriceCategory_Call struct {
+	*mock.Call
+}
+
+// DeletePriceCategory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) DeletePriceCategory(ctx interface{}, id interface{}) *Application_DeletePriceCategory_Call {
+	return &Application_DeletePriceCategory_Call{Call: _e.mock.On("DeletePriceCategory", ctx, id)}
+}
+
+func (_c *Application_DeletePriceCategory_Call) Run(run func(ctx context.Context, id int64)) *Application_DeletePriceCategory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_DeletePriceCategory_Call) Return(_a0 error) *Application_DeletePriceCategory_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Application_DeletePriceCategory_Call) RunAndReturn(run func(context.Context, int64) error) *Application_DeletePriceCategory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteWebhook provides a mock function with given fields: ctx, id
+func (_m *Application) DeleteWebhook(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Application_DeleteWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteWebhook'
+type Application_DeleteWebhook_Call struct {
+	*mock.Call
+}
+
+// DeleteWebhook is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) DeleteWebhook(ctx interface{}, id interface{}) *Application_DeleteWebhook_Call {
+	return &Application_DeleteWebhook_Call{Call: _e.mock.On("DeleteWebhook", ctx, id)}
+}
+
+func (_c *Application_DeleteWebhook_Call) Run(run func(ctx context.Context, id int64)) *Application_DeleteWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_DeleteWebhook_Call) Return(_a0 error) *Application_DeleteWebhook_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Application_DeleteWebhook_Call) RunAndReturn(run func(context.Context, int64) error) *Application_DeleteWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EraseCustomer provides a mock function with given fields: ctx, id
+func (_m *Application) EraseCustomer(ctx context.Context, id int64) (models.Customer, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EraseCustomer")
+	}
+
+	var r0 models.Customer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Customer, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Customer); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Customer)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_EraseCustomer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EraseCustomer'
+type Application_EraseCustomer_Call struct {
+	*mock.Call
+}
+
+// EraseCustomer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) EraseCustomer(ctx interface{}, id interface{}) *Application_EraseCustomer_Call {
+	return &Application_EraseCustomer_Call{Call: _e.mock.On("EraseCustomer", ctx, id)}
+}
+
+func (_c *Application_EraseCustomer_Call) Run(run func(ctx context.Context, id int64)) *Application_EraseCustomer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_EraseCustomer_Call) Return(_a0 models.Customer, _a1 error) *Application_EraseCustomer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_EraseCustomer_Call) RunAndReturn(run func(context.Context, int64) (models.Customer, error)) *Application_EraseCustomer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExchangeOrderItems provides a mock function with given fields: ctx, orderID, itemIDs, eventID, placeIDs
+func (_m *Application) ExchangeOrderItems(ctx context.Context, orderID int64, itemIDs []int64, eventID int64, placeIDs []int64) (models.Exchange, error) {
+	ret := _m.Called(ctx, orderID, itemIDs, eventID, placeIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExchangeOrderItems")
+	}
+
+	var r0 models.Exchange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64, int64, []int64) (models.Exchange, error)); ok {
+		return rf(ctx, orderID, itemIDs, eventID, placeIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64, int64, []int64) models.Exchange); ok {
+		r0 = rf(ctx, orderID, itemIDs, eventID, placeIDs)
+	} else {
+		r0 = ret.Get(0).(models.Exchange)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []int64, int64, []int64) error); ok {
+		r1 = rf(ctx, orderID, itemIDs, eventID, placeIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_ExchangeOrderItems_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExchangeOrderItems'
+type Application_ExchangeOrderItems_Call struct {
+	*mock.Call
+}
+
+// ExchangeOrderItems is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orderID int64
+//   - itemIDs []int64
+//   - eventID int64
+//   - placeIDs []int64
+func (_e *Application_Expecter) ExchangeOrderItems(ctx interface{}, orderID interface{}, itemIDs interface{}, eventID interface{}, placeIDs interface{}) *Application_ExchangeOrderItems_Call {
+	return &Application_ExchangeOrderItems_Call{Call: _e.mock.On("ExchangeOrderItems", ctx, orderID, itemIDs, eventID, placeIDs)}
+}
+
+func (_c *Application_ExchangeOrderItems_Call) Run(run func(ctx context.Context, orderID int64, itemIDs []int64, eventID int64, placeIDs []int64)) *Application_ExchangeOrderItems_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].([]int64), args[3].(int64), args[4].([]int64))
+	})
+	return _c
+}
+
+func (_c *Application_ExchangeOrderItems_Call) Return(_a0 models.Exchange, _a1 error) *Application_ExchangeOrderItems_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_ExchangeOrderItems_Call) RunAndReturn(run func(context.Context, int64, []int64, int64, []int64) (models.Exchange, error)) *Application_ExchangeOrderItems_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExportCustomer provides a mock function with given fields: ctx, id
+func (_m *Application) ExportCustomer(ctx context.Context, id int64) (models.CustomerExport, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportCustomer")
+	}
+
+	var r0 models.CustomerExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.CustomerExport, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.CustomerExport); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.CustomerExport)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_ExportCustomer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExportCustomer'
+type Application_ExportCustomer_Call struct {
+	*mock.Call
+}
+
+// ExportCustomer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) ExportCustomer(ctx interface{}, id interface{}) *Application_ExportCustomer_Call {
+	return &Application_ExportCustomer_Call{Call: _e.mock.On("ExportCustomer", ctx, id)}
+}
+
+func (_c *Application_ExportCustomer_Call) Run(run func(ctx context.Context, id int64)) *Application_ExportCustomer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_ExportCustomer_Call) Return(_a0 models.CustomerExport, _a1 error) *Application_ExportCustomer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_ExportCustomer_Call) RunAndReturn(run func(context.Context, int64) (models.CustomerExport, error)) *Application_ExportCustomer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCustomer provides a mock function with given fields: ctx, id
+func (_m *Application) GetCustomer(ctx context.Context, id int64) (models.Customer, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCustomer")
+	}
+
+	var r0 models.Customer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Customer, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Customer); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Customer)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetCustomer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCustomer'
+type Application_GetCustomer_Call struct {
+	*mock.Call
+}
+
+// GetCustomer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) GetCustomer(ctx interface{}, id interface{}) *Application_GetCustomer_Call {
+	return &Application_GetCustomer_Call{Call: _e.mock.On("GetCustomer", ctx, id)}
+}
+
+func (_c *Application_GetCustomer_Call) Run(run func(ctx context.Context, id int64)) *Application_GetCustomer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetCustomer_Call) Return(_a0 models.Customer, _a1 error) *Application_GetCustomer_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetCustomer_Call) RunAndReturn(run func(context.Context, int64) (models.Customer, error)) *Application_GetCustomer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCustomerOrders provides a mock function with given fields: ctx, customerID
+func (_m *Application) GetCustomerOrders(ctx context.Context, customerID int64) ([]models.Order, error) {
+	ret := _m.Called(ctx, customerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCustomerOrders")
+	}
+
+	var r0 []models.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.Order, error)); ok {
+		return rf(ctx, customerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.Order); ok {
+		r0 = rf(ctx, customerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Order)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, customerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetCustomerOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCustomerOrders'
+type Application_GetCustomerOrders_Call struct {
+	*mock.Call
+}
+
+// GetCustomerOrders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - customerID int64
+func (_e *Application_Expecter) GetCustomerOrders(ctx interface{}, customerID interface{}) *Application_GetCustomerOrders_Call {
+	return &Application_GetCustomerOrders_Call{Call: _e.mock.On("GetCustomerOrders", ctx, customerID)}
+}
+
+func (_c *Application_GetCustomerOrders_Call) Run(run func(ctx context.Context, customerID int64)) *Application_GetCustomerOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetCustomerOrders_Call) Return(_a0 []models.Order, _a1 error) *Application_GetCustomerOrders_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetCustomerOrders_Call) RunAndReturn(run func(context.Context, int64) ([]models.Order, error)) *Application_GetCustomerOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetEvent provides a mock function with given fields: ctx, id
+func (_m *Application) GetEvent(ctx context.Context, id int64) (models.Event, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEvent")
+	}
+
+	var r0 models.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Event, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Event); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Event)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEvent'
+type Application_GetEvent_Call struct {
+	*mock.Call
+}
+
+// GetEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) GetEvent(ctx interface{}, id interface{}) *Application_GetEvent_Call {
+	return &Application_GetEvent_Call{Call: _e.mock.On("GetEvent", ctx, id)}
+}
+
+func (_c *Application_GetEvent_Call) Run(run func(ctx context.Context, id int64)) *Application_GetEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetEvent_Call) Return(_a0 models.Event, _a1 error) *Application_GetEvent_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetEvent_Call) RunAndReturn(run func(context.Context, int64) (models.Event, error)) *Application_GetEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetEvents provides a mock function with given fields: ctx, filter
+func (_m *Application) GetEvents(ctx context.Context, filter models.EventFilter) ([]models.Event, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEvents")
+	}
+
+	var r0 []models.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.EventFilter) ([]models.Event, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.EventFilter) []models.Event); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.EventFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEvents'
+type Application_GetEvents_Call struct {
+	*mock.Call
+}
+
+// GetEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter models.EventFilter
+func (_e *Application_Expecter) GetEvents(ctx interface{}, filter interface{}) *Application_GetEvents_Call {
+	return &Application_GetEvents_Call{Call: _e.mock.On("GetEvents", ctx, filter)}
+}
+
+func (_c *Application_GetEvents_Call) Run(run func(ctx context.Context, filter models.EventFilter)) *Application_GetEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.EventFilter))
+	})
+	return _c
+}
+
+func (_c *Application_GetEvents_Call) Return(_a0 []models.Event, _a1 error) *Application_GetEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetEvents_Call) RunAndReturn(run func(context.Context, models.EventFilter) ([]models.Event, error)) *Application_GetEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetHall provides a mock function with given fields: ctx, id
+func (_m *Application) GetHall(ctx context.Context, id int64) (models.Hall, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHall")
+	}
+
+	var r0 models.Hall
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Hall, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Hall); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Hall)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetHall_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetHall'
+type Application_GetHall_Call struct {
+	*mock.Call
+}
+
+// GetHall is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) GetHall(ctx interface{}, id interface{}) *Application_GetHall_Call {
+	return &Application_GetHall_Call{Call: _e.mock.On("GetHall", ctx, id)}
+}
+
+func (_c *Application_GetHall_Call) Run(run func(ctx context.Context, id int64)) *Application_GetHall_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetHall_Call) Return(_a0 models.Hall, _a1 error) *Application_GetHall_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetHall_Call) RunAndReturn(run func(context.Context, int64) (models.Hall, error)) *Application_GetHall_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetHalls provides a mock function with given fields: ctx, venueID
+func (_m *Application) GetHalls(ctx context.Context, venueID int64) ([]models.Hall, error) {
+	ret := _m.Called(ctx, venueID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHalls")
+	}
+
+	var r0 []models.Hall
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.Hall, error)); ok {
+		return rf(ctx, venueID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.Hall); ok {
+		r0 = rf(ctx, venueID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Hall)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, venueID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetHalls_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetHalls'
+type Application_GetHalls_Call struct {
+	*mock.Call
+}
+
+// GetHalls is a helper method to define mock.On call
+//   - ctx context.Context
+//   - venueID int64
+func (_e *Application_Expecter) GetHalls(ctx interface{}, venueID interface{}) *Application_GetHalls_Call {
+	return &Application_GetHalls_Call{Call: _e.mock.On("GetHalls", ctx, venueID)}
+}
+
+func (_c *Application_GetHalls_Call) Run(run func(ctx context.Context, venueID int64)) *Application_GetHalls_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetHalls_Call) Return(_a0 []models.Hall, _a1 error) *Application_GetHalls_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetHalls_Call) RunAndReturn(run func(context.Context, int64) ([]models.Hall, error)) *Application_GetHalls_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetHold provides a mock function with given fields: ctx, id
+func (_m *Application) GetHold(ctx context.Context, id int64) (models.Hold, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHold")
+	}
+
+	var r0 models.Hold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Hold, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Hold); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Hold)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetHold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetHold'
+type Application_GetHold_Call struct {
+	*mock.Call
+}
+
+// GetHold is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) GetHold(ctx interface{}, id interface{}) *Application_GetHold_Call {
+	return &Application_GetHold_Call{Call: _e.mock.On("GetHold", ctx, id)}
+}
+
+func (_c *Application_GetHold_Call) Run(run func(ctx context.Context, id int64)) *Application_GetHold_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetHold_Call) Return(_a0 models.Hold, _a1 error) *Application_GetHold_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetHold_Call) RunAndReturn(run func(context.Context, int64) (models.Hold, error)) *Application_GetHold_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetIdempotencyKey provides a mock function with given fields: ctx, key
+func (_m *Application) GetIdempotencyKey(ctx context.Context, key string) (models.IdempotencyKey, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIdempotencyKey")
+	}
+
+	var r0 models.IdempotencyKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.IdempotencyKey, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.IdempotencyKey); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(models.IdempotencyKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetIdempotencyKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIdempotencyKey'
+type Application_GetIdempotencyKey_Call struct {
+	*mock.Call
+}
+
+// GetIdempotencyKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *Application_Expecter) GetIdempotencyKey(ctx interface{}, key interface{}) *Application_GetIdempotencyKey_Call {
+	return &Application_GetIdempotencyKey_Call{Call: _e.mock.On("GetIdempotencyKey", ctx, key)}
+}
+
+func (_c *Application_GetIdempotencyKey_Call) Run(run func(ctx context.Context, key string)) *Application_GetIdempotencyKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Application_GetIdempotencyKey_Call) Return(_a0 models.IdempotencyKey, _a1 error) *Application_GetIdempotencyKey_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetIdempotencyKey_Call) RunAndReturn(run func(context.Context, string) (models.IdempotencyKey, error)) *Application_GetIdempotencyKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNearestPlaces provides a mock function with given fields: ctx, eventID, pt, n
+func (_m *Application) GetNearestPlaces(ctx context.Context, eventID int64, pt models.Point, n int) ([]models.Place, error) {
+	ret := _m.Called(ctx, eventID, pt, n)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNearestPlaces")
+	}
+
+	var r0 []models.Place
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.Point, int) ([]models.Place, error)); ok {
+		return rf(ctx, eventID, pt, n)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.Point, int) []models.Place); ok {
+		r0 = rf(ctx, eventID, pt, n)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Place)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, models.Point, int) error); ok {
+		r1 = rf(ctx, eventID, pt, n)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetNearestPlaces_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNearestPlaces'
+type Application_GetNearestPlaces_Call struct {
+	*mock.Call
+}
+
+// GetNearestPlaces is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventID int64
+//   - pt models.Point
+//   - n int
+func (_e *Application_Expecter) GetNearestPlaces(ctx interface{}, eventID interface{}, pt interface{}, n interface{}) *Application_GetNearestPlaces_Call {
+	return &Application_GetNearestPlaces_Call{Call: _e.mock.On("GetNearestPlaces", ctx, eventID, pt, n)}
+}
+
+func (_c *Application_GetNearestPlaces_Call) Run(run func(ctx context.Context, eventID int64, pt models.Point, n int)) *Application_GetNearestPlaces_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(models.Point), args[3].(int))
+	})
+	return _c
+}
+
+func (_c *Application_GetNearestPlaces_Call) Return(_a0 []models.Place, _a1 error) *Application_GetNearestPlaces_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetNearestPlaces_Call) RunAndReturn(run func(context.Context, int64, models.Point, int) ([]models.Place, error)) *Application_GetNearestPlaces_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrder provides a mock function with given fields: ctx, id
+func (_m *Application) GetOrder(ctx context.Context, id int64) (models.Order, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrder")
+	}
+
+	var r0 models.Order
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Order, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Order); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Order)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrder'
+type Application_GetOrder_Call struct {
+	*mock.Call
+}
+
+// GetOrder is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) GetOrder(ctx interface{}, id interface{}) *Application_GetOrder_Call {
+	return &Application_GetOrder_Call{Call: _e.mock.On("GetOrder", ctx, id)}
+}
+
+func (_c *Application_GetOrder_Call) Run(run func(ctx context.Context, id int64)) *Application_GetOrder_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetOrder_Call) Return(_a0 models.Order, _a1 error) *Application_GetOrder_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetOrder_Call) RunAndReturn(run func(context.Context, int64) (models.Order, error)) *Application_GetOrder_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrderAudit provides a mock function with given fields: ctx, orderID
+func (_m *Application) GetOrderAudit(ctx context.Context, orderID int64) ([]models.AuditRecord, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrderAudit")
+	}
+
+	var r0 []models.AuditRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.AuditRecord, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.AuditRecord); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.AuditRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetOrderAudit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrderAudit'
+type Application_GetOrderAudit_Call struct {
+	*mock.Call
+}
+
+// GetOrderAudit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orderID int64
+func (_e *Application_Expecter) GetOrderAudit(ctx interface{}, orderID interface{}) *Application_GetOrderAudit_Call {
+	return &Application_GetOrderAudit_Call{Call: _e.mock.On("GetOrderAudit", ctx, orderID)}
+}
+
+func (_c *Application_GetOrderAudit_Call) Run(run func(ctx context.Context, orderID int64)) *Application_GetOrderAudit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetOrderAudit_Call) Return(_a0 []models.AuditRecord, _a1 error) *Application_GetOrderAudit_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetOrderAudit_Call) RunAndReturn(run func(context.Context, int64) ([]models.AuditRecord, error)) *Application_GetOrderAudit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPlaceAdjacency provides a mock function with given fields: ctx, eventID
+func (_m *Application) GetPlaceAdjacency(ctx context.Context, eventID int64) ([]models.Adjacency, error) {
+	ret := _m.Called(ctx, eventID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPlaceAdjacency")
+	}
+
+	var r0 []models.Adjacency
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.Adjacency, error)); ok {
+		return rf(ctx, eventID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.Adjacency); ok {
+		r0 = rf(ctx, eventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Adjacency)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, eventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetPlaceAdjacency_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPlaceAdjacency'
+type Application_GetPlaceAdjacency_Call struct {
+	*mock.Call
+}
+
+// GetPlaceAdjacency is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventID int64
+func (_e *Application_Expecter) GetPlaceAdjacency(ctx interface{}, eventID interface{}) *Application_GetPlaceAdjacency_Call {
+	return &Application_GetPlaceAdjacency_Call{Call: _e.mock.On("GetPlaceAdjacency", ctx, eventID)}
+}
+
+func (_c *Application_GetPlaceAdjacency_Call) Run(run func(ctx context.Context, eventID int64)) *Application_GetPlaceAdjacency_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetPlaceAdjacency_Call) Return(_a0 []models.Adjacency, _a1 error) *Application_GetPlaceAdjacency_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetPlaceAdjacency_Call) RunAndReturn(run func(context.Context, int64) ([]models.Adjacency, error)) *Application_GetPlaceAdjacency_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPlaces provides a mock function with given fields: ctx, filter
+func (_m *Application) GetPlaces(ctx context.Context, filter models.PlaceFilter) ([]models.Place, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPlaces")
+	}
+
+	var r0 []models.Place
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.PlaceFilter) ([]models.Place, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.PlaceFilter) []models.Place); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Place)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.PlaceFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetPlaces_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPlaces'
+type Application_GetPlaces_Call struct {
+	*mock.Call
+}
+
+// GetPlaces is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter models.PlaceFilter
+func (_e *Application_Expecter) GetPlaces(ctx interface{}, filter interface{}) *Application_GetPlaces_Call {
+	return &Application_GetPlaces_Call{Call: _e.mock.On("GetPlaces", ctx, filter)}
+}
+
+func (_c *Application_GetPlaces_Call) Run(run func(ctx context.Context, filter models.PlaceFilter)) *Application_GetPlaces_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.PlaceFilter))
+	})
+	return _c
+}
+
+func (_c *Application_GetPlaces_Call) Return(_a0 []models.Place, _a1 error) *Application_GetPlaces_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetPlaces_Call) RunAndReturn(run func(context.Context, models.PlaceFilter) ([]models.Place, error)) *Application_GetPlaces_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPlacesInRect provides a mock function with given fields: ctx, eventID, r
+func (_m *Application) GetPlacesInRect(ctx context.Context, eventID int64, r models.Rect) ([]models.Place, error) {
+	ret := _m.Called(ctx, eventID, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPlacesInRect")
+	}
+
+	var r0 []models.Place
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.Rect) ([]models.Place, error)); ok {
+		return rf(ctx, eventID, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.Rect) []models.Place); ok {
+		r0 = rf(ctx, eventID, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Place)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, models.Rect) error); ok {
+		r1 = rf(ctx, eventID, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetPlacesInRect_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPlacesInRect'
+type Application_GetPlacesInRect_Call struct {
+	*mock.Call
+}
+
+// GetPlacesInRect is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventID int64
+//   - r models.Rect
+func (_e *Application_Expecter) GetPlacesInRect(ctx interface{}, eventID interface{}, r interface{}) *Application_GetPlacesInRect_Call {
+	return &Application_GetPlacesInRect_Call{Call: _e.mock.On("GetPlacesInRect", ctx, eventID, r)}
+}
+
+func (_c *Application_GetPlacesInRect_Call) Run(run func(ctx context.Context, eventID int64, r models.Rect)) *Application_GetPlacesInRect_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(models.Rect))
+	})
+	return _c
+}
+
+func (_c *Application_GetPlacesInRect_Call) Return(_a0 []models.Place, _a1 error) *Application_GetPlacesInRect_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetPlacesInRect_Call) RunAndReturn(run func(context.Context, int64, models.Rect) ([]models.Place, error)) *Application_GetPlacesInRect_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPriceCategories provides a mock function with given fields: ctx, eventID
+func (_m *Application) GetPriceCategories(ctx context.Context, eventID int64) ([]models.PriceCategory, error) {
+	ret := _m.Called(ctx, eventID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPriceCategories")
+	}
+
+	var r0 []models.PriceCategory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.PriceCategory, error)); ok {
+		return rf(ctx, eventID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.PriceCategory); ok {
+		r0 = rf(ctx, eventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PriceCategory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, eventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetPriceCategories_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPriceCategories'
+type Application_GetPriceCategories_Call struct {
+	*mock.Call
+}
+
+// GetPriceCategories is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventID int64
+func (_e *Application_Expecter) GetPriceCategories(ctx interface{}, eventID interface{}) *Application_GetPriceCategories_Call {
+	return &Application_GetPriceCategories_Call{Call: _e.mock.On("GetPriceCategories", ctx, eventID)}
+}
+
+func (_c *Application_GetPriceCategories_Call) Run(run func(ctx context.Context, eventID int64)) *Application_GetPriceCategories_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetPriceCategories_Call) Return(_a0 []models.PriceCategory, _a1 error) *Application_GetPriceCategories_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetPriceCategories_Call) RunAndReturn(run func(context.Context, int64) ([]models.PriceCategory, error)) *Application_GetPriceCategories_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPromotion provides a mock function with given fields: ctx, id
+func (_m *Application) GetPromotion(ctx context.Context, id int64) (models.Promotion, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPromotion")
+	}
+
+	var r0 models.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Promotion, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Promotion); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Promotion)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetPromotion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPromotion'
+type Application_GetPromotion_Call struct {
+	*mock.Call
+}
+
+// GetPromotion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) GetPromotion(ctx interface{}, id interface{}) *Application_GetPromotion_Call {
+	return &Application_GetPromotion_Call{Call: _e.mock.On("GetPromotion", ctx, id)}
+}
+
+func (_c *Application_GetPromotion_Call) Run(run func(ctx context.Context, id int64)) *Application_GetPromotion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetPromotion_Call) Return(_a0 models.Promotion, _a1 error) *Application_GetPromotion_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetPromotion_Call) RunAndReturn(run func(context.Context, int64) (models.Promotion, error)) *Application_GetPromotion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPromotions provides a mock function with given fields: ctx
+func (_m *Application) GetPromotions(ctx context.Context) ([]models.Promotion, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPromotions")
+	}
+
+	var r0 []models.Promotion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Promotion, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Promotion); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Promotion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetPromotions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPromotions'
+type Application_GetPromotions_Call struct {
+	*mock.Call
+}
+
+// GetPromotions is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Application_Expecter) GetPromotions(ctx interface{}) *Application_GetPromotions_Call {
+	return &Application_GetPromotions_Call{Call: _e.mock.On("GetPromotions", ctx)}
+}
+
+func (_c *Application_GetPromotions_Call) Run(run func(ctx context.Context)) *Application_GetPromotions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Application_GetPromotions_Call) Return(_a0 []models.Promotion, _a1 error) *Application_GetPromotions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetPromotions_Call) RunAndReturn(run func(context.Context) ([]models.Promotion, error)) *Application_GetPromotions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetQuarantinedItems provides a mock function with given fields: ctx
+func (_m *Application) GetQuarantinedItems(ctx context.Context) ([]models.QuarantinedItem, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQuarantinedItems")
+	}
+
+	var r0 []models.QuarantinedItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.QuarantinedItem, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.QuarantinedItem); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.QuarantinedItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetQuarantinedItems_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetQuarantinedItems'
+type Application_GetQuarantinedItems_Call struct {
+	*mock.Call
+}
+
+// GetQuarantinedItems is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Application_Expecter) GetQuarantinedItems(ctx interface{}) *Application_GetQuarantinedItems_Call {
+	return &Application_GetQuarantinedItems_Call{Call: _e.mock.On("GetQuarantinedItems", ctx)}
+}
+
+func (_c *Application_GetQuarantinedItems_Call) Run(run func(ctx context.Context)) *Application_GetQuarantinedItems_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Application_GetQuarantinedItems_Call) Return(_a0 []models.QuarantinedItem, _a1 error) *Application_GetQuarantinedItems_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetQuarantinedItems_Call) RunAndReturn(run func(context.Context) ([]models.QuarantinedItem, error)) *Application_GetQuarantinedItems_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetSections provides a mock function with given fields: ctx, hallID
+func (_m *Application) GetSections(ctx context.Context, hallID int64) ([]models.Section, error) {
+	ret := _m.Called(ctx, hallID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSections")
+	}
+
+	var r0 []models.Section
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.Section, error)); ok {
+		return rf(ctx, hallID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.Section); ok {
+		r0 = rf(ctx, hallID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Section)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, hallID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetSections_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetSections'
+type Application_GetSections_Call struct {
+	*mock.Call
+}
+
+// GetSections is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hallID int64
+func (_e *Application_Expecter) GetSections(ctx interface{}, hallID interface{}) *Application_GetSections_Call {
+	return &Application_GetSections_Call{Call: _e.mock.On("GetSections", ctx, hallID)}
+}
+
+func (_c *Application_GetSections_Call) Run(run func(ctx context.Context, hallID int64)) *Application_GetSections_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetSections_Call) Return(_a0 []models.Section, _a1 error) *Application_GetSections_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetSections_Call) RunAndReturn(run func(context.Context, int64) ([]models.Section, error)) *Application_GetSections_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetShows provides a mock function with given fields: ctx
+func (_m *Application) GetShows(ctx context.Context) ([]models.Show, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShows")
+	}
+
+	var r0 []models.Show
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Show, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Show); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Show)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetShows_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetShows'
+type Application_GetShows_Call struct {
+	*mock.Call
+}
+
+// GetShows is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Application_Expecter) GetShows(ctx interface{}) *Application_GetShows_Call {
+	return &Application_GetShows_Call{Call: _e.mock.On("GetShows", ctx)}
+}
+
+func (_c *Application_GetShows_Call) Run(run func(ctx context.Context)) *Application_GetShows_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Application_GetShows_Call) Return(_a0 []models.Show, _a1 error) *Application_GetShows_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetShows_Call) RunAndReturn(run func(context.Context) ([]models.Show, error)) *Application_GetShows_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetVenue provides a mock function with given fields: ctx, id
+func (_m *Application) GetVenue(ctx context.Context, id int64) (models.Venue, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVenue")
+	}
+
+	var r0 models.Venue
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Venue, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Venue); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Venue)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetVenue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetVenue'
+type Application_GetVenue_Call struct {
+	*mock.Call
+}
+
+// GetVenue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) GetVenue(ctx interface{}, id interface{}) *Application_GetVenue_Call {
+	return &Application_GetVenue_Call{Call: _e.mock.On("GetVenue", ctx, id)}
+}
+
+func (_c *Application_GetVenue_Call) Run(run func(ctx context.Context, id int64)) *Application_GetVenue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetVenue_Call) Return(_a0 models.Venue, _a1 error) *Application_GetVenue_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetVenue_Call) RunAndReturn(run func(context.Context, int64) (models.Venue, error)) *Application_GetVenue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetVenues provides a mock function with given fields: ctx
+func (_m *Application) GetVenues(ctx context.Context) ([]models.Venue, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVenues")
+	}
+
+	var r0 []models.Venue
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Venue, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Venue); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Venue)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetVenues_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetVenues'
+type Application_GetVenues_Call struct {
+	*mock.Call
+}
+
+// GetVenues is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Application_Expecter) GetVenues(ctx interface{}) *Application_GetVenues_Call {
+	return &Application_GetVenues_Call{Call: _e.mock.On("GetVenues", ctx)}
+}
+
+func (_c *Application_GetVenues_Call) Run(run func(ctx context.Context)) *Application_GetVenues_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Application_GetVenues_Call) Return(_a0 []models.Venue, _a1 error) *Application_GetVenues_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetVenues_Call) RunAndReturn(run func(context.Context) ([]models.Venue, error)) *Application_GetVenues_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWaitlist provides a mock function with given fields: ctx, eventID
+func (_m *Application) GetWaitlist(ctx context.Context, eventID int64) ([]models.WaitlistEntry, error) {
+	ret := _m.Called(ctx, eventID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWaitlist")
+	}
+
+	var r0 []models.WaitlistEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.WaitlistEntry, error)); ok {
+		return rf(ctx, eventID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.WaitlistEntry); ok {
+		r0 = rf(ctx, eventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WaitlistEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, eventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetWaitlist_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWaitlist'
+type Application_GetWaitlist_Call struct {
+	*mock.Call
+}
+
+// GetWaitlist is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventID int64
+func (_e *Application_Expecter) GetWaitlist(ctx interface{}, eventID interface{}) *Application_GetWaitlist_Call {
+	return &Application_GetWaitlist_Call{Call: _e.mock.On("GetWaitlist", ctx, eventID)}
+}
+
+func (_c *Application_GetWaitlist_Call) Run(run func(ctx context.Context, eventID int64)) *Application_GetWaitlist_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetWaitlist_Call) Return(_a0 []models.WaitlistEntry, _a1 error) *Application_GetWaitlist_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetWaitlist_Call) RunAndReturn(run func(context.Context, int64) ([]models.WaitlistEntry, error)) *Application_GetWaitlist_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWaitlistEntry provides a mock function with given fields: ctx, id
+func (_m *Application) GetWaitlistEntry(ctx context.Context, id int64) (models.WaitlistEntry, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWaitlistEntry")
+	}
+
+	var r0 models.WaitlistEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.WaitlistEntry, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.WaitlistEntry); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.WaitlistEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetWaitlistEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWaitlistEntry'
+type Application_GetWaitlistEntry_Call struct {
+	*mock.Call
+}
+
+// GetWaitlistEntry is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) GetWaitlistEntry(ctx interface{}, id interface{}) *Application_GetWaitlistEntry_Call {
+	return &Application_GetWaitlistEntry_Call{Call: _e.mock.On("GetWaitlistEntry", ctx, id)}
+}
+
+func (_c *Application_GetWaitlistEntry_Call) Run(run func(ctx context.Context, id int64)) *Application_GetWaitlistEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetWaitlistEntry_Call) Return(_a0 models.WaitlistEntry, _a1 error) *Application_GetWaitlistEntry_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetWaitlistEntry_Call) RunAndReturn(run func(context.Context, int64) (models.WaitlistEntry, error)) *Application_GetWaitlistEntry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebhook provides a mock function with given fields: ctx, id
+func (_m *Application) GetWebhook(ctx context.Context, id int64) (models.Webhook, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhook")
+	}
+
+	var r0 models.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Webhook, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Webhook); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.Webhook)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetWebhook_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhook'
+type Application_GetWebhook_Call struct {
+	*mock.Call
+}
+
+// GetWebhook is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) GetWebhook(ctx interface{}, id interface{}) *Application_GetWebhook_Call {
+	return &Application_GetWebhook_Call{Call: _e.mock.On("GetWebhook", ctx, id)}
+}
+
+func (_c *Application_GetWebhook_Call) Run(run func(ctx context.Context, id int64)) *Application_GetWebhook_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetWebhook_Call) Return(_a0 models.Webhook, _a1 error) *Application_GetWebhook_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetWebhook_Call) RunAndReturn(run func(context.Context, int64) (models.Webhook, error)) *Application_GetWebhook_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebhookDeliveries provides a mock function with given fields: ctx, filter
+func (_m *Application) GetWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookDeliveries")
+	}
+
+	var r0 []models.WebhookDelivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.WebhookDeliveryFilter) []models.WebhookDelivery); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WebhookDelivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.WebhookDeliveryFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetWebhookDeliveries_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhookDeliveries'
+type Application_GetWebhookDeliveries_Call struct {
+	*mock.Call
+}
+
+// GetWebhookDeliveries is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter models.WebhookDeliveryFilter
+func (_e *Application_Expecter) GetWebhookDeliveries(ctx interface{}, filter interface{}) *Application_GetWebhookDeliveries_Call {
+	return &Application_GetWebhookDeliveries_Call{Call: _e.mock.On("GetWebhookDeliveries", ctx, filter)}
+}
+
+func (_c *Application_GetWebhookDeliveries_Call) Run(run func(ctx context.Context, filter models.WebhookDeliveryFilter)) *Application_GetWebhookDeliveries_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.WebhookDeliveryFilter))
+	})
+	return _c
+}
+
+func (_c *Application_GetWebhookDeliveries_Call) Return(_a0 []models.WebhookDelivery, _a1 error) *Application_GetWebhookDeliveries_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetWebhookDeliveries_Call) RunAndReturn(run func(context.Context, models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)) *Application_GetWebhookDeliveries_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebhookDelivery provides a mock function with given fields: ctx, id
+func (_m *Application) GetWebhookDelivery(ctx context.Context, id int64) (models.WebhookDelivery, []models.WebhookAttempt, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhookDelivery")
+	}
+
+	var r0 models.WebhookDelivery
+	var r1 []models.WebhookAttempt
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.WebhookDelivery, []models.WebhookAttempt, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.WebhookDelivery); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(models.WebhookDelivery)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) []models.WebhookAttempt); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]models.WebhookAttempt)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64) error); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Application_GetWebhookDelivery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhookDelivery'
+type Application_GetWebhookDelivery_Call struct {
+	*mock.Call
+}
+
+// GetWebhookDelivery is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *Application_Expecter) GetWebhookDelivery(ctx interface{}, id interface{}) *Application_GetWebhookDelivery_Call {
+	return &Application_GetWebhookDelivery_Call{Call: _e.mock.On("GetWebhookDelivery", ctx, id)}
+}
+
+func (_c *Application_GetWebhookDelivery_Call) Run(run func(ctx context.Context, id int64)) *Application_GetWebhookDelivery_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_GetWebhookDelivery_Call) Return(_a0 models.WebhookDelivery, _a1 []models.WebhookAttempt, _a2 error) *Application_GetWebhookDelivery_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *Application_GetWebhookDelivery_Call) RunAndReturn(run func(context.Context, int64) (models.WebhookDelivery, []models.WebhookAttempt, error)) *Application_GetWebhookDelivery_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetWebhooks provides a mock function with given fields: ctx
+func (_m *Application) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhooks")
+	}
+
+	var r0 []models.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Webhook, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Webhook); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Webhook)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_GetWebhooks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetWebhooks'
+type Application_GetWebhooks_Call struct {
+	*mock.Call
+}
+
+// GetWebhooks is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Application_Expecter) GetWebhooks(ctx interface{}) *Application_GetWebhooks_Call {
+	return &Application_GetWebhooks_Call{Call: _e.mock.On("GetWebhooks", ctx)}
+}
+
+func (_c *Application_GetWebhooks_Call) Run(run func(ctx context.Context)) *Application_GetWebhooks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Application_GetWebhooks_Call) Return(_a0 []models.Webhook, _a1 error) *Application_GetWebhooks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_GetWebhooks_Call) RunAndReturn(run func(context.Context) ([]models.Webhook, error)) *Application_GetWebhooks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// JoinWaitlist provides a mock function with given fields: ctx, entry
+func (_m *Application) JoinWaitlist(ctx context.Context, entry models.WaitlistEntry) (models.WaitlistEntry, error) {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for JoinWaitlist")
+	}
+
+	var r0 models.WaitlistEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.WaitlistEntry) (models.WaitlistEntry, error)); ok {
+		return rf(ctx, entry)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.WaitlistEntry) models.WaitlistEntry); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Get(0).(models.WaitlistEntry)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.WaitlistEntry) error); ok {
+		r1 = rf(ctx, entry)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_JoinWaitlist_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'JoinWaitlist'
+type Application_JoinWaitlist_Call struct {
+	*mock.Call
+}
+
+// JoinWaitlist is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entry models.WaitlistEntry
+func (_e *Application_Expecter) JoinWaitlist(ctx interface{}, entry interface{}) *Application_JoinWaitlist_Call {
+	return &Application_JoinWaitlist_Call{Call: _e.mock.On("JoinWaitlist", ctx, entry)}
+}
+
+func (_c *Application_JoinWaitlist_Call) Run(run func(ctx context.Context, entry models.WaitlistEntry)) *Application_JoinWaitlist_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.WaitlistEntry))
+	})
+	return _c
+}
+
+func (_c *Application_JoinWaitlist_Call) Return(_a0 models.WaitlistEntry, _a1 error) *Application_JoinWaitlist_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_JoinWaitlist_Call) RunAndReturn(run func(context.Context, models.WaitlistEntry) (models.WaitlistEntry, error)) *Application_JoinWaitlist_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// OrderCalendar provides a mock function with given fields: ctx, orderID
+func (_m *Application) OrderCalendar(ctx context.Context, orderID int64) (ical.Calendar, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OrderCalendar")
+	}
+
+	var r0 ical.Calendar
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (ical.Calendar, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ical.Calendar); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		r0 = ret.Get(0).(ical.Calendar)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_OrderCalendar_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OrderCalendar'
+type Application_OrderCalendar_Call struct {
+	*mock.Call
+}
+
+// OrderCalendar is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orderID int64
+func (_e *Application_Expecter) OrderCalendar(ctx interface{}, orderID interface{}) *Application_OrderCalendar_Call {
+	return &Application_OrderCalendar_Call{Call: _e.mock.On("OrderCalendar", ctx, orderID)}
+}
+
+func (_c *Application_OrderCalendar_Call) Run(run func(ctx context.Context, orderID int64)) *Application_OrderCalendar_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_OrderCalendar_Call) Return(_a0 ical.Calendar, _a1 error) *Application_OrderCalendar_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_OrderCalendar_Call) RunAndReturn(run func(context.Context, int64) (ical.Calendar, error)) *Application_OrderCalendar_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// OrderTicketsPDF provides a mock function with given fields: ctx, orderID
+func (_m *Application) OrderTicketsPDF(ctx context.Context, orderID int64) ([]byte, error) {
+	ret := _m.Called(ctx, orderID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OrderTicketsPDF")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]byte, error)); ok {
+		return rf(ctx, orderID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []byte); ok {
+		r0 = rf(ctx, orderID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, orderID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_OrderTicketsPDF_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'OrderTicketsPDF'
+type Application_OrderTicketsPDF_Call struct {
+	*mock.Call
+}
+
+// OrderTicketsPDF is a helper method to define mock.On call
+//   - ctx context.Context
+//   - orderID int64
+func (_e *Application_Expecter) OrderTicketsPDF(ctx interface{}, orderID interface{}) *Application_OrderTicketsPDF_Call {
+	return &Application_OrderTicketsPDF_Call{Call: _e.mock.On("OrderTicketsPDF", ctx, orderID)}
+}
+
+func (_c *Application_OrderTicketsPDF_Call) Run(run func(ctx context.Context, orderID int64)) *Application_OrderTicketsPDF_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_OrderTicketsPDF_Call) Return(_a0 []byte, _a1 error) *Application_OrderTicketsPDF_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Application_OrderTicketsPDF_Call) RunAndReturn(run func(context.Context, int64) ([]byte, error)) *Application_OrderTicketsPDF_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// QuarantineItems provides a mock function with given fields: ctx, items
+func (_m *Application) QuarantineItems(ctx context.Context, items []models.QuarantinedItem) ([]models.QuarantinedItem, error) {
+	ret := _m.Called(ctx, items)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QuarantineItems")
+	}
+
+	var r0 []models.QuarantinedItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []models.QuarantinedItem) ([]models.QuarantinedItem, error)); ok {
+		return rf(ctx, items)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []models.QuarantinedItem) []models.QuarantinedItem); ok {
+		r0 = rf(ctx, items)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.QuarantinedItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []models.QuarantinedItem) error); ok {
+		r1 = rf(ctx, items)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Application_QuarantineItems_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QuarantineItems'
+type Application_QuarantineItems_Call struct {
+	*mock.Call
+}
+
+// QuarantineItems is a helper method to define mock.On call
+//   - ctx context.Context
+//   - items []models.QuarantinedItem
+func (_e *Application_Expecter) QuarantineItems(ctx interface{}, items interface{}) *Application_QuarantineItems_Call {
+	return &Application_QuarantineItems_Call{Call: _e.mock.On("QuarantineItems", ctx, items)}
+}
+
+func (_c *Application_QuarantineItems_Call) Run(run func(ctx context.Context, items []models.QuarantinedItem)) *Application_QuarantineItems_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]models.QuarantinedItem))
+	})
+	return _c
+}
+
+func (_c *Application_QuarantineItems_Call) Return(_a0 []models.QuarantinedItem, _a1 error) *Application_QuarantineItems_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
 }
 
-func (_m *Application) EXPECT() *Application_Expecter {
-	return &Application_Expecter{mock: &_m.Mock}
+func (_c *Application_QuarantineItems_Call) RunAndReturn(run func(context.Context, []models.QuarantinedItem) ([]models.QuarantinedItem, error)) *Application_QuarantineItems_Call {
+	_c.Call.Return(run)
+	return _c
 }
 
-// CreateEvent provides a mock function with given fields: ctx, event
-func (_m *Application) CreateEvent(ctx context.Context, event models.Event) (models.Event, error) {
-	ret := _m.Called(ctx, event)
+// QuoteHold provides a mock function with given fields: ctx, checkout
+func (_m *Application) QuoteHold(ctx context.Context, checkout models.Checkout) (pricing.Quote, error) {
+	ret := _m.Called(ctx, checkout)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateEvent")
+		panic("no return value specified for QuoteHold")
 	}
 
-	var r0 models.Event
+	var r0 pricing.Quote
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, models.Event) (models.Event, error)); ok {
-		return rf(ctx, event)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Checkout) (pricing.Quote, error)); ok {
+		return rf(ctx, checkout)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, models.Event) models.Event); ok {
-		r0 = rf(ctx, event)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Checkout) pricing.Quote); ok {
+		r0 = rf(ctx, checkout)
 	} else {
-		r0 = ret.Get(0).(models.Event)
+		r0 = ret.Get(0).(pricing.Quote)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, models.Event) error); ok {
-		r1 = rf(ctx, event)
+	if rf, ok := ret.Get(1).(func(context.Context, models.Checkout) error); ok {
+		r1 = rf(ctx, checkout)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -50,58 +3680,56 @@ func (_m *Application) CreateEvent(ctx context.Context, event models.Event) (mod
 	return r0, r1
 }
 
-// Application_CreateEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateEvent'
-type Application_CreateEvent_Call struct {
+// Application_QuoteHold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'QuoteHold'
+type Application_QuoteHold_Call struct {
 	*mock.Call
 }
 
-// CreateEvent is a helper method to define mock.On call
+// QuoteHold is a helper method to define mock.On call
 //   - ctx context.Context
-//   - event models.Event
-func (_e *Application_Expecter) CreateEvent(ctx interface{}, event interface{}) *Application_CreateEvent_Call {
-	return &Application_CreateEvent_Call{Call: _e.mock.On("CreateEvent", ctx, event)}
+//   - checkout models.Checkout
+func (_e *Application_Expecter) QuoteHold(ctx interface{}, checkout interface{}) *Application_QuoteHold_Call {
+	return &Application_QuoteHold_Call{Call: _e.mock.On("QuoteHold", ctx, checkout)}
 }
 
-func (_c *Application_CreateEvent_Call) Run(run func(ctx context.Context, event models.Event)) *Application_CreateEvent_Call {
+func (_c *Application_QuoteHold_Call) Run(run func(ctx context.Context, checkout models.Checkout)) *Application_QuoteHold_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(models.Event))
+		run(args[0].(context.Context), args[1].(models.Checkout))
 	})
 	return _c
 }
 
-func (_c *Application_CreateEvent_Call) Return(_a0 models.Event, _a1 error) *Application_CreateEvent_Call {
+func (_c *Application_QuoteHold_Call) Return(_a0 pricing.Quote, _a1 error) *Application_QuoteHold_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Application_CreateEvent_Call) RunAndReturn(run func(context.Context, models.Event) (models.Event, error)) *Application_CreateEvent_Call {
+func (_c *Application_QuoteHold_Call) RunAndReturn(run func(context.Context, models.Checkout) (pricing.Quote, error)) *Application_QuoteHold_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateEvents provides a mock function with given fields: ctx, events
-func (_m *Application) CreateEvents(ctx context.Context, events []models.Event) ([]models.Event, error) {
-	ret := _m.Called(ctx, events)
+// RedeliverWebhookDelivery provides a mock function with given fields: ctx, id
+func (_m *Application) RedeliverWebhookDelivery(ctx context.Context, id int64) (models.WebhookDelivery, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateEvents")
+		panic("no return value specified for RedeliverWebhookDelivery")
 	}
 
-	var r0 []models.Event
+	var r0 models.WebhookDelivery
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []models.Event) ([]models.Event, error)); ok {
-		return rf(ctx, events)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.WebhookDelivery, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []models.Event) []models.Event); ok {
-		r0 = rf(ctx, events)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.WebhookDelivery); ok {
+		r0 = rf(ctx, id)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Event)
-		}
+		r0 = ret.Get(0).(models.WebhookDelivery)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []models.Event) error); ok {
-		r1 = rf(ctx, events)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -109,56 +3737,56 @@ func (_m *Application) CreateEvents(ctx context.Context, events []models.Event)
 	return r0, r1
 }
 
-// Application_CreateEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateEvents'
-type Application_CreateEvents_Call struct {
+// Application_RedeliverWebhookDelivery_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RedeliverWebhookDelivery'
+type Application_RedeliverWebhookDelivery_Call struct {
 	*mock.Call
 }
 
-// CreateEvents is a helper method to define mock.On call
+// RedeliverWebhookDelivery is a helper method to define mock.On call
 //   - ctx context.Context
-//   - events []models.Event
-func (_e *Application_Expecter) CreateEvents(ctx interface{}, events interface{}) *Application_CreateEvents_Call {
-	return &Application_CreateEvents_Call{Call: _e.mock.On("CreateEvents", ctx, events)}
+//   - id int64
+func (_e *Application_Expecter) RedeliverWebhookDelivery(ctx interface{}, id interface{}) *Application_RedeliverWebhookDelivery_Call {
+	return &Application_RedeliverWebhookDelivery_Call{Call: _e.mock.On("RedeliverWebhookDelivery", ctx, id)}
 }
 
-func (_c *Application_CreateEvents_Call) Run(run func(ctx context.Context, events []models.Event)) *Application_CreateEvents_Call {
+func (_c *Application_RedeliverWebhookDelivery_Call) Run(run func(ctx context.Context, id int64)) *Application_RedeliverWebhookDelivery_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].([]models.Event))
+		run(args[0].(context.Context), args[1].(int64))
 	})
 	return _c
 }
 
-func (_c *Application_CreateEvents_Call) Return(_a0 []models.Event, _a1 error) *Application_CreateEvents_Call {
+func (_c *Application_RedeliverWebhookDelivery_Call) Return(_a0 models.WebhookDelivery, _a1 error) *Application_RedeliverWebhookDelivery_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Application_CreateEvents_Call) RunAndReturn(run func(context.Context, []models.Event) ([]models.Event, error)) *Application_CreateEvents_Call {
+func (_c *Application_RedeliverWebhookDelivery_Call) RunAndReturn(run func(context.Context, int64) (models.WebhookDelivery, error)) *Application_RedeliverWebhookDelivery_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreatePlace provides a mock function with given fields: ctx, place
-func (_m *Application) CreatePlace(ctx context.Context, place models.Place) (models.Place, error) {
-	ret := _m.Called(ctx, place)
+// RefundOrder provides a mock function with given fields: ctx, orderID, itemIDs, reason
+func (_m *Application) RefundOrder(ctx context.Context, orderID int64, itemIDs []int64, reason string) (models.Refund, error) {
+	ret := _m.Called(ctx, orderID, itemIDs, reason)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreatePlace")
+		panic("no return value specified for RefundOrder")
 	}
 
-	var r0 models.Place
+	var r0 models.Refund
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, models.Place) (models.Place, error)); ok {
-		return rf(ctx, place)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64, string) (models.Refund, error)); ok {
+		return rf(ctx, orderID, itemIDs, reason)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, models.Place) models.Place); ok {
-		r0 = rf(ctx, place)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64, string) models.Refund); ok {
+		r0 = rf(ctx, orderID, itemIDs, reason)
 	} else {
-		r0 = ret.Get(0).(models.Place)
+		r0 = ret.Get(0).(models.Refund)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, models.Place) error); ok {
-		r1 = rf(ctx, place)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []int64, string) error); ok {
+		r1 = rf(ctx, orderID, itemIDs, reason)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -166,58 +3794,58 @@ func (_m *Application) CreatePlace(ctx context.Context, place models.Place) (mod
 	return r0, r1
 }
 
-// Application_CreatePlace_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePlace'
-type Application_CreatePlace_Call struct {
+// Application_RefundOrder_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RefundOrder'
+type Application_RefundOrder_Call struct {
 	*mock.Call
 }
 
-// CreatePlace is a helper method to define mock.On call
+// RefundOrder is a helper method to define mock.On call
 //   - ctx context.Context
-//   - place models.Place
-func (_e *Application_Expecter) CreatePlace(ctx interface{}, place interface{}) *Application_CreatePlace_Call {
-	return &Application_CreatePlace_Call{Call: _e.mock.On("CreatePlace", ctx, place)}
+//   - orderID int64
+//   - itemIDs []int64
+//   - reason string
+func (_e *Application_Expecter) RefundOrder(ctx interface{}, orderID interface{}, itemIDs interface{}, reason interface{}) *Application_RefundOrder_Call {
+	return &Application_RefundOrder_Call{Call: _e.mock.On("RefundOrder", ctx, orderID, itemIDs, reason)}
 }
 
-func (_c *Application_CreatePlace_Call) Run(run func(ctx context.Context, place models.Place)) *Application_CreatePlace_Call {
+func (_c *Application_RefundOrder_Call) Run(run func(ctx context.Context, orderID int64, itemIDs []int64, reason string)) *Application_RefundOrder_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(models.Place))
+		run(args[0].(context.Context), args[1].(int64), args[2].([]int64), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *Application_CreatePlace_Call) Return(_a0 models.Place, _a1 error) *Application_CreatePlace_Call {
+func (_c *Application_RefundOrder_Call) Return(_a0 models.Refund, _a1 error) *Application_RefundOrder_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Application_CreatePlace_Call) RunAndReturn(run func(context.Context, models.Place) (models.Place, error)) *Application_CreatePlace_Call {
+func (_c *Application_RefundOrder_Call) RunAndReturn(run func(context.Context, int64, []int64, string) (models.Refund, error)) *Application_RefundOrder_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreatePlaces provides a mock function with given fields: ctx, places
-func (_m *Application) CreatePlaces(ctx context.Context, places []models.Place) ([]models.Place, error) {
-	ret := _m.Called(ctx, places)
+// ReleaseHold provides a mock function with given fields: ctx, id
+func (_m *Application) ReleaseHold(ctx context.Context, id int64) (models.Hold, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreatePlaces")
+		panic("no return value specified for ReleaseHold")
 	}
 
-	var r0 []models.Place
+	var r0 models.Hold
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []models.Place) ([]models.Place, error)); ok {
-		return rf(ctx, places)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Hold, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []models.Place) []models.Place); ok {
-		r0 = rf(ctx, places)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Hold); ok {
+		r0 = rf(ctx, id)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Place)
-		}
+		r0 = ret.Get(0).(models.Hold)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []models.Place) error); ok {
-		r1 = rf(ctx, places)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -225,56 +3853,56 @@ func (_m *Application) CreatePlaces(ctx context.Context, places []models.Place)
 	return r0, r1
 }
 
-// Application_CreatePlaces_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePlaces'
-type Application_CreatePlaces_Call struct {
+// Application_ReleaseHold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseHold'
+type Application_ReleaseHold_Call struct {
 	*mock.Call
 }
 
-// CreatePlaces is a helper method to define mock.On call
+// ReleaseHold is a helper method to define mock.On call
 //   - ctx context.Context
-//   - places []models.Place
-func (_e *Application_Expecter) CreatePlaces(ctx interface{}, places interface{}) *Application_CreatePlaces_Call {
-	return &Application_CreatePlaces_Call{Call: _e.mock.On("CreatePlaces", ctx, places)}
+//   - id int64
+func (_e *Application_Expecter) ReleaseHold(ctx interface{}, id interface{}) *Application_ReleaseHold_Call {
+	return &Application_ReleaseHold_Call{Call: _e.mock.On("ReleaseHold", ctx, id)}
 }
 
-func (_c *Application_CreatePlaces_Call) Run(run func(ctx context.Context, places []models.Place)) *Application_CreatePlaces_Call {
+func (_c *Application_ReleaseHold_Call) Run(run func(ctx context.Context, id int64)) *Application_ReleaseHold_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].([]models.Place))
+		run(args[0].(context.Context), args[1].(int64))
 	})
 	return _c
 }
 
-func (_c *Application_CreatePlaces_Call) Return(_a0 []models.Place, _a1 error) *Application_CreatePlaces_Call {
+func (_c *Application_ReleaseHold_Call) Return(_a0 models.Hold, _a1 error) *Application_ReleaseHold_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Application_CreatePlaces_Call) RunAndReturn(run func(context.Context, []models.Place) ([]models.Place, error)) *Application_CreatePlaces_Call {
+func (_c *Application_ReleaseHold_Call) RunAndReturn(run func(context.Context, int64) (models.Hold, error)) *Application_ReleaseHold_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateShow provides a mock function with given fields: ctx, shows
-func (_m *Application) CreateShow(ctx context.Context, shows models.Show) (models.Show, error) {
-	ret := _m.Called(ctx, shows)
+// ScanTicket provides a mock function with given fields: ctx, code, eventID
+func (_m *Application) ScanTicket(ctx context.Context, code string, eventID int64) (models.OrderItem, error) {
+	ret := _m.Called(ctx, code, eventID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateShow")
+		panic("no return value specified for ScanTicket")
 	}
 
-	var r0 models.Show
+	var r0 models.OrderItem
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, models.Show) (models.Show, error)); ok {
-		return rf(ctx, shows)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (models.OrderItem, error)); ok {
+		return rf(ctx, code, eventID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, models.Show) models.Show); ok {
-		r0 = rf(ctx, shows)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) models.OrderItem); ok {
+		r0 = rf(ctx, code, eventID)
 	} else {
-		r0 = ret.Get(0).(models.Show)
+		r0 = ret.Get(0).(models.OrderItem)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, models.Show) error); ok {
-		r1 = rf(ctx, shows)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, code, eventID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -282,58 +3910,57 @@ func (_m *Application) CreateShow(ctx context.Context, shows models.Show) (model
 	return r0, r1
 }
 
-// Application_CreateShow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateShow'
-type Application_CreateShow_Call struct {
+// Application_ScanTicket_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ScanTicket'
+type Application_ScanTicket_Call struct {
 	*mock.Call
 }
 
-// CreateShow is a helper method to define mock.On call
+// ScanTicket is a helper method to define mock.On call
 //   - ctx context.Context
-//   - shows models.Show
-func (_e *Application_Expecter) CreateShow(ctx interface{}, shows interface{}) *Application_CreateShow_Call {
-	return &Application_CreateShow_Call{Call: _e.mock.On("CreateShow", ctx, shows)}
+//   - code string
+//   - eventID int64
+func (_e *Application_Expecter) ScanTicket(ctx interface{}, code interface{}, eventID interface{}) *Application_ScanTicket_Call {
+	return &Application_ScanTicket_Call{Call: _e.mock.On("ScanTicket", ctx, code, eventID)}
 }
 
-func (_c *Application_CreateShow_Call) Run(run func(ctx context.Context, shows models.Show)) *Application_CreateShow_Call {
+func (_c *Application_ScanTicket_Call) Run(run func(ctx context.Context, code string, eventID int64)) *Application_ScanTicket_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(models.Show))
+		run(args[0].(context.Context), args[1].(string), args[2].(int64))
 	})
 	return _c
 }
 
-func (_c *Application_CreateShow_Call) Return(_a0 models.Show, _a1 error) *Application_CreateShow_Call {
+func (_c *Application_ScanTicket_Call) Return(_a0 models.OrderItem, _a1 error) *Application_ScanTicket_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Application_CreateShow_Call) RunAndReturn(run func(context.Context, models.Show) (models.Show, error)) *Application_CreateShow_Call {
+func (_c *Application_ScanTicket_Call) RunAndReturn(run func(context.Context, string, int64) (models.OrderItem, error)) *Application_ScanTicket_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateShows provides a mock function with given fields: ctx, shows
-func (_m *Application) CreateShows(ctx context.Context, shows []models.Show) ([]models.Show, error) {
-	ret := _m.Called(ctx, shows)
+// ShowCalendar provides a mock function with given fields: ctx, showID
+func (_m *Application) ShowCalendar(ctx context.Context, showID int64) (ical.Calendar, error) {
+	ret := _m.Called(ctx, showID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateShows")
+		panic("no return value specified for ShowCalendar")
 	}
 
-	var r0 []models.Show
+	var r0 ical.Calendar
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []models.Show) ([]models.Show, error)); ok {
-		return rf(ctx, shows)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (ical.Calendar, error)); ok {
+		return rf(ctx, showID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []models.Show) []models.Show); ok {
-		r0 = rf(ctx, shows)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ical.Calendar); ok {
+		r0 = rf(ctx, showID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Show)
-		}
+		r0 = ret.Get(0).(ical.Calendar)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []models.Show) error); ok {
-		r1 = rf(ctx, shows)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, showID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -341,58 +3968,105 @@ func (_m *Application) CreateShows(ctx context.Context, shows []models.Show) ([]
 	return r0, r1
 }
 
-// Application_CreateShows_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateShows'
-type Application_CreateShows_Call struct {
+// Application_ShowCalendar_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ShowCalendar'
+type Application_ShowCalendar_Call struct {
 	*mock.Call
 }
 
-// CreateShows is a helper method to define mock.On call
+// ShowCalendar is a helper method to define mock.On call
 //   - ctx context.Context
-//   - shows []models.Show
-func (_e *Application_Expecter) CreateShows(ctx interface{}, shows interface{}) *Application_CreateShows_Call {
-	return &Application_CreateShows_Call{Call: _e.mock.On("CreateShows", ctx, shows)}
+//   - showID int64
+func (_e *Application_Expecter) ShowCalendar(ctx interface{}, showID interface{}) *Application_ShowCalendar_Call {
+	return &Application_ShowCalendar_Call{Call: _e.mock.On("ShowCalendar", ctx, showID)}
 }
 
-func (_c *Application_CreateShows_Call) Run(run func(ctx context.Context, shows []models.Show)) *Application_CreateShows_Call {
+func (_c *Application_ShowCalendar_Call) Run(run func(ctx context.Context, showID int64)) *Application_ShowCalendar_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].([]models.Show))
+		run(args[0].(context.Context), args[1].(int64))
 	})
 	return _c
 }
 
-func (_c *Application_CreateShows_Call) Return(_a0 []models.Show, _a1 error) *Application_CreateShows_Call {
+func (_c *Application_ShowCalendar_Call) Return(_a0 ical.Calendar, _a1 error) *Application_ShowCalendar_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Application_CreateShows_Call) RunAndReturn(run func(context.Context, []models.Show) ([]models.Show, error)) *Application_CreateShows_Call {
+func (_c *Application_ShowCalendar_Call) RunAndReturn(run func(context.Context, int64) (ical.Calendar, error)) *Application_ShowCalendar_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetEvents provides a mock function with given fields: ctx
-func (_m *Application) GetEvents(ctx context.Context) ([]models.Event, error) {
-	ret := _m.Called(ctx)
+// SubscribeAvailability provides a mock function with given fields: eventID, lastID
+func (_m *Application) SubscribeAvailability(eventID int64, lastID int64) *availability.Subscription {
+	ret := _m.Called(eventID, lastID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetEvents")
+		panic("no return value specified for SubscribeAvailability")
 	}
 
-	var r0 []models.Event
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Event, error)); ok {
-		return rf(ctx)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context) []models.Event); ok {
-		r0 = rf(ctx)
+	var r0 *availability.Subscription
+	if rf, ok := ret.Get(0).(func(int64, int64) *availability.Subscription); ok {
+		r0 = rf(eventID, lastID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Event)
+			r0 = ret.Get(0).(*availability.Subscription)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	return r0
+}
+
+// Application_SubscribeAvailability_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SubscribeAvailability'
+type Application_SubscribeAvailability_Call struct {
+	*mock.Call
+}
+
+// SubscribeAvailability is a helper method to define mock.On call
+//   - eventID int64
+//   - lastID int64
+func (_e *Application_Expecter) SubscribeAvailability(eventID interface{}, lastID interface{}) *Application_SubscribeAvailability_Call {
+	return &Application_SubscribeAvailability_Call{Call: _e.mock.On("SubscribeAvailability", eventID, lastID)}
+}
+
+func (_c *Application_SubscribeAvailability_Call) Run(run func(eventID int64, lastID int64)) *Application_SubscribeAvailability_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *Application_SubscribeAvailability_Call) Return(_a0 *availability.Subscription) *Application_SubscribeAvailability_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Application_SubscribeAvailability_Call) RunAndReturn(run func(int64, int64) *availability.Subscription) *Application_SubscribeAvailability_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TicketCode provides a mock function with given fields: ctx, id
+func (_m *Application) TicketCode(ctx context.Context, id int64) (string, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TicketCode")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (string, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) string); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -400,57 +4074,103 @@ func (_m *Application) GetEvents(ctx context.Context) ([]models.Event, error) {
 	return r0, r1
 }
 
-// Application_GetEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetEvents'
-type Application_GetEvents_Call struct {
+// Application_TicketCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TicketCode'
+type Application_TicketCode_Call struct {
 	*mock.Call
 }
 
-// GetEvents is a helper method to define mock.On call
+// TicketCode is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *Application_Expecter) GetEvents(ctx interface{}) *Application_GetEvents_Call {
-	return &Application_GetEvents_Call{Call: _e.mock.On("GetEvents", ctx)}
+//   - id int64
+func (_e *Application_Expecter) TicketCode(ctx interface{}, id interface{}) *Application_TicketCode_Call {
+	return &Application_TicketCode_Call{Call: _e.mock.On("TicketCode", ctx, id)}
 }
 
-func (_c *Application_GetEvents_Call) Run(run func(ctx context.Context)) *Application_GetEvents_Call {
+func (_c *Application_TicketCode_Call) Run(run func(ctx context.Context, id int64)) *Application_TicketCode_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(int64))
 	})
 	return _c
 }
 
-func (_c *Application_GetEvents_Call) Return(_a0 []models.Event, _a1 error) *Application_GetEvents_Call {
+func (_c *Application_TicketCode_Call) Return(_a0 string, _a1 error) *Application_TicketCode_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Application_GetEvents_Call) RunAndReturn(run func(context.Context) ([]models.Event, error)) *Application_GetEvents_Call {
+func (_c *Application_TicketCode_Call) RunAndReturn(run func(context.Context, int64) (string, error)) *Application_TicketCode_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetPlaces provides a mock function with given fields: ctx
-func (_m *Application) GetPlaces(ctx context.Context) ([]models.Place, error) {
-	ret := _m.Called(ctx)
+// TicketKeys provides a mock function with no fields
+func (_m *Application) TicketKeys() []ed25519.PublicKey {
+	ret := _m.Called()
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPlaces")
+		panic("no return value specified for TicketKeys")
 	}
 
-	var r0 []models.Place
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Place, error)); ok {
-		return rf(ctx)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context) []models.Place); ok {
-		r0 = rf(ctx)
+	var r0 []ed25519.PublicKey
+	if rf, ok := ret.Get(0).(func() []ed25519.PublicKey); ok {
+		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Place)
+			r0 = ret.Get(0).([]ed25519.PublicKey)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	return r0
+}
+
+// Application_TicketKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TicketKeys'
+type Application_TicketKeys_Call struct {
+	*mock.Call
+}
+
+// TicketKeys is a helper method to define mock.On call
+func (_e *Application_Expecter) TicketKeys() *Application_TicketKeys_Call {
+	return &Application_TicketKeys_Call{Call: _e.mock.On("TicketKeys")}
+}
+
+func (_c *Application_TicketKeys_Call) Run(run func()) *Application_TicketKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Application_TicketKeys_Call) Return(_a0 []ed25519.PublicKey) *Application_TicketKeys_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Application_TicketKeys_Call) RunAndReturn(run func() []ed25519.PublicKey) *Application_TicketKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateCustomer provides a mock function with given fields: ctx, customer, check
+func (_m *Application) UpdateCustomer(ctx context.Context, customer models.Customer, check models.Check[models.Customer]) (models.Customer, error) {
+	ret := _m.Called(ctx, customer, check)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateCustomer")
+	}
+
+	var r0 models.Customer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Customer, models.Check[models.Customer]) (models.Customer, error)); ok {
+		return rf(ctx, customer, check)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Customer, models.Check[models.Customer]) models.Customer); ok {
+		r0 = rf(ctx, customer, check)
+	} else {
+		r0 = ret.Get(0).(models.Customer)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Customer, models.Check[models.Customer]) error); ok {
+		r1 = rf(ctx, customer, check)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -458,57 +4178,57 @@ func (_m *Application) GetPlaces(ctx context.Context) ([]models.Place, error) {
 	return r0, r1
 }
 
-// Application_GetPlaces_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPlaces'
-type Application_GetPlaces_Call struct {
+// Application_UpdateCustomer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateCustomer'
+type Application_UpdateCustomer_Call struct {
 	*mock.Call
 }
 
-// GetPlaces is a helper method to define mock.On call
+// UpdateCustomer is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *Application_Expecter) GetPlaces(ctx interface{}) *Application_GetPlaces_Call {
-	return &Application_GetPlaces_Call{Call: _e.mock.On("GetPlaces", ctx)}
+//   - customer models.Customer
+//   - check models.Check[models.Customer]
+func (_e *Application_Expecter) UpdateCustomer(ctx interface{}, customer interface{}, check interface{}) *Application_UpdateCustomer_Call {
+	return &Application_UpdateCustomer_Call{Call: _e.mock.On("UpdateCustomer", ctx, customer, check)}
 }
 
-func (_c *Application_GetPlaces_Call) Run(run func(ctx context.Context)) *Application_GetPlaces_Call {
+func (_c *Application_UpdateCustomer_Call) Run(run func(ctx context.Context, customer models.Customer, check models.Check[models.Customer])) *Application_UpdateCustomer_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(models.Customer), args[2].(models.Check[models.Customer]))
 	})
 	return _c
 }
 
-func (_c *Application_GetPlaces_Call) Return(_a0 []models.Place, _a1 error) *Application_GetPlaces_Call {
+func (_c *Application_UpdateCustomer_Call) Return(_a0 models.Customer, _a1 error) *Application_UpdateCustomer_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Application_GetPlaces_Call) RunAndReturn(run func(context.Context) ([]models.Place, error)) *Application_GetPlaces_Call {
+func (_c *Application_UpdateCustomer_Call) RunAndReturn(run func(context.Context, models.Customer, models.Check[models.Customer]) (models.Customer, error)) *Application_UpdateCustomer_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetShows provides a mock function with given fields: ctx
-func (_m *Application) GetShows(ctx context.Context) ([]models.Show, error) {
-	ret := _m.Called(ctx)
+// UpdatePriceCategory provides a mock function with given fields: ctx, category, check
+func (_m *Application) UpdatePriceCategory(ctx context.Context, category models.PriceCategory, check models.Check[models.PriceCategory]) (models.PriceCategory, error) {
+	ret := _m.Called(ctx, category, check)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetShows")
+		panic("no return value specified for UpdatePriceCategory")
 	}
 
-	var r0 []models.Show
+	var r0 models.PriceCategory
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Show, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, models.PriceCategory, models.Check[models.PriceCategory]) (models.PriceCategory, error)); ok {
+		return rf(ctx, category, check)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []models.Show); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, models.PriceCategory, models.Check[models.PriceCategory]) models.PriceCategory); ok {
+		r0 = rf(ctx, category, check)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Show)
-		}
+		r0 = ret.Get(0).(models.PriceCategory)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, models.PriceCategory, models.Check[models.PriceCategory]) error); ok {
+		r1 = rf(ctx, category, check)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -516,30 +4236,32 @@ func (_m *Application) GetShows(ctx context.Context) ([]models.Show, error) {
 	return r0, r1
 }
 
-// Application_GetShows_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetShows'
-type Application_GetShows_Call struct {
+// Application_UpdatePriceCategory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePriceCategory'
+type Application_UpdatePriceCategory_Call struct {
 	*mock.Call
 }
 
-// GetShows is a helper method to define mock.On call
+// UpdatePriceCategory is a helper method to define mock.On call
 //   - ctx context.Context
-func (_e *Application_Expecter) GetShows(ctx interface{}) *Application_GetShows_Call {
-	return &Application_GetShows_Call{Call: _e.mock.On("GetShows", ctx)}
+//   - category models.PriceCategory
+//   - check models.Check[models.PriceCategory]
+func (_e *Application_Expecter) UpdatePriceCategory(ctx interface{}, category interface{}, check interface{}) *Application_UpdatePriceCategory_Call {
+	return &Application_UpdatePriceCategory_Call{Call: _e.mock.On("UpdatePriceCategory", ctx, category, check)}
 }
 
-func (_c *Application_GetShows_Call) Run(run func(ctx context.Context)) *Application_GetShows_Call {
+func (_c *Application_UpdatePriceCategory_Call) Run(run func(ctx context.Context, category models.PriceCategory, check models.Check[models.PriceCategory])) *Application_UpdatePriceCategory_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context))
+		run(args[0].(context.Context), args[1].(models.PriceCategory), args[2].(models.Check[models.PriceCategory]))
 	})
 	return _c
 }
 
-func (_c *Application_GetShows_Call) Return(_a0 []models.Show, _a1 error) *Application_GetShows_Call {
+func (_c *Application_UpdatePriceCategory_Call) Return(_a0 models.PriceCategory, _a1 error) *Application_UpdatePriceCategory_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *Application_GetShows_Call) RunAndReturn(run func(context.Context) ([]models.Show, error)) *Application_GetShows_Call {
+func (_c *Application_UpdatePriceCategory_Call) RunAndReturn(run func(context.Context, models.PriceCategory, models.Check[models.PriceCategory]) (models.PriceCategory, error)) *Application_UpdatePriceCategory_Call {
 	_c.Call.Return(run)
 	return _c
 }
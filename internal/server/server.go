@@ -4,10 +4,15 @@ package server
 
 import (
 	"context"
+	"crypto/ed25519"
 	"errors"
 	"fmt"
 	"os"
 
+	"github.com/cronnoss/tk-api/internal/availability"
+	"github.com/cronnoss/tk-api/internal/ical"
+	"github.com/cronnoss/tk-api/internal/pricing"
+	"github.com/cronnoss/tk-api/internal/seating"
 	"github.com/cronnoss/tk-api/internal/storage/models"
 )
 
@@ -29,12 +34,83 @@ type Application interface {
 	GetShows(ctx context.Context) ([]models.Show, error)
 	CreateShows(ctx context.Context, shows []models.Show) ([]models.Show, error)
 	CreateShow(ctx context.Context, shows models.Show) (models.Show, error)
-	GetEvents(ctx context.Context) ([]models.Event, error)
+	GetEvents(ctx context.Context, filter models.EventFilter) ([]models.Event, error)
 	CreateEvents(ctx context.Context, events []models.Event) ([]models.Event, error)
 	CreateEvent(ctx context.Context, event models.Event) (models.Event, error)
-	GetPlaces(ctx context.Context) ([]models.Place, error)
+	GetPlaces(ctx context.Context, filter models.PlaceFilter) ([]models.Place, error)
+	GetPlacesInRect(ctx context.Context, eventID int64, r models.Rect) ([]models.Place, error)
+	GetNearestPlaces(ctx context.Context, eventID int64, pt models.Point, n int) ([]models.Place, error)
+	GetPlaceAdjacency(ctx context.Context, eventID int64) ([]models.Adjacency, error)
 	CreatePlaces(ctx context.Context, places []models.Place) ([]models.Place, error)
 	CreatePlace(ctx context.Context, place models.Place) (models.Place, error)
+	GetEvent(ctx context.Context, id int64) (models.Event, error)
+	AttachEventToHall(ctx context.Context, eventID, hallID int64, check models.Check[models.Event],
+	) (models.Event, error)
+	CreateVenue(ctx context.Context, venue models.Venue) (models.Venue, error)
+	GetVenues(ctx context.Context) ([]models.Venue, error)
+	GetVenue(ctx context.Context, id int64) (models.Venue, error)
+	CreateHall(ctx context.Context, hall models.Hall) (models.Hall, error)
+	GetHalls(ctx context.Context, venueID int64) ([]models.Hall, error)
+	GetHall(ctx context.Context, id int64) (models.Hall, error)
+	CreateSection(ctx context.Context, section models.Section) (models.Section, error)
+	GetSections(ctx context.Context, hallID int64) ([]models.Section, error)
+	CreateHallPlaces(ctx context.Context, hallID int64, places []models.Place) ([]models.Place, error)
+	GetIdempotencyKey(ctx context.Context, key string) (models.IdempotencyKey, error)
+	CreateIdempotencyKey(ctx context.Context, key models.IdempotencyKey) (models.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, key models.IdempotencyKey) error
+	DeleteIdempotencyKey(ctx context.Context, key string) error
+	QuarantineItems(ctx context.Context, items []models.QuarantinedItem) ([]models.QuarantinedItem, error)
+	GetQuarantinedItems(ctx context.Context) ([]models.QuarantinedItem, error)
+	CreateHold(ctx context.Context, eventID, customerID int64, placeIDs []int64) (models.Hold, error)
+	GetHold(ctx context.Context, id int64) (models.Hold, error)
+	ReleaseHold(ctx context.Context, id int64) (models.Hold, error)
+	BestSeats(ctx context.Context, eventID int64, prefs seating.Preferences, hold bool, customerID int64,
+	) ([]models.Place, *models.Hold, error)
+	CreatePriceCategory(ctx context.Context, category models.PriceCategory) (models.PriceCategory, error)
+	GetPriceCategories(ctx context.Context, eventID int64) ([]models.PriceCategory, error)
+	UpdatePriceCategory(ctx context.Context, category models.PriceCategory, check models.Check[models.PriceCategory],
+	) (models.PriceCategory, error)
+	DeletePriceCategory(ctx context.Context, id int64) error
+	AssignPrices(ctx context.Context, eventID int64, assignments []models.PriceAssignment,
+		check models.Check[models.Event]) (int64, error)
+	QuoteHold(ctx context.Context, checkout models.Checkout) (pricing.Quote, error)
+	CreateOrder(ctx context.Context, checkout models.Checkout) (models.Order, error)
+	GetOrder(ctx context.Context, id int64) (models.Order, error)
+	CreatePromotion(ctx context.Context, promotion models.Promotion) (models.Promotion, error)
+	GetPromotions(ctx context.Context) ([]models.Promotion, error)
+	GetPromotion(ctx context.Context, id int64) (models.Promotion, error)
+	DeactivatePromotion(ctx context.Context, id int64) (models.Promotion, error)
+	CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error)
+	GetCustomer(ctx context.Context, id int64) (models.Customer, error)
+	UpdateCustomer(ctx context.Context, customer models.Customer, check models.Check[models.Customer],
+	) (models.Customer, error)
+	EraseCustomer(ctx context.Context, id int64) (models.Customer, error)
+	GetCustomerOrders(ctx context.Context, customerID int64) ([]models.Order, error)
+	ExportCustomer(ctx context.Context, id int64) (models.CustomerExport, error)
+	JoinWaitlist(ctx context.Context, entry models.WaitlistEntry) (models.WaitlistEntry, error)
+	GetWaitlist(ctx context.Context, eventID int64) ([]models.WaitlistEntry, error)
+	GetWaitlistEntry(ctx context.Context, id int64) (models.WaitlistEntry, error)
+	CancelWaitlistEntry(ctx context.Context, id int64) (models.WaitlistEntry, error)
+	RefundOrder(ctx context.Context, orderID int64, itemIDs []int64, reason string) (models.Refund, error)
+	ExchangeOrderItems(ctx context.Context, orderID int64, itemIDs []int64, eventID int64, placeIDs []int64,
+	) (models.Exchange, error)
+	GetOrderAudit(ctx context.Context, orderID int64) ([]models.AuditRecord, error)
+	TicketCode(ctx context.Context, id int64) (string, error)
+	TicketKeys() []ed25519.PublicKey
+	ScanTicket(ctx context.Context, code string, eventID int64) (models.OrderItem, error)
+	OrderTicketsPDF(ctx context.Context, orderID int64) ([]byte, error)
+	CancelEvent(ctx context.Context, id int64, check models.Check[models.Event]) (models.Event, error)
+	ShowCalendar(ctx context.Context, showID int64) (ical.Calendar, error)
+	OrderCalendar(ctx context.Context, orderID int64) (ical.Calendar, error)
+	SubscribeAvailability(eventID, lastID int64) *availability.Subscription
+	AvailabilitySubscribed() []int64
+	CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, id int64) (models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	GetWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (models.WebhookDelivery, []models.WebhookAttempt, error)
+	RedeliverWebhookDelivery(ctx context.Context, id int64) (models.WebhookDelivery, error)
 }
 
 func Exitfail(msg string) {
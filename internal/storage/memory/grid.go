@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"math"
+
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+// gridCellSize is the side of a grid cell in seat map units, a few seats wide.
+const gridCellSize = 64.0
+
+type cell struct {
+	x, y int
+}
+
+func cellOf(x, y float64) cell {
+	return cell{x: int(math.Floor(x / gridCellSize)), y: int(math.Floor(y / gridCellSize))}
+}
+
+// grid is a uniform grid spatial index of the places of one event. A place is registered
+// in every cell its bounds cover, so lookups need to visit only the cells of the query area.
+type grid struct {
+	cells    map[cell]map[int64]struct{}
+	min, max cell
+}
+
+func newGrid() *grid {
+	return &grid{cells: make(map[cell]map[int64]struct{})}
+}
+
+// span calls fn for every cell covering r, clamped to the populated extent of the grid.
+func (g *grid) span(r models.Rect, fn func(c cell)) {
+	lo, hi := cellOf(r.MinX, r.MinY), cellOf(r.MaxX, r.MaxY)
+	lo.x, lo.y = max(lo.x, g.min.x), max(lo.y, g.min.y)
+	hi.x, hi.y = min(hi.x, g.max.x), min(hi.y, g.max.y)
+	for x := lo.x; x <= hi.x; x++ {
+		for y := lo.y; y <= hi.y; y++ {
+			fn(cell{x: x, y: y})
+		}
+	}
+}
+
+func (g *grid) insert(p models.Place) {
+	b := p.Bounds()
+	lo, hi := cellOf(b.MinX, b.MinY), cellOf(b.MaxX, b.MaxY)
+	if len(g.cells) == 0 {
+		g.min, g.max = lo, hi
+	}
+	g.min.x, g.min.y = min(g.min.x, lo.x), min(g.min.y, lo.y)
+	g.max.x, g.max.y = max(g.max.x, hi.x), max(g.max.y, hi.y)
+	g.span(b, func(c cell) {
+		if g.cells[c] == nil {
+			g.cells[c] = make(map[int64]struct{})
+		}
+		g.cells[c][p.ID] = struct{}{}
+	})
+}
+
+func (g *grid) remove(p models.Place) {
+	g.span(p.Bounds(), func(c cell) {
+		delete(g.cells[c], p.ID)
+		if len(g.cells[c]) == 0 {
+			delete(g.cells, c)
+		}
+	})
+}
+
+// search returns IDs of places whose bounds may intersect r.
+func (g *grid) search(r models.Rect) map[int64]struct{} {
+	ids := make(map[int64]struct{})
+	if len(g.cells) == 0 {
+		return ids
+	}
+	g.span(r, func(c cell) {
+		for id := range g.cells[c] {
+			ids[id] = struct{}{}
+		}
+	})
+	return ids
+}
+
+// all returns IDs of all indexed places.
+func (g *grid) all() map[int64]struct{} {
+	ids := make(map[int64]struct{})
+	for _, c := range g.cells {
+		for id := range c {
+			ids[id] = struct{}{}
+		}
+	}
+	return ids
+}
+
+// ring calls fn for the cells at Chebyshev distance k from center.
+func (g *grid) ring(center cell, k int, fn func(ids map[int64]struct{})) {
+	for x := center.x - k; x <= center.x+k; x++ {
+		for y := center.y - k; y <= center.y+k; y++ {
+			if max(abs(x-center.x), abs(y-center.y)) != k {
+				continue
+			}
+			if ids, ok := g.cells[cell{x: x, y: y}]; ok {
+				fn(ids)
+			}
+		}
+	}
+}
+
+// nearest visits places in rings of cells around pt until visit reports that farther places
+// can't improve the result. A place not visited after ring k has its centre at least
+// k*gridCellSize away from pt, as every place is registered in the cell of its centre.
+func (g *grid) nearest(pt models.Point, visit func(id int64), done func(reach float64) bool) {
+	if len(g.cells) == 0 {
+		return
+	}
+	center := cellOf(pt.X, pt.Y)
+	maxK := max(abs(center.x-g.min.x), abs(center.x-g.max.x), abs(center.y-g.min.y), abs(center.y-g.max.y))
+	seen := make(map[int64]struct{})
+	for k := 0; k <= maxK; k++ {
+		g.ring(center, k, func(ids map[int64]struct{}) {
+			for id := range ids {
+				if _, ok := seen[id]; !ok {
+					seen[id] = struct{}{}
+					visit(id)
+				}
+			}
+		})
+		if done(float64(k) * gridCellSize) {
+			return
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -2,9 +2,17 @@ package memory
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/cronnoss/tk-api/internal/domain"
+	"github.com/cronnoss/tk-api/internal/model"
 	"github.com/cronnoss/tk-api/internal/storage/models"
 )
 
@@ -14,11 +22,55 @@ type mapEvent map[int64]*models.Event
 
 type mapPlace map[int64]*models.Place
 
+type mapVenue map[int64]*models.Venue
+
+type mapHall map[int64]*models.Hall
+
+type mapSection map[int64]*models.Section
+
+type mapHold map[int64]*models.Hold
+
+type mapPriceCategory map[int64]*models.PriceCategory
+
+type mapOrder map[int64]*models.Order
+
+type mapPromotion map[int64]*models.Promotion
+
+type mapCustomer map[int64]*models.Customer
+
+type mapWaitlistEntry map[int64]*models.WaitlistEntry
+
+type mapIdempotencyKey map[string]*models.IdempotencyKey
+
+type mapWebhook map[int64]*models.Webhook
+
+type mapWebhookDelivery map[int64]*models.WebhookDelivery
+
 type Storage struct {
-	dataShow  mapShow
-	dataEvent mapEvent
-	dataPlace mapPlace
-	mu        sync.RWMutex
+	dataShow           mapShow
+	dataEvent          mapEvent
+	dataPlace          mapPlace
+	placeIndex         map[int64]*grid
+	dataVenue          mapVenue
+	dataHall           mapHall
+	dataSection        mapSection
+	dataHold           mapHold
+	dataPriceCategory  mapPriceCategory
+	dataOrder          mapOrder
+	dataPromotion      mapPromotion
+	dataRedemption     []models.Redemption
+	dataCustomer       mapCustomer
+	dataWaitlist       mapWaitlistEntry
+	dataRefund         []models.Refund
+	dataExchange       []models.Exchange
+	dataAudit          []models.AuditRecord
+	dataIdempotencyKey mapIdempotencyKey
+	dataQuarantine     []models.QuarantinedItem
+	dataWebhook        mapWebhook
+	dataDelivery       mapWebhookDelivery
+	dataAttempt        []models.WebhookAttempt
+	dataOutbox         []models.DomainEvent
+	mu                 sync.RWMutex
 }
 
 var GenID int64
@@ -27,12 +79,45 @@ func getNewIDSafe() int64 {
 	return atomic.AddInt64(&GenID, 1)
 }
 
+var genLocalPlaceID int64 = models.FirstLocalPlaceID - 1
+
+// newLocalPlaceID returns an ID of a place created here, above the IDs of places synced from the remote API.
+func newLocalPlaceID() int64 {
+	return atomic.AddInt64(&genLocalPlaceID, 1)
+}
+
+// newID returns id if set, new IDs are generated above it from then on. A new ID is returned otherwise.
+func newID(id int64) int64 {
+	if id == 0 {
+		return getNewIDSafe()
+	}
+	for {
+		last := atomic.LoadInt64(&GenID)
+		if last >= id || atomic.CompareAndSwapInt64(&GenID, last, id) {
+			return id
+		}
+	}
+}
+
 func New() *Storage {
 	return &Storage{
-		dataShow:  make(mapShow),
-		dataEvent: make(mapEvent),
-		dataPlace: make(mapPlace),
-		mu:        sync.RWMutex{},
+		dataShow:           make(mapShow),
+		dataEvent:          make(mapEvent),
+		dataPlace:          make(mapPlace),
+		placeIndex:         make(map[int64]*grid),
+		dataVenue:          make(mapVenue),
+		dataHall:           make(mapHall),
+		dataSection:        make(mapSection),
+		dataHold:           make(mapHold),
+		dataPriceCategory:  make(mapPriceCategory),
+		dataOrder:          make(mapOrder),
+		dataPromotion:      make(mapPromotion),
+		dataCustomer:       make(mapCustomer),
+		dataWaitlist:       make(mapWaitlistEntry),
+		dataIdempotencyKey: make(mapIdempotencyKey),
+		dataWebhook:        make(mapWebhook),
+		dataDelivery:       make(mapWebhookDelivery),
+		mu:                 sync.RWMutex{},
 	}
 }
 
@@ -59,9 +144,9 @@ func (s *Storage) GetShows(_ context.Context) ([]models.Show, error) {
 func (s *Storage) CreateShows(_ context.Context, shows []models.Show) ([]models.Show, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	now := time.Now()
 	for i := range shows {
-		shows[i].ID = getNewIDSafe()
-		s.dataShow[shows[i].ID] = &shows[i]
+		shows[i] = s.upsertShow(shows[i], now)
 	}
 	return shows, nil
 }
@@ -70,19 +155,46 @@ func (s *Storage) CreateShows(_ context.Context, shows []models.Show) ([]models.
 func (s *Storage) CreateShow(_ context.Context, show models.Show) (models.Show, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	show.ID = getNewIDSafe()
+	return s.upsertShow(show, time.Now()), nil
+}
+
+// upsertShow stores a show by ID unless one of the same ID or name is stored, then that one is touched.
+func (s *Storage) upsertShow(show models.Show, now time.Time) models.Show {
+	stored, ok := s.dataShow[show.ID]
+	if !ok {
+		for _, v := range s.dataShow {
+			if v.Name == show.Name {
+				stored, ok = v, true
+				break
+			}
+		}
+	}
+	if ok {
+		stored.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+		return *stored
+	}
+
+	show.ID = newID(show.ID)
+	show.CreatedAt = now
+	show.UpdatedAt = sql.NullTime{}
 	s.dataShow[show.ID] = &show
-	return show, nil
+	s.outbox(domain.ShowCreated, domain.Show(show), now)
+	return show
 }
 
-// GetEvents returns events.
-func (s *Storage) GetEvents(_ context.Context) ([]models.Event, error) {
+// GetEvents returns events matching the filter ordered by date.
+func (s *Storage) GetEvents(_ context.Context, filter models.EventFilter) ([]models.Event, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	sliceE := []models.Event{}
 	for _, v := range s.dataEvent {
-		sliceE = append(sliceE, *v)
+		if filter.Match(*v) {
+			sliceE = append(sliceE, *v)
+		}
 	}
+	sort.Slice(sliceE, func(i, j int) bool {
+		return sliceE[i].Date.Before(sliceE[j].Date)
+	})
 	return sliceE, nil
 }
 
@@ -90,9 +202,9 @@ func (s *Storage) GetEvents(_ context.Context) ([]models.Event, error) {
 func (s *Storage) CreateEvents(_ context.Context, events []models.Event) ([]models.Event, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	now := time.Now()
 	for i := range events {
-		events[i].ID = getNewIDSafe()
-		s.dataEvent[events[i].ID] = &events[i]
+		events[i] = s.upsertEvent(events[i], now)
 	}
 	return events, nil
 }
@@ -101,19 +213,50 @@ func (s *Storage) CreateEvents(_ context.Context, events []models.Event) ([]mode
 func (s *Storage) CreateEvent(_ context.Context, event models.Event) (models.Event, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	event.ID = getNewIDSafe()
-	s.dataEvent[event.ID] = &event
-	return event, nil
+	return s.upsertEvent(event, time.Now()), nil
 }
 
-// GetPlaces returns places.
-func (s *Storage) GetPlaces(_ context.Context) ([]models.Place, error) {
+// upsertEvent stores an event by ID, a stored event moved to another date or time zone gets the next sequence.
+// Events in a hall keep the venue time zone, the remote API doesn't know it.
+func (s *Storage) upsertEvent(event models.Event, now time.Time) models.Event {
+	event.Date = event.Date.UTC()
+	stored, ok := s.dataEvent[event.ID]
+	if !ok {
+		event.ID = newID(event.ID)
+		event.CreatedAt = now
+		event.UpdatedAt = sql.NullTime{}
+		s.dataEvent[event.ID] = &event
+		s.outbox(domain.EventCreated, domain.Event(event), now)
+		return event
+	}
+
+	moved := !stored.Date.Equal(event.Date)
+	if !stored.HallID.Valid {
+		moved = moved || stored.TimeZone != event.TimeZone
+		stored.TimeZone = event.TimeZone
+	}
+	stored.Date = event.Date
+	stored.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	if moved {
+		stored.Sequence++
+		s.outbox(domain.EventUpdated, domain.Event(*stored), now)
+	}
+	return *stored
+}
+
+// GetPlaces returns places matching the filter ordered by ID.
+func (s *Storage) GetPlaces(_ context.Context, filter models.PlaceFilter) ([]models.Place, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	sliceP := []models.Place{}
 	for _, v := range s.dataPlace {
-		sliceP = append(sliceP, *v)
+		if filter.Match(*v) {
+			sliceP = append(sliceP, *v)
+		}
 	}
+	sort.Slice(sliceP, func(i, j int) bool {
+		return sliceP[i].ID < sliceP[j].ID
+	})
 	return sliceP, nil
 }
 
@@ -121,9 +264,9 @@ func (s *Storage) GetPlaces(_ context.Context) ([]models.Place, error) {
 func (s *Storage) CreatePlaces(_ context.Context, places []models.Place) ([]models.Place, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	now := time.Now()
 	for i := range places {
-		places[i].ID = getNewIDSafe()
-		s.dataPlace[places[i].ID] = &places[i]
+		places[i] = s.upsertPlace(places[i], now)
 	}
 	return places, nil
 }
@@ -132,7 +275,1448 @@ func (s *Storage) CreatePlaces(_ context.Context, places []models.Place) ([]mode
 func (s *Storage) CreatePlace(_ context.Context, place models.Place) (models.Place, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	place.ID = getNewIDSafe()
-	s.dataPlace[place.ID] = &place
-	return place, nil
+	return s.upsertPlace(place, time.Now()), nil
+}
+
+// upsertPlace stores a place by ID, a stored place only moves to the event if one is given.
+// A place without an ID is a local one.
+func (s *Storage) upsertPlace(place models.Place, now time.Time) models.Place {
+	stored, ok := s.dataPlace[place.ID]
+	if !ok {
+		if place.ID == 0 {
+			place.ID = newLocalPlaceID()
+		}
+		place.CreatedAt = now
+		place.UpdatedAt = sql.NullTime{}
+		s.dataPlace[place.ID] = &place
+		s.indexPlace(place)
+		return place
+	}
+
+	if place.EventID.Valid {
+		s.unindexPlace(*stored)
+		stored.EventID = place.EventID
+		s.indexPlace(*stored)
+	}
+	stored.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	return *stored
+}
+
+// GetEvent returns an event by ID.
+func (s *Storage) GetEvent(_ context.Context, id int64) (models.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.dataEvent[id]
+	if !ok {
+		return models.Event{}, model.ErrNotFound
+	}
+	return *e, nil
+}
+
+// AttachEventToHall makes the event take place in the hall: the event gets the venue time zone
+// and a copy of the hall layout as its places, replacing places inherited from a previous hall
+// or synced from the remote API. ErrPlacesInUse is returned if any of those is held, sold or was ever ordered.
+func (s *Storage) AttachEventToHall(_ context.Context, eventID, hallID int64, check models.Check[models.Event],
+) (models.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.dataEvent[eventID]
+	if !ok {
+		return models.Event{}, model.ErrNotFound
+	}
+	if err := check.Run(*e); err != nil {
+		return models.Event{}, err
+	}
+	h, ok := s.dataHall[hallID]
+	if !ok {
+		return models.Event{}, model.ErrNotFound
+	}
+	ordered := make(map[int64]bool)
+	for _, o := range s.dataOrder {
+		for _, item := range o.Items {
+			ordered[item.PlaceID] = true
+		}
+	}
+	for id, p := range s.dataPlace {
+		if p.EventID.Int64 == eventID && (!p.IsAvailable || p.HoldID.Valid || ordered[id]) {
+			return models.Event{}, model.ErrPlacesInUse
+		}
+	}
+
+	for id, p := range s.dataPlace {
+		if p.EventID.Int64 == eventID {
+			s.unindexPlace(*p)
+			delete(s.dataPlace, id)
+		}
+	}
+	// Copies get IDs in the layout order, like the SQL storage.
+	filter := models.PlaceFilter{HallID: hallID}
+	var layout []models.Place
+	for _, p := range s.dataPlace {
+		if filter.Match(*p) {
+			layout = append(layout, *p)
+		}
+	}
+	sort.Slice(layout, func(i, j int) bool {
+		return layout[i].ID < layout[j].ID
+	})
+	for _, place := range layout {
+		place.ID = newLocalPlaceID()
+		place.EventID = sql.NullInt64{Int64: eventID, Valid: true}
+		place.IsAvailable = true
+		place.CreatedAt = time.Now()
+		place.UpdatedAt = sql.NullTime{}
+		s.dataPlace[place.ID] = &place
+		s.indexPlace(place)
+	}
+
+	if e.HallID.Int64 != hallID {
+		e.Sequence++
+	}
+	e.HallID = sql.NullInt64{Int64: hallID, Valid: true}
+	if v, ok := s.dataVenue[h.VenueID]; ok && v.TimeZone != "" {
+		e.TimeZone = v.TimeZone
+	}
+	e.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	return *e, nil
+}
+
+// CancelEvent marks an event as cancelled, cancelling it again changes nothing.
+func (s *Storage) CancelEvent(_ context.Context, id int64, now time.Time, check models.Check[models.Event],
+) (models.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.dataEvent[id]
+	if !ok {
+		return models.Event{}, model.ErrNotFound
+	}
+	if err := check.Run(*e); err != nil {
+		return models.Event{}, err
+	}
+	if !e.CancelledAt.Valid {
+		e.CancelledAt = sql.NullTime{Time: now, Valid: true}
+		e.UpdatedAt = e.CancelledAt
+		e.Sequence++
+		s.outbox(domain.EventCancelled, domain.Event(*e), now)
+	}
+	return *e, nil
+}
+
+// indexPlace adds an event place to the spatial index of its event.
+func (s *Storage) indexPlace(p models.Place) {
+	if !p.EventID.Valid {
+		return
+	}
+	g, ok := s.placeIndex[p.EventID.Int64]
+	if !ok {
+		g = newGrid()
+		s.placeIndex[p.EventID.Int64] = g
+	}
+	g.insert(p)
+}
+
+func (s *Storage) unindexPlace(p models.Place) {
+	if g, ok := s.placeIndex[p.EventID.Int64]; ok && p.EventID.Valid {
+		g.remove(p)
+	}
+}
+
+// GetPlacesInRect returns places of the event intersecting r ordered by ID.
+func (s *Storage) GetPlacesInRect(_ context.Context, eventID int64, r models.Rect) ([]models.Place, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceP := []models.Place{}
+	g, ok := s.placeIndex[eventID]
+	if !ok {
+		return sliceP, nil
+	}
+	for id := range g.search(r) {
+		if p := s.dataPlace[id]; p.Bounds().Intersects(r) {
+			sliceP = append(sliceP, *p)
+		}
+	}
+	sort.Slice(sliceP, func(i, j int) bool {
+		return sliceP[i].ID < sliceP[j].ID
+	})
+	return sliceP, nil
+}
+
+// GetNearestPlaces returns up to n available places of the event closest to pt,
+// nearest first by the distance between pt and the place centre.
+func (s *Storage) GetNearestPlaces(_ context.Context, eventID int64, pt models.Point, n int,
+) ([]models.Place, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceP := []models.Place{}
+	g, ok := s.placeIndex[eventID]
+	if !ok || n <= 0 {
+		return sliceP, nil
+	}
+	less := func(a, b models.Place) bool {
+		da, db := a.Center().Distance(pt), b.Center().Distance(pt)
+		if da != db {
+			return da < db
+		}
+		return a.ID < b.ID
+	}
+	g.nearest(pt, func(id int64) {
+		if p := s.dataPlace[id]; p.IsAvailable {
+			sliceP = append(sliceP, *p)
+		}
+	}, func(reach float64) bool {
+		if len(sliceP) < n {
+			return false
+		}
+		sort.Slice(sliceP, func(i, j int) bool { return less(sliceP[i], sliceP[j]) })
+		sliceP = sliceP[:n]
+		return sliceP[n-1].Center().Distance(pt) <= reach
+	})
+	sort.Slice(sliceP, func(i, j int) bool { return less(sliceP[i], sliceP[j]) })
+	if len(sliceP) > n {
+		sliceP = sliceP[:n]
+	}
+	return sliceP, nil
+}
+
+// GetPlaceAdjacency returns links between neighbouring seats of the event rows ordered by IDs.
+func (s *Storage) GetPlaceAdjacency(_ context.Context, eventID int64) ([]models.Adjacency, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	links := []models.Adjacency{}
+	g, ok := s.placeIndex[eventID]
+	if !ok {
+		return links, nil
+	}
+	for id := range g.all() {
+		p := s.dataPlace[id]
+		for id := range g.search(p.AdjacencyReach()) {
+			if q := s.dataPlace[id]; p.Precedes(*q) {
+				links = append(links, models.Adjacency{LeftID: p.ID, RightID: q.ID})
+			}
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].LeftID != links[j].LeftID {
+			return links[i].LeftID < links[j].LeftID
+		}
+		return links[i].RightID < links[j].RightID
+	})
+	return links, nil
+}
+
+// CreateVenue creates a venue.
+func (s *Storage) CreateVenue(_ context.Context, venue models.Venue) (models.Venue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	venue.ID = getNewIDSafe()
+	venue.CreatedAt = time.Now()
+	s.dataVenue[venue.ID] = &venue
+	return venue, nil
+}
+
+// GetVenues returns venues ordered by ID.
+func (s *Storage) GetVenues(_ context.Context) ([]models.Venue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceV := []models.Venue{}
+	for _, v := range s.dataVenue {
+		sliceV = append(sliceV, *v)
+	}
+	sort.Slice(sliceV, func(i, j int) bool {
+		return sliceV[i].ID < sliceV[j].ID
+	})
+	return sliceV, nil
+}
+
+// GetVenue returns a venue by ID.
+func (s *Storage) GetVenue(_ context.Context, id int64) (models.Venue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.dataVenue[id]
+	if !ok {
+		return models.Venue{}, model.ErrNotFound
+	}
+	return *v, nil
+}
+
+// CreateHall creates a hall in an existing venue.
+func (s *Storage) CreateHall(_ context.Context, hall models.Hall) (models.Hall, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.dataVenue[hall.VenueID]; !ok {
+		return models.Hall{}, model.ErrNotFound
+	}
+	hall.ID = getNewIDSafe()
+	hall.CreatedAt = time.Now()
+	s.dataHall[hall.ID] = &hall
+	return hall, nil
+}
+
+// GetHalls returns halls of a venue ordered by ID.
+func (s *Storage) GetHalls(_ context.Context, venueID int64) ([]models.Hall, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceH := []models.Hall{}
+	for _, v := range s.dataHall {
+		if v.VenueID == venueID {
+			sliceH = append(sliceH, *v)
+		}
+	}
+	sort.Slice(sliceH, func(i, j int) bool {
+		return sliceH[i].ID < sliceH[j].ID
+	})
+	return sliceH, nil
+}
+
+// GetHall returns a hall by ID.
+func (s *Storage) GetHall(_ context.Context, id int64) (models.Hall, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := s.dataHall[id]
+	if !ok {
+		return models.Hall{}, model.ErrNotFound
+	}
+	return *h, nil
+}
+
+// CreateSection creates a section in an existing hall.
+func (s *Storage) CreateSection(_ context.Context, section models.Section) (models.Section, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.dataHall[section.HallID]; !ok {
+		return models.Section{}, model.ErrNotFound
+	}
+	section.ID = getNewIDSafe()
+	section.CreatedAt = time.Now()
+	s.dataSection[section.ID] = &section
+	return section, nil
+}
+
+// GetSections returns sections of a hall ordered by ID.
+func (s *Storage) GetSections(_ context.Context, hallID int64) ([]models.Section, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceS := []models.Section{}
+	for _, v := range s.dataSection {
+		if v.HallID == hallID {
+			sliceS = append(sliceS, *v)
+		}
+	}
+	sort.Slice(sliceS, func(i, j int) bool {
+		return sliceS[i].ID < sliceS[j].ID
+	})
+	return sliceS, nil
+}
+
+// CreateHallPlaces adds places to the hall layout. Sections of the places must belong to the hall.
+func (s *Storage) CreateHallPlaces(_ context.Context, hallID int64, places []models.Place) ([]models.Place, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.dataHall[hallID]; !ok {
+		return nil, model.ErrNotFound
+	}
+	for _, p := range places {
+		if sec, ok := s.dataSection[p.SectionID.Int64]; p.SectionID.Valid && (!ok || sec.HallID != hallID) {
+			return nil, model.ErrNotFound
+		}
+	}
+	for i := range places {
+		places[i].ID = newLocalPlaceID()
+		places[i].EventID = sql.NullInt64{}
+		places[i].HallID = sql.NullInt64{Int64: hallID, Valid: true}
+		places[i].CreatedAt = time.Now()
+		place := places[i]
+		s.dataPlace[place.ID] = &place
+	}
+	return places, nil
+}
+
+// CreateHold holds available places of the event. Either all places are held or none:
+// if any of them is missing or not available, or the event is cancelled, ErrUnavailable is returned.
+func (s *Storage) CreateHold(_ context.Context, hold models.Hold) (models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.dataEvent[hold.EventID]
+	if !ok {
+		return models.Hold{}, model.ErrNotFound
+	}
+	if e.CancelledAt.Valid {
+		return models.Hold{}, model.ErrUnavailable
+	}
+	for _, id := range hold.PlaceIDs {
+		p, ok := s.dataPlace[id]
+		if !ok || p.EventID.Int64 != hold.EventID || !p.IsAvailable {
+			return models.Hold{}, model.ErrUnavailable
+		}
+	}
+	hold.ID = getNewIDSafe()
+	hold.PlaceIDs = append([]int64(nil), hold.PlaceIDs...)
+	for _, id := range hold.PlaceIDs {
+		p := s.dataPlace[id]
+		p.IsAvailable = false
+		p.HoldID = sql.NullInt64{Int64: hold.ID, Valid: true}
+		p.UpdatedAt = sql.NullTime{Time: hold.CreatedAt, Valid: true}
+	}
+	s.dataHold[hold.ID] = &hold
+	s.outbox(domain.PlaceHeld, domain.PlacesData{EventID: hold.EventID, PlaceIDs: hold.PlaceIDs, HoldID: hold.ID},
+		hold.CreatedAt)
+	return hold, nil
+}
+
+// GetHold returns a hold by ID.
+func (s *Storage) GetHold(_ context.Context, id int64) (models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := s.dataHold[id]
+	if !ok {
+		return models.Hold{}, model.ErrNotFound
+	}
+	return *h, nil
+}
+
+// ReleaseHold makes places of an active hold available again.
+func (s *Storage) ReleaseHold(_ context.Context, id int64, now time.Time) (models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := s.dataHold[id]
+	if !ok || h.ReleasedAt.Valid {
+		return models.Hold{}, model.ErrNotFound
+	}
+	s.releaseHold(h, now)
+	return *h, nil
+}
+
+// ReleaseExpiredHolds releases holds expired by now.
+func (s *Storage) ReleaseExpiredHolds(_ context.Context, now time.Time) ([]models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	released := []models.Hold{}
+	for _, h := range s.dataHold {
+		if !h.ReleasedAt.Valid && !h.ExpiresAt.After(now) {
+			s.releaseHold(h, now)
+			released = append(released, *h)
+		}
+	}
+	sort.Slice(released, func(i, j int) bool {
+		return released[i].ID < released[j].ID
+	})
+	return released, nil
+}
+
+func (s *Storage) releaseHold(h *models.Hold, now time.Time) {
+	var released []int64
+	for _, id := range h.PlaceIDs {
+		if p, ok := s.dataPlace[id]; ok && p.HoldID.Int64 == h.ID {
+			p.IsAvailable = true
+			p.HoldID = sql.NullInt64{}
+			p.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+			released = append(released, id)
+		}
+	}
+	h.ReleasedAt = sql.NullTime{Time: now, Valid: true}
+	if len(released) > 0 {
+		s.outbox(domain.PlaceReleased, domain.PlacesData{EventID: h.EventID, PlaceIDs: released, HoldID: h.ID}, now)
+	}
+}
+
+// CreatePriceCategory creates a price category of an existing event.
+func (s *Storage) CreatePriceCategory(_ context.Context, category models.PriceCategory) (models.PriceCategory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.dataEvent[category.EventID]; !ok {
+		return models.PriceCategory{}, model.ErrNotFound
+	}
+	category.ID = getNewIDSafe()
+	category.CreatedAt = time.Now()
+	s.dataPriceCategory[category.ID] = &category
+	return category, nil
+}
+
+// GetPriceCategories returns price categories of an event ordered by ID.
+func (s *Storage) GetPriceCategories(_ context.Context, eventID int64) ([]models.PriceCategory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceC := []models.PriceCategory{}
+	for _, v := range s.dataPriceCategory {
+		if v.EventID == eventID {
+			sliceC = append(sliceC, *v)
+		}
+	}
+	sort.Slice(sliceC, func(i, j int) bool {
+		return sliceC[i].ID < sliceC[j].ID
+	})
+	return sliceC, nil
+}
+
+// UpdatePriceCategory changes name and price of a price category.
+func (s *Storage) UpdatePriceCategory(_ context.Context, category models.PriceCategory,
+	check models.Check[models.PriceCategory],
+) (models.PriceCategory, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.dataPriceCategory[category.ID]
+	if !ok {
+		return models.PriceCategory{}, model.ErrNotFound
+	}
+	if err := check.Run(*c); err != nil {
+		return models.PriceCategory{}, err
+	}
+	c.Name = category.Name
+	c.Amount = category.Amount
+	c.Currency = category.Currency
+	c.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	return *c, nil
+}
+
+// DeletePriceCategory deletes a price category, its places become unpriced.
+func (s *Storage) DeletePriceCategory(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.dataPriceCategory[id]; !ok {
+		return model.ErrNotFound
+	}
+	for _, p := range s.dataPlace {
+		if p.PriceCategoryID.Int64 == id {
+			p.PriceCategoryID = sql.NullInt64{}
+		}
+	}
+	delete(s.dataPriceCategory, id)
+	return nil
+}
+
+// AssignPrices applies the price map assignments to the event places in order
+// and returns the number of places changed. Categories must belong to the event.
+func (s *Storage) AssignPrices(_ context.Context, eventID int64, assignments []models.PriceAssignment,
+	check models.Check[models.Event],
+) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if check != nil {
+		e, ok := s.dataEvent[eventID]
+		if !ok {
+			return 0, model.ErrNotFound
+		}
+		if err := check(*e); err != nil {
+			return 0, err
+		}
+	}
+	for _, a := range assignments {
+		if c, ok := s.dataPriceCategory[a.CategoryID]; a.CategoryID != 0 && (!ok || c.EventID != eventID) {
+			return 0, model.ErrNotFound
+		}
+	}
+	var n int64
+	for _, a := range assignments {
+		category := sql.NullInt64{Int64: a.CategoryID, Valid: a.CategoryID != 0}
+		for _, p := range s.dataPlace {
+			if p.EventID.Int64 == eventID && p.EventID.Valid && a.Match(*p) {
+				p.PriceCategoryID = category
+				n++
+			}
+		}
+	}
+	return n, nil
+}
+
+// CreateOrder sells the places of an active hold at the prices of the order items
+// and redeems the promotions of the order discounts.
+// The hold ends and its places stay unavailable. ErrUnavailable is returned if the hold is not active,
+// ErrLimitReached if a promotion has been redeemed as many times as allowed.
+func (s *Storage) CreateOrder(_ context.Context, order models.Order) (models.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := s.dataHold[order.HoldID.Int64]
+	if !ok || !h.IsActive(order.CreatedAt) {
+		return models.Order{}, model.ErrUnavailable
+	}
+	for _, d := range order.Discounts {
+		p, ok := s.dataPromotion[d.PromotionID]
+		if !ok {
+			return models.Order{}, model.ErrNotFound
+		}
+		if p.MaxUses > 0 && p.Uses >= p.MaxUses {
+			return models.Order{}, model.ErrLimitReached
+		}
+		if p.MaxUsesPerCustomer > 0 && s.countRedemptions(p.ID, order.CustomerID) >= p.MaxUsesPerCustomer {
+			return models.Order{}, model.ErrLimitReached
+		}
+	}
+	for _, id := range h.PlaceIDs {
+		if p, ok := s.dataPlace[id]; ok && p.HoldID.Int64 == h.ID {
+			p.HoldID = sql.NullInt64{}
+			p.UpdatedAt = sql.NullTime{Time: order.CreatedAt, Valid: true}
+		}
+	}
+	h.ReleasedAt = sql.NullTime{Time: order.CreatedAt, Valid: true}
+
+	order.ID = getNewIDSafe()
+	order.Items = append([]models.OrderItem(nil), order.Items...)
+	for i := range order.Items {
+		order.Items[i].ID = getNewIDSafe()
+		order.Items[i].OrderID = order.ID
+		order.Items[i].EventID = order.EventID
+		order.Items[i].Status = models.ItemIssued
+	}
+	order.Discounts = append([]models.OrderDiscount(nil), order.Discounts...)
+	for i, d := range order.Discounts {
+		order.Discounts[i].ID = getNewIDSafe()
+		order.Discounts[i].OrderID = order.ID
+		s.dataPromotion[d.PromotionID].Uses++
+		s.dataRedemption = append(s.dataRedemption, models.Redemption{
+			PromotionID: d.PromotionID,
+			OrderID:     order.ID,
+			CustomerID:  order.CustomerID,
+			CreatedAt:   order.CreatedAt,
+		})
+	}
+	s.dataOrder[order.ID] = &order
+	s.audit("order", order.ID, models.AuditOrderCreated, map[string]any{
+		"holdId": order.HoldID.Int64, "currency": order.Currency, "total": order.Total, "items": len(order.Items),
+	}, order.CreatedAt)
+	for _, places := range domain.ItemPlaces(order.ID, order.Items) {
+		s.outbox(domain.PlaceSold, places, order.CreatedAt)
+	}
+	s.outbox(domain.OrderPaid, domain.Order(order), order.CreatedAt)
+	return copyOrder(&order), nil
+}
+
+// copyOrder returns a copy of a stored order that doesn't share its items.
+func copyOrder(o *models.Order) models.Order {
+	order := *o
+	order.Items = append([]models.OrderItem(nil), o.Items...)
+	order.Discounts = append([]models.OrderDiscount(nil), o.Discounts...)
+	return order
+}
+
+func (s *Storage) countRedemptions(promotionID int64, customerID sql.NullInt64) int {
+	n := 0
+	for _, r := range s.dataRedemption {
+		if r.PromotionID == promotionID && customerID.Valid && r.CustomerID == customerID {
+			n++
+		}
+	}
+	return n
+}
+
+// GetOrder returns an order with its items and discounts.
+func (s *Storage) GetOrder(_ context.Context, id int64) (models.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.dataOrder[id]
+	if !ok {
+		return models.Order{}, model.ErrNotFound
+	}
+	return copyOrder(o), nil
+}
+
+// CreatePromotion creates a promotion, ErrAlreadyExists is returned if its code is taken.
+func (s *Storage) CreatePromotion(_ context.Context, promotion models.Promotion) (models.Promotion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if promotion.Code != "" {
+		for _, p := range s.dataPromotion {
+			if p.Code == promotion.Code {
+				return models.Promotion{}, model.ErrAlreadyExists
+			}
+		}
+	}
+	promotion.ID = getNewIDSafe()
+	promotion.Uses = 0
+	promotion.Active = true
+	promotion.CreatedAt = time.Now()
+	s.dataPromotion[promotion.ID] = &promotion
+	return promotion, nil
+}
+
+// GetPromotions returns all promotions ordered by ID.
+func (s *Storage) GetPromotions(_ context.Context) ([]models.Promotion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceP := []models.Promotion{}
+	for _, v := range s.dataPromotion {
+		sliceP = append(sliceP, *v)
+	}
+	sort.Slice(sliceP, func(i, j int) bool {
+		return sliceP[i].ID < sliceP[j].ID
+	})
+	return sliceP, nil
+}
+
+func (s *Storage) GetPromotion(_ context.Context, id int64) (models.Promotion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.dataPromotion[id]
+	if !ok {
+		return models.Promotion{}, model.ErrNotFound
+	}
+	return *p, nil
+}
+
+// DeactivatePromotion stops a promotion, its redemptions are kept.
+func (s *Storage) DeactivatePromotion(_ context.Context, id int64, now time.Time) (models.Promotion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.dataPromotion[id]
+	if !ok {
+		return models.Promotion{}, model.ErrNotFound
+	}
+	p.Active = false
+	p.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	return *p, nil
+}
+
+// GetCheckoutPromotions returns active promotions without a code and promotions with one of the codes
+// ordered by ID.
+func (s *Storage) GetCheckoutPromotions(_ context.Context, codes []string) ([]models.Promotion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entered := make(map[string]bool, len(codes))
+	for _, c := range codes {
+		entered[c] = true
+	}
+	sliceP := []models.Promotion{}
+	for _, v := range s.dataPromotion {
+		if (v.Code == "" && v.Active) || (v.Code != "" && entered[v.Code]) {
+			sliceP = append(sliceP, *v)
+		}
+	}
+	sort.Slice(sliceP, func(i, j int) bool {
+		return sliceP[i].ID < sliceP[j].ID
+	})
+	return sliceP, nil
+}
+
+// GetCustomerRedemptions counts redemptions of the customer by promotion ID.
+func (s *Storage) GetCustomerRedemptions(_ context.Context, customerID int64) (map[int64]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make(map[int64]int)
+	for _, r := range s.dataRedemption {
+		if r.CustomerID.Valid && r.CustomerID.Int64 == customerID {
+			res[r.PromotionID]++
+		}
+	}
+	return res, nil
+}
+
+// emailTaken reports whether another customer has the email, emails are compared case-insensitively.
+func (s *Storage) emailTaken(email string, id int64) bool {
+	for _, c := range s.dataCustomer {
+		if c.ID != id && c.Email != "" && strings.EqualFold(c.Email, email) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateCustomer creates a customer, ErrAlreadyExists is returned if the email is taken.
+func (s *Storage) CreateCustomer(_ context.Context, customer models.Customer) (models.Customer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.emailTaken(customer.Email, 0) {
+		return models.Customer{}, model.ErrAlreadyExists
+	}
+	customer.ID = getNewIDSafe()
+	s.dataCustomer[customer.ID] = &customer
+	return customer, nil
+}
+
+func (s *Storage) GetCustomer(_ context.Context, id int64) (models.Customer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.dataCustomer[id]
+	if !ok {
+		return models.Customer{}, model.ErrNotFound
+	}
+	return *c, nil
+}
+
+// UpdateCustomer changes contacts and consents of a customer that is not erased.
+func (s *Storage) UpdateCustomer(_ context.Context, customer models.Customer, check models.Check[models.Customer],
+) (models.Customer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.dataCustomer[customer.ID]
+	if !ok || c.ErasedAt.Valid {
+		return models.Customer{}, model.ErrNotFound
+	}
+	if err := check.Run(*c); err != nil {
+		return models.Customer{}, err
+	}
+	if s.emailTaken(customer.Email, customer.ID) {
+		return models.Customer{}, model.ErrAlreadyExists
+	}
+	c.Email = customer.Email
+	c.Name = customer.Name
+	c.Phone = customer.Phone
+	c.MarketingEmail = customer.MarketingEmail
+	c.MarketingSMS = customer.MarketingSMS
+	c.UpdatedAt = customer.UpdatedAt
+	return *c, nil
+}
+
+// EraseCustomer drops the personal data of a customer, its holds and orders are kept.
+func (s *Storage) EraseCustomer(_ context.Context, id int64, now time.Time) (models.Customer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.dataCustomer[id]
+	if !ok {
+		return models.Customer{}, model.ErrNotFound
+	}
+	erasedAt := c.ErasedAt
+	c.Erase(now)
+	if erasedAt.Valid {
+		c.ErasedAt = erasedAt
+	}
+	return *c, nil
+}
+
+// GetCustomerOrders returns orders of a customer with their items and discounts ordered by ID.
+func (s *Storage) GetCustomerOrders(_ context.Context, customerID int64) ([]models.Order, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceO := []models.Order{}
+	for _, v := range s.dataOrder {
+		if v.CustomerID.Valid && v.CustomerID.Int64 == customerID {
+			sliceO = append(sliceO, copyOrder(v))
+		}
+	}
+	sort.Slice(sliceO, func(i, j int) bool {
+		return sliceO[i].ID < sliceO[j].ID
+	})
+	return sliceO, nil
+}
+
+// GetCustomerHolds returns holds of a customer with their places ordered by ID.
+func (s *Storage) GetCustomerHolds(_ context.Context, customerID int64) ([]models.Hold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceH := []models.Hold{}
+	for _, v := range s.dataHold {
+		if v.CustomerID.Valid && v.CustomerID.Int64 == customerID {
+			sliceH = append(sliceH, *v)
+		}
+	}
+	sort.Slice(sliceH, func(i, j int) bool {
+		return sliceH[i].ID < sliceH[j].ID
+	})
+	return sliceH, nil
+}
+
+// CreateWaitlistEntry puts a customer on the waitlist of an existing event.
+// ErrAlreadyExists is returned if the customer is already waiting for the event.
+func (s *Storage) CreateWaitlistEntry(_ context.Context, entry models.WaitlistEntry) (models.WaitlistEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.dataEvent[entry.EventID]; !ok {
+		return models.WaitlistEntry{}, model.ErrNotFound
+	}
+	for _, e := range s.dataWaitlist {
+		if e.EventID == entry.EventID && e.CustomerID == entry.CustomerID && e.Status == models.WaitlistWaiting {
+			return models.WaitlistEntry{}, model.ErrAlreadyExists
+		}
+	}
+	entry.ID = getNewIDSafe()
+	entry.Status = models.WaitlistWaiting
+	s.dataWaitlist[entry.ID] = &entry
+	return entry, nil
+}
+
+func (s *Storage) GetWaitlistEntry(_ context.Context, id int64) (models.WaitlistEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.dataWaitlist[id]
+	if !ok {
+		return models.WaitlistEntry{}, model.ErrNotFound
+	}
+	return *e, nil
+}
+
+// GetWaitlist returns waitlist entries of an event in the order they joined.
+func (s *Storage) GetWaitlist(_ context.Context, eventID int64) ([]models.WaitlistEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.waitlist(func(e *models.WaitlistEntry) bool { return e.EventID == eventID }), nil
+}
+
+// GetWaitingEntries returns entries of all events still waiting for places in the order they joined.
+func (s *Storage) GetWaitingEntries(_ context.Context) ([]models.WaitlistEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.waitlist(func(e *models.WaitlistEntry) bool { return e.Status == models.WaitlistWaiting }), nil
+}
+
+func (s *Storage) waitlist(match func(e *models.WaitlistEntry) bool) []models.WaitlistEntry {
+	sliceE := []models.WaitlistEntry{}
+	for _, v := range s.dataWaitlist {
+		if match(v) {
+			sliceE = append(sliceE, *v)
+		}
+	}
+	sort.Slice(sliceE, func(i, j int) bool {
+		if !sliceE[i].CreatedAt.Equal(sliceE[j].CreatedAt) {
+			return sliceE[i].CreatedAt.Before(sliceE[j].CreatedAt)
+		}
+		return sliceE[i].ID < sliceE[j].ID
+	})
+	return sliceE
+}
+
+// OfferWaitlistEntry records the hold offered to a waiting entry, ErrNotFound is returned unless it waits.
+func (s *Storage) OfferWaitlistEntry(_ context.Context, id, holdID int64, now time.Time,
+) (models.WaitlistEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.dataWaitlist[id]
+	if !ok || e.Status != models.WaitlistWaiting {
+		return models.WaitlistEntry{}, model.ErrNotFound
+	}
+	e.Status = models.WaitlistOffered
+	e.HoldID = sql.NullInt64{Int64: holdID, Valid: true}
+	e.OfferedAt = sql.NullTime{Time: now, Valid: true}
+	e.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	data := domain.WaitlistData{EntryID: e.ID, EventID: e.EventID, CustomerID: e.CustomerID, HoldID: holdID}
+	if h, ok := s.dataHold[holdID]; ok {
+		data.PlaceIDs = h.PlaceIDs
+		data.ExpiresAt = h.ExpiresAt.UTC()
+	}
+	s.outbox(domain.WaitlistOffered, data, now)
+	return *e, nil
+}
+
+// CancelWaitlistEntry takes a waiting or offered entry off the waitlist.
+func (s *Storage) CancelWaitlistEntry(_ context.Context, id int64, now time.Time) (models.WaitlistEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.dataWaitlist[id]
+	if !ok || e.Status == models.WaitlistCancelled {
+		return models.WaitlistEntry{}, model.ErrNotFound
+	}
+	e.Status = models.WaitlistCancelled
+	e.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	return *e, nil
+}
+
+// audit appends a record to the audit trail of an entity.
+func (s *Storage) audit(entity string, id int64, action string, details any, now time.Time) {
+	data, err := json.Marshal(details)
+	if err != nil {
+		data = []byte("{}")
+	}
+	s.dataAudit = append(s.dataAudit, models.AuditRecord{
+		ID:        getNewIDSafe(),
+		Entity:    entity,
+		EntityID:  id,
+		Action:    action,
+		Details:   string(data),
+		CreatedAt: now,
+	})
+}
+
+// outbox writes a domain event, it is written under the same lock as the change it describes.
+func (s *Storage) outbox(typ string, data any, now time.Time) {
+	e, err := domain.New(typ, data, now)
+	if err != nil {
+		return
+	}
+	e.ID = getNewIDSafe()
+	s.dataOutbox = append(s.dataOutbox, e)
+}
+
+// GetAuditTrail returns the audit trail of an entity oldest first.
+func (s *Storage) GetAuditTrail(_ context.Context, entity string, id int64) ([]models.AuditRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceA := []models.AuditRecord{}
+	for _, a := range s.dataAudit {
+		if a.Entity == entity && a.EntityID == id {
+			sliceA = append(sliceA, a)
+		}
+	}
+	return sliceA, nil
+}
+
+// issuedItems returns indexes of the order items with the IDs if all of them are issued and unused.
+func issuedItems(o *models.Order, itemIDs []int64) ([]int, bool) {
+	var res []int
+	for _, id := range itemIDs {
+		found := false
+		for i, item := range o.Items {
+			if item.ID == id && item.Status == models.ItemIssued && !item.UsedAt.Valid {
+				res = append(res, i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return res, true
+}
+
+// orderItem returns the order of an item and the index of the item in it.
+func (s *Storage) orderItem(id int64) (*models.Order, int, bool) {
+	for _, o := range s.dataOrder {
+		for i, item := range o.Items {
+			if item.ID == id {
+				return o, i, true
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+// GetOrderItem returns an order item.
+func (s *Storage) GetOrderItem(_ context.Context, id int64) (models.OrderItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, i, ok := s.orderItem(id)
+	if !ok {
+		return models.OrderItem{}, model.ErrNotFound
+	}
+	return o.Items[i], nil
+}
+
+// UseOrderItem marks an issued order item as used at the door. The item is returned with ErrUnavailable
+// if it is not issued and with ErrAlreadyUsed if it has been used.
+func (s *Storage) UseOrderItem(_ context.Context, id int64, now time.Time) (models.OrderItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, i, ok := s.orderItem(id)
+	if !ok {
+		return models.OrderItem{}, model.ErrNotFound
+	}
+	item := &o.Items[i]
+	switch {
+	case item.Status != models.ItemIssued:
+		return *item, model.ErrUnavailable
+	case item.UsedAt.Valid:
+		return *item, model.ErrAlreadyUsed
+	}
+	item.UsedAt = sql.NullTime{Time: now, Valid: true}
+	s.audit("order", o.ID, models.AuditTicketScanned, map[string]any{
+		"itemId": item.ID, "eventId": item.EventID, "placeId": item.PlaceID,
+	}, now)
+	return *item, nil
+}
+
+// returnPlace puts a sold place back on sale.
+func (s *Storage) returnPlace(id int64, now time.Time) {
+	if p, ok := s.dataPlace[id]; ok {
+		p.IsAvailable = true
+		p.HoldID = sql.NullInt64{}
+		p.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	}
+}
+
+// RefundOrder refunds issued items of an order and puts their places back on sale.
+// ErrUnavailable is returned if any of the items is not issued.
+func (s *Storage) RefundOrder(_ context.Context, refund models.Refund) (models.Refund, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.dataOrder[refund.OrderID]
+	if !ok {
+		return models.Refund{}, model.ErrNotFound
+	}
+	items, ok := issuedItems(o, refund.ItemIDs)
+	if !ok {
+		return models.Refund{}, model.ErrUnavailable
+	}
+	refund.ID = getNewIDSafe()
+	refund.ItemIDs = append([]int64(nil), refund.ItemIDs...)
+	refunded := make([]models.OrderItem, 0, len(items))
+	for _, i := range items {
+		o.Items[i].Status = models.ItemRefunded
+		o.Items[i].RefundID = sql.NullInt64{Int64: refund.ID, Valid: true}
+		s.returnPlace(o.Items[i].PlaceID, refund.CreatedAt)
+		refunded = append(refunded, o.Items[i])
+	}
+	o.Refunded += refund.Amount
+	o.Status = models.OrderStatusOf(o.Items)
+	o.UpdatedAt = sql.NullTime{Time: refund.CreatedAt, Valid: true}
+	s.dataRefund = append(s.dataRefund, refund)
+	s.audit("order", o.ID, models.AuditOrderRefunded, map[string]any{
+		"refundId": refund.ID, "itemIds": refund.ItemIDs, "amount": refund.Amount, "fee": refund.Fee,
+		"reason": refund.Reason,
+	}, refund.CreatedAt)
+	for _, places := range domain.ItemPlaces(o.ID, refunded) {
+		s.outbox(domain.PlaceReleased, places, refund.CreatedAt)
+	}
+	s.outbox(domain.OrderRefunded, domain.Order(*o), refund.CreatedAt)
+	return refund, nil
+}
+
+// ExchangeOrderItems replaces issued items of an order with the exchange items, the places of the old items
+// go back on sale. ErrUnavailable is returned if any old item is not issued or any new place is not available.
+func (s *Storage) ExchangeOrderItems(_ context.Context, exchange models.Exchange) (models.Exchange, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.dataOrder[exchange.OrderID]
+	if !ok {
+		return models.Exchange{}, model.ErrNotFound
+	}
+	items, ok := issuedItems(o, exchange.ItemIDs)
+	if !ok {
+		return models.Exchange{}, model.ErrUnavailable
+	}
+	placeIDs := make([]int64, 0, len(exchange.Items))
+	for _, item := range exchange.Items {
+		p, ok := s.dataPlace[item.PlaceID]
+		if !ok || p.EventID.Int64 != exchange.EventID || !p.IsAvailable {
+			return models.Exchange{}, model.ErrUnavailable
+		}
+		placeIDs = append(placeIDs, item.PlaceID)
+	}
+
+	exchange.ID = getNewIDSafe()
+	exchange.ItemIDs = append([]int64(nil), exchange.ItemIDs...)
+	exchanged := make([]models.OrderItem, 0, len(items))
+	for _, i := range items {
+		o.Items[i].Status = models.ItemExchanged
+		s.returnPlace(o.Items[i].PlaceID, exchange.CreatedAt)
+		exchanged = append(exchanged, o.Items[i])
+	}
+	exchange.Items = append([]models.OrderItem(nil), exchange.Items...)
+	for i := range exchange.Items {
+		item := &exchange.Items[i]
+		item.ID = getNewIDSafe()
+		item.OrderID = o.ID
+		item.EventID = exchange.EventID
+		item.Status = models.ItemIssued
+		p := s.dataPlace[item.PlaceID]
+		p.IsAvailable = false
+		p.UpdatedAt = sql.NullTime{Time: exchange.CreatedAt, Valid: true}
+		o.Items = append(o.Items, *item)
+	}
+	o.Total += exchange.Difference + exchange.Fee
+	o.UpdatedAt = sql.NullTime{Time: exchange.CreatedAt, Valid: true}
+	s.dataExchange = append(s.dataExchange, exchange)
+	s.audit("order", o.ID, models.AuditOrderExchanged, map[string]any{
+		"exchangeId": exchange.ID, "eventId": exchange.EventID, "itemIds": exchange.ItemIDs, "placeIds": placeIDs,
+		"difference": exchange.Difference, "fee": exchange.Fee,
+	}, exchange.CreatedAt)
+	for _, places := range domain.ItemPlaces(o.ID, exchanged) {
+		s.outbox(domain.PlaceReleased, places, exchange.CreatedAt)
+	}
+	s.outbox(domain.PlaceSold, domain.PlacesData{EventID: exchange.EventID, PlaceIDs: placeIDs, OrderID: o.ID},
+		exchange.CreatedAt)
+	s.outbox(domain.OrderExchanged, domain.Order(*o), exchange.CreatedAt)
+	return exchange, nil
+}
+
+// GetIdempotencyKey returns a non-expired idempotency key.
+func (s *Storage) GetIdempotencyKey(_ context.Context, key string) (models.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.dataIdempotencyKey[key]
+	if !ok || !k.ExpiresAt.After(time.Now()) {
+		return models.IdempotencyKey{}, model.ErrNotFound
+	}
+	return *k, nil
+}
+
+// CreateIdempotencyKey reserves an idempotency key, replacing an expired one.
+func (s *Storage) CreateIdempotencyKey(_ context.Context, key models.IdempotencyKey) (models.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if k, ok := s.dataIdempotencyKey[key.Key]; ok && k.ExpiresAt.After(key.CreatedAt) {
+		return models.IdempotencyKey{}, model.ErrAlreadyExists
+	}
+	s.dataIdempotencyKey[key.Key] = &key
+	return key, nil
+}
+
+// CompleteIdempotencyKey stores the response of a reserved idempotency key.
+func (s *Storage) CompleteIdempotencyKey(_ context.Context, key models.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k, ok := s.dataIdempotencyKey[key.Key]
+	if !ok {
+		return model.ErrNotFound
+	}
+	k.StatusCode = key.StatusCode
+	k.ContentType = key.ContentType
+	k.ResponseHeaders = key.ResponseHeaders
+	k.ResponseBody = key.ResponseBody
+	k.CompletedAt.Time = time.Now()
+	k.CompletedAt.Valid = true
+	return nil
+}
+
+// DeleteIdempotencyKey deletes an idempotency key.
+func (s *Storage) DeleteIdempotencyKey(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.dataIdempotencyKey, key)
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys deletes idempotency keys expired by now.
+func (s *Storage) DeleteExpiredIdempotencyKeys(_ context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for key, k := range s.dataIdempotencyKey {
+		if !k.ExpiresAt.After(now) {
+			delete(s.dataIdempotencyKey, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// CreateQuarantinedItems saves items that failed validation.
+func (s *Storage) CreateQuarantinedItems(_ context.Context, items []models.QuarantinedItem) ([]models.QuarantinedItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range items {
+		items[i].ID = getNewIDSafe()
+		items[i].CreatedAt = time.Now()
+		s.dataQuarantine = append(s.dataQuarantine, items[i])
+	}
+	return items, nil
+}
+
+// GetQuarantinedItems returns quarantined items.
+func (s *Storage) GetQuarantinedItems(_ context.Context) ([]models.QuarantinedItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceQ := make([]models.QuarantinedItem, len(s.dataQuarantine))
+	copy(sliceQ, s.dataQuarantine)
+	return sliceQ, nil
+}
+
+// CreateWebhook registers a webhook with the event types it receives.
+func (s *Storage) CreateWebhook(_ context.Context, webhook models.Webhook) (models.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	webhook.ID = getNewIDSafe()
+	webhook.EventTypes = slices.Clone(webhook.EventTypes)
+	s.dataWebhook[webhook.ID] = &webhook
+	return webhook, nil
+}
+
+// GetWebhooks returns webhooks ordered by ID.
+func (s *Storage) GetWebhooks(_ context.Context) ([]models.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceW := []models.Webhook{}
+	for _, v := range s.dataWebhook {
+		sliceW = append(sliceW, *v)
+	}
+	sort.Slice(sliceW, func(i, j int) bool {
+		return sliceW[i].ID < sliceW[j].ID
+	})
+	return sliceW, nil
+}
+
+func (s *Storage) GetWebhook(_ context.Context, id int64) (models.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w, ok := s.dataWebhook[id]
+	if !ok {
+		return models.Webhook{}, model.ErrNotFound
+	}
+	return *w, nil
+}
+
+// DeleteWebhook removes a webhook with its deliveries.
+func (s *Storage) DeleteWebhook(_ context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.dataWebhook[id]; !ok {
+		return model.ErrNotFound
+	}
+	delete(s.dataWebhook, id)
+	for deliveryID, d := range s.dataDelivery {
+		if d.WebhookID == id {
+			delete(s.dataDelivery, deliveryID)
+		}
+	}
+	s.dataAttempt = slices.DeleteFunc(s.dataAttempt, func(a models.WebhookAttempt) bool {
+		_, ok := s.dataDelivery[a.DeliveryID]
+		return !ok
+	})
+	return nil
+}
+
+// CreateWebhookDeliveries queues the event of the delivery for every webhook receiving its type.
+func (s *Storage) CreateWebhookDeliveries(_ context.Context, delivery models.WebhookDelivery,
+) ([]models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	queued := make(map[int64]bool)
+	for _, d := range s.dataDelivery {
+		if d.EventID == delivery.EventID {
+			queued[d.WebhookID] = true
+		}
+	}
+	deliveries := []models.WebhookDelivery{}
+	for _, w := range s.dataWebhook {
+		if !slices.Contains(w.EventTypes, delivery.EventType) || queued[w.ID] {
+			continue
+		}
+		d := delivery
+		d.ID = getNewIDSafe()
+		d.WebhookID = w.ID
+		d.Status = models.DeliveryPending
+		s.dataDelivery[d.ID] = &d
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+// ClaimWebhookDeliveries returns up to limit pending deliveries due by now, oldest first, and postpones them
+// until the given time.
+func (s *Storage) ClaimWebhookDeliveries(_ context.Context, now, until time.Time, limit int,
+) ([]models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	due := []*models.WebhookDelivery{}
+	for _, d := range s.dataDelivery {
+		if d.Status == models.DeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	sliceD := make([]models.WebhookDelivery, 0, len(due))
+	for _, d := range due {
+		d.NextAttemptAt = until
+		sliceD = append(sliceD, *d)
+	}
+	sort.Slice(sliceD, func(i, j int) bool {
+		return sliceD[i].ID < sliceD[j].ID
+	})
+	return sliceD, nil
+}
+
+// RecordWebhookAttempt saves the state of a delivery after an attempt and logs the attempt.
+func (s *Storage) RecordWebhookAttempt(_ context.Context, delivery models.WebhookDelivery,
+	attempt models.WebhookAttempt,
+) (models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.dataDelivery[delivery.ID]
+	if !ok {
+		return models.WebhookDelivery{}, model.ErrNotFound
+	}
+	d.Status = delivery.Status
+	d.Attempts = delivery.Attempts
+	d.NextAttemptAt = delivery.NextAttemptAt
+	d.LastError = delivery.LastError
+	d.DeliveredAt = delivery.DeliveredAt
+	d.UpdatedAt = sql.NullTime{Time: attempt.CreatedAt, Valid: true}
+	attempt.ID = getNewIDSafe()
+	attempt.DeliveryID = d.ID
+	s.dataAttempt = append(s.dataAttempt, attempt)
+	return *d, nil
+}
+
+// GetWebhookDeliveries returns deliveries matching the filter newest first.
+func (s *Storage) GetWebhookDeliveries(_ context.Context, filter models.WebhookDeliveryFilter,
+) ([]models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceD := []models.WebhookDelivery{}
+	for _, v := range s.dataDelivery {
+		if filter.Match(*v) {
+			sliceD = append(sliceD, *v)
+		}
+	}
+	sort.Slice(sliceD, func(i, j int) bool {
+		return sliceD[i].ID > sliceD[j].ID
+	})
+	return sliceD, nil
+}
+
+func (s *Storage) GetWebhookDelivery(_ context.Context, id int64) (models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.dataDelivery[id]
+	if !ok {
+		return models.WebhookDelivery{}, model.ErrNotFound
+	}
+	return *d, nil
+}
+
+// GetWebhookAttempts returns the attempts of a delivery oldest first.
+func (s *Storage) GetWebhookAttempts(_ context.Context, deliveryID int64) ([]models.WebhookAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceA := []models.WebhookAttempt{}
+	for _, a := range s.dataAttempt {
+		if a.DeliveryID == deliveryID {
+			sliceA = append(sliceA, a)
+		}
+	}
+	return sliceA, nil
+}
+
+// RedeliverWebhookDelivery queues a delivery again with a fresh set of attempts, whatever its status.
+func (s *Storage) RedeliverWebhookDelivery(_ context.Context, id int64, now time.Time,
+) (models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.dataDelivery[id]
+	if !ok {
+		return models.WebhookDelivery{}, model.ErrNotFound
+	}
+	d.Status = models.DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	d.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	return *d, nil
+}
+
+// ClaimDomainEvents returns up to limit undispatched events oldest first that aren't claimed at now
+// and claims them until the given time.
+func (s *Storage) ClaimDomainEvents(_ context.Context, now, until time.Time, limit int,
+) ([]models.DomainEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sliceE := []models.DomainEvent{}
+	for i := range s.dataOutbox {
+		if len(sliceE) == limit {
+			break
+		}
+		e := &s.dataOutbox[i]
+		if e.DispatchedAt.Valid || (e.ClaimedUntil.Valid && e.ClaimedUntil.Time.After(now)) {
+			continue
+		}
+		e.ClaimedUntil = sql.NullTime{Time: until, Valid: true}
+		sliceE = append(sliceE, *e)
+	}
+	return sliceE, nil
+}
+
+// MarkDomainEventsDispatched records that events have been handed to all subscribers.
+func (s *Storage) MarkDomainEventsDispatched(_ context.Context, ids []int64, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.dataOutbox {
+		if slices.Contains(ids, s.dataOutbox[i].ID) {
+			s.dataOutbox[i].DispatchedAt = sql.NullTime{Time: now, Valid: true}
+		}
+	}
+	return nil
+}
+
+// DeleteDispatchedDomainEvents removes events dispatched before the given time.
+func (s *Storage) DeleteDispatchedDomainEvents(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.dataOutbox)
+	s.dataOutbox = slices.DeleteFunc(s.dataOutbox, func(e models.DomainEvent) bool {
+		return e.DispatchedAt.Valid && e.DispatchedAt.Time.Before(before)
+	})
+	return int64(n - len(s.dataOutbox)), nil
 }
@@ -0,0 +1,690 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/domain"
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/cronnoss/tk-api/internal/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachEventToHall(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	venue, err := s.CreateVenue(ctx, models.Venue{Name: "Opera", TimeZone: "Europe/Moscow"})
+	require.NoError(t, err)
+	hall, err := s.CreateHall(ctx, models.Hall{VenueID: venue.ID, Name: "Main"})
+	require.NoError(t, err)
+	section, err := s.CreateSection(ctx, models.Section{HallID: hall.ID, Name: "Stalls"})
+	require.NoError(t, err)
+
+	_, err = s.CreateHallPlaces(ctx, hall.ID, []models.Place{
+		{SectionID: sql.NullInt64{Int64: section.ID, Valid: true}, Row: "5", Seat: 12, Width: 1, Height: 1},
+		{SectionID: sql.NullInt64{Int64: section.ID, Valid: true}, Row: "5", Seat: 13, X: 1, Width: 1, Height: 1},
+	})
+	require.NoError(t, err)
+	_, err = s.CreateHallPlaces(ctx, hall.ID, []models.Place{{SectionID: sql.NullInt64{Int64: 999, Valid: true}}})
+	require.ErrorIs(t, err, model.ErrNotFound)
+
+	event, err := s.CreateEvent(ctx, models.Event{ShowID: 1, Date: time.Now(), TimeZone: "UTC"})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		event, err = s.AttachEventToHall(ctx, event.ID, hall.ID, nil)
+		require.NoError(t, err)
+	}
+	require.Equal(t, hall.ID, event.HallID.Int64)
+	require.Equal(t, "Europe/Moscow", event.TimeZone)
+
+	places, err := s.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+	require.NoError(t, err)
+	require.Len(t, places, 2, "re-attaching must replace inherited places")
+	require.Equal(t, "Row 5, Seat 12", places[0].Label())
+	require.True(t, places[0].IsAvailable)
+
+	layout, err := s.GetPlaces(ctx, models.PlaceFilter{HallID: hall.ID})
+	require.NoError(t, err)
+	require.Len(t, layout, 2)
+
+	_, err = s.AttachEventToHall(ctx, event.ID, 999, nil)
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func TestConcurrentChecks(t *testing.T) {
+	storagetest.ConcurrentChecks(t, New())
+}
+
+func TestAttachEventToHallInUse(t *testing.T) {
+	storagetest.AttachEventToHallInUse(t, New())
+}
+
+func TestUpsertEvent(t *testing.T) {
+	storagetest.UpsertEvent(t, New())
+}
+
+func TestAttachEventToHallReplacesUpstreamPlaces(t *testing.T) {
+	storagetest.AttachEventToHallReplacesUpstreamPlaces(t, New())
+}
+
+func TestHoldCancelledEvent(t *testing.T) {
+	storagetest.HoldCancelledEvent(t, New())
+}
+
+func TestSyncHallEvent(t *testing.T) {
+	storagetest.SyncHallEvent(t, New())
+}
+
+func TestHolds(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	now := time.Now()
+
+	event, err := s.CreateEvent(ctx, models.Event{ShowID: 1, Date: now})
+	require.NoError(t, err)
+	eventID := sql.NullInt64{Int64: event.ID, Valid: true}
+	places, err := s.CreatePlaces(ctx, []models.Place{
+		{EventID: eventID, IsAvailable: true},
+		{EventID: eventID, IsAvailable: true},
+		{EventID: eventID, IsAvailable: true},
+	})
+	require.NoError(t, err)
+
+	hold, err := s.CreateHold(ctx, models.Hold{
+		EventID: event.ID, PlaceIDs: []int64{places[0].ID, places[1].ID}, CreatedAt: now, ExpiresAt: now.Add(time.Minute),
+	})
+	require.NoError(t, err)
+
+	_, err = s.CreateHold(ctx, models.Hold{
+		EventID: event.ID, PlaceIDs: []int64{places[1].ID, places[2].ID}, CreatedAt: now, ExpiresAt: now.Add(time.Minute),
+	})
+	require.ErrorIs(t, err, model.ErrUnavailable)
+
+	got, err := s.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+	require.NoError(t, err)
+	require.Equal(t, models.PlaceHeld, got[0].Status())
+	require.Equal(t, models.PlaceAvailable, got[2].Status(), "a failed hold must not take any place")
+
+	released, err := s.ReleaseExpiredHolds(ctx, now)
+	require.NoError(t, err)
+	require.Empty(t, released)
+	released, err = s.ReleaseExpiredHolds(ctx, now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, released, 1)
+	require.Equal(t, hold.PlaceIDs, released[0].PlaceIDs)
+
+	got, err = s.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+	require.NoError(t, err)
+	require.Equal(t, models.PlaceAvailable, got[0].Status())
+
+	_, err = s.ReleaseHold(ctx, hold.ID, now)
+	require.ErrorIs(t, err, model.ErrNotFound, "released holds can't be released again")
+}
+
+func TestSpatialQueries(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	eventID := sql.NullInt64{Int64: 42, Valid: true}
+
+	// Two 20x30 sections of 10x10 seats spaced by 12, the second one far away in negative coordinates.
+	var places []models.Place
+	for section, origin := range []models.Point{{X: 0, Y: 0}, {X: -1000, Y: -500}} {
+		for row := 0; row < 20; row++ {
+			for seat := 0; seat < 30; seat++ {
+				places = append(places, models.Place{
+					EventID:     eventID,
+					SectionID:   sql.NullInt64{Int64: int64(section + 1), Valid: true},
+					X:           origin.X + float64(seat)*12,
+					Y:           origin.Y + float64(row)*12,
+					Width:       10,
+					Height:      10,
+					IsAvailable: (row+seat)%3 != 0,
+				})
+			}
+		}
+	}
+	places, err := s.CreatePlaces(ctx, places)
+	require.NoError(t, err)
+
+	rect := models.Rect{MinX: 25, MinY: 5, MaxX: 60, MaxY: 22}
+	got, err := s.GetPlacesInRect(ctx, eventID.Int64, rect)
+	require.NoError(t, err)
+	var want []int64
+	for _, p := range places {
+		if p.Bounds().Intersects(rect) {
+			want = append(want, p.ID)
+		}
+	}
+	require.Len(t, got, len(want))
+	for i, p := range got {
+		require.Equal(t, want[i], p.ID)
+	}
+
+	for _, pt := range []models.Point{{X: 100, Y: 100}, {X: -2000, Y: 3000}, {X: -640, Y: -260}} {
+		nearest, err := s.GetNearestPlaces(ctx, eventID.Int64, pt, 5)
+		require.NoError(t, err)
+		require.Len(t, nearest, 5)
+
+		farthest := nearest[4].Center().Distance(pt)
+		closer := 0
+		for _, p := range places {
+			if p.IsAvailable && p.Center().Distance(pt) < farthest {
+				closer++
+			}
+		}
+		require.LessOrEqual(t, closer, 4, "no other available place may be closer than the fifth result")
+		for _, p := range nearest {
+			require.True(t, p.IsAvailable)
+		}
+	}
+
+	links, err := s.GetPlaceAdjacency(ctx, eventID.Int64)
+	require.NoError(t, err)
+	require.Len(t, links, 2*20*29, "every seat but the last in a row links to the next one")
+	require.Equal(t, models.Adjacency{LeftID: places[0].ID, RightID: places[1].ID}, links[0])
+
+	empty, err := s.GetPlacesInRect(ctx, 999, rect)
+	require.NoError(t, err)
+	require.Empty(t, empty)
+}
+
+func TestPricesAndOrders(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	now := time.Now()
+
+	event, err := s.CreateEvent(ctx, models.Event{ShowID: 1, Date: now})
+	require.NoError(t, err)
+	eventID := sql.NullInt64{Int64: event.ID, Valid: true}
+	stalls := sql.NullInt64{Int64: 1, Valid: true}
+	places, err := s.CreatePlaces(ctx, []models.Place{
+		{EventID: eventID, SectionID: stalls, IsAvailable: true},
+		{EventID: eventID, SectionID: stalls, IsAvailable: true},
+		{EventID: eventID, IsAvailable: true},
+	})
+	require.NoError(t, err)
+
+	vip, err := s.CreatePriceCategory(ctx, models.PriceCategory{EventID: event.ID, Name: "VIP", Amount: 500000, Currency: "RUB"})
+	require.NoError(t, err)
+	std, err := s.CreatePriceCategory(ctx, models.PriceCategory{EventID: event.ID, Name: "Standard", Amount: 150000, Currency: "RUB"})
+	require.NoError(t, err)
+	_, err = s.CreatePriceCategory(ctx, models.PriceCategory{EventID: 999, Name: "Nowhere"})
+	require.ErrorIs(t, err, model.ErrNotFound)
+
+	n, err := s.AssignPrices(ctx, event.ID, []models.PriceAssignment{
+		{CategoryID: std.ID, SectionIDs: []int64{stalls.Int64}},
+		{CategoryID: vip.ID, PlaceIDs: []int64{places[0].ID}},
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+	_, err = s.AssignPrices(ctx, 999, []models.PriceAssignment{{CategoryID: vip.ID, PlaceIDs: []int64{places[2].ID}}}, nil)
+	require.ErrorIs(t, err, model.ErrNotFound, "categories of other events can't be assigned")
+
+	got, err := s.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+	require.NoError(t, err)
+	require.Equal(t, vip.ID, got[0].PriceCategoryID.Int64)
+	require.Equal(t, std.ID, got[1].PriceCategoryID.Int64)
+	require.False(t, got[2].PriceCategoryID.Valid)
+
+	hold, err := s.CreateHold(ctx, models.Hold{
+		EventID: event.ID, PlaceIDs: []int64{places[0].ID, places[1].ID}, CreatedAt: now, ExpiresAt: now.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	order, err := s.CreateOrder(ctx, models.Order{
+		EventID: event.ID, HoldID: sql.NullInt64{Int64: hold.ID, Valid: true}, Status: models.OrderConfirmed,
+		Currency: "RUB", Subtotal: 650000, Total: 650000, CreatedAt: now,
+		Items: []models.OrderItem{{PlaceID: places[0].ID, Amount: 500000}, {PlaceID: places[1].ID, Amount: 150000}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, order.ID, order.Items[1].OrderID)
+
+	got, err = s.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+	require.NoError(t, err)
+	require.Equal(t, models.PlaceSold, got[0].Status())
+
+	_, err = s.CreateOrder(ctx, models.Order{HoldID: sql.NullInt64{Int64: hold.ID, Valid: true}, CreatedAt: now})
+	require.ErrorIs(t, err, model.ErrUnavailable, "a hold can be sold once")
+	_, err = s.ReleaseHold(ctx, hold.ID, now)
+	require.ErrorIs(t, err, model.ErrNotFound, "sold places can't be released")
+
+	require.NoError(t, s.DeletePriceCategory(ctx, std.ID))
+	got, err = s.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+	require.NoError(t, err)
+	require.False(t, got[1].PriceCategoryID.Valid)
+}
+
+func TestPromotionRedemptions(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	now := time.Now()
+
+	event, err := s.CreateEvent(ctx, models.Event{ShowID: 1, Date: now})
+	require.NoError(t, err)
+	eventID := sql.NullInt64{Int64: event.ID, Valid: true}
+	places, err := s.CreatePlaces(ctx, []models.Place{
+		{EventID: eventID, IsAvailable: true},
+		{EventID: eventID, IsAvailable: true},
+		{EventID: eventID, IsAvailable: true},
+		{EventID: eventID, IsAvailable: true},
+	})
+	require.NoError(t, err)
+
+	promo, err := s.CreatePromotion(ctx, models.Promotion{
+		Code: "ONCE", Name: "Once", Kind: models.PromotionPercent, Percent: 10, MaxUses: 2, MaxUsesPerCustomer: 1,
+	})
+	require.NoError(t, err)
+	require.True(t, promo.Active)
+	_, err = s.CreatePromotion(ctx, models.Promotion{Code: "ONCE", Name: "Again"})
+	require.ErrorIs(t, err, model.ErrAlreadyExists)
+	auto, err := s.CreatePromotion(ctx, models.Promotion{Name: "Auto", Kind: models.PromotionPercent, Percent: 5})
+	require.NoError(t, err)
+
+	got, err := s.GetCheckoutPromotions(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, got, 1, "coded promotions need their code")
+	got, err = s.GetCheckoutPromotions(ctx, []string{"ONCE"})
+	require.NoError(t, err)
+	require.Equal(t, []int64{promo.ID, auto.ID}, []int64{got[0].ID, got[1].ID})
+
+	order := func(placeID, customerID int64) (models.Order, error) {
+		hold, err := s.CreateHold(ctx, models.Hold{
+			EventID: event.ID, PlaceIDs: []int64{placeID}, CreatedAt: now, ExpiresAt: now.Add(time.Minute),
+		})
+		require.NoError(t, err)
+		return s.CreateOrder(ctx, models.Order{
+			EventID: event.ID, HoldID: sql.NullInt64{Int64: hold.ID, Valid: true}, CreatedAt: now,
+			CustomerID: sql.NullInt64{Int64: customerID, Valid: true},
+			Discounts:  []models.OrderDiscount{{PromotionID: promo.ID, Code: "ONCE", Amount: 100}},
+		})
+	}
+
+	o, err := order(places[0].ID, 7)
+	require.NoError(t, err)
+	require.Equal(t, o.ID, o.Discounts[0].OrderID)
+	_, err = order(places[1].ID, 7)
+	require.ErrorIs(t, err, model.ErrLimitReached, "one use per customer")
+
+	redeemed, err := s.GetCustomerRedemptions(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, map[int64]int{promo.ID: 1}, redeemed)
+
+	_, err = order(places[2].ID, 8)
+	require.NoError(t, err)
+	_, err = order(places[3].ID, 9)
+	require.ErrorIs(t, err, model.ErrLimitReached, "two uses in total")
+
+	promo, err = s.DeactivatePromotion(ctx, promo.ID, now)
+	require.NoError(t, err)
+	require.False(t, promo.Active)
+	require.Equal(t, 2, promo.Uses)
+}
+
+func TestCustomers(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	now := time.Now()
+
+	c, err := s.CreateCustomer(ctx, models.Customer{Email: "ann@example.com", Name: "Ann", MarketingEmail: true})
+	require.NoError(t, err)
+	_, err = s.CreateCustomer(ctx, models.Customer{Email: "ANN@example.com", Name: "Other"})
+	require.ErrorIs(t, err, model.ErrAlreadyExists)
+
+	event, err := s.CreateEvent(ctx, models.Event{ShowID: 1, Date: now})
+	require.NoError(t, err)
+	places, err := s.CreatePlaces(ctx, []models.Place{{EventID: sql.NullInt64{Int64: event.ID, Valid: true}, IsAvailable: true}})
+	require.NoError(t, err)
+	customerID := sql.NullInt64{Int64: c.ID, Valid: true}
+	hold, err := s.CreateHold(ctx, models.Hold{
+		EventID: event.ID, PlaceIDs: []int64{places[0].ID}, CustomerID: customerID,
+		CreatedAt: now, ExpiresAt: now.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	_, err = s.CreateOrder(ctx, models.Order{
+		EventID: event.ID, HoldID: sql.NullInt64{Int64: hold.ID, Valid: true}, CustomerID: customerID,
+		Currency: "RUB", Subtotal: 1000, Total: 1000, CreatedAt: now,
+	})
+	require.NoError(t, err)
+
+	holds, err := s.GetCustomerHolds(ctx, c.ID)
+	require.NoError(t, err)
+	require.Len(t, holds, 1)
+
+	c, err = s.EraseCustomer(ctx, c.ID, now)
+	require.NoError(t, err)
+	require.Empty(t, c.Email)
+	require.False(t, c.MarketingEmail)
+	require.True(t, c.ErasedAt.Valid)
+	_, err = s.UpdateCustomer(ctx, models.Customer{ID: c.ID, Email: "ann@example.com"}, nil)
+	require.ErrorIs(t, err, model.ErrNotFound, "erased customers can't be changed")
+
+	orders, err := s.GetCustomerOrders(ctx, c.ID)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	require.Equal(t, int64(1000), orders[0].Total, "erasure keeps order totals")
+
+	_, err = s.CreateCustomer(ctx, models.Customer{Email: "ann@example.com", Name: "Ann"})
+	require.NoError(t, err, "the email of an erased customer is free")
+}
+
+func TestWaitlist(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	now := time.Now()
+
+	event, err := s.CreateEvent(ctx, models.Event{ShowID: 1, Date: now})
+	require.NoError(t, err)
+	_, err = s.CreateWaitlistEntry(ctx, models.WaitlistEntry{EventID: 999, CustomerID: 1, Quantity: 1})
+	require.ErrorIs(t, err, model.ErrNotFound)
+
+	late, err := s.CreateWaitlistEntry(ctx, models.WaitlistEntry{
+		EventID: event.ID, CustomerID: 1, Quantity: 2, CreatedAt: now.Add(time.Second),
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.WaitlistWaiting, late.Status)
+	early, err := s.CreateWaitlistEntry(ctx, models.WaitlistEntry{EventID: event.ID, CustomerID: 2, Quantity: 1, CreatedAt: now})
+	require.NoError(t, err)
+	_, err = s.CreateWaitlistEntry(ctx, models.WaitlistEntry{EventID: event.ID, CustomerID: 2, Quantity: 3, CreatedAt: now})
+	require.ErrorIs(t, err, model.ErrAlreadyExists, "a customer waits once")
+
+	waiting, err := s.GetWaitingEntries(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []int64{early.ID, late.ID}, []int64{waiting[0].ID, waiting[1].ID}, "first come first served")
+
+	early, err = s.OfferWaitlistEntry(ctx, early.ID, 42, now)
+	require.NoError(t, err)
+	require.Equal(t, models.WaitlistOffered, early.Status)
+	require.Equal(t, int64(42), early.HoldID.Int64)
+	_, err = s.OfferWaitlistEntry(ctx, early.ID, 43, now)
+	require.ErrorIs(t, err, model.ErrNotFound, "an entry is offered once")
+
+	_, err = s.CancelWaitlistEntry(ctx, late.ID, now)
+	require.NoError(t, err)
+	waiting, err = s.GetWaitingEntries(ctx)
+	require.NoError(t, err)
+	require.Empty(t, waiting)
+
+	all, err := s.GetWaitlist(ctx, event.ID)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+}
+
+func TestRefundsAndExchanges(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	now := time.Now()
+
+	event, err := s.CreateEvent(ctx, models.Event{ShowID: 1, Date: now.Add(48 * time.Hour)})
+	require.NoError(t, err)
+	later, err := s.CreateEvent(ctx, models.Event{ShowID: 1, Date: now.Add(72 * time.Hour)})
+	require.NoError(t, err)
+	places, err := s.CreatePlaces(ctx, []models.Place{
+		{EventID: sql.NullInt64{Int64: event.ID, Valid: true}, IsAvailable: true},
+		{EventID: sql.NullInt64{Int64: event.ID, Valid: true}, IsAvailable: true},
+		{EventID: sql.NullInt64{Int64: later.ID, Valid: true}, IsAvailable: true},
+	})
+	require.NoError(t, err)
+
+	hold, err := s.CreateHold(ctx, models.Hold{
+		EventID: event.ID, PlaceIDs: []int64{places[0].ID, places[1].ID}, CreatedAt: now, ExpiresAt: now.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	order, err := s.CreateOrder(ctx, models.Order{
+		EventID: event.ID, HoldID: sql.NullInt64{Int64: hold.ID, Valid: true}, Status: models.OrderConfirmed,
+		Currency: "RUB", Subtotal: 300000, Total: 300000, CreatedAt: now,
+		Items: []models.OrderItem{{PlaceID: places[0].ID, Amount: 150000}, {PlaceID: places[1].ID, Amount: 150000}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.ItemIssued, order.Items[0].Status)
+	require.Equal(t, event.ID, order.Items[0].EventID)
+
+	_, err = s.RefundOrder(ctx, models.Refund{
+		OrderID: order.ID, ItemIDs: []int64{order.Items[0].ID}, Amount: 140000, Fee: 10000, CreatedAt: now,
+	})
+	require.NoError(t, err)
+	_, err = s.RefundOrder(ctx, models.Refund{OrderID: order.ID, ItemIDs: []int64{order.Items[0].ID}, CreatedAt: now})
+	require.ErrorIs(t, err, model.ErrUnavailable, "an item is refunded once")
+
+	got, err := s.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+	require.NoError(t, err)
+	require.True(t, got[0].IsAvailable, "refunded places go back on sale")
+	require.False(t, got[1].IsAvailable)
+
+	order, err = s.GetOrder(ctx, order.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.OrderPartiallyRefunded, order.Status)
+	require.Equal(t, int64(140000), order.Refunded)
+
+	exchange, err := s.ExchangeOrderItems(ctx, models.Exchange{
+		OrderID: order.ID, EventID: later.ID, ItemIDs: []int64{order.Items[1].ID}, CreatedAt: now,
+		Items: []models.OrderItem{{
+			PlaceID: places[2].ID, Amount: 150000, ExchangedFrom: sql.NullInt64{Int64: order.Items[1].ID, Valid: true},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, later.ID, exchange.Items[0].EventID)
+
+	order, err = s.GetOrder(ctx, order.ID)
+	require.NoError(t, err)
+	require.Len(t, order.Items, 3)
+	require.Equal(t, models.ItemExchanged, order.Items[1].Status)
+	require.Equal(t, models.ItemIssued, order.Items[2].Status)
+	got, err = s.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+	require.NoError(t, err)
+	require.True(t, got[1].IsAvailable, "exchanged places go back on sale")
+
+	_, err = s.RefundOrder(ctx, models.Refund{OrderID: order.ID, ItemIDs: []int64{order.Items[2].ID}, CreatedAt: now})
+	require.NoError(t, err)
+	order, err = s.GetOrder(ctx, order.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.OrderRefunded, order.Status)
+
+	trail, err := s.GetAuditTrail(ctx, "order", order.ID)
+	require.NoError(t, err)
+	actions := make([]string, 0, len(trail))
+	for _, a := range trail {
+		actions = append(actions, a.Action)
+	}
+	require.Equal(t, []string{
+		models.AuditOrderCreated, models.AuditOrderRefunded, models.AuditOrderExchanged, models.AuditOrderRefunded,
+	}, actions)
+}
+
+func TestUseOrderItem(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	now := time.Now()
+
+	event, err := s.CreateEvent(ctx, models.Event{ShowID: 1, Date: now.Add(48 * time.Hour)})
+	require.NoError(t, err)
+	places, err := s.CreatePlaces(ctx, []models.Place{
+		{EventID: sql.NullInt64{Int64: event.ID, Valid: true}, IsAvailable: true},
+		{EventID: sql.NullInt64{Int64: event.ID, Valid: true}, IsAvailable: true},
+	})
+	require.NoError(t, err)
+	hold, err := s.CreateHold(ctx, models.Hold{
+		EventID: event.ID, PlaceIDs: []int64{places[0].ID, places[1].ID}, CreatedAt: now, ExpiresAt: now.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	order, err := s.CreateOrder(ctx, models.Order{
+		EventID: event.ID, HoldID: sql.NullInt64{Int64: hold.ID, Valid: true}, Status: models.OrderConfirmed,
+		Currency: "RUB", CreatedAt: now,
+		Items: []models.OrderItem{{PlaceID: places[0].ID}, {PlaceID: places[1].ID}},
+	})
+	require.NoError(t, err)
+
+	used, err := s.UseOrderItem(ctx, order.Items[0].ID, now)
+	require.NoError(t, err)
+	require.True(t, used.UsedAt.Valid)
+	again, err := s.UseOrderItem(ctx, order.Items[0].ID, now.Add(time.Minute))
+	require.ErrorIs(t, err, model.ErrAlreadyUsed, "a ticket is admitted once")
+	require.Equal(t, used.UsedAt, again.UsedAt)
+	_, err = s.RefundOrder(ctx, models.Refund{OrderID: order.ID, ItemIDs: []int64{order.Items[0].ID}, CreatedAt: now})
+	require.ErrorIs(t, err, model.ErrUnavailable, "used tickets can't be refunded")
+
+	_, err = s.RefundOrder(ctx, models.Refund{OrderID: order.ID, ItemIDs: []int64{order.Items[1].ID}, CreatedAt: now})
+	require.NoError(t, err)
+	refunded, err := s.UseOrderItem(ctx, order.Items[1].ID, now)
+	require.ErrorIs(t, err, model.ErrUnavailable)
+	require.Equal(t, models.ItemRefunded, refunded.Status)
+
+	_, err = s.UseOrderItem(ctx, 999, now)
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func TestCancelEvent(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	now := time.Now()
+
+	event, err := s.CreateEvent(ctx, models.Event{ShowID: 1, Date: now})
+	require.NoError(t, err)
+	venue, err := s.CreateVenue(ctx, models.Venue{Name: "Opera", TimeZone: "Europe/Berlin"})
+	require.NoError(t, err)
+	hall, err := s.CreateHall(ctx, models.Hall{VenueID: venue.ID, Name: "Main"})
+	require.NoError(t, err)
+
+	event, err = s.AttachEventToHall(ctx, event.ID, hall.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, event.Sequence, "moving the event is a new revision")
+	event, err = s.AttachEventToHall(ctx, event.ID, hall.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, event.Sequence)
+
+	event, err = s.CancelEvent(ctx, event.ID, now, nil)
+	require.NoError(t, err)
+	require.True(t, event.CancelledAt.Valid)
+	require.Equal(t, 2, event.Sequence)
+	event, err = s.CancelEvent(ctx, event.ID, now.Add(time.Hour), nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, event.Sequence, "cancelling twice changes nothing")
+	require.Equal(t, now, event.CancelledAt.Time)
+
+	_, err = s.CancelEvent(ctx, 999, now, nil)
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	now := time.Now()
+
+	orders, err := s.CreateWebhook(ctx, models.Webhook{URL: "https://a.example", EventTypes: []string{"order.paid"}})
+	require.NoError(t, err)
+	_, err = s.CreateWebhook(ctx, models.Webhook{URL: "https://b.example", EventTypes: []string{"show.created"}})
+	require.NoError(t, err)
+
+	created, err := s.CreateWebhookDeliveries(ctx, models.WebhookDelivery{
+		EventID: "evt_1", EventType: "order.paid", Payload: []byte(`{}`), NextAttemptAt: now, CreatedAt: now,
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 1, "only webhooks receiving the type get deliveries")
+	require.Equal(t, orders.ID, created[0].WebhookID)
+	require.Equal(t, models.DeliveryPending, created[0].Status)
+	again, err := s.CreateWebhookDeliveries(ctx, models.WebhookDelivery{
+		EventID: "evt_1", EventType: "order.paid", Payload: []byte(`{}`), NextAttemptAt: now, CreatedAt: now,
+	})
+	require.NoError(t, err)
+	require.Empty(t, again, "an event is queued once per webhook")
+
+	claimed, err := s.ClaimWebhookDeliveries(ctx, now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	claimed, err = s.ClaimWebhookDeliveries(ctx, now.Add(time.Second), now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Empty(t, claimed, "claimed deliveries aren't due until the claim runs out")
+
+	d := created[0]
+	d.Attempts, d.Status, d.LastError = 1, models.DeliveryDead, "unexpected status 500"
+	d, err = s.RecordWebhookAttempt(ctx, d, models.WebhookAttempt{StatusCode: 500, Error: d.LastError, CreatedAt: now})
+	require.NoError(t, err)
+	dead, err := s.GetWebhookDeliveries(ctx, models.WebhookDeliveryFilter{Status: models.DeliveryDead})
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	attempts, err := s.GetWebhookAttempts(ctx, d.ID)
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	require.Equal(t, 500, attempts[0].StatusCode)
+
+	d, err = s.RedeliverWebhookDelivery(ctx, d.ID, now)
+	require.NoError(t, err)
+	require.Equal(t, models.DeliveryPending, d.Status)
+	require.Zero(t, d.Attempts)
+	claimed, err = s.ClaimWebhookDeliveries(ctx, now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	require.NoError(t, s.DeleteWebhook(ctx, orders.ID))
+	_, err = s.GetWebhookDelivery(ctx, d.ID)
+	require.ErrorIs(t, err, model.ErrNotFound, "deliveries go with their webhook")
+	attempts, err = s.GetWebhookAttempts(ctx, d.ID)
+	require.NoError(t, err)
+	require.Empty(t, attempts)
+	require.ErrorIs(t, s.DeleteWebhook(ctx, orders.ID), model.ErrNotFound)
+}
+
+func TestDomainEventOutbox(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+	now := time.Now()
+
+	event, err := s.CreateEvent(ctx, models.Event{ShowID: 1, Date: now.Add(48 * time.Hour)})
+	require.NoError(t, err)
+	places, err := s.CreatePlaces(ctx, []models.Place{
+		{EventID: sql.NullInt64{Int64: event.ID, Valid: true}, IsAvailable: true},
+		{EventID: sql.NullInt64{Int64: event.ID, Valid: true}, IsAvailable: true},
+	})
+	require.NoError(t, err)
+	hold, err := s.CreateHold(ctx, models.Hold{
+		EventID: event.ID, PlaceIDs: []int64{places[0].ID, places[1].ID}, CreatedAt: now, ExpiresAt: now.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	order, err := s.CreateOrder(ctx, models.Order{
+		EventID: event.ID, HoldID: sql.NullInt64{Int64: hold.ID, Valid: true}, Status: models.OrderConfirmed,
+		Currency: "RUB", Subtotal: 300000, Total: 300000, CreatedAt: now,
+		Items: []models.OrderItem{{PlaceID: places[0].ID, Amount: 150000}, {PlaceID: places[1].ID, Amount: 150000}},
+	})
+	require.NoError(t, err)
+	_, err = s.RefundOrder(ctx, models.Refund{OrderID: order.ID, ItemIDs: []int64{order.Items[0].ID}, CreatedAt: now})
+	require.NoError(t, err)
+	_, err = s.CreateHold(ctx, models.Hold{
+		EventID: event.ID, PlaceIDs: []int64{places[1].ID}, CreatedAt: now, ExpiresAt: now.Add(time.Minute),
+	})
+	require.ErrorIs(t, err, model.ErrUnavailable, "failed changes write no events")
+
+	claimed, err := s.ClaimDomainEvents(ctx, now, now.Add(time.Minute), 100)
+	require.NoError(t, err)
+	types := make([]string, 0, len(claimed))
+	for _, e := range claimed {
+		types = append(types, e.Type)
+	}
+	require.Equal(t, []string{
+		domain.EventCreated, domain.PlaceHeld, domain.PlaceSold, domain.OrderPaid, domain.PlaceReleased,
+		domain.OrderRefunded,
+	}, types, "events are claimed in the order they were written")
+
+	var released domain.PlacesData
+	require.NoError(t, domain.Decode(claimed[4], &released))
+	require.Equal(t, domain.PlacesData{EventID: event.ID, PlaceIDs: []int64{places[0].ID}, OrderID: order.ID}, released)
+	var refunded domain.OrderData
+	require.NoError(t, domain.Decode(claimed[5], &refunded))
+	require.Equal(t, string(models.OrderPartiallyRefunded), refunded.Status)
+
+	again, err := s.ClaimDomainEvents(ctx, now.Add(time.Second), now.Add(time.Minute), 100)
+	require.NoError(t, err)
+	require.Empty(t, again, "claimed events are left to their dispatcher")
+
+	require.NoError(t, s.MarkDomainEventsDispatched(ctx, []int64{claimed[0].ID, claimed[1].ID}, now))
+	again, err = s.ClaimDomainEvents(ctx, now.Add(time.Minute), now.Add(2*time.Minute), 100)
+	require.NoError(t, err)
+	require.Len(t, again, 4, "undispatched events are claimed again once the claim runs out")
+	require.Equal(t, claimed[2].ID, again[0].ID)
+
+	n, err := s.DeleteDispatchedDomainEvents(ctx, now.Add(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+const (
+	AuditOrderCreated   = "order.created"
+	AuditOrderRefunded  = "order.refunded"
+	AuditOrderExchanged = "order.exchanged"
+	AuditTicketScanned  = "ticket.scanned"
+)
+
+// AuditRecord is an entry of the audit trail of an entity, Details is a JSON document.
+type AuditRecord struct {
+	ID        int64     `db:"id"`
+	Entity    string    `db:"entity"`
+	EntityID  int64     `db:"entity_id"`
+	Action    string    `db:"action"`
+	Details   string    `db:"details"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// Check is a precondition of a write, such as If-Match of an HTTP update. Storage runs it on the stored
+// record within the write, so nothing changes the record in between, and returns its error as is.
+type Check[T any] func(T) error
+
+// Run runs the check on record, a nil Check passes.
+func (c Check[T]) Run(record T) error {
+	if c == nil {
+		return nil
+	}
+	return c(record)
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Customer is a buyer. Erased customers keep their ID and orders but no personal data.
+type Customer struct {
+	ID    int64  `db:"id"`
+	Email string `db:"email"`
+	Name  string `db:"name"`
+	Phone string `db:"phone"`
+	// Consents to marketing by email and by SMS.
+	MarketingEmail bool         `db:"marketing_email"`
+	MarketingSMS   bool         `db:"marketing_sms"`
+	CreatedAt      time.Time    `db:"created_at"`
+	UpdatedAt      sql.NullTime `db:"updated_at"`
+	ErasedAt       sql.NullTime `db:"erased_at"`
+}
+
+// ModifiedAt returns when the customer was last written.
+func (c Customer) ModifiedAt() time.Time {
+	return modifiedAt(c.CreatedAt, c.UpdatedAt)
+}
+
+// Erase drops the personal data of the customer.
+func (c *Customer) Erase(now time.Time) {
+	c.Email, c.Name, c.Phone = "", "", ""
+	c.MarketingEmail, c.MarketingSMS = false, false
+	c.UpdatedAt = sql.NullTime{Time: now, Valid: true}
+	c.ErasedAt = sql.NullTime{Time: now, Valid: true}
+}
+
+// CustomerExport is everything stored about a customer.
+type CustomerExport struct {
+	Customer Customer
+	Holds    []Hold
+	Orders   []Order
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DomainEvent is a change of stored data kept in the outbox. It is written in the same transaction as
+// the change, so an event is recorded if and only if the change is, and dispatched to subscribers later.
+type DomainEvent struct {
+	ID   int64  `db:"id"`
+	Type string `db:"type"`
+	// Payload is the JSON data of the event.
+	Payload   []byte    `db:"payload"`
+	CreatedAt time.Time `db:"created_at"`
+	// ClaimedUntil is when a dispatcher that hasn't marked the event dispatched gives it up.
+	ClaimedUntil sql.NullTime `db:"claimed_until"`
+	DispatchedAt sql.NullTime `db:"dispatched_at"`
+}
@@ -2,13 +2,59 @@ package models
 
 import (
 	"database/sql"
+	"slices"
 	"time"
 )
 
 type Event struct {
-	ID        int64        `db:"id"`
-	ShowID    int64        `db:"show_id"`
-	Date      string       `db:"date"`
-	CreatedAt time.Time    `db:"created_at"`
-	UpdatedAt sql.NullTime `db:"updated_at"`
+	ID       int64         `db:"id"`
+	ShowID   int64         `db:"show_id"`
+	HallID   sql.NullInt64 `db:"hall_id"`
+	Date     time.Time     `db:"date"`
+	TimeZone string        `db:"time_zone"`
+	// Sequence counts changes of the date, time zone and hall, calendars use it to order revisions.
+	Sequence    int          `db:"sequence"`
+	CancelledAt sql.NullTime `db:"cancelled_at"`
+	CreatedAt   time.Time    `db:"created_at"`
+	UpdatedAt   sql.NullTime `db:"updated_at"`
+}
+
+// ModifiedAt returns when the event was last written.
+func (e Event) ModifiedAt() time.Time {
+	return modifiedAt(e.CreatedAt, e.UpdatedAt)
+}
+
+// LocalDate returns the event date in the venue time zone, falling back to UTC.
+func (e Event) LocalDate() time.Time {
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		return e.Date.UTC()
+	}
+	return e.Date.In(loc)
+}
+
+// EventFilter narrows GetEvents. Zero fields are not applied; the date range is [From, To).
+type EventFilter struct {
+	ShowID int64
+	From   time.Time
+	To     time.Time
+	// ShowIDs narrows to events of any of the shows.
+	ShowIDs []int64
+}
+
+// Match reports whether the event passes the filter.
+func (f EventFilter) Match(e Event) bool {
+	if f.ShowID != 0 && e.ShowID != f.ShowID {
+		return false
+	}
+	if !f.From.IsZero() && e.Date.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !e.Date.Before(f.To) {
+		return false
+	}
+	if len(f.ShowIDs) != 0 && !slices.Contains(f.ShowIDs, e.ShowID) {
+		return false
+	}
+	return true
 }
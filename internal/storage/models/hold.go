@@ -0,0 +1,22 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Hold reserves places of an event until it expires or is released.
+type Hold struct {
+	ID         int64         `db:"id"`
+	EventID    int64         `db:"event_id"`
+	PlaceIDs   []int64       `db:"-"`
+	CustomerID sql.NullInt64 `db:"customer_id"`
+	ExpiresAt  time.Time     `db:"expires_at"`
+	CreatedAt  time.Time     `db:"created_at"`
+	ReleasedAt sql.NullTime  `db:"released_at"`
+}
+
+// IsActive reports whether the hold still reserves its places at now.
+func (h Hold) IsActive(now time.Time) bool {
+	return !h.ReleasedAt.Valid && h.ExpiresAt.After(now)
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type IdempotencyKey struct {
+	Key         string `db:"key"`
+	Method      string `db:"method"`
+	Path        string `db:"path"`
+	Fingerprint string `db:"fingerprint"`
+	StatusCode  int    `db:"status_code"`
+	ContentType string `db:"content_type"`
+	// ResponseHeaders is a JSON object of the replayed response headers, e.g. Location and ETag.
+	ResponseHeaders []byte       `db:"response_headers"`
+	ResponseBody    []byte       `db:"response_body"`
+	CreatedAt       time.Time    `db:"created_at"`
+	ExpiresAt       time.Time    `db:"expires_at"`
+	CompletedAt     sql.NullTime `db:"completed_at"`
+}
+
+// IsCompleted reports whether the response for the key has been stored.
+func (k IdempotencyKey) IsCompleted() bool {
+	return k.CompletedAt.Valid
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+const (
+	// NotificationWaitlistOffer tells a waitlisted customer that places are held for them.
+	NotificationWaitlistOffer = "waitlist.offered"
+)
+
+// Notification tells customers and integrations about something that happened.
+type Notification struct {
+	Type       string
+	CustomerID int64
+	// Data describes what happened, it is marshalled to JSON.
+	Data      any
+	CreatedAt time.Time
+}
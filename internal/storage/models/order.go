@@ -0,0 +1,127 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type OrderStatus string
+
+const (
+	OrderConfirmed         OrderStatus = "confirmed"
+	OrderPartiallyRefunded OrderStatus = "partially_refunded"
+	OrderRefunded          OrderStatus = "refunded"
+)
+
+type OrderItemStatus string
+
+const (
+	ItemIssued    OrderItemStatus = "issued"
+	ItemRefunded  OrderItemStatus = "refunded"
+	ItemExchanged OrderItemStatus = "exchanged"
+)
+
+// Order sells the places of a hold. Amounts are in minor units of Currency,
+// Total is Subtotal less the discounts adjusted by exchanges, Refunded is what has been paid back.
+type Order struct {
+	ID         int64           `db:"id"`
+	EventID    int64           `db:"event_id"`
+	HoldID     sql.NullInt64   `db:"hold_id"`
+	Status     OrderStatus     `db:"status"`
+	Currency   string          `db:"currency"`
+	Subtotal   int64           `db:"subtotal"`
+	Total      int64           `db:"total"`
+	Refunded   int64           `db:"refunded"`
+	Items      []OrderItem     `db:"-"`
+	Discounts  []OrderDiscount `db:"-"`
+	CustomerID sql.NullInt64   `db:"customer_id"`
+	CreatedAt  time.Time       `db:"created_at"`
+	UpdatedAt  sql.NullTime    `db:"updated_at"`
+}
+
+// OrderItem is a sold place with the price it was sold at, Discount of the Amount is taken off by promotions.
+type OrderItem struct {
+	ID              int64           `db:"id"`
+	OrderID         int64           `db:"order_id"`
+	EventID         int64           `db:"event_id"`
+	PlaceID         int64           `db:"place_id"`
+	PriceCategoryID sql.NullInt64   `db:"price_category_id"`
+	Amount          int64           `db:"amount"`
+	Discount        int64           `db:"discount"`
+	Status          OrderItemStatus `db:"status"`
+	RefundID        sql.NullInt64   `db:"refund_id"`
+	// ExchangedFrom is the item this one replaced in an exchange.
+	ExchangedFrom sql.NullInt64 `db:"exchanged_from"`
+	// UsedAt is when the ticket was scanned at the door.
+	UsedAt sql.NullTime `db:"used_at"`
+}
+
+// OrderStatusOf returns the status of an order with the items: refunded once no item is issued.
+func OrderStatusOf(items []OrderItem) OrderStatus {
+	issued, refunded := 0, 0
+	for _, item := range items {
+		switch item.Status {
+		case ItemIssued:
+			issued++
+		case ItemRefunded:
+			refunded++
+		}
+	}
+	switch {
+	case issued == 0 && refunded > 0:
+		return OrderRefunded
+	case refunded > 0:
+		return OrderPartiallyRefunded
+	default:
+		return OrderConfirmed
+	}
+}
+
+// Paid returns the amount paid for the item.
+func (i OrderItem) Paid() int64 {
+	return i.Amount - i.Discount
+}
+
+// OrderDiscount explains a promotion applied to an order.
+type OrderDiscount struct {
+	ID          int64  `db:"id"`
+	OrderID     int64  `db:"order_id"`
+	PromotionID int64  `db:"promotion_id"`
+	Code        string `db:"code"`
+	Name        string `db:"name"`
+	Description string `db:"description"`
+	Amount      int64  `db:"amount"`
+}
+
+// Checkout asks to sell the places of a hold with the promo codes entered by the buyer.
+type Checkout struct {
+	HoldID     int64
+	PromoCodes []string
+	// CustomerID identifies the buyer, 0 if anonymous.
+	CustomerID int64
+}
+
+// Refund pays back issued items of an order less the refund fees, their places go back on sale.
+type Refund struct {
+	ID        int64     `db:"id"`
+	OrderID   int64     `db:"order_id"`
+	ItemIDs   []int64   `db:"-"`
+	Amount    int64     `db:"amount"`
+	Fee       int64     `db:"fee"`
+	Reason    string    `db:"reason"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Exchange replaces issued items of an order with places of another event of the same show.
+// Difference is the price of the new items less what was paid for the old ones, the customer
+// pays Difference plus Fee, a negative sum is owed to the customer.
+type Exchange struct {
+	ID         int64       `db:"id"`
+	OrderID    int64       `db:"order_id"`
+	EventID    int64       `db:"event_id"`
+	ItemIDs    []int64     `db:"-"`
+	Items      []OrderItem `db:"-"`
+	Difference int64       `db:"difference"`
+	Fee        int64       `db:"fee"`
+	CreatedAt  time.Time   `db:"created_at"`
+}
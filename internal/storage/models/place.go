@@ -2,16 +2,90 @@ package models
 
 import (
 	"database/sql"
+	"fmt"
+	"slices"
 	"time"
 )
 
+// FirstLocalPlaceID is the first ID of places created here, hall layouts and their copies. Lower IDs
+// are left to places synced from the remote API, which are stored by their IDs.
+const FirstLocalPlaceID = 1_000_000_000
+
 type Place struct {
-	ID          int64        `db:"id"`
-	X           float64      `db:"x"`
-	Y           float64      `db:"y"`
-	Width       float64      `db:"width"`
-	Height      float64      `db:"height"`
-	IsAvailable bool         `db:"is_available"`
-	CreatedAt   time.Time    `db:"created_at"`
-	UpdatedAt   sql.NullTime `db:"updated_at"`
+	ID              int64         `db:"id"`
+	EventID         sql.NullInt64 `db:"event_id"`
+	HallID          sql.NullInt64 `db:"hall_id"`
+	SectionID       sql.NullInt64 `db:"section_id"`
+	HoldID          sql.NullInt64 `db:"hold_id"`
+	PriceCategoryID sql.NullInt64 `db:"price_category_id"`
+	Row             string        `db:"row_label"`
+	Seat            int           `db:"seat_number"`
+	X               float64       `db:"x"`
+	Y               float64       `db:"y"`
+	Width           float64       `db:"width"`
+	Height          float64       `db:"height"`
+	IsAvailable     bool          `db:"is_available"`
+	Accessible      bool          `db:"is_accessible"`
+	CreatedAt       time.Time     `db:"created_at"`
+	UpdatedAt       sql.NullTime  `db:"updated_at"`
+}
+
+// ModifiedAt returns when the place was last written.
+func (p Place) ModifiedAt() time.Time {
+	return modifiedAt(p.CreatedAt, p.UpdatedAt)
+}
+
+type PlaceStatus string
+
+const (
+	PlaceAvailable PlaceStatus = "available"
+	PlaceHeld      PlaceStatus = "held"
+	PlaceSold      PlaceStatus = "sold"
+)
+
+// Status returns the sale status of the place.
+func (p Place) Status() PlaceStatus {
+	switch {
+	case p.IsAvailable:
+		return PlaceAvailable
+	case p.HoldID.Valid:
+		return PlaceHeld
+	}
+	return PlaceSold
+}
+
+// Label returns a human-readable seat label such as "Row 5, Seat 12".
+func (p Place) Label() string {
+	switch {
+	case p.Row != "" && p.Seat > 0:
+		return fmt.Sprintf("Row %s, Seat %d", p.Row, p.Seat)
+	case p.Seat > 0:
+		return fmt.Sprintf("Seat %d", p.Seat)
+	case p.Row != "":
+		return fmt.Sprintf("Row %s", p.Row)
+	}
+	return ""
+}
+
+// PlaceFilter narrows GetPlaces. With HallID set only the hall layout is returned,
+// i.e. places of the hall not yet copied to an event.
+type PlaceFilter struct {
+	EventID int64
+	HallID  int64
+	// EventIDs narrows to places of any of the events.
+	EventIDs []int64
+}
+
+// Match reports whether the place passes the filter.
+func (f PlaceFilter) Match(p Place) bool {
+	if f.EventID != 0 && p.EventID.Int64 != f.EventID {
+		return false
+	}
+	if f.HallID != 0 && (p.HallID.Int64 != f.HallID || p.EventID.Valid) {
+		return false
+	}
+	if len(f.EventIDs) != 0 && !slices.Contains(f.EventIDs, p.EventID.Int64) {
+		return false
+	}
+	return true
 }
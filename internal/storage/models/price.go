@@ -0,0 +1,45 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PriceCategory is a price tier of an event. Amount is in minor units of Currency, an ISO 4217 code.
+type PriceCategory struct {
+	ID        int64        `db:"id"`
+	EventID   int64        `db:"event_id"`
+	Name      string       `db:"name"`
+	Amount    int64        `db:"amount"`
+	Currency  string       `db:"currency"`
+	CreatedAt time.Time    `db:"created_at"`
+	UpdatedAt sql.NullTime `db:"updated_at"`
+}
+
+// ModifiedAt returns when the price category was last written.
+func (p PriceCategory) ModifiedAt() time.Time {
+	return modifiedAt(p.CreatedAt, p.UpdatedAt)
+}
+
+// PriceAssignment puts places of an event into a price category: the listed places
+// and all places of the listed sections. CategoryID 0 removes the places from their category.
+type PriceAssignment struct {
+	CategoryID int64
+	PlaceIDs   []int64
+	SectionIDs []int64
+}
+
+// Match reports whether the assignment covers the place.
+func (a PriceAssignment) Match(p Place) bool {
+	for _, id := range a.PlaceIDs {
+		if p.ID == id {
+			return true
+		}
+	}
+	for _, id := range a.SectionIDs {
+		if p.SectionID.Valid && p.SectionID.Int64 == id {
+			return true
+		}
+	}
+	return false
+}
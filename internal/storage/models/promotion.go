@@ -0,0 +1,63 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type PromotionKind string
+
+const (
+	// PromotionPercent takes Percent off every place in scope.
+	PromotionPercent PromotionKind = "percent"
+	// PromotionFixed takes Amount off the places in scope in total.
+	PromotionFixed PromotionKind = "fixed"
+	// PromotionBuyNGetM makes the M cheapest of every N+M places in scope free.
+	PromotionBuyNGetM PromotionKind = "buy_n_get_m"
+)
+
+// Promotion is a discount rule. Promotions with a code apply when the code is entered,
+// promotions without one apply to every order in their scope.
+type Promotion struct {
+	ID       int64         `db:"id"`
+	Code     string        `db:"code"`
+	Name     string        `db:"name"`
+	Kind     PromotionKind `db:"kind"`
+	Percent  int           `db:"percent"`
+	Amount   int64         `db:"amount"`
+	Currency string        `db:"currency"`
+	BuyN     int           `db:"buy_n"`
+	GetM     int           `db:"get_m"`
+	// Scope: unset IDs match everything.
+	ShowID          sql.NullInt64 `db:"show_id"`
+	EventID         sql.NullInt64 `db:"event_id"`
+	PriceCategoryID sql.NullInt64 `db:"price_category_id"`
+	ValidFrom       sql.NullTime  `db:"valid_from"`
+	ValidTo         sql.NullTime  `db:"valid_to"`
+	// MaxUses and MaxUsesPerCustomer limit redemptions, 0 is unlimited.
+	MaxUses            int          `db:"max_uses"`
+	MaxUsesPerCustomer int          `db:"max_uses_per_customer"`
+	Uses               int          `db:"uses"`
+	Active             bool         `db:"is_active"`
+	CreatedAt          time.Time    `db:"created_at"`
+	UpdatedAt          sql.NullTime `db:"updated_at"`
+}
+
+// IsValid reports whether the promotion is active and within its validity window [ValidFrom, ValidTo) at now.
+func (p Promotion) IsValid(now time.Time) bool {
+	if !p.Active {
+		return false
+	}
+	if p.ValidFrom.Valid && now.Before(p.ValidFrom.Time) {
+		return false
+	}
+	return !p.ValidTo.Valid || now.Before(p.ValidTo.Time)
+}
+
+// Redemption records a use of a promotion by an order.
+type Redemption struct {
+	PromotionID int64         `db:"promotion_id"`
+	OrderID     int64         `db:"order_id"`
+	CustomerID  sql.NullInt64 `db:"customer_id"`
+	CreatedAt   time.Time     `db:"created_at"`
+}
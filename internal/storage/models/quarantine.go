@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// QuarantinedItem is an upstream item that failed validation, kept for review.
+type QuarantinedItem struct {
+	ID        int64     `db:"id"`
+	Entity    string    `db:"entity"`
+	Source    string    `db:"source"`
+	Payload   []byte    `db:"payload"`
+	Errors    []byte    `db:"errors"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -11,3 +11,16 @@ type Show struct {
 	CreatedAt time.Time    `db:"created_at"`
 	UpdatedAt sql.NullTime `db:"updated_at"`
 }
+
+// ModifiedAt returns when the show was last written.
+func (s Show) ModifiedAt() time.Time {
+	return modifiedAt(s.CreatedAt, s.UpdatedAt)
+}
+
+// modifiedAt returns the later of the creation and update times of a record.
+func modifiedAt(created time.Time, updated sql.NullTime) time.Time {
+	if updated.Valid && updated.Time.After(created) {
+		return updated.Time
+	}
+	return created
+}
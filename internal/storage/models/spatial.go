@@ -0,0 +1,62 @@
+package models
+
+import "math"
+
+// Point is a position on the seat map.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Distance returns the Euclidean distance between p and q.
+func (p Point) Distance(q Point) float64 {
+	return math.Hypot(p.X-q.X, p.Y-q.Y)
+}
+
+// Rect is an axis-aligned rectangle on the seat map. Edges belong to the rectangle.
+type Rect struct {
+	MinX float64
+	MinY float64
+	MaxX float64
+	MaxY float64
+}
+
+// Intersects reports whether r and o share at least one point.
+func (r Rect) Intersects(o Rect) bool {
+	return r.MinX <= o.MaxX && o.MinX <= r.MaxX && r.MinY <= o.MaxY && o.MinY <= r.MaxY
+}
+
+// Bounds returns the rectangle the place occupies.
+func (p Place) Bounds() Rect {
+	return Rect{MinX: p.X, MinY: p.Y, MaxX: p.X + p.Width, MaxY: p.Y + p.Height}
+}
+
+// Center returns the centre of the place.
+func (p Place) Center() Point {
+	return Point{X: p.X + p.Width/2, Y: p.Y + p.Height/2}
+}
+
+// Precedes reports whether q is the next seat to the right of p in the same row:
+// both are in one section, their vertical centres are within half the taller seat height
+// and the gap between them is at most half the narrower seat width.
+func (p Place) Precedes(q Place) bool {
+	if p.SectionID.Int64 != q.SectionID.Int64 || q.X <= p.X {
+		return false
+	}
+	if math.Abs(p.Center().Y-q.Center().Y) > math.Max(p.Height, q.Height)/2 {
+		return false
+	}
+	return q.X-(p.X+p.Width) <= math.Min(p.Width, q.Width)/2
+}
+
+// Adjacency links two neighbouring seats of a row, Left precedes Right.
+type Adjacency struct {
+	LeftID  int64 `db:"left_id"`
+	RightID int64 `db:"right_id"`
+}
+
+// AdjacencyReach returns the area where seats following p may lie, for index lookups
+// before checking Precedes.
+func (p Place) AdjacencyReach() Rect {
+	return Rect{MinX: p.X, MinY: p.Y, MaxX: p.X + p.Width + p.Width/2, MaxY: p.Y + p.Height}
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Venue struct {
+	ID        int64        `db:"id"`
+	Name      string       `db:"name"`
+	Address   string       `db:"address"`
+	TimeZone  string       `db:"time_zone"`
+	CreatedAt time.Time    `db:"created_at"`
+	UpdatedAt sql.NullTime `db:"updated_at"`
+}
+
+type Hall struct {
+	ID        int64        `db:"id"`
+	VenueID   int64        `db:"venue_id"`
+	Name      string       `db:"name"`
+	CreatedAt time.Time    `db:"created_at"`
+	UpdatedAt sql.NullTime `db:"updated_at"`
+}
+
+type Section struct {
+	ID        int64        `db:"id"`
+	HallID    int64        `db:"hall_id"`
+	Name      string       `db:"name"`
+	CreatedAt time.Time    `db:"created_at"`
+	UpdatedAt sql.NullTime `db:"updated_at"`
+}
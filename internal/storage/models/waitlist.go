@@ -0,0 +1,30 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+type WaitlistStatus string
+
+const (
+	WaitlistWaiting   WaitlistStatus = "waiting"
+	WaitlistOffered   WaitlistStatus = "offered"
+	WaitlistCancelled WaitlistStatus = "cancelled"
+)
+
+// WaitlistEntry is a customer waiting for places of a sold-out event.
+// Once places are found they are held for the customer and the entry is offered.
+type WaitlistEntry struct {
+	ID         int64 `db:"id"`
+	EventID    int64 `db:"event_id"`
+	CustomerID int64 `db:"customer_id"`
+	Quantity   int   `db:"quantity"`
+	// MaxPrice is the price ceiling per place in minor units, 0 if any price goes.
+	MaxPrice  int64          `db:"max_price"`
+	Status    WaitlistStatus `db:"status"`
+	HoldID    sql.NullInt64  `db:"hold_id"`
+	CreatedAt time.Time      `db:"created_at"`
+	OfferedAt sql.NullTime   `db:"offered_at"`
+	UpdatedAt sql.NullTime   `db:"updated_at"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Webhook is an endpoint of an integrator receiving the events of EventTypes.
+type Webhook struct {
+	ID         int64     `db:"id"`
+	URL        string    `db:"url"`
+	Secret     string    `db:"secret"`
+	EventTypes []string  `db:"-"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// DeliveryDead deliveries ran out of attempts, they are only retried by hand.
+	DeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is an event to be sent to a webhook. Deliveries are the outbox of webhooks:
+// they are stored when the event happens and sent by the dispatcher, so none is lost on restart.
+type WebhookDelivery struct {
+	ID        int64  `db:"id"`
+	WebhookID int64  `db:"webhook_id"`
+	EventID   string `db:"event_id"`
+	EventType string `db:"event_type"`
+	// Payload is the JSON body sent to the webhook.
+	Payload  []byte                `db:"payload"`
+	Status   WebhookDeliveryStatus `db:"status"`
+	Attempts int                   `db:"attempts"`
+	// NextAttemptAt is when a pending delivery is due.
+	NextAttemptAt time.Time    `db:"next_attempt_at"`
+	LastError     string       `db:"last_error"`
+	CreatedAt     time.Time    `db:"created_at"`
+	DeliveredAt   sql.NullTime `db:"delivered_at"`
+	UpdatedAt     sql.NullTime `db:"updated_at"`
+}
+
+// WebhookAttempt logs an attempt to send a delivery.
+type WebhookAttempt struct {
+	ID         int64 `db:"id"`
+	DeliveryID int64 `db:"delivery_id"`
+	// StatusCode is 0 if no response was received.
+	StatusCode int       `db:"status_code"`
+	Error      string    `db:"error"`
+	DurationMS int64     `db:"duration_ms"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// WebhookDeliveryFilter narrows deliveries down, zero values match any.
+type WebhookDeliveryFilter struct {
+	WebhookID int64
+	Status    WebhookDeliveryStatus
+}
+
+// Match reports whether the delivery passes the filter.
+func (f WebhookDeliveryFilter) Match(d WebhookDelivery) bool {
+	if f.WebhookID != 0 && d.WebhookID != f.WebhookID {
+		return false
+	}
+	if f.Status != "" && d.Status != f.Status {
+		return false
+	}
+	return true
+}
@@ -3,11 +3,16 @@ package sqlstorage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"time"
 
+	"github.com/cronnoss/tk-api/internal/domain"
 	"github.com/cronnoss/tk-api/internal/model"
 	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/jackc/pgx"
 	_ "github.com/jackc/pgx/stdlib" // nolint: revive
 	"github.com/jmoiron/sqlx"
 )
@@ -68,7 +73,7 @@ func stringNull(s string) sql.NullString { // nolint: unused
 // GetShows returns shows.
 func (s *Storage) GetShows(ctx context.Context) ([]models.Show, error) {
 	var shows []models.Show
-	query := `SELECT id, name FROM shows`
+	query := `SELECT id, name, created_at, updated_at FROM shows`
 	if err := s.db.SelectContext(ctx, &shows, query); err != nil {
 		return nil, fmt.Errorf("failed to get shows: %w", err)
 	}
@@ -79,12 +84,7 @@ func (s *Storage) GetShows(ctx context.Context) ([]models.Show, error) {
 func (s *Storage) CreateShows(ctx context.Context, shows []models.Show) ([]models.Show, error) {
 	insertedShows := make([]models.Show, 0, len(shows))
 	for _, show := range shows {
-		var newShow models.Show
-		err := s.db.GetContext(ctx, &newShow,
-			`INSERT INTO shows (name) VALUES ($1)
-            ON CONFLICT (name) DO UPDATE SET updated_at = now()
-            RETURNING *`,
-			show.Name)
+		newShow, err := s.createShow(ctx, show)
 		if err != nil {
 			return insertedShows, nil // nolint: nilerr
 		}
@@ -95,12 +95,7 @@ func (s *Storage) CreateShows(ctx context.Context, shows []models.Show) ([]model
 
 // CreateShow creates a show.
 func (s *Storage) CreateShow(ctx context.Context, show models.Show) (models.Show, error) {
-	var insertedShow models.Show
-	err := s.db.GetContext(ctx, &insertedShow,
-		`INSERT INTO shows (name) VALUES ($1) 
-		ON CONFLICT (name) DO UPDATE SET updated_at = now()
-		RETURNING *`,
-		show.Name)
+	insertedShow, err := s.createShow(ctx, show)
 	if err != nil {
 		return insertedShow, nil // nolint: nilerr
 	}
@@ -108,11 +103,58 @@ func (s *Storage) CreateShow(ctx context.Context, show models.Show) (models.Show
 	return insertedShow, nil
 }
 
-// GetEvents returns events.
-func (s *Storage) GetEvents(ctx context.Context) ([]models.Event, error) {
+// createShow stores a show unless one of the same name is stored, then that one is touched.
+func (s *Storage) createShow(ctx context.Context, show models.Show) (models.Show, error) {
+	var newShow models.Show
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return newShow, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	if err := tx.GetContext(ctx, &newShow,
+		`INSERT INTO shows (name) VALUES ($1)
+		ON CONFLICT (name) DO UPDATE SET updated_at = now()
+		RETURNING *`,
+		show.Name); err != nil {
+		return newShow, fmt.Errorf("failed to create show: %w", err)
+	}
+	if !newShow.UpdatedAt.Valid {
+		if err := outbox(ctx, tx, domain.ShowCreated, domain.Show(newShow), time.Now()); err != nil {
+			return newShow, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newShow, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return newShow, nil
+}
+
+func timeNull(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+// GetEvents returns events matching the filter ordered by date.
+func (s *Storage) GetEvents(ctx context.Context, filter models.EventFilter) ([]models.Event, error) {
 	var events []models.Event
-	query := `SELECT id, show_id, date FROM events`
-	if err := s.db.SelectContext(ctx, &events, query); err != nil {
+	// sqlx.In can't expand empty lists, an impossible ID keeps the query valid.
+	query, args, err := sqlx.In(`SELECT id, show_id, hall_id, date, time_zone, sequence, cancelled_at,
+		created_at, updated_at FROM events
+		WHERE (? = 0 OR show_id = ?)
+		AND (?::timestamptz IS NULL OR date >= ?)
+		AND (?::timestamptz IS NULL OR date < ?)
+		AND (? OR show_id IN (?))
+		ORDER BY date`,
+		filter.ShowID, filter.ShowID, timeNull(filter.From), timeNull(filter.From),
+		timeNull(filter.To), timeNull(filter.To), len(filter.ShowIDs) == 0, append([]int64{0}, filter.ShowIDs...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build events query: %w", err)
+	}
+	if err := s.db.SelectContext(ctx, &events, s.db.Rebind(query), args...); err != nil {
 		return nil, fmt.Errorf("failed to get events: %w", err)
 	}
 	return events, nil
@@ -122,12 +164,7 @@ func (s *Storage) GetEvents(ctx context.Context) ([]models.Event, error) {
 func (s *Storage) CreateEvents(ctx context.Context, events []models.Event) ([]models.Event, error) {
 	insertedEvents := make([]models.Event, 0, len(events))
 	for _, event := range events {
-		var newEvent models.Event
-		err := s.db.GetContext(ctx, &newEvent,
-			`INSERT INTO events (show_id, date) VALUES ($1, $2)
-			ON CONFLICT (show_id, date) DO UPDATE SET updated_at = now()
-			RETURNING *`,
-			event.ShowID, event.Date)
+		newEvent, err := s.createEvent(ctx, event)
 		if err != nil {
 			return insertedEvents, nil // nolint: nilerr
 		}
@@ -136,14 +173,37 @@ func (s *Storage) CreateEvents(ctx context.Context, events []models.Event) ([]mo
 	return insertedEvents, nil
 }
 
+// createEvent stores an event unless the show has one at the same date, then that one is touched.
+func (s *Storage) createEvent(ctx context.Context, event models.Event) (models.Event, error) {
+	var newEvent models.Event
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return newEvent, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	if err := tx.GetContext(ctx, &newEvent,
+		`INSERT INTO events (show_id, date, time_zone) VALUES ($1, $2, $3)
+		ON CONFLICT (show_id, date) DO UPDATE SET updated_at = now()
+		RETURNING *`,
+		event.ShowID, event.Date.UTC(), event.TimeZone); err != nil {
+		return newEvent, fmt.Errorf("failed to create event: %w", err)
+	}
+	if !newEvent.UpdatedAt.Valid {
+		if err := outbox(ctx, tx, domain.EventCreated, domain.Event(newEvent), time.Now()); err != nil {
+			return newEvent, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newEvent, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return newEvent, nil
+}
+
 // CreateEvent creates a event.
 func (s *Storage) CreateEvent(ctx context.Context, event models.Event) (models.Event, error) {
-	var insertedEvent models.Event
-	err := s.db.GetContext(ctx, &insertedEvent,
-		`INSERT INTO events (id, show_id, date) VALUES ($1, $2, $3)
-	   	ON CONFLICT (id) DO UPDATE SET updated_at = now()
-		RETURNING *`,
-		event.ID, event.ShowID, event.Date)
+	insertedEvent, err := s.upsertEvent(ctx, event)
 	if err != nil {
 		return insertedEvent, nil // nolint: nilerr
 	}
@@ -151,16 +211,122 @@ func (s *Storage) CreateEvent(ctx context.Context, event models.Event) (models.E
 	return insertedEvent, nil
 }
 
-// GetPlaces returns places.
-func (s *Storage) GetPlaces(ctx context.Context) ([]models.Place, error) {
+// upsertEvent stores an event by ID, a stored event moved to another date or time zone gets the next sequence.
+// Events in a hall keep the venue time zone, the remote API doesn't know it.
+func (s *Storage) upsertEvent(ctx context.Context, event models.Event) (models.Event, error) {
+	var newEvent models.Event
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return newEvent, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	var sequence sql.NullInt64
+	err = tx.GetContext(ctx, &sequence, `SELECT sequence FROM events WHERE id = $1 FOR UPDATE`, event.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return newEvent, fmt.Errorf("failed to lock event: %w", err)
+	}
+	if err := tx.GetContext(ctx, &newEvent,
+		`INSERT INTO events (id, show_id, date, time_zone) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET date = EXCLUDED.date, updated_at = now(),
+			time_zone = CASE WHEN events.hall_id IS NULL THEN EXCLUDED.time_zone ELSE events.time_zone END,
+			sequence = events.sequence +
+				CASE WHEN events.date IS DISTINCT FROM EXCLUDED.date
+					OR (events.hall_id IS NULL AND events.time_zone IS DISTINCT FROM EXCLUDED.time_zone)
+				THEN 1 ELSE 0 END
+		RETURNING *`,
+		event.ID, event.ShowID, event.Date.UTC(), event.TimeZone); err != nil {
+		return newEvent, fmt.Errorf("failed to create event: %w", err)
+	}
+	switch {
+	case !sequence.Valid:
+		err = outbox(ctx, tx, domain.EventCreated, domain.Event(newEvent), time.Now())
+	case int64(newEvent.Sequence) != sequence.Int64:
+		err = outbox(ctx, tx, domain.EventUpdated, domain.Event(newEvent), time.Now())
+	}
+	if err != nil {
+		return newEvent, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return newEvent, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return newEvent, nil
+}
+
+// GetPlaces returns places matching the filter ordered by ID.
+func (s *Storage) GetPlaces(ctx context.Context, filter models.PlaceFilter) ([]models.Place, error) {
 	var places []models.Place
-	query := `SELECT id, x, y, width, height, is_available FROM places`
-	if err := s.db.SelectContext(ctx, &places, query); err != nil {
+	// sqlx.In can't expand empty lists, an impossible ID keeps the query valid.
+	query, args, err := sqlx.In(`SELECT `+placeColumns+` FROM places
+		WHERE (? = 0 OR event_id = ?)
+		AND (? = 0 OR (hall_id = ? AND event_id IS NULL))
+		AND (? OR event_id IN (?))
+		ORDER BY id`,
+		filter.EventID, filter.EventID, filter.HallID, filter.HallID,
+		len(filter.EventIDs) == 0, append([]int64{0}, filter.EventIDs...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build places query: %w", err)
+	}
+	if err := s.db.SelectContext(ctx, &places, s.db.Rebind(query), args...); err != nil {
 		return nil, fmt.Errorf("failed to get places: %w", err)
 	}
 	return places, nil
 }
 
+// placeColumns are the places columns mapped to models.Place.
+const placeColumns = `id, event_id, hall_id, section_id, hold_id, price_category_id, row_label, seat_number,
+	x, y, width, height, is_available, is_accessible, created_at, updated_at`
+
+// GetPlacesInRect returns places of the event intersecting r ordered by ID.
+// The box expression matches the places_event_bounds_idx GiST index.
+func (s *Storage) GetPlacesInRect(ctx context.Context, eventID int64, r models.Rect) ([]models.Place, error) {
+	var places []models.Place
+	query := `SELECT ` + placeColumns + ` FROM places
+		WHERE event_id = $1 AND box(point(x, y), point(x + width, y + height)) && box(point($2, $3), point($4, $5))
+		ORDER BY id`
+	if err := s.db.SelectContext(ctx, &places, query, eventID, r.MinX, r.MinY, r.MaxX, r.MaxY); err != nil {
+		return nil, fmt.Errorf("failed to get places in rect: %w", err)
+	}
+	return places, nil
+}
+
+// GetNearestPlaces returns up to n available places of the event closest to pt,
+// nearest first by the distance between pt and the place centre.
+// The KNN ordering is served by the places_event_center_idx GiST index.
+func (s *Storage) GetNearestPlaces(ctx context.Context, eventID int64, pt models.Point, n int,
+) ([]models.Place, error) {
+	var places []models.Place
+	query := `SELECT ` + placeColumns + ` FROM places
+		WHERE event_id = $1 AND is_available
+		ORDER BY point(x + width / 2, y + height / 2) <-> point($2, $3), id
+		LIMIT $4`
+	if err := s.db.SelectContext(ctx, &places, query, eventID, pt.X, pt.Y, n); err != nil {
+		return nil, fmt.Errorf("failed to get nearest places: %w", err)
+	}
+	return places, nil
+}
+
+// GetPlaceAdjacency returns links between neighbouring seats of the event rows ordered by IDs.
+// Candidates are found with the bounds index, then checked with the rules of models.Place.Precedes.
+func (s *Storage) GetPlaceAdjacency(ctx context.Context, eventID int64) ([]models.Adjacency, error) {
+	var links []models.Adjacency
+	query := `SELECT a.id AS left_id, b.id AS right_id
+		FROM places a JOIN places b ON b.event_id = a.event_id
+			AND box(point(b.x, b.y), point(b.x + b.width, b.y + b.height))
+				&& box(point(a.x, a.y), point(a.x + a.width * 1.5, a.y + a.height))
+		WHERE a.event_id = $1
+			AND COALESCE(a.section_id, 0) = COALESCE(b.section_id, 0)
+			AND b.x > a.x
+			AND abs((a.y + a.height / 2) - (b.y + b.height / 2)) <= GREATEST(a.height, b.height) / 2
+			AND b.x - (a.x + a.width) <= LEAST(a.width, b.width) / 2
+		ORDER BY a.id, b.id`
+	if err := s.db.SelectContext(ctx, &links, query, eventID); err != nil {
+		return nil, fmt.Errorf("failed to get place adjacency: %w", err)
+	}
+	return links, nil
+}
+
 // CreatePlaces creates places.
 func (s *Storage) CreatePlaces(ctx context.Context, places []models.Place) ([]models.Place, error) {
 	insertedPlaces := make([]models.Place, 0, len(places))
@@ -183,13 +349,1688 @@ func (s *Storage) CreatePlaces(ctx context.Context, places []models.Place) ([]mo
 func (s *Storage) CreatePlace(ctx context.Context, place models.Place) (models.Place, error) {
 	var insertedPlace models.Place
 	err := s.db.GetContext(ctx, &insertedPlace,
-		`INSERT INTO places (id, x, y, width, height, is_available) VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (id) DO UPDATE SET updated_at = now()
+		`INSERT INTO places (id, event_id, x, y, width, height, is_available) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE SET event_id = COALESCE(EXCLUDED.event_id, places.event_id), updated_at = now()
 	   	RETURNING *`,
-		place.ID, place.X, place.Y, place.Width, place.Height, place.IsAvailable)
+		place.ID, place.EventID, place.X, place.Y, place.Width, place.Height, place.IsAvailable)
 	if err != nil {
 		return insertedPlace, nil // nolint: nilerr
 	}
 
 	return insertedPlace, nil
 }
+
+// GetEvent returns an event by ID.
+func (s *Storage) GetEvent(ctx context.Context, id int64) (models.Event, error) {
+	var event models.Event
+	err := s.db.GetContext(ctx, &event, `SELECT * FROM events WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return event, model.ErrNotFound
+	}
+	if err != nil {
+		return event, fmt.Errorf("failed to get event: %w", err)
+	}
+	return event, nil
+}
+
+// AttachEventToHall makes the event take place in the hall: the event gets the venue time zone
+// and a copy of the hall layout as its places, replacing places inherited from a previous hall
+// or synced from the remote API. ErrPlacesInUse is returned if any of those is held, sold or was ever ordered.
+func (s *Storage) AttachEventToHall(ctx context.Context, eventID, hallID int64, check models.Check[models.Event],
+) (models.Event, error) {
+	var event models.Event
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return event, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	if err := runCheck(ctx, tx, check, `SELECT * FROM events WHERE id = $1 FOR UPDATE`, eventID); err != nil {
+		return event, err
+	}
+	err = tx.GetContext(ctx, &event,
+		`UPDATE events SET hall_id = h.id, time_zone = v.time_zone, updated_at = now(),
+			sequence = sequence + CASE WHEN events.hall_id IS DISTINCT FROM h.id THEN 1 ELSE 0 END
+		FROM halls h JOIN venues v ON v.id = h.venue_id
+		WHERE events.id = $1 AND h.id = $2
+		RETURNING events.*`,
+		eventID, hallID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return event, model.ErrNotFound
+	}
+	if err != nil {
+		return event, fmt.Errorf("failed to attach event to hall: %w", err)
+	}
+
+	// Locking the replaced places makes holds racing the check wait for them to be deleted.
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM places WHERE event_id = $1 FOR UPDATE`, eventID); err != nil {
+		return event, fmt.Errorf("failed to lock replaced places: %w", err)
+	}
+	var inUse bool
+	if err := tx.GetContext(ctx, &inUse,
+		`SELECT EXISTS (SELECT 1 FROM places p WHERE p.event_id = $1
+			AND (NOT p.is_available OR p.hold_id IS NOT NULL
+				OR EXISTS (SELECT 1 FROM order_items i WHERE i.place_id = p.id)))`, eventID); err != nil {
+		return event, fmt.Errorf("failed to check replaced places: %w", err)
+	}
+	if inUse {
+		return event, model.ErrPlacesInUse
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM places WHERE event_id = $1`, eventID); err != nil {
+		return event, fmt.Errorf("failed to delete replaced places: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO places (event_id, hall_id, section_id, row_label, seat_number,
+			x, y, width, height, is_available, is_accessible)
+		SELECT $1, hall_id, section_id, row_label, seat_number, x, y, width, height, true, is_accessible
+		FROM places WHERE hall_id = $2 AND event_id IS NULL ORDER BY id`, eventID, hallID); err != nil {
+		return event, fmt.Errorf("failed to copy hall layout: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return event, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return event, nil
+}
+
+// CancelEvent marks an event as cancelled, cancelling it again changes nothing.
+func (s *Storage) CancelEvent(ctx context.Context, id int64, now time.Time, check models.Check[models.Event],
+) (models.Event, error) {
+	var event models.Event
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return event, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	if err := runCheck(ctx, tx, check, `SELECT * FROM events WHERE id = $1 FOR UPDATE`, id); err != nil {
+		return event, err
+	}
+	err = tx.GetContext(ctx, &event,
+		`UPDATE events SET cancelled_at = $2, sequence = sequence + 1, updated_at = $2
+		WHERE id = $1 AND cancelled_at IS NULL
+		RETURNING *`,
+		id, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Missing or cancelled already.
+		err = tx.GetContext(ctx, &event, `SELECT * FROM events WHERE id = $1`, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return event, model.ErrNotFound
+		}
+		if err != nil {
+			return event, fmt.Errorf("failed to get event: %w", err)
+		}
+		return event, nil
+	}
+	if err != nil {
+		return event, fmt.Errorf("failed to cancel event: %w", err)
+	}
+	if err := outbox(ctx, tx, domain.EventCancelled, domain.Event(event), now); err != nil {
+		return event, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return event, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return event, nil
+}
+
+// CreateVenue creates a venue.
+func (s *Storage) CreateVenue(ctx context.Context, venue models.Venue) (models.Venue, error) {
+	var insertedVenue models.Venue
+	err := s.db.GetContext(ctx, &insertedVenue,
+		`INSERT INTO venues (name, address, time_zone) VALUES ($1, $2, $3) RETURNING *`,
+		venue.Name, venue.Address, venue.TimeZone)
+	if err != nil {
+		return insertedVenue, fmt.Errorf("failed to create venue: %w", err)
+	}
+	return insertedVenue, nil
+}
+
+// GetVenues returns venues ordered by ID.
+func (s *Storage) GetVenues(ctx context.Context) ([]models.Venue, error) {
+	var venues []models.Venue
+	if err := s.db.SelectContext(ctx, &venues, `SELECT * FROM venues ORDER BY id`); err != nil {
+		return nil, fmt.Errorf("failed to get venues: %w", err)
+	}
+	return venues, nil
+}
+
+// GetVenue returns a venue by ID.
+func (s *Storage) GetVenue(ctx context.Context, id int64) (models.Venue, error) {
+	var venue models.Venue
+	err := s.db.GetContext(ctx, &venue, `SELECT * FROM venues WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return venue, model.ErrNotFound
+	}
+	if err != nil {
+		return venue, fmt.Errorf("failed to get venue: %w", err)
+	}
+	return venue, nil
+}
+
+// CreateHall creates a hall in an existing venue.
+func (s *Storage) CreateHall(ctx context.Context, hall models.Hall) (models.Hall, error) {
+	var insertedHall models.Hall
+	err := s.db.GetContext(ctx, &insertedHall,
+		`INSERT INTO halls (venue_id, name) SELECT id, $2 FROM venues WHERE id = $1 RETURNING *`,
+		hall.VenueID, hall.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return insertedHall, model.ErrNotFound
+	}
+	if err != nil {
+		return insertedHall, fmt.Errorf("failed to create hall: %w", err)
+	}
+	return insertedHall, nil
+}
+
+// GetHalls returns halls of a venue ordered by ID.
+func (s *Storage) GetHalls(ctx context.Context, venueID int64) ([]models.Hall, error) {
+	var halls []models.Hall
+	if err := s.db.SelectContext(ctx, &halls,
+		`SELECT * FROM halls WHERE venue_id = $1 ORDER BY id`, venueID); err != nil {
+		return nil, fmt.Errorf("failed to get halls: %w", err)
+	}
+	return halls, nil
+}
+
+// GetHall returns a hall by ID.
+func (s *Storage) GetHall(ctx context.Context, id int64) (models.Hall, error) {
+	var hall models.Hall
+	err := s.db.GetContext(ctx, &hall, `SELECT * FROM halls WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return hall, model.ErrNotFound
+	}
+	if err != nil {
+		return hall, fmt.Errorf("failed to get hall: %w", err)
+	}
+	return hall, nil
+}
+
+// CreateSection creates a section in an existing hall.
+func (s *Storage) CreateSection(ctx context.Context, section models.Section) (models.Section, error) {
+	var insertedSection models.Section
+	err := s.db.GetContext(ctx, &insertedSection,
+		`INSERT INTO sections (hall_id, name) SELECT id, $2 FROM halls WHERE id = $1 RETURNING *`,
+		section.HallID, section.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return insertedSection, model.ErrNotFound
+	}
+	if err != nil {
+		return insertedSection, fmt.Errorf("failed to create section: %w", err)
+	}
+	return insertedSection, nil
+}
+
+// GetSections returns sections of a hall ordered by ID.
+func (s *Storage) GetSections(ctx context.Context, hallID int64) ([]models.Section, error) {
+	var sections []models.Section
+	if err := s.db.SelectContext(ctx, &sections,
+		`SELECT * FROM sections WHERE hall_id = $1 ORDER BY id`, hallID); err != nil {
+		return nil, fmt.Errorf("failed to get sections: %w", err)
+	}
+	return sections, nil
+}
+
+// CreateHallPlaces adds places to the hall layout. Sections of the places must belong to the hall.
+func (s *Storage) CreateHallPlaces(ctx context.Context, hallID int64, places []models.Place) ([]models.Place, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	insertedPlaces := make([]models.Place, 0, len(places))
+	for _, place := range places {
+		var newPlace models.Place
+		err := tx.GetContext(ctx, &newPlace,
+			`INSERT INTO places (hall_id, section_id, row_label, seat_number,
+				x, y, width, height, is_available, is_accessible)
+			SELECT h.id, $2, $3, $4, $5, $6, $7, $8, $9, $10 FROM halls h
+			WHERE h.id = $1 AND ($2::integer IS NULL
+				OR EXISTS (SELECT 1 FROM sections WHERE id = $2 AND hall_id = h.id))
+			RETURNING *`,
+			hallID, place.SectionID, place.Row, place.Seat,
+			place.X, place.Y, place.Width, place.Height, place.IsAvailable, place.Accessible)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create hall place: %w", err)
+		}
+		insertedPlaces = append(insertedPlaces, newPlace)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return insertedPlaces, nil
+}
+
+// CreateHold holds available places of the event. Either all places are held or none:
+// if any of them is missing or not available, or the event is cancelled, ErrUnavailable is returned.
+func (s *Storage) CreateHold(ctx context.Context, hold models.Hold) (models.Hold, error) {
+	var h models.Hold
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return h, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	// The shared lock makes a concurrent cancellation either wait for the hold or be seen by it.
+	err = tx.GetContext(ctx, &h,
+		`INSERT INTO holds (event_id, customer_id, expires_at, created_at)
+		SELECT id, $2, $3, $4 FROM events WHERE id = $1 AND cancelled_at IS NULL FOR SHARE
+		RETURNING *`,
+		hold.EventID, hold.CustomerID, hold.ExpiresAt, hold.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		var exists bool
+		if err := tx.GetContext(ctx, &exists,
+			`SELECT EXISTS (SELECT 1 FROM events WHERE id = $1)`, hold.EventID); err != nil {
+			return h, fmt.Errorf("failed to get event: %w", err)
+		}
+		if exists {
+			return h, model.ErrUnavailable
+		}
+		return h, model.ErrNotFound
+	}
+	if err != nil {
+		return h, fmt.Errorf("failed to create hold: %w", err)
+	}
+
+	query, args, err := sqlx.In(
+		`UPDATE places SET is_available = false, hold_id = ?, updated_at = ?
+		WHERE event_id = ? AND is_available AND id IN (?)
+		RETURNING id`,
+		h.ID, hold.CreatedAt, hold.EventID, hold.PlaceIDs)
+	if err != nil {
+		return h, fmt.Errorf("failed to build hold query: %w", err)
+	}
+	if err := tx.SelectContext(ctx, &h.PlaceIDs, tx.Rebind(query), args...); err != nil {
+		return h, fmt.Errorf("failed to hold places: %w", err)
+	}
+	if len(h.PlaceIDs) != len(hold.PlaceIDs) {
+		return models.Hold{}, model.ErrUnavailable
+	}
+	for _, placeID := range h.PlaceIDs {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO hold_places (hold_id, place_id) VALUES ($1, $2)`, h.ID, placeID); err != nil {
+			return h, fmt.Errorf("failed to save hold place: %w", err)
+		}
+	}
+	if err := outbox(ctx, tx, domain.PlaceHeld,
+		domain.PlacesData{EventID: h.EventID, PlaceIDs: h.PlaceIDs, HoldID: h.ID}, hold.CreatedAt); err != nil {
+		return h, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return h, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return h, nil
+}
+
+// GetHold returns a hold by ID.
+func (s *Storage) GetHold(ctx context.Context, id int64) (models.Hold, error) {
+	return getHold(ctx, s.db, id)
+}
+
+func getHold(ctx context.Context, q sqlx.QueryerContext, id int64) (models.Hold, error) {
+	var h models.Hold
+	err := sqlx.GetContext(ctx, q, &h, `SELECT * FROM holds WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return h, model.ErrNotFound
+	}
+	if err != nil {
+		return h, fmt.Errorf("failed to get hold: %w", err)
+	}
+	if err := sqlx.SelectContext(ctx, q, &h.PlaceIDs,
+		`SELECT place_id FROM hold_places WHERE hold_id = $1 ORDER BY place_id`, id); err != nil {
+		return h, fmt.Errorf("failed to get hold places: %w", err)
+	}
+	return h, nil
+}
+
+// ReleaseHold makes places of an active hold available again.
+func (s *Storage) ReleaseHold(ctx context.Context, id int64, now time.Time) (models.Hold, error) {
+	var h models.Hold
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return h, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	err = tx.GetContext(ctx, &h,
+		`UPDATE holds SET released_at = $2 WHERE id = $1 AND released_at IS NULL RETURNING *`, id, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return h, model.ErrNotFound
+	}
+	if err != nil {
+		return h, fmt.Errorf("failed to release hold: %w", err)
+	}
+	if h.PlaceIDs, err = releaseHoldPlaces(ctx, tx, h, now); err != nil {
+		return h, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return h, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return h, nil
+}
+
+// ReleaseExpiredHolds releases holds expired by now.
+func (s *Storage) ReleaseExpiredHolds(ctx context.Context, now time.Time) ([]models.Hold, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	var holds []models.Hold
+	if err := tx.SelectContext(ctx, &holds,
+		`UPDATE holds SET released_at = $1 WHERE released_at IS NULL AND expires_at <= $1
+		RETURNING *`, now); err != nil {
+		return nil, fmt.Errorf("failed to release expired holds: %w", err)
+	}
+	for i := range holds {
+		if holds[i].PlaceIDs, err = releaseHoldPlaces(ctx, tx, holds[i], now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return holds, nil
+}
+
+func releaseHoldPlaces(ctx context.Context, tx *sqlx.Tx, h models.Hold, now time.Time) ([]int64, error) {
+	var ids []int64
+	if err := tx.SelectContext(ctx, &ids,
+		`UPDATE places SET is_available = true, hold_id = NULL, updated_at = $2
+		WHERE hold_id = $1
+		RETURNING id`, h.ID, now); err != nil {
+		return nil, fmt.Errorf("failed to release hold places: %w", err)
+	}
+	if len(ids) > 0 {
+		if err := outbox(ctx, tx, domain.PlaceReleased,
+			domain.PlacesData{EventID: h.EventID, PlaceIDs: ids, HoldID: h.ID}, now); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// CreatePriceCategory creates a price category of an existing event.
+func (s *Storage) CreatePriceCategory(ctx context.Context, category models.PriceCategory) (models.PriceCategory, error) {
+	var c models.PriceCategory
+	err := s.db.GetContext(ctx, &c,
+		`INSERT INTO price_categories (event_id, name, amount, currency)
+		SELECT id, $2, $3, $4 FROM events WHERE id = $1
+		RETURNING *`,
+		category.EventID, category.Name, category.Amount, category.Currency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, model.ErrNotFound
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to create price category: %w", err)
+	}
+	return c, nil
+}
+
+// GetPriceCategories returns price categories of an event ordered by ID.
+func (s *Storage) GetPriceCategories(ctx context.Context, eventID int64) ([]models.PriceCategory, error) {
+	var categories []models.PriceCategory
+	if err := s.db.SelectContext(ctx, &categories,
+		`SELECT * FROM price_categories WHERE event_id = $1 ORDER BY id`, eventID); err != nil {
+		return nil, fmt.Errorf("failed to get price categories: %w", err)
+	}
+	return categories, nil
+}
+
+// UpdatePriceCategory changes name and price of a price category.
+func (s *Storage) UpdatePriceCategory(ctx context.Context, category models.PriceCategory,
+	check models.Check[models.PriceCategory],
+) (models.PriceCategory, error) {
+	var c models.PriceCategory
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return c, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	if err := runCheck(ctx, tx, check, `SELECT * FROM price_categories WHERE id = $1 FOR UPDATE`,
+		category.ID); err != nil {
+		return c, err
+	}
+	err = tx.GetContext(ctx, &c,
+		`UPDATE price_categories SET name = $2, amount = $3, currency = $4, updated_at = now()
+		WHERE id = $1
+		RETURNING *`,
+		category.ID, category.Name, category.Amount, category.Currency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, model.ErrNotFound
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to update price category: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return c, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return c, nil
+}
+
+// DeletePriceCategory deletes a price category, its places become unpriced.
+func (s *Storage) DeletePriceCategory(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM price_categories WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete price category: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return model.ErrNotFound
+	}
+	return nil
+}
+
+// AssignPrices applies the price map assignments to the event places in order
+// and returns the number of places changed. Categories must belong to the event.
+func (s *Storage) AssignPrices(ctx context.Context, eventID int64, assignments []models.PriceAssignment,
+	check models.Check[models.Event],
+) (int64, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	if err := runCheck(ctx, tx, check, `SELECT * FROM events WHERE id = $1 FOR UPDATE`, eventID); err != nil {
+		return 0, err
+	}
+	var n int64
+	for _, a := range assignments {
+		category := sql.NullInt64{Int64: a.CategoryID, Valid: a.CategoryID != 0}
+		if category.Valid {
+			var exists bool
+			if err := tx.GetContext(ctx, &exists,
+				`SELECT EXISTS (SELECT 1 FROM price_categories WHERE id = $1 AND event_id = $2)`,
+				a.CategoryID, eventID); err != nil {
+				return 0, fmt.Errorf("failed to check price category: %w", err)
+			}
+			if !exists {
+				return 0, model.ErrNotFound
+			}
+		}
+		// sqlx.In can't expand empty lists, an impossible ID keeps the query valid.
+		placeIDs, sectionIDs := append([]int64{0}, a.PlaceIDs...), append([]int64{0}, a.SectionIDs...)
+		query, args, err := sqlx.In(
+			`UPDATE places SET price_category_id = ?, updated_at = now()
+			WHERE event_id = ? AND (id IN (?) OR section_id IN (?))`,
+			category, eventID, placeIDs, sectionIDs)
+		if err != nil {
+			return 0, fmt.Errorf("failed to build price map query: %w", err)
+		}
+		res, err := tx.ExecContext(ctx, tx.Rebind(query), args...)
+		if err != nil {
+			return 0, fmt.Errorf("failed to assign prices: %w", err)
+		}
+		if rows, err := res.RowsAffected(); err == nil {
+			n += rows
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return n, nil
+}
+
+// CreateOrder sells the places of an active hold at the prices of the order items
+// and redeems the promotions of the order discounts.
+// The hold ends and its places stay unavailable. ErrUnavailable is returned if the hold is not active,
+// ErrLimitReached if a promotion has been redeemed as many times as allowed.
+func (s *Storage) CreateOrder(ctx context.Context, order models.Order) (models.Order, error) {
+	var o models.Order
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return o, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE holds SET released_at = $2 WHERE id = $1 AND released_at IS NULL AND expires_at > $2`,
+		order.HoldID, order.CreatedAt)
+	if err != nil {
+		return o, fmt.Errorf("failed to end hold: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return o, model.ErrUnavailable
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE places SET hold_id = NULL, updated_at = $2 WHERE hold_id = $1`,
+		order.HoldID, order.CreatedAt); err != nil {
+		return o, fmt.Errorf("failed to sell places: %w", err)
+	}
+
+	err = tx.GetContext(ctx, &o,
+		`INSERT INTO orders (event_id, hold_id, status, currency, subtotal, total, customer_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING *`,
+		order.EventID, order.HoldID, order.Status, order.Currency, order.Subtotal, order.Total,
+		order.CustomerID, order.CreatedAt)
+	if err != nil {
+		return o, fmt.Errorf("failed to create order: %w", err)
+	}
+	for _, item := range order.Items {
+		item.OrderID, item.EventID = o.ID, o.EventID
+		newItem, err := createOrderItem(ctx, tx, item)
+		if err != nil {
+			return o, err
+		}
+		o.Items = append(o.Items, newItem)
+	}
+	for _, d := range order.Discounts {
+		if err := redeemPromotion(ctx, tx, d.PromotionID, o); err != nil {
+			return o, err
+		}
+		var newDiscount models.OrderDiscount
+		err := tx.GetContext(ctx, &newDiscount,
+			`INSERT INTO order_discounts (order_id, promotion_id, code, name, description, amount)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING *`,
+			o.ID, d.PromotionID, d.Code, d.Name, d.Description, d.Amount)
+		if err != nil {
+			return o, fmt.Errorf("failed to create order discount: %w", err)
+		}
+		o.Discounts = append(o.Discounts, newDiscount)
+	}
+	if err := audit(ctx, tx, "order", o.ID, models.AuditOrderCreated, map[string]any{
+		"holdId": o.HoldID.Int64, "currency": o.Currency, "total": o.Total, "items": len(o.Items),
+	}, o.CreatedAt); err != nil {
+		return o, err
+	}
+	for _, places := range domain.ItemPlaces(o.ID, o.Items) {
+		if err := outbox(ctx, tx, domain.PlaceSold, places, o.CreatedAt); err != nil {
+			return o, err
+		}
+	}
+	if err := outbox(ctx, tx, domain.OrderPaid, domain.Order(o), o.CreatedAt); err != nil {
+		return o, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return o, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return o, nil
+}
+
+func createOrderItem(ctx context.Context, tx *sqlx.Tx, item models.OrderItem) (models.OrderItem, error) {
+	var newItem models.OrderItem
+	err := tx.GetContext(ctx, &newItem,
+		`INSERT INTO order_items (order_id, event_id, place_id, price_category_id, amount, discount, status, exchanged_from)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING *`,
+		item.OrderID, item.EventID, item.PlaceID, item.PriceCategoryID, item.Amount, item.Discount,
+		models.ItemIssued, item.ExchangedFrom)
+	if err != nil {
+		return newItem, fmt.Errorf("failed to create order item: %w", err)
+	}
+	return newItem, nil
+}
+
+// audit appends a record to the audit trail of an entity.
+func audit(ctx context.Context, tx *sqlx.Tx, entity string, id int64, action string, details any, now time.Time,
+) error {
+	data, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit details: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO audit_log (entity, entity_id, action, details, created_at) VALUES ($1, $2, $3, $4, $5)`,
+		entity, id, action, string(data), now); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// runCheck locks the record query selects by id for the rest of tx and runs check on it, so nothing
+// changes the record until tx ends. Nothing is locked without a check.
+func runCheck[T any](ctx context.Context, tx *sqlx.Tx, check models.Check[T], query string, id int64) error {
+	if check == nil {
+		return nil
+	}
+	var record T
+	err := tx.GetContext(ctx, &record, query, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock record: %w", err)
+	}
+	return check(record)
+}
+
+// outbox writes a domain event in tx, it is dispatched once tx commits.
+func outbox(ctx context.Context, tx *sqlx.Tx, typ string, data any, now time.Time) error {
+	e, err := domain.New(typ, data, now)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO domain_events (type, payload, created_at) VALUES ($1, $2, $3)`,
+		e.Type, string(e.Payload), e.CreatedAt); err != nil {
+		return fmt.Errorf("failed to write %s event: %w", typ, err)
+	}
+	return nil
+}
+
+// RefundOrder refunds issued items of an order and puts their places back on sale.
+// ErrUnavailable is returned if any of the items is not issued.
+func (s *Storage) RefundOrder(ctx context.Context, refund models.Refund) (models.Refund, error) {
+	var r models.Refund
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return r, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	if err := lockOrder(ctx, tx, refund.OrderID); err != nil {
+		return r, err
+	}
+	err = tx.GetContext(ctx, &r,
+		`INSERT INTO refunds (order_id, amount, fee, reason, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING *`,
+		refund.OrderID, refund.Amount, refund.Fee, refund.Reason, refund.CreatedAt)
+	if err != nil {
+		return r, fmt.Errorf("failed to create refund: %w", err)
+	}
+	retired, err := retireItems(ctx, tx, refund.OrderID, refund.ItemIDs, models.ItemRefunded,
+		sql.NullInt64{Int64: r.ID, Valid: true}, refund.CreatedAt)
+	if err != nil {
+		return r, err
+	}
+	r.ItemIDs = refund.ItemIDs
+
+	status, err := orderStatus(ctx, tx, refund.OrderID)
+	if err != nil {
+		return r, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE orders SET refunded = refunded + $2, status = $3, updated_at = $4 WHERE id = $1`,
+		refund.OrderID, r.Amount, status, r.CreatedAt); err != nil {
+		return r, fmt.Errorf("failed to update order: %w", err)
+	}
+	if err := audit(ctx, tx, "order", r.OrderID, models.AuditOrderRefunded, map[string]any{
+		"refundId": r.ID, "itemIds": r.ItemIDs, "amount": r.Amount, "fee": r.Fee, "reason": r.Reason,
+	}, r.CreatedAt); err != nil {
+		return r, err
+	}
+	if err := outboxOrder(ctx, tx, domain.OrderRefunded, r.OrderID, retired, r.CreatedAt); err != nil {
+		return r, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return r, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return r, nil
+}
+
+// ExchangeOrderItems replaces issued items of an order with the exchange items, the places of the old items
+// go back on sale. ErrUnavailable is returned if any old item is not issued or any new place is not available.
+func (s *Storage) ExchangeOrderItems(ctx context.Context, exchange models.Exchange) (models.Exchange, error) {
+	var e models.Exchange
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return e, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	if err := lockOrder(ctx, tx, exchange.OrderID); err != nil {
+		return e, err
+	}
+	err = tx.GetContext(ctx, &e,
+		`INSERT INTO exchanges (order_id, event_id, difference, fee, created_at) VALUES ($1, $2, $3, $4, $5)
+		RETURNING *`,
+		exchange.OrderID, exchange.EventID, exchange.Difference, exchange.Fee, exchange.CreatedAt)
+	if err != nil {
+		return e, fmt.Errorf("failed to create exchange: %w", err)
+	}
+	retired, err := retireItems(ctx, tx, exchange.OrderID, exchange.ItemIDs, models.ItemExchanged,
+		sql.NullInt64{}, exchange.CreatedAt)
+	if err != nil {
+		return e, err
+	}
+	e.ItemIDs = exchange.ItemIDs
+
+	placeIDs := make([]int64, 0, len(exchange.Items))
+	for _, item := range exchange.Items {
+		placeIDs = append(placeIDs, item.PlaceID)
+	}
+	query, args, err := sqlx.In(
+		`UPDATE places SET is_available = false, updated_at = ?
+		WHERE event_id = ? AND is_available AND id IN (?)
+		RETURNING id`,
+		exchange.CreatedAt, exchange.EventID, placeIDs)
+	if err != nil {
+		return e, fmt.Errorf("failed to build exchange query: %w", err)
+	}
+	var taken []int64
+	if err := tx.SelectContext(ctx, &taken, tx.Rebind(query), args...); err != nil {
+		return e, fmt.Errorf("failed to take places: %w", err)
+	}
+	if len(taken) != len(placeIDs) {
+		return e, model.ErrUnavailable
+	}
+	for _, item := range exchange.Items {
+		item.OrderID, item.EventID = exchange.OrderID, exchange.EventID
+		newItem, err := createOrderItem(ctx, tx, item)
+		if err != nil {
+			return e, err
+		}
+		e.Items = append(e.Items, newItem)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE orders SET total = total + $2, updated_at = $3 WHERE id = $1`,
+		exchange.OrderID, e.Difference+e.Fee, e.CreatedAt); err != nil {
+		return e, fmt.Errorf("failed to update order: %w", err)
+	}
+	if err := audit(ctx, tx, "order", e.OrderID, models.AuditOrderExchanged, map[string]any{
+		"exchangeId": e.ID, "eventId": e.EventID, "itemIds": e.ItemIDs, "placeIds": placeIDs,
+		"difference": e.Difference, "fee": e.Fee,
+	}, e.CreatedAt); err != nil {
+		return e, err
+	}
+	if err := outbox(ctx, tx, domain.PlaceSold,
+		domain.PlacesData{EventID: e.EventID, PlaceIDs: placeIDs, OrderID: e.OrderID}, e.CreatedAt); err != nil {
+		return e, err
+	}
+	if err := outboxOrder(ctx, tx, domain.OrderExchanged, e.OrderID, retired, e.CreatedAt); err != nil {
+		return e, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return e, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return e, nil
+}
+
+// outboxOrder writes the release of the places of retired items and an order event with the state of the order.
+func outboxOrder(ctx context.Context, tx *sqlx.Tx, typ string, orderID int64, retired []models.OrderItem,
+	now time.Time,
+) error {
+	for _, places := range domain.ItemPlaces(orderID, retired) {
+		if err := outbox(ctx, tx, domain.PlaceReleased, places, now); err != nil {
+			return err
+		}
+	}
+	o, err := getOrder(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	return outbox(ctx, tx, typ, domain.Order(o), now)
+}
+
+// lockOrder locks an order until the end of tx, so its items change one request at a time.
+func lockOrder(ctx context.Context, tx *sqlx.Tx, id int64) error {
+	var locked int64
+	err := tx.GetContext(ctx, &locked, `SELECT id FROM orders WHERE id = $1 FOR UPDATE`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock order: %w", err)
+	}
+	return nil
+}
+
+// retireItems moves issued unused items of an order to status, puts their places back on sale
+// and returns the items.
+func retireItems(ctx context.Context, tx *sqlx.Tx, orderID int64, itemIDs []int64, status models.OrderItemStatus,
+	refundID sql.NullInt64, now time.Time,
+) ([]models.OrderItem, error) {
+	query, args, err := sqlx.In(
+		`UPDATE order_items SET status = ?, refund_id = ?
+		WHERE order_id = ? AND status = ? AND used_at IS NULL AND id IN (?)
+		RETURNING *`,
+		status, refundID, orderID, models.ItemIssued, itemIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build order items query: %w", err)
+	}
+	var items []models.OrderItem
+	if err := tx.SelectContext(ctx, &items, tx.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to update order items: %w", err)
+	}
+	if len(items) != len(itemIDs) {
+		return nil, model.ErrUnavailable
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+	placeIDs := make([]int64, 0, len(items))
+	for _, item := range items {
+		placeIDs = append(placeIDs, item.PlaceID)
+	}
+	query, args, err = sqlx.In(
+		`UPDATE places SET is_available = true, hold_id = NULL, updated_at = ? WHERE id IN (?)`, now, placeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build places query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to return places: %w", err)
+	}
+	return items, nil
+}
+
+func orderStatus(ctx context.Context, tx *sqlx.Tx, orderID int64) (models.OrderStatus, error) {
+	var items []models.OrderItem
+	if err := tx.SelectContext(ctx, &items, `SELECT * FROM order_items WHERE order_id = $1`, orderID); err != nil {
+		return "", fmt.Errorf("failed to get order items: %w", err)
+	}
+	return models.OrderStatusOf(items), nil
+}
+
+// GetOrderItem returns an order item.
+func (s *Storage) GetOrderItem(ctx context.Context, id int64) (models.OrderItem, error) {
+	var item models.OrderItem
+	err := s.db.GetContext(ctx, &item, `SELECT * FROM order_items WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return item, model.ErrNotFound
+	}
+	if err != nil {
+		return item, fmt.Errorf("failed to get order item: %w", err)
+	}
+	return item, nil
+}
+
+// UseOrderItem marks an issued order item as used at the door. The item is returned with ErrUnavailable
+// if it is not issued and with ErrAlreadyUsed if it has been used.
+func (s *Storage) UseOrderItem(ctx context.Context, id int64, now time.Time) (models.OrderItem, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return models.OrderItem{}, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	var item models.OrderItem
+	err = tx.GetContext(ctx, &item,
+		`UPDATE order_items SET used_at = $2 WHERE id = $1 AND status = $3 AND used_at IS NULL RETURNING *`,
+		id, now, models.ItemIssued)
+	if errors.Is(err, sql.ErrNoRows) {
+		if item, err = s.GetOrderItem(ctx, id); err != nil {
+			return item, err
+		}
+		if item.Status != models.ItemIssued {
+			return item, model.ErrUnavailable
+		}
+		return item, model.ErrAlreadyUsed
+	}
+	if err != nil {
+		return item, fmt.Errorf("failed to use order item: %w", err)
+	}
+	if err := audit(ctx, tx, "order", item.OrderID, models.AuditTicketScanned, map[string]any{
+		"itemId": item.ID, "eventId": item.EventID, "placeId": item.PlaceID,
+	}, now); err != nil {
+		return item, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return item, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return item, nil
+}
+
+// GetAuditTrail returns the audit trail of an entity oldest first.
+func (s *Storage) GetAuditTrail(ctx context.Context, entity string, id int64) ([]models.AuditRecord, error) {
+	var records []models.AuditRecord
+	if err := s.db.SelectContext(ctx, &records,
+		`SELECT * FROM audit_log WHERE entity = $1 AND entity_id = $2 ORDER BY id`, entity, id); err != nil {
+		return nil, fmt.Errorf("failed to get audit trail: %w", err)
+	}
+	return records, nil
+}
+
+// redeemPromotion counts a use of the promotion by the order within its limits.
+// The promotion row stays locked until the end of tx, so concurrent orders can't exceed the limits.
+func redeemPromotion(ctx context.Context, tx *sqlx.Tx, promotionID int64, o models.Order) error {
+	var perCustomer int
+	err := tx.GetContext(ctx, &perCustomer,
+		`UPDATE promotions SET uses = uses + 1
+		WHERE id = $1 AND (max_uses = 0 OR uses < max_uses)
+		RETURNING max_uses_per_customer`,
+		promotionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ErrLimitReached
+	}
+	if err != nil {
+		return fmt.Errorf("failed to redeem promotion: %w", err)
+	}
+	if perCustomer > 0 {
+		var n int
+		if err := tx.GetContext(ctx, &n,
+			`SELECT count(*) FROM promotion_redemptions WHERE promotion_id = $1 AND customer_id = $2`,
+			promotionID, o.CustomerID); err != nil {
+			return fmt.Errorf("failed to count redemptions: %w", err)
+		}
+		if n >= perCustomer {
+			return model.ErrLimitReached
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO promotion_redemptions (promotion_id, order_id, customer_id, created_at) VALUES ($1, $2, $3, $4)`,
+		promotionID, o.ID, o.CustomerID, o.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create redemption: %w", err)
+	}
+	return nil
+}
+
+// GetOrder returns an order with its items and discounts.
+func (s *Storage) GetOrder(ctx context.Context, id int64) (models.Order, error) {
+	return getOrder(ctx, s.db, id)
+}
+
+func getOrder(ctx context.Context, q sqlx.QueryerContext, id int64) (models.Order, error) {
+	var o models.Order
+	err := sqlx.GetContext(ctx, q, &o, `SELECT * FROM orders WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return o, model.ErrNotFound
+	}
+	if err != nil {
+		return o, fmt.Errorf("failed to get order: %w", err)
+	}
+	if err := sqlx.SelectContext(ctx, q, &o.Items,
+		`SELECT * FROM order_items WHERE order_id = $1 ORDER BY id`, id); err != nil {
+		return o, fmt.Errorf("failed to get order items: %w", err)
+	}
+	if err := sqlx.SelectContext(ctx, q, &o.Discounts,
+		`SELECT * FROM order_discounts WHERE order_id = $1 ORDER BY id`, id); err != nil {
+		return o, fmt.Errorf("failed to get order discounts: %w", err)
+	}
+	return o, nil
+}
+
+// CreatePromotion creates a promotion, ErrAlreadyExists is returned if its code is taken.
+func (s *Storage) CreatePromotion(ctx context.Context, promotion models.Promotion) (models.Promotion, error) {
+	var p models.Promotion
+	err := s.db.GetContext(ctx, &p,
+		`INSERT INTO promotions (code, name, kind, percent, amount, currency, buy_n, get_m,
+			show_id, event_id, price_category_id, valid_from, valid_to, max_uses, max_uses_per_customer)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (code) WHERE code <> '' DO NOTHING
+		RETURNING *`,
+		promotion.Code, promotion.Name, promotion.Kind, promotion.Percent, promotion.Amount, promotion.Currency,
+		promotion.BuyN, promotion.GetM, promotion.ShowID, promotion.EventID, promotion.PriceCategoryID,
+		promotion.ValidFrom, promotion.ValidTo, promotion.MaxUses, promotion.MaxUsesPerCustomer)
+	if errors.Is(err, sql.ErrNoRows) {
+		return p, model.ErrAlreadyExists
+	}
+	if err != nil {
+		return p, fmt.Errorf("failed to create promotion: %w", err)
+	}
+	return p, nil
+}
+
+// GetPromotions returns all promotions ordered by ID.
+func (s *Storage) GetPromotions(ctx context.Context) ([]models.Promotion, error) {
+	var promotions []models.Promotion
+	if err := s.db.SelectContext(ctx, &promotions, `SELECT * FROM promotions ORDER BY id`); err != nil {
+		return nil, fmt.Errorf("failed to get promotions: %w", err)
+	}
+	return promotions, nil
+}
+
+func (s *Storage) GetPromotion(ctx context.Context, id int64) (models.Promotion, error) {
+	var p models.Promotion
+	err := s.db.GetContext(ctx, &p, `SELECT * FROM promotions WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return p, model.ErrNotFound
+	}
+	if err != nil {
+		return p, fmt.Errorf("failed to get promotion: %w", err)
+	}
+	return p, nil
+}
+
+// DeactivatePromotion stops a promotion, its redemptions are kept.
+func (s *Storage) DeactivatePromotion(ctx context.Context, id int64, now time.Time) (models.Promotion, error) {
+	var p models.Promotion
+	err := s.db.GetContext(ctx, &p,
+		`UPDATE promotions SET is_active = false, updated_at = $2 WHERE id = $1 RETURNING *`, id, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return p, model.ErrNotFound
+	}
+	if err != nil {
+		return p, fmt.Errorf("failed to deactivate promotion: %w", err)
+	}
+	return p, nil
+}
+
+// GetCheckoutPromotions returns active promotions without a code and promotions with one of the codes
+// ordered by ID.
+func (s *Storage) GetCheckoutPromotions(ctx context.Context, codes []string) ([]models.Promotion, error) {
+	// sqlx.In can't expand empty lists, the empty code never matches a promotion with a code.
+	query, args, err := sqlx.In(
+		`SELECT * FROM promotions WHERE (code = '' AND is_active) OR (code <> '' AND code IN (?)) ORDER BY id`,
+		append([]string{""}, codes...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build promotions query: %w", err)
+	}
+	var promotions []models.Promotion
+	if err := s.db.SelectContext(ctx, &promotions, s.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to get promotions: %w", err)
+	}
+	return promotions, nil
+}
+
+// GetCustomerRedemptions counts redemptions of the customer by promotion ID.
+func (s *Storage) GetCustomerRedemptions(ctx context.Context, customerID int64) (map[int64]int, error) {
+	var rows []struct {
+		PromotionID int64 `db:"promotion_id"`
+		N           int   `db:"n"`
+	}
+	if err := s.db.SelectContext(ctx, &rows,
+		`SELECT promotion_id, count(*) AS n FROM promotion_redemptions WHERE customer_id = $1 GROUP BY promotion_id`,
+		customerID); err != nil {
+		return nil, fmt.Errorf("failed to count redemptions: %w", err)
+	}
+	res := make(map[int64]int, len(rows))
+	for _, r := range rows {
+		res[r.PromotionID] = r.N
+	}
+	return res, nil
+}
+
+// isUniqueViolation reports whether err is a violation of a unique constraint.
+func isUniqueViolation(err error) bool {
+	var pgErr pgx.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// CreateCustomer creates a customer, ErrAlreadyExists is returned if the email is taken.
+func (s *Storage) CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error) {
+	var c models.Customer
+	err := s.db.GetContext(ctx, &c,
+		`INSERT INTO customers (email, name, phone, marketing_email, marketing_sms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING *`,
+		customer.Email, customer.Name, customer.Phone, customer.MarketingEmail, customer.MarketingSMS,
+		customer.CreatedAt)
+	if isUniqueViolation(err) {
+		return c, model.ErrAlreadyExists
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to create customer: %w", err)
+	}
+	return c, nil
+}
+
+func (s *Storage) GetCustomer(ctx context.Context, id int64) (models.Customer, error) {
+	var c models.Customer
+	err := s.db.GetContext(ctx, &c, `SELECT * FROM customers WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, model.ErrNotFound
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to get customer: %w", err)
+	}
+	return c, nil
+}
+
+// UpdateCustomer changes contacts and consents of a customer that is not erased.
+func (s *Storage) UpdateCustomer(ctx context.Context, customer models.Customer, check models.Check[models.Customer],
+) (models.Customer, error) {
+	var c models.Customer
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return c, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	if err := runCheck(ctx, tx, check, `SELECT * FROM customers WHERE id = $1 AND erased_at IS NULL FOR UPDATE`,
+		customer.ID); err != nil {
+		return c, err
+	}
+	err = tx.GetContext(ctx, &c,
+		`UPDATE customers SET email = $2, name = $3, phone = $4, marketing_email = $5, marketing_sms = $6,
+			updated_at = $7
+		WHERE id = $1 AND erased_at IS NULL
+		RETURNING *`,
+		customer.ID, customer.Email, customer.Name, customer.Phone, customer.MarketingEmail, customer.MarketingSMS,
+		customer.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, model.ErrNotFound
+	}
+	if isUniqueViolation(err) {
+		return c, model.ErrAlreadyExists
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to update customer: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return c, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return c, nil
+}
+
+// EraseCustomer drops the personal data of a customer, its holds and orders are kept.
+func (s *Storage) EraseCustomer(ctx context.Context, id int64, now time.Time) (models.Customer, error) {
+	var c models.Customer
+	err := s.db.GetContext(ctx, &c,
+		`UPDATE customers SET email = '', name = '', phone = '', marketing_email = false, marketing_sms = false,
+			updated_at = $2, erased_at = coalesce(erased_at, $2)
+		WHERE id = $1
+		RETURNING *`,
+		id, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, model.ErrNotFound
+	}
+	if err != nil {
+		return c, fmt.Errorf("failed to erase customer: %w", err)
+	}
+	return c, nil
+}
+
+// GetCustomerOrders returns orders of a customer with their items and discounts ordered by ID.
+func (s *Storage) GetCustomerOrders(ctx context.Context, customerID int64) ([]models.Order, error) {
+	var orders []models.Order
+	if err := s.db.SelectContext(ctx, &orders,
+		`SELECT * FROM orders WHERE customer_id = $1 ORDER BY id`, customerID); err != nil {
+		return nil, fmt.Errorf("failed to get customer orders: %w", err)
+	}
+	for i := range orders {
+		if err := s.db.SelectContext(ctx, &orders[i].Items,
+			`SELECT * FROM order_items WHERE order_id = $1 ORDER BY id`, orders[i].ID); err != nil {
+			return nil, fmt.Errorf("failed to get order items: %w", err)
+		}
+		if err := s.db.SelectContext(ctx, &orders[i].Discounts,
+			`SELECT * FROM order_discounts WHERE order_id = $1 ORDER BY id`, orders[i].ID); err != nil {
+			return nil, fmt.Errorf("failed to get order discounts: %w", err)
+		}
+	}
+	return orders, nil
+}
+
+// GetCustomerHolds returns holds of a customer with their places ordered by ID.
+func (s *Storage) GetCustomerHolds(ctx context.Context, customerID int64) ([]models.Hold, error) {
+	var holds []models.Hold
+	if err := s.db.SelectContext(ctx, &holds,
+		`SELECT * FROM holds WHERE customer_id = $1 ORDER BY id`, customerID); err != nil {
+		return nil, fmt.Errorf("failed to get customer holds: %w", err)
+	}
+	for i := range holds {
+		if err := s.db.SelectContext(ctx, &holds[i].PlaceIDs,
+			`SELECT place_id FROM hold_places WHERE hold_id = $1 ORDER BY place_id`, holds[i].ID); err != nil {
+			return nil, fmt.Errorf("failed to get hold places: %w", err)
+		}
+	}
+	return holds, nil
+}
+
+// CreateWaitlistEntry puts a customer on the waitlist of an existing event.
+// ErrAlreadyExists is returned if the customer is already waiting for the event.
+func (s *Storage) CreateWaitlistEntry(ctx context.Context, entry models.WaitlistEntry) (models.WaitlistEntry, error) {
+	var e models.WaitlistEntry
+	err := s.db.GetContext(ctx, &e,
+		`INSERT INTO waitlist_entries (event_id, customer_id, quantity, max_price, status, created_at)
+		SELECT id, $2, $3, $4, $5, $6 FROM events WHERE id = $1
+		RETURNING *`,
+		entry.EventID, entry.CustomerID, entry.Quantity, entry.MaxPrice, models.WaitlistWaiting, entry.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return e, model.ErrNotFound
+	}
+	if isUniqueViolation(err) {
+		return e, model.ErrAlreadyExists
+	}
+	if err != nil {
+		return e, fmt.Errorf("failed to create waitlist entry: %w", err)
+	}
+	return e, nil
+}
+
+func (s *Storage) GetWaitlistEntry(ctx context.Context, id int64) (models.WaitlistEntry, error) {
+	var e models.WaitlistEntry
+	err := s.db.GetContext(ctx, &e, `SELECT * FROM waitlist_entries WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return e, model.ErrNotFound
+	}
+	if err != nil {
+		return e, fmt.Errorf("failed to get waitlist entry: %w", err)
+	}
+	return e, nil
+}
+
+// GetWaitlist returns waitlist entries of an event in the order they joined.
+func (s *Storage) GetWaitlist(ctx context.Context, eventID int64) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	if err := s.db.SelectContext(ctx, &entries,
+		`SELECT * FROM waitlist_entries WHERE event_id = $1 ORDER BY created_at, id`, eventID); err != nil {
+		return nil, fmt.Errorf("failed to get waitlist: %w", err)
+	}
+	return entries, nil
+}
+
+// GetWaitingEntries returns entries of all events still waiting for places in the order they joined.
+func (s *Storage) GetWaitingEntries(ctx context.Context) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	if err := s.db.SelectContext(ctx, &entries,
+		`SELECT * FROM waitlist_entries WHERE status = $1 ORDER BY created_at, id`, models.WaitlistWaiting); err != nil {
+		return nil, fmt.Errorf("failed to get waiting entries: %w", err)
+	}
+	return entries, nil
+}
+
+// OfferWaitlistEntry records the hold offered to a waiting entry, ErrNotFound is returned unless it waits.
+func (s *Storage) OfferWaitlistEntry(ctx context.Context, id, holdID int64, now time.Time,
+) (models.WaitlistEntry, error) {
+	var e models.WaitlistEntry
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return e, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	err = tx.GetContext(ctx, &e,
+		`UPDATE waitlist_entries SET status = $2, hold_id = $3, offered_at = $4, updated_at = $4
+		WHERE id = $1 AND status = $5
+		RETURNING *`,
+		id, models.WaitlistOffered, holdID, now, models.WaitlistWaiting)
+	if errors.Is(err, sql.ErrNoRows) {
+		return e, model.ErrNotFound
+	}
+	if err != nil {
+		return e, fmt.Errorf("failed to offer waitlist entry: %w", err)
+	}
+	data := domain.WaitlistData{EntryID: e.ID, EventID: e.EventID, CustomerID: e.CustomerID, HoldID: holdID}
+	hold, err := getHold(ctx, tx, holdID)
+	switch {
+	case err == nil:
+		data.PlaceIDs = hold.PlaceIDs
+		data.ExpiresAt = hold.ExpiresAt.UTC()
+	case !errors.Is(err, model.ErrNotFound):
+		return e, err
+	}
+	if err := outbox(ctx, tx, domain.WaitlistOffered, data, now); err != nil {
+		return e, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return e, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return e, nil
+}
+
+// CancelWaitlistEntry takes a waiting or offered entry off the waitlist.
+func (s *Storage) CancelWaitlistEntry(ctx context.Context, id int64, now time.Time) (models.WaitlistEntry, error) {
+	var e models.WaitlistEntry
+	err := s.db.GetContext(ctx, &e,
+		`UPDATE waitlist_entries SET status = $2, updated_at = $3
+		WHERE id = $1 AND status <> $2
+		RETURNING *`,
+		id, models.WaitlistCancelled, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return e, model.ErrNotFound
+	}
+	if err != nil {
+		return e, fmt.Errorf("failed to cancel waitlist entry: %w", err)
+	}
+	return e, nil
+}
+
+// GetIdempotencyKey returns a non-expired idempotency key.
+func (s *Storage) GetIdempotencyKey(ctx context.Context, key string) (models.IdempotencyKey, error) {
+	var k models.IdempotencyKey
+	err := s.db.GetContext(ctx, &k,
+		`SELECT * FROM idempotency_keys WHERE key = $1 AND expires_at > now()`, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return k, model.ErrNotFound
+	}
+	if err != nil {
+		return k, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return k, nil
+}
+
+// CreateIdempotencyKey reserves an idempotency key, replacing an expired one.
+func (s *Storage) CreateIdempotencyKey(ctx context.Context, key models.IdempotencyKey) (models.IdempotencyKey, error) {
+	var k models.IdempotencyKey
+	err := s.db.GetContext(ctx, &k,
+		`INSERT INTO idempotency_keys (key, method, path, fingerprint, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (key) DO UPDATE SET
+			method = EXCLUDED.method, path = EXCLUDED.path, fingerprint = EXCLUDED.fingerprint,
+			status_code = 0, content_type = '', response_headers = '{}', response_body = NULL,
+			created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at, completed_at = NULL
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+		RETURNING *`,
+		key.Key, key.Method, key.Path, key.Fingerprint, key.CreatedAt, key.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return k, model.ErrAlreadyExists
+	}
+	if err != nil {
+		return k, fmt.Errorf("failed to create idempotency key: %w", err)
+	}
+	return k, nil
+}
+
+// CompleteIdempotencyKey stores the response of a reserved idempotency key.
+func (s *Storage) CompleteIdempotencyKey(ctx context.Context, key models.IdempotencyKey) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE idempotency_keys
+		SET status_code = $2, content_type = $3, response_headers = $4, response_body = $5, completed_at = now()
+		WHERE key = $1`,
+		key.Key, key.StatusCode, key.ContentType, key.ResponseHeaders, key.ResponseBody)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return model.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteIdempotencyKey deletes an idempotency key.
+func (s *Storage) DeleteIdempotencyKey(ctx context.Context, key string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys deletes idempotency keys expired by now.
+func (s *Storage) DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// CreateQuarantinedItems saves items that failed validation.
+func (s *Storage) CreateQuarantinedItems(ctx context.Context, items []models.QuarantinedItem) ([]models.QuarantinedItem, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	insertedItems := make([]models.QuarantinedItem, 0, len(items))
+	for _, item := range items {
+		var newItem models.QuarantinedItem
+		err := tx.GetContext(ctx, &newItem,
+			`INSERT INTO quarantined_items (entity, source, payload, errors) VALUES ($1, $2, $3, $4)
+			RETURNING *`,
+			item.Entity, item.Source, item.Payload, item.Errors)
+		if err != nil {
+			return nil, fmt.Errorf("failed to quarantine item: %w", err)
+		}
+		insertedItems = append(insertedItems, newItem)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return insertedItems, nil
+}
+
+// GetQuarantinedItems returns quarantined items.
+func (s *Storage) GetQuarantinedItems(ctx context.Context) ([]models.QuarantinedItem, error) {
+	var items []models.QuarantinedItem
+	query := `SELECT id, entity, source, payload, errors, created_at FROM quarantined_items ORDER BY id`
+	if err := s.db.SelectContext(ctx, &items, query); err != nil {
+		return nil, fmt.Errorf("failed to get quarantined items: %w", err)
+	}
+	return items, nil
+}
+
+// CreateWebhook registers a webhook with the event types it receives.
+func (s *Storage) CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error) {
+	var w models.Webhook
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return w, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	if err := tx.GetContext(ctx, &w,
+		`INSERT INTO webhooks (url, secret, created_at) VALUES ($1, $2, $3) RETURNING *`,
+		webhook.URL, webhook.Secret, webhook.CreatedAt); err != nil {
+		return w, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	for _, typ := range webhook.EventTypes {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO webhook_event_types (webhook_id, event_type) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			w.ID, typ); err != nil {
+			return w, fmt.Errorf("failed to save webhook event type: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return w, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	w.EventTypes = webhook.EventTypes
+	return w, nil
+}
+
+// GetWebhooks returns webhooks ordered by ID.
+func (s *Storage) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	if err := s.db.SelectContext(ctx, &webhooks, `SELECT * FROM webhooks ORDER BY id`); err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	var types []struct {
+		WebhookID int64  `db:"webhook_id"`
+		EventType string `db:"event_type"`
+	}
+	if err := s.db.SelectContext(ctx, &types,
+		`SELECT webhook_id, event_type FROM webhook_event_types ORDER BY webhook_id, event_type`); err != nil {
+		return nil, fmt.Errorf("failed to get webhook event types: %w", err)
+	}
+	byID := make(map[int64][]string, len(webhooks))
+	for _, t := range types {
+		byID[t.WebhookID] = append(byID[t.WebhookID], t.EventType)
+	}
+	for i := range webhooks {
+		webhooks[i].EventTypes = byID[webhooks[i].ID]
+	}
+	return webhooks, nil
+}
+
+// GetWebhook returns a webhook by ID.
+func (s *Storage) GetWebhook(ctx context.Context, id int64) (models.Webhook, error) {
+	var w models.Webhook
+	err := s.db.GetContext(ctx, &w, `SELECT * FROM webhooks WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return w, model.ErrNotFound
+	}
+	if err != nil {
+		return w, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	if err := s.db.SelectContext(ctx, &w.EventTypes,
+		`SELECT event_type FROM webhook_event_types WHERE webhook_id = $1 ORDER BY event_type`, id); err != nil {
+		return w, fmt.Errorf("failed to get webhook event types: %w", err)
+	}
+	return w, nil
+}
+
+// DeleteWebhook removes a webhook with its deliveries.
+func (s *Storage) DeleteWebhook(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if n == 0 {
+		return model.ErrNotFound
+	}
+	return nil
+}
+
+// CreateWebhookDeliveries queues the event of the delivery for every webhook receiving its type.
+func (s *Storage) CreateWebhookDeliveries(ctx context.Context, delivery models.WebhookDelivery,
+) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	if err := s.db.SelectContext(ctx, &deliveries,
+		`INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+		SELECT webhook_id, $1::text, $2::text, $3::jsonb, $4::text, $5::timestamptz, $6::timestamptz
+		FROM webhook_event_types WHERE event_type = $2
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+		RETURNING *`,
+		delivery.EventID, delivery.EventType, delivery.Payload, models.DeliveryPending,
+		delivery.NextAttemptAt, delivery.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed to create webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// ClaimWebhookDeliveries returns up to limit pending deliveries due by now, oldest first, and postpones them
+// until the given time. Deliveries whose sender dies are so retried once the claim runs out.
+func (s *Storage) ClaimWebhookDeliveries(ctx context.Context, now, until time.Time, limit int,
+) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	if err := s.db.SelectContext(ctx, &deliveries,
+		`UPDATE webhook_deliveries SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at, id LIMIT $3 FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		now, until, limit); err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].ID < deliveries[j].ID
+	})
+	return deliveries, nil
+}
+
+// RecordWebhookAttempt saves the state of a delivery after an attempt and logs the attempt.
+func (s *Storage) RecordWebhookAttempt(ctx context.Context, delivery models.WebhookDelivery,
+	attempt models.WebhookAttempt,
+) (models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return d, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	err = tx.GetContext(ctx, &d,
+		`UPDATE webhook_deliveries SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5,
+		delivered_at = $6, updated_at = $7
+		WHERE id = $1
+		RETURNING *`,
+		delivery.ID, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastError,
+		delivery.DeliveredAt, attempt.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return d, model.ErrNotFound
+	}
+	if err != nil {
+		return d, fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO webhook_attempts (delivery_id, status_code, error, duration_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5)`,
+		d.ID, attempt.StatusCode, attempt.Error, attempt.DurationMS, attempt.CreatedAt); err != nil {
+		return d, fmt.Errorf("failed to log webhook attempt: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return d, fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return d, nil
+}
+
+// GetWebhookDeliveries returns deliveries matching the filter newest first.
+func (s *Storage) GetWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter,
+) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	if err := s.db.SelectContext(ctx, &deliveries,
+		`SELECT * FROM webhook_deliveries
+		WHERE ($1 = 0 OR webhook_id = $1) AND ($2 = '' OR status = $2)
+		ORDER BY id DESC`,
+		filter.WebhookID, filter.Status); err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// GetWebhookDelivery returns a delivery by ID.
+func (s *Storage) GetWebhookDelivery(ctx context.Context, id int64) (models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	err := s.db.GetContext(ctx, &d, `SELECT * FROM webhook_deliveries WHERE id = $1`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return d, model.ErrNotFound
+	}
+	if err != nil {
+		return d, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return d, nil
+}
+
+// GetWebhookAttempts returns the attempts of a delivery oldest first.
+func (s *Storage) GetWebhookAttempts(ctx context.Context, deliveryID int64) ([]models.WebhookAttempt, error) {
+	var attempts []models.WebhookAttempt
+	if err := s.db.SelectContext(ctx, &attempts,
+		`SELECT * FROM webhook_attempts WHERE delivery_id = $1 ORDER BY id`, deliveryID); err != nil {
+		return nil, fmt.Errorf("failed to get webhook attempts: %w", err)
+	}
+	return attempts, nil
+}
+
+// RedeliverWebhookDelivery queues a delivery again with a fresh set of attempts, whatever its status.
+func (s *Storage) RedeliverWebhookDelivery(ctx context.Context, id int64, now time.Time,
+) (models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	err := s.db.GetContext(ctx, &d,
+		`UPDATE webhook_deliveries SET status = $2, attempts = 0, next_attempt_at = $3, updated_at = $3
+		WHERE id = $1
+		RETURNING *`,
+		id, models.DeliveryPending, now)
+	if errors.Is(err, sql.ErrNoRows) {
+		return d, model.ErrNotFound
+	}
+	if err != nil {
+		return d, fmt.Errorf("failed to redeliver webhook delivery: %w", err)
+	}
+	return d, nil
+}
+
+// ClaimDomainEvents returns up to limit undispatched events oldest first that aren't claimed at now and claims
+// them until the given time, so dispatchers of several instances don't hand out the same events.
+func (s *Storage) ClaimDomainEvents(ctx context.Context, now, until time.Time, limit int,
+) ([]models.DomainEvent, error) {
+	var events []models.DomainEvent
+	if err := s.db.SelectContext(ctx, &events,
+		`UPDATE domain_events SET claimed_until = $2
+		WHERE id IN (
+			SELECT id FROM domain_events
+			WHERE dispatched_at IS NULL AND (claimed_until IS NULL OR claimed_until <= $1)
+			ORDER BY id LIMIT $3 FOR UPDATE SKIP LOCKED)
+		RETURNING *`,
+		now, until, limit); err != nil {
+		return nil, fmt.Errorf("failed to claim domain events: %w", err)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+	return events, nil
+}
+
+// MarkDomainEventsDispatched records that events have been handed to all subscribers.
+func (s *Storage) MarkDomainEventsDispatched(ctx context.Context, ids []int64, now time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query, args, err := sqlx.In(`UPDATE domain_events SET dispatched_at = ? WHERE id IN (?)`, now, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build domain events query: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, s.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to mark domain events dispatched: %w", err)
+	}
+	return nil
+}
+
+// DeleteDispatchedDomainEvents removes events dispatched before the given time.
+func (s *Storage) DeleteDispatchedDomainEvents(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM domain_events WHERE dispatched_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete domain events: %w", err)
+	}
+	return res.RowsAffected()
+}
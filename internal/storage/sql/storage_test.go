@@ -0,0 +1,48 @@
+package sqlstorage
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/cronnoss/tk-api/internal/storage/storagetest"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStorage connects to the migrated database named by TK_TEST_POSTGRES_DSN, e.g. the one of
+// make run-postgres. Tests are skipped without it.
+func newTestStorage(t *testing.T) *Storage {
+	t.Helper()
+	dsn := os.Getenv("TK_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TK_TEST_POSTGRES_DSN is not set")
+	}
+	s := New(dsn)
+	require.NoError(t, s.Connect(context.Background()))
+	t.Cleanup(func() { _ = s.Close(context.Background()) })
+	return s
+}
+
+func TestAttachEventToHallInUse(t *testing.T) {
+	storagetest.AttachEventToHallInUse(t, newTestStorage(t))
+}
+
+func TestUpsertEvent(t *testing.T) {
+	storagetest.UpsertEvent(t, newTestStorage(t))
+}
+
+func TestAttachEventToHallReplacesUpstreamPlaces(t *testing.T) {
+	storagetest.AttachEventToHallReplacesUpstreamPlaces(t, newTestStorage(t))
+}
+
+func TestHoldCancelledEvent(t *testing.T) {
+	storagetest.HoldCancelledEvent(t, newTestStorage(t))
+}
+
+func TestSyncHallEvent(t *testing.T) {
+	storagetest.SyncHallEvent(t, newTestStorage(t))
+}
+
+func TestConcurrentChecks(t *testing.T) {
+	storagetest.ConcurrentChecks(t, newTestStorage(t))
+}
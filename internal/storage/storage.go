@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	memorystorage "github.com/cronnoss/tk-api/internal/storage/memory"
 	"github.com/cronnoss/tk-api/internal/storage/models"
@@ -21,12 +22,87 @@ type Storage interface {
 	GetShows(ctx context.Context) ([]models.Show, error)
 	CreateShows(ctx context.Context, shows []models.Show) ([]models.Show, error)
 	CreateShow(ctx context.Context, shows models.Show) (models.Show, error)
-	GetEvents(ctx context.Context) ([]models.Event, error)
+	GetEvents(ctx context.Context, filter models.EventFilter) ([]models.Event, error)
 	CreateEvents(ctx context.Context, events []models.Event) ([]models.Event, error)
 	CreateEvent(ctx context.Context, event models.Event) (models.Event, error)
-	GetPlaces(ctx context.Context) ([]models.Place, error)
+	GetPlaces(ctx context.Context, filter models.PlaceFilter) ([]models.Place, error)
+	GetPlacesInRect(ctx context.Context, eventID int64, r models.Rect) ([]models.Place, error)
+	GetNearestPlaces(ctx context.Context, eventID int64, pt models.Point, n int) ([]models.Place, error)
+	GetPlaceAdjacency(ctx context.Context, eventID int64) ([]models.Adjacency, error)
 	CreatePlaces(ctx context.Context, places []models.Place) ([]models.Place, error)
 	CreatePlace(ctx context.Context, place models.Place) (models.Place, error)
+	GetEvent(ctx context.Context, id int64) (models.Event, error)
+	AttachEventToHall(ctx context.Context, eventID, hallID int64, check models.Check[models.Event],
+	) (models.Event, error)
+	CancelEvent(ctx context.Context, id int64, now time.Time, check models.Check[models.Event]) (models.Event, error)
+	CreateVenue(ctx context.Context, venue models.Venue) (models.Venue, error)
+	GetVenues(ctx context.Context) ([]models.Venue, error)
+	GetVenue(ctx context.Context, id int64) (models.Venue, error)
+	CreateHall(ctx context.Context, hall models.Hall) (models.Hall, error)
+	GetHalls(ctx context.Context, venueID int64) ([]models.Hall, error)
+	GetHall(ctx context.Context, id int64) (models.Hall, error)
+	CreateSection(ctx context.Context, section models.Section) (models.Section, error)
+	GetSections(ctx context.Context, hallID int64) ([]models.Section, error)
+	CreateHallPlaces(ctx context.Context, hallID int64, places []models.Place) ([]models.Place, error)
+	GetIdempotencyKey(ctx context.Context, key string) (models.IdempotencyKey, error)
+	CreateIdempotencyKey(ctx context.Context, key models.IdempotencyKey) (models.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, key models.IdempotencyKey) error
+	DeleteIdempotencyKey(ctx context.Context, key string) error
+	DeleteExpiredIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+	CreateQuarantinedItems(ctx context.Context, items []models.QuarantinedItem) ([]models.QuarantinedItem, error)
+	GetQuarantinedItems(ctx context.Context) ([]models.QuarantinedItem, error)
+	CreateHold(ctx context.Context, hold models.Hold) (models.Hold, error)
+	GetHold(ctx context.Context, id int64) (models.Hold, error)
+	ReleaseHold(ctx context.Context, id int64, now time.Time) (models.Hold, error)
+	ReleaseExpiredHolds(ctx context.Context, now time.Time) ([]models.Hold, error)
+	CreatePriceCategory(ctx context.Context, category models.PriceCategory) (models.PriceCategory, error)
+	GetPriceCategories(ctx context.Context, eventID int64) ([]models.PriceCategory, error)
+	UpdatePriceCategory(ctx context.Context, category models.PriceCategory, check models.Check[models.PriceCategory],
+	) (models.PriceCategory, error)
+	DeletePriceCategory(ctx context.Context, id int64) error
+	AssignPrices(ctx context.Context, eventID int64, assignments []models.PriceAssignment,
+		check models.Check[models.Event]) (int64, error)
+	CreateOrder(ctx context.Context, order models.Order) (models.Order, error)
+	GetOrder(ctx context.Context, id int64) (models.Order, error)
+	CreatePromotion(ctx context.Context, promotion models.Promotion) (models.Promotion, error)
+	GetPromotions(ctx context.Context) ([]models.Promotion, error)
+	GetPromotion(ctx context.Context, id int64) (models.Promotion, error)
+	DeactivatePromotion(ctx context.Context, id int64, now time.Time) (models.Promotion, error)
+	GetCheckoutPromotions(ctx context.Context, codes []string) ([]models.Promotion, error)
+	GetCustomerRedemptions(ctx context.Context, customerID int64) (map[int64]int, error)
+	CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error)
+	GetCustomer(ctx context.Context, id int64) (models.Customer, error)
+	UpdateCustomer(ctx context.Context, customer models.Customer, check models.Check[models.Customer],
+	) (models.Customer, error)
+	EraseCustomer(ctx context.Context, id int64, now time.Time) (models.Customer, error)
+	GetCustomerOrders(ctx context.Context, customerID int64) ([]models.Order, error)
+	GetCustomerHolds(ctx context.Context, customerID int64) ([]models.Hold, error)
+	CreateWaitlistEntry(ctx context.Context, entry models.WaitlistEntry) (models.WaitlistEntry, error)
+	GetWaitlistEntry(ctx context.Context, id int64) (models.WaitlistEntry, error)
+	GetWaitlist(ctx context.Context, eventID int64) ([]models.WaitlistEntry, error)
+	GetWaitingEntries(ctx context.Context) ([]models.WaitlistEntry, error)
+	OfferWaitlistEntry(ctx context.Context, id, holdID int64, now time.Time) (models.WaitlistEntry, error)
+	CancelWaitlistEntry(ctx context.Context, id int64, now time.Time) (models.WaitlistEntry, error)
+	RefundOrder(ctx context.Context, refund models.Refund) (models.Refund, error)
+	ExchangeOrderItems(ctx context.Context, exchange models.Exchange) (models.Exchange, error)
+	GetAuditTrail(ctx context.Context, entity string, id int64) ([]models.AuditRecord, error)
+	GetOrderItem(ctx context.Context, id int64) (models.OrderItem, error)
+	UseOrderItem(ctx context.Context, id int64, now time.Time) (models.OrderItem, error)
+	CreateWebhook(ctx context.Context, webhook models.Webhook) (models.Webhook, error)
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, id int64) (models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) error
+	CreateWebhookDeliveries(ctx context.Context, delivery models.WebhookDelivery) ([]models.WebhookDelivery, error)
+	ClaimWebhookDeliveries(ctx context.Context, now, until time.Time, limit int) ([]models.WebhookDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, delivery models.WebhookDelivery,
+		attempt models.WebhookAttempt) (models.WebhookDelivery, error)
+	GetWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (models.WebhookDelivery, error)
+	GetWebhookAttempts(ctx context.Context, deliveryID int64) ([]models.WebhookAttempt, error)
+	RedeliverWebhookDelivery(ctx context.Context, id int64, now time.Time) (models.WebhookDelivery, error)
+	ClaimDomainEvents(ctx context.Context, now, until time.Time, limit int) ([]models.DomainEvent, error)
+	MarkDomainEventsDispatched(ctx context.Context, ids []int64, now time.Time) error
+	DeleteDispatchedDomainEvents(ctx context.Context, before time.Time) (int64, error)
 }
 
 func NewStorage(conf Conf) Storage {
@@ -0,0 +1,296 @@
+// Package storagetest holds scenarios run against every storage.Storage implementation, so the
+// backends are held to the same behaviour. It can't import package storage, which imports the backends.
+package storagetest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/model"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Storage is the part of storage.Storage the scenarios use.
+type Storage interface {
+	CreateShow(ctx context.Context, show models.Show) (models.Show, error)
+	GetEvents(ctx context.Context, filter models.EventFilter) ([]models.Event, error)
+	GetEvent(ctx context.Context, id int64) (models.Event, error)
+	CreateEvent(ctx context.Context, event models.Event) (models.Event, error)
+	AttachEventToHall(ctx context.Context, eventID, hallID int64, check models.Check[models.Event],
+	) (models.Event, error)
+	CreateVenue(ctx context.Context, venue models.Venue) (models.Venue, error)
+	CreateHall(ctx context.Context, hall models.Hall) (models.Hall, error)
+	CreateHallPlaces(ctx context.Context, hallID int64, places []models.Place) ([]models.Place, error)
+	CreatePlace(ctx context.Context, place models.Place) (models.Place, error)
+	GetPlaces(ctx context.Context, filter models.PlaceFilter) ([]models.Place, error)
+	CancelEvent(ctx context.Context, id int64, now time.Time, check models.Check[models.Event]) (models.Event, error)
+	CreateHold(ctx context.Context, hold models.Hold) (models.Hold, error)
+	GetHold(ctx context.Context, id int64) (models.Hold, error)
+	CreateOrder(ctx context.Context, order models.Order) (models.Order, error)
+	CreatePriceCategory(ctx context.Context, category models.PriceCategory) (models.PriceCategory, error)
+	UpdatePriceCategory(ctx context.Context, category models.PriceCategory, check models.Check[models.PriceCategory],
+	) (models.PriceCategory, error)
+}
+
+// NewEventID returns an event ID unlikely to be taken: upstream events are stored by their IDs.
+func NewEventID() int64 {
+	return time.Now().UnixNano()/1000%1_000_000_000 + 1_000_000_000
+}
+
+// newUpstreamPlaceID returns a place ID of the remote API unlikely to be taken.
+func newUpstreamPlaceID() int64 {
+	return NewEventID()%(models.FirstLocalPlaceID-1) + 1
+}
+
+// newEvent creates an event of a new show fetched from the remote API.
+func newEvent(ctx context.Context, t *testing.T, s Storage) models.Event {
+	t.Helper()
+	show, err := s.CreateShow(ctx, models.Show{Name: fmt.Sprintf("Show %d", NewEventID())})
+	require.NoError(t, err)
+	event, err := s.CreateEvent(ctx, models.Event{
+		ID: NewEventID(), ShowID: show.ID, Date: time.Now().Add(24 * time.Hour).Truncate(time.Second), TimeZone: "UTC",
+	})
+	require.NoError(t, err)
+	return event
+}
+
+// newHallEvent creates an event attached to a hall of two seats.
+func newHallEvent(ctx context.Context, t *testing.T, s Storage) (models.Event, models.Hall) {
+	t.Helper()
+	hall := newHall(ctx, t, s)
+	event, err := s.AttachEventToHall(ctx, newEvent(ctx, t, s).ID, hall.ID, nil)
+	require.NoError(t, err)
+	return event, hall
+}
+
+// newHall creates a hall of two seats.
+func newHall(ctx context.Context, t *testing.T, s Storage) models.Hall {
+	t.Helper()
+	venue, err := s.CreateVenue(ctx, models.Venue{Name: "Opera", TimeZone: "Europe/Moscow"})
+	require.NoError(t, err)
+	hall, err := s.CreateHall(ctx, models.Hall{VenueID: venue.ID, Name: "Main"})
+	require.NoError(t, err)
+	_, err = s.CreateHallPlaces(ctx, hall.ID, []models.Place{
+		{Row: "1", Seat: 1, Width: 1, Height: 1},
+		{Row: "1", Seat: 2, X: 1, Width: 1, Height: 1},
+	})
+	require.NoError(t, err)
+	return hall
+}
+
+// AttachEventToHallInUse checks that an event with held or sold seats keeps its seat map.
+func AttachEventToHallInUse(t *testing.T, s Storage) {
+	ctx := context.Background()
+	now := time.Now()
+	event, hall := newHallEvent(ctx, t, s)
+
+	places, err := s.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+	require.NoError(t, err)
+	require.Len(t, places, 2)
+
+	hold, err := s.CreateHold(ctx, models.Hold{
+		EventID: event.ID, PlaceIDs: []int64{places[0].ID}, CreatedAt: now, ExpiresAt: now.Add(time.Minute),
+	})
+	require.NoError(t, err)
+	_, err = s.AttachEventToHall(ctx, event.ID, hall.ID, nil)
+	require.ErrorIs(t, err, model.ErrPlacesInUse, "held places")
+	got, err := s.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+	require.NoError(t, err)
+	require.Equal(t, places[0].ID, got[0].ID)
+	require.Equal(t, models.PlaceHeld, got[0].Status())
+	held, err := s.GetHold(ctx, hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, []int64{places[0].ID}, held.PlaceIDs)
+
+	_, err = s.CreateOrder(ctx, models.Order{
+		EventID: event.ID, HoldID: sql.NullInt64{Int64: hold.ID, Valid: true}, Status: models.OrderConfirmed,
+		Currency: "RUB", Subtotal: 100000, Total: 100000, CreatedAt: now,
+		Items: []models.OrderItem{{PlaceID: places[0].ID, Amount: 100000}},
+	})
+	require.NoError(t, err)
+	_, err = s.AttachEventToHall(ctx, event.ID, hall.ID, nil)
+	require.ErrorIs(t, err, model.ErrPlacesInUse, "sold places")
+	got, err = s.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+	require.NoError(t, err)
+	require.Equal(t, places[0].ID, got[0].ID)
+	require.Equal(t, models.PlaceSold, got[0].Status())
+}
+
+// SyncHallEvent checks that storing an event fetched again from the remote API keeps the venue
+// time zone of an event in a hall, and doesn't count that as a change.
+func SyncHallEvent(t *testing.T, s Storage) {
+	ctx := context.Background()
+	event, _ := newHallEvent(ctx, t, s)
+	require.Equal(t, "Europe/Moscow", event.TimeZone)
+
+	synced, err := s.CreateEvent(ctx, models.Event{ID: event.ID, ShowID: event.ShowID, Date: event.Date, TimeZone: "UTC"})
+	require.NoError(t, err)
+	require.Equal(t, event.ID, synced.ID)
+	require.Equal(t, "Europe/Moscow", synced.TimeZone)
+
+	events, err := s.GetEvents(ctx, models.EventFilter{ShowID: event.ShowID})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	got := events[0]
+	require.Equal(t, event.ID, got.ID)
+	require.Equal(t, "Europe/Moscow", got.TimeZone)
+	require.Equal(t, event.Sequence, got.Sequence)
+	require.Equal(t, event.HallID, got.HallID)
+	require.True(t, event.Date.Equal(got.Date))
+}
+
+// AttachEventToHallReplacesUpstreamPlaces checks that places synced from the remote API and local
+// places don't share IDs, and that an event attached to a hall only keeps the copy of the layout.
+func AttachEventToHallReplacesUpstreamPlaces(t *testing.T, s Storage) {
+	ctx := context.Background()
+	event := newEvent(ctx, t, s)
+	upstreamID := newUpstreamPlaceID()
+	upstream, err := s.CreatePlace(ctx, models.Place{
+		ID: upstreamID, EventID: sql.NullInt64{Int64: event.ID, Valid: true}, Width: 1, Height: 1, IsAvailable: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, upstreamID, upstream.ID)
+
+	hall := newHall(ctx, t, s)
+	layout, err := s.GetPlaces(ctx, models.PlaceFilter{HallID: hall.ID})
+	require.NoError(t, err)
+	require.Len(t, layout, 2)
+	for _, p := range layout {
+		require.GreaterOrEqual(t, p.ID, int64(models.FirstLocalPlaceID))
+	}
+
+	_, err = s.AttachEventToHall(ctx, event.ID, hall.ID, nil)
+	require.NoError(t, err)
+	places, err := s.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+	require.NoError(t, err)
+	require.Len(t, places, 2, "upstream places are replaced")
+	for _, p := range places {
+		require.GreaterOrEqual(t, p.ID, int64(models.FirstLocalPlaceID))
+		require.Equal(t, hall.ID, p.HallID.Int64)
+	}
+
+	// Synced again, the upstream place doesn't take over a local one.
+	again, err := s.CreatePlace(ctx, models.Place{
+		ID: upstreamID, EventID: sql.NullInt64{Int64: newEvent(ctx, t, s).ID, Valid: true}, Width: 1, Height: 1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, upstreamID, again.ID)
+	require.False(t, again.HallID.Valid)
+}
+
+// UpsertEvent checks that an event fetched again from the remote API is stored by its ID and
+// gets the next sequence only if it moved to another date or time zone.
+func UpsertEvent(t *testing.T, s Storage) {
+	ctx := context.Background()
+	show, err := s.CreateShow(ctx, models.Show{Name: fmt.Sprintf("Show %d", NewEventID())})
+	require.NoError(t, err)
+	date := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+	id := NewEventID()
+
+	event, err := s.CreateEvent(ctx, models.Event{ID: id, ShowID: show.ID, Date: date, TimeZone: "UTC"})
+	require.NoError(t, err)
+	require.Equal(t, id, event.ID)
+
+	event, err = s.CreateEvent(ctx, models.Event{ID: id, ShowID: show.ID, Date: date, TimeZone: "UTC"})
+	require.NoError(t, err)
+	require.Equal(t, id, event.ID)
+	require.Equal(t, 0, event.Sequence, "nothing changed")
+
+	event, err = s.CreateEvent(ctx, models.Event{ID: id, ShowID: show.ID, Date: date.Add(time.Hour), TimeZone: "UTC"})
+	require.NoError(t, err)
+	require.Equal(t, 1, event.Sequence, "moved to another date")
+
+	event, err = s.CreateEvent(ctx, models.Event{
+		ID: id, ShowID: show.ID, Date: date.Add(time.Hour), TimeZone: "Europe/Moscow",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, event.Sequence, "moved to another time zone")
+	require.Equal(t, "Europe/Moscow", event.TimeZone)
+
+	events, err := s.GetEvents(ctx, models.EventFilter{ShowID: show.ID})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, id, events[0].ID)
+	require.True(t, date.Add(time.Hour).Equal(events[0].Date))
+}
+
+// HoldCancelledEvent checks that places of a cancelled event can't be held.
+func HoldCancelledEvent(t *testing.T, s Storage) {
+	ctx := context.Background()
+	now := time.Now()
+	event, _ := newHallEvent(ctx, t, s)
+	places, err := s.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+	require.NoError(t, err)
+
+	_, err = s.CancelEvent(ctx, event.ID, now, nil)
+	require.NoError(t, err)
+	_, err = s.CreateHold(ctx, models.Hold{
+		EventID: event.ID, PlaceIDs: []int64{places[0].ID}, CreatedAt: now, ExpiresAt: now.Add(time.Minute),
+	})
+	require.ErrorIs(t, err, model.ErrUnavailable)
+	got, err := s.GetPlaces(ctx, models.PlaceFilter{EventID: event.ID})
+	require.NoError(t, err)
+	require.Equal(t, models.PlaceAvailable, got[0].Status())
+
+	_, err = s.CreateHold(ctx, models.Hold{
+		EventID: NewEventID(), PlaceIDs: []int64{places[0].ID}, CreatedAt: now, ExpiresAt: now.Add(time.Minute),
+	})
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
+
+// ConcurrentChecks checks that conditional writes based on the same read don't overwrite each other:
+// only the first one passes its check, the others see the record it wrote.
+func ConcurrentChecks(t *testing.T, s Storage) {
+	ctx := context.Background()
+	event, _ := newHallEvent(ctx, t, s)
+	read, err := s.CreatePriceCategory(ctx, models.PriceCategory{
+		EventID: event.ID, Name: "Stalls", Amount: 100000, Currency: "RUB", CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	errStale := errors.New("stale")
+	check := func(c models.PriceCategory) error {
+		if c.Name != read.Name {
+			return errStale
+		}
+		return nil
+	}
+	const writers = 10
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		updated []string
+	)
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := read
+			c.Name = fmt.Sprintf("Stalls %d", i)
+			_, err := s.UpdatePriceCategory(ctx, c, check)
+			if errors.Is(err, errStale) {
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			mu.Lock()
+			updated = append(updated, c.Name)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	require.Len(t, updated, 1, "one write is based on the record read")
+
+	_, err = s.UpdatePriceCategory(ctx, read, check)
+	require.ErrorIs(t, err, errStale)
+	_, err = s.UpdatePriceCategory(ctx, models.PriceCategory{ID: read.ID + 1_000_000}, check)
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
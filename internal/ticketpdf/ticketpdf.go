@@ -0,0 +1,259 @@
+// Package ticketpdf renders printable tickets, one per page, laid out by a template.
+package ticketpdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cronnoss/tk-api/internal/tickets"
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+)
+
+const (
+	fontFamily = "ticket"
+	qrPixels   = 512
+)
+
+// Ticket is what a template can print. Date is already in the venue time zone.
+type Ticket struct {
+	TicketID int64
+	OrderID  int64
+	Show     string
+	Venue    string
+	Hall     string
+	Date     time.Time
+	Seat     string
+	Price    string
+	// Code is the signed ticket code drawn as a QR code.
+	Code string
+}
+
+// Text is a line of text at X, Y, the baseline, in millimetres. Text is a text/template
+// executed with the Ticket, dates are formatted by the date function.
+type Text struct {
+	X    float64 `toml:"x"`
+	Y    float64 `toml:"y"`
+	Size float64 `toml:"size"`
+	Bold bool    `toml:"bold"`
+	Text string  `toml:"text"`
+}
+
+// Box is a square at X, Y, the top left corner, Size millimetres wide.
+type Box struct {
+	X    float64 `toml:"x"`
+	Y    float64 `toml:"y"`
+	Size float64 `toml:"size"`
+}
+
+// Template lays out a ticket page, sizes are in millimetres.
+type Template struct {
+	PageWidth  float64 `toml:"page-width"`
+	PageHeight float64 `toml:"page-height"`
+	// Font is a TrueType font relative to the template file, the Go font if empty. A single
+	// font file has no bold face.
+	Font string `toml:"font"`
+	// DateFormat is the Go layout of the date function.
+	DateFormat string `toml:"date-format"`
+	Texts      []Text `toml:"text"`
+	QR         Box    `toml:"qr"`
+
+	regular *fontFace
+	bold    *fontFace
+	texts   []*template.Template
+}
+
+// fontFace is a TrueType font embedded into rendered PDFs.
+type fontFace struct {
+	data []byte
+	font *sfnt.Font
+}
+
+func newFontFace(data []byte) (*fontFace, error) {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ticket font: %w", err)
+	}
+	return &fontFace{data: data, font: f}, nil
+}
+
+// missing returns the first rune of s the font has no glyph for.
+func (f *fontFace) missing(s string) (rune, bool) {
+	var b sfnt.Buffer
+	for _, r := range s {
+		if i, err := f.font.GlyphIndex(&b, r); err != nil || i == 0 {
+			return r, true
+		}
+	}
+	return 0, false
+}
+
+// DefaultTemplate is used when no template is configured.
+const DefaultTemplate = `
+page-width = 210
+page-height = 99
+date-format = "Mon, 02 Jan 2006 15:04"
+
+[[text]]
+x = 12
+y = 22
+size = 20
+bold = true
+text = "{{.Show}}"
+
+[[text]]
+x = 12
+y = 34
+size = 12
+text = "{{date .Date}}"
+
+[[text]]
+x = 12
+y = 44
+size = 12
+text = "{{.Venue}}{{if .Hall}}, {{.Hall}}{{end}}"
+
+[[text]]
+x = 12
+y = 60
+size = 16
+bold = true
+text = "{{.Seat}}"
+
+[[text]]
+x = 12
+y = 72
+size = 12
+text = "{{.Price}}"
+
+[[text]]
+x = 12
+y = 88
+size = 8
+text = "Order {{.OrderID}} / Ticket {{.TicketID}}"
+
+[qr]
+x = 140
+y = 19
+size = 60
+`
+
+// Load reads a template file, the default template if path is empty.
+func Load(path string) (*Template, error) {
+	data := []byte(DefaultTemplate)
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read ticket template: %w", err)
+		}
+	}
+
+	var t Template
+	if err := toml.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("failed to parse ticket template: %w", err)
+	}
+	if t.PageWidth <= 0 || t.PageHeight <= 0 {
+		return nil, fmt.Errorf("ticket template page size must be positive")
+	}
+	if t.DateFormat == "" {
+		t.DateFormat = time.RFC1123
+	}
+	if err := t.loadFonts(path); err != nil {
+		return nil, err
+	}
+
+	funcs := template.FuncMap{"date": func(d time.Time) string { return d.Format(t.DateFormat) }}
+	for i, text := range t.Texts {
+		tmpl, err := template.New(fmt.Sprintf("text %d", i)).Funcs(funcs).Parse(text.Text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ticket template text %d: %w", i, err)
+		}
+		t.texts = append(t.texts, tmpl)
+	}
+	return &t, nil
+}
+
+func (t *Template) loadFonts(path string) error {
+	var err error
+	if t.Font == "" {
+		if t.regular, err = newFontFace(goregular.TTF); err != nil {
+			return err
+		}
+		t.bold, err = newFontFace(gobold.TTF)
+		return err
+	}
+
+	fontPath := t.Font
+	if !filepath.IsAbs(fontPath) {
+		fontPath = filepath.Join(filepath.Dir(path), fontPath)
+	}
+	data, err := os.ReadFile(fontPath)
+	if err != nil {
+		return fmt.Errorf("failed to read ticket font: %w", err)
+	}
+	if t.regular, err = newFontFace(data); err != nil {
+		return err
+	}
+	t.bold = t.regular
+	return nil
+}
+
+// Render writes a PDF with a page for every ticket. Text with characters the font can't print fails.
+func (t *Template) Render(w io.Writer, list []Ticket) error {
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		UnitStr: "mm",
+		Size:    fpdf.SizeType{Wd: t.PageWidth, Ht: t.PageHeight},
+	})
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddUTF8FontFromBytes(fontFamily, "", t.regular.data)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", t.bold.data)
+
+	for i, ticket := range list {
+		pdf.AddPage()
+		for j, text := range t.Texts {
+			var b strings.Builder
+			if err := t.texts[j].Execute(&b, ticket); err != nil {
+				return fmt.Errorf("failed to execute ticket template text %d: %w", j, err)
+			}
+			face := t.setFont(pdf, text)
+			if r, ok := face.missing(b.String()); ok {
+				return fmt.Errorf("ticket font has no glyph for %q in text %d", r, j)
+			}
+			pdf.Text(text.X, text.Y, b.String())
+		}
+
+		if t.QR.Size > 0 {
+			png, err := tickets.QR(ticket.Code, qrPixels)
+			if err != nil {
+				return fmt.Errorf("failed to render QR code: %w", err)
+			}
+			name := fmt.Sprintf("qr%d", i)
+			opts := fpdf.ImageOptions{ImageType: "PNG"}
+			pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(png))
+			pdf.ImageOptions(name, t.QR.X, t.QR.Y, t.QR.Size, t.QR.Size, false, opts, 0, "")
+		}
+	}
+	return pdf.Output(w)
+}
+
+func (t *Template) setFont(pdf *fpdf.Fpdf, text Text) *fontFace {
+	size := text.Size
+	if size <= 0 {
+		size = 12
+	}
+	if text.Bold {
+		pdf.SetFont(fontFamily, "B", size)
+		return t.bold
+	}
+	pdf.SetFont(fontFamily, "", size)
+	return t.regular
+}
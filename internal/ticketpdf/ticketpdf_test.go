@@ -0,0 +1,66 @@
+package ticketpdf
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestRender(t *testing.T) {
+	tmpl, err := Load("")
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	err = tmpl.Render(&b, []Ticket{
+		{TicketID: 1, OrderID: 7, Show: "Café Concert", Venue: "Hall", Date: time.Now(), Seat: "Row 1, Seat 2",
+			Price: "1500.00 RUB", Code: "abc"},
+		{TicketID: 2, OrderID: 7, Show: "Café Concert", Code: "def"},
+	})
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(b.Bytes(), []byte("%PDF-")))
+	require.Equal(t, 2, bytes.Count(b.Bytes(), []byte("/Type /Page\n")), "a page per ticket")
+
+	b.Reset()
+	require.NoError(t, tmpl.Render(&b, []Ticket{{Show: "Щелкунчик", Venue: "Ωδείο Αθηνών", Code: "abc"}}))
+
+	err = tmpl.Render(&bytes.Buffer{}, []Ticket{{Show: "くるみ割り人形", Code: "abc"}})
+	require.ErrorContains(t, err, "no glyph", "text the font can't print fails")
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ticket.toml")
+
+	require.NoError(t, os.WriteFile(path, []byte("page-width = 100\npage-height = 50\n[[text]]\ntext = \"{{.Nope}}\"\n"), 0o600))
+	tmpl, err := Load(path)
+	require.NoError(t, err)
+	require.Error(t, tmpl.Render(&bytes.Buffer{}, []Ticket{{}}), "unknown fields fail at render")
+
+	require.NoError(t, os.WriteFile(path, []byte("page-width = 100\n"), 0o600))
+	_, err = Load(path)
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("page-width = 100\npage-height = 50\nfont = \"missing.ttf\"\n"), 0o600))
+	_, err = Load(path)
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.ttf"), goregular.TTF, 0o600))
+	require.NoError(t, os.WriteFile(path, []byte("page-width = 100\npage-height = 50\nfont = \"go.ttf\"\n"+
+		"[[text]]\nbold = true\ntext = \"{{.Show}}\"\n"), 0o600))
+	tmpl, err = Load(path)
+	require.NoError(t, err)
+	require.NoError(t, tmpl.Render(&bytes.Buffer{}, []Ticket{{Show: "Щелкунчик"}}))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.ttf"), []byte("not a font"), 0o600))
+	require.NoError(t, os.WriteFile(path, []byte("page-width = 100\npage-height = 50\nfont = \"bad.ttf\"\n"), 0o600))
+	_, err = Load(path)
+	require.Error(t, err)
+
+	_, err = Load(filepath.Join(dir, "missing.toml"))
+	require.Error(t, err)
+}
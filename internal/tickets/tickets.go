@@ -0,0 +1,141 @@
+// Package tickets issues compact signed ticket codes. Codes are signed with Ed25519 so door scanners
+// can verify them offline with the public keys alone.
+package tickets
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const version = 1
+
+var (
+	ErrMalformed    = errors.New("malformed ticket code")
+	ErrSignature    = errors.New("invalid ticket signature")
+	ErrNoSigningKey = errors.New("signing key is not set, set ephemeral-key for a random one in development")
+)
+
+// Conf holds the keys as base64 of their raw bytes.
+type Conf struct {
+	// SigningKey is the Ed25519 seed codes are signed with.
+	SigningKey string `toml:"signing-key"`
+	// EphemeralKey allows an empty SigningKey for development: codes are signed with a random key and
+	// don't verify after restart.
+	EphemeralKey bool `toml:"ephemeral-key"`
+	// VerifyKeys are public keys of retired signing keys whose codes are still accepted.
+	VerifyKeys []string `toml:"verify-keys"`
+}
+
+// Claims are what a ticket code vouches for. TicketID is the order item ID.
+type Claims struct {
+	TicketID int64
+	OrderID  int64
+	EventID  int64
+	PlaceID  int64
+}
+
+type Signer struct {
+	key ed25519.PrivateKey
+	// keys verify codes, the public key of key first.
+	keys []ed25519.PublicKey
+}
+
+// NewSigner returns ErrNoSigningKey if there is no signing key and a random one isn't allowed.
+func NewSigner(conf Conf) (*Signer, error) {
+	var key ed25519.PrivateKey
+	switch {
+	case conf.SigningKey == "" && !conf.EphemeralKey:
+		return nil, ErrNoSigningKey
+	case conf.SigningKey == "":
+		var err error
+		if _, key, err = ed25519.GenerateKey(rand.Reader); err != nil {
+			return nil, fmt.Errorf("failed to generate signing key: %w", err)
+		}
+	default:
+		seed, err := base64.StdEncoding.DecodeString(conf.SigningKey)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("signing key must be base64 of a %d byte Ed25519 seed", ed25519.SeedSize)
+		}
+		key = ed25519.NewKeyFromSeed(seed)
+	}
+
+	s := &Signer{key: key, keys: []ed25519.PublicKey{key.Public().(ed25519.PublicKey)}}
+	for i, k := range conf.VerifyKeys {
+		pub, err := base64.StdEncoding.DecodeString(k)
+		if err != nil || len(pub) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("verify key %d must be base64 of a %d byte Ed25519 public key",
+				i, ed25519.PublicKeySize)
+		}
+		s.keys = append(s.keys, pub)
+	}
+	return s, nil
+}
+
+// PublicKeys returns the keys codes are verified with, the current one first.
+func (s *Signer) PublicKeys() []ed25519.PublicKey {
+	return append([]ed25519.PublicKey(nil), s.keys...)
+}
+
+// Sign returns the code of a ticket: URL-safe base64 of the version, the claims as varints and the signature.
+func (s *Signer) Sign(c Claims) string {
+	payload := []byte{version}
+	for _, v := range []int64{c.TicketID, c.OrderID, c.EventID, c.PlaceID} {
+		payload = binary.AppendUvarint(payload, uint64(v))
+	}
+	return base64.RawURLEncoding.EncodeToString(append(payload, ed25519.Sign(s.key, payload)...))
+}
+
+// Verify returns the claims of a code signed by any of the keys.
+func (s *Signer) Verify(code string) (Claims, error) {
+	return Verify(code, s.keys)
+}
+
+// Verify returns the claims of a code signed by any of the keys. It needs no storage, so scanners
+// holding the public keys can run it offline.
+func Verify(code string, keys []ed25519.PublicKey) (Claims, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(code)
+	if err != nil || len(raw) <= ed25519.SignatureSize {
+		return Claims{}, ErrMalformed
+	}
+	payload, sig := raw[:len(raw)-ed25519.SignatureSize], raw[len(raw)-ed25519.SignatureSize:]
+
+	signed := false
+	for _, k := range keys {
+		if ed25519.Verify(k, payload, sig) {
+			signed = true
+			break
+		}
+	}
+	if !signed {
+		return Claims{}, ErrSignature
+	}
+
+	if payload[0] != version {
+		return Claims{}, ErrMalformed
+	}
+	var values [4]int64
+	rest := payload[1:]
+	for i := range values {
+		v, n := binary.Uvarint(rest)
+		if n <= 0 {
+			return Claims{}, ErrMalformed
+		}
+		values[i] = int64(v)
+		rest = rest[n:]
+	}
+	if len(rest) != 0 {
+		return Claims{}, ErrMalformed
+	}
+	return Claims{TicketID: values[0], OrderID: values[1], EventID: values[2], PlaceID: values[3]}, nil
+}
+
+// QR returns a PNG of the code as a QR code size pixels wide.
+func QR(code string, size int) ([]byte, error) {
+	return qrcode.Encode(code, qrcode.Medium, size)
+}
@@ -0,0 +1,74 @@
+package tickets
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	s, err := NewSigner(Conf{EphemeralKey: true})
+	require.NoError(t, err)
+	claims := Claims{TicketID: 12345, OrderID: 678, EventID: 9, PlaceID: 1 << 40}
+
+	code := s.Sign(claims)
+	got, err := s.Verify(code)
+	require.NoError(t, err)
+	require.Equal(t, claims, got)
+
+	got, err = Verify(code, s.PublicKeys())
+	require.NoError(t, err, "public keys are enough to verify")
+	require.Equal(t, claims, got)
+
+	raw, err := base64.RawURLEncoding.DecodeString(code)
+	require.NoError(t, err)
+	raw[2] ^= 1
+	_, err = s.Verify(base64.RawURLEncoding.EncodeToString(raw))
+	require.ErrorIs(t, err, ErrSignature, "tampered claims")
+
+	_, err = s.Verify("not a code")
+	require.ErrorIs(t, err, ErrMalformed)
+	_, err = s.Verify(code[:20])
+	require.ErrorIs(t, err, ErrMalformed)
+
+	other, err := NewSigner(Conf{EphemeralKey: true})
+	require.NoError(t, err)
+	_, err = other.Verify(code)
+	require.ErrorIs(t, err, ErrSignature, "codes of another key")
+}
+
+func TestKeyRotation(t *testing.T) {
+	seed := bytes.Repeat([]byte{1}, ed25519.SeedSize)
+	old, err := NewSigner(Conf{SigningKey: base64.StdEncoding.EncodeToString(seed)})
+	require.NoError(t, err)
+	code := old.Sign(Claims{TicketID: 1, OrderID: 2, EventID: 3, PlaceID: 4})
+
+	current, err := NewSigner(Conf{
+		EphemeralKey: true,
+		VerifyKeys:   []string{base64.StdEncoding.EncodeToString(old.PublicKeys()[0])},
+	})
+	require.NoError(t, err)
+	_, err = current.Verify(code)
+	require.NoError(t, err, "codes of retired keys are accepted")
+
+	_, err = NewSigner(Conf{SigningKey: "c2hvcnQ="})
+	require.Error(t, err)
+	_, err = NewSigner(Conf{EphemeralKey: true, VerifyKeys: []string{"!"}})
+	require.Error(t, err)
+	_, err = NewSigner(Conf{})
+	require.ErrorIs(t, err, ErrNoSigningKey, "a random key has to be asked for")
+}
+
+func TestQR(t *testing.T) {
+	s, err := NewSigner(Conf{EphemeralKey: true})
+	require.NoError(t, err)
+	b, err := QR(s.Sign(Claims{TicketID: 1, OrderID: 1, EventID: 1, PlaceID: 1}), 256)
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(b))
+	require.NoError(t, err)
+	require.Equal(t, 256, img.Bounds().Dx())
+}
@@ -0,0 +1,111 @@
+// Package upstream fetches shows, events and places from the remote tickets API.
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// DefaultURL is the base URL of the remote API.
+const DefaultURL = "https://leadbook.ru/test-task-api"
+
+// Response is an undecoded upstream response.
+type Response struct {
+	// URL the body was fetched from.
+	URL        string `json:"url"`
+	StatusCode int    `json:"statusCode"`
+	Body       []byte `json:"body"`
+	// ETag and LastModified are the validators of the body, if the API sent any.
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	// Cached is set when the response was served from a cache or revalidated unchanged: its items were
+	// stored when it was fetched.
+	Cached bool `json:"-"`
+}
+
+// Provider fetches lists from the remote API.
+type Provider interface {
+	Shows(ctx context.Context) (Response, error)
+	Events(ctx context.Context, showID int64) (Response, error)
+	Places(ctx context.Context, eventID int64) (Response, error)
+}
+
+// Revalidator is a Provider fetching a response again with a conditional request.
+type Revalidator interface {
+	Revalidate(ctx context.Context, res Response) (Response, error)
+}
+
+// Client is the Provider speaking HTTP to the remote API.
+type Client struct {
+	baseURL string
+	hc      *http.Client
+}
+
+// NewClient returns a client of the API at baseURL, DefaultURL if empty.
+func NewClient(baseURL string, hc *http.Client) *Client {
+	if baseURL == "" {
+		baseURL = DefaultURL
+	}
+	return &Client{baseURL: baseURL, hc: hc}
+}
+
+func (c *Client) Shows(ctx context.Context) (Response, error) {
+	return c.get(ctx, Response{URL: c.baseURL + "/shows"})
+}
+
+func (c *Client) Events(ctx context.Context, showID int64) (Response, error) {
+	return c.get(ctx, Response{URL: c.baseURL + "/shows/" + strconv.FormatInt(showID, 10) + "/events"})
+}
+
+func (c *Client) Places(ctx context.Context, eventID int64) (Response, error) {
+	return c.get(ctx, Response{URL: c.baseURL + "/events/" + strconv.FormatInt(eventID, 10) + "/places"})
+}
+
+// Revalidate fetches res again sending its validators. If the API answers it is not modified, res is
+// returned marked Cached.
+func (c *Client) Revalidate(ctx context.Context, res Response) (Response, error) {
+	return c.get(ctx, res)
+}
+
+// get fetches prev.URL, conditionally if prev has validators.
+func (c *Client) get(ctx context.Context, prev Response) (Response, error) {
+	res := Response{URL: prev.URL}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, res.URL, nil)
+	if err != nil {
+		return res, fmt.Errorf("failed to create request: %w", err)
+	}
+	if prev.ETag != "" {
+		req.Header.Set("If-None-Match", prev.ETag)
+	}
+	if prev.LastModified != "" {
+		req.Header.Set("If-Modified-Since", prev.LastModified)
+	}
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return res, fmt.Errorf("failed to do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	res.ETag, res.LastModified = resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode == http.StatusNotModified && prev.StatusCode == http.StatusOK {
+		// A 304 may update the validators.
+		if res.ETag != "" {
+			prev.ETag = res.ETag
+		}
+		if res.LastModified != "" {
+			prev.LastModified = res.LastModified
+		}
+		prev.Cached = true
+		return prev, nil
+	}
+
+	res.StatusCode = resp.StatusCode
+	res.Body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return res, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,261 @@
+// Package webhooks delivers domain events to HTTP endpoints registered by integrators. Payloads are
+// signed with HMAC-SHA256 of the endpoint secret so receivers can tell they come from us.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/domain"
+	"github.com/cronnoss/tk-api/internal/storage/models"
+)
+
+// Headers of deliveries.
+const (
+	SignatureHeader = "Tk-Signature"
+	EventHeader     = "Tk-Event"
+	DeliveryHeader  = "Tk-Delivery"
+)
+
+const (
+	// maxDrain is how much of a response body is read so the connection can be reused.
+	maxDrain     = 64 << 10
+	secretPrefix = "whsec_"
+)
+
+var (
+	ErrMalformed = errors.New("malformed webhook signature")
+	ErrSignature = errors.New("invalid webhook signature")
+	ErrExpired   = errors.New("webhook signature is too old")
+	ErrNotPublic = errors.New("webhook endpoint address is not public")
+)
+
+// nonPublic are the special-purpose ranges netip.Addr has no predicate for.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// Public reports whether ip is routable on the internet: loopback, private, link-local (such as the
+// 169.254.169.254 metadata service) and other special-purpose addresses aren't.
+func Public(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, p := range nonPublic {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// dialPublic refuses connections to addresses that aren't Public. It runs after the host is resolved,
+// so names resolving to internal addresses are caught as well.
+func dialPublic(network, address string, _ syscall.RawConn) error {
+	addr, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s address %q: %w", network, address, err)
+	}
+	if !Public(addr.Addr()) {
+		return ErrNotPublic
+	}
+	return nil
+}
+
+// ValidType reports whether t is a known event type, see domain.Types.
+func ValidType(t string) bool {
+	return slices.Contains(domain.Types, t)
+}
+
+// Envelope is the body of a delivery. ID is the same for deliveries of an event to all endpoints,
+// receivers use it to drop duplicates.
+type Envelope struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewEnvelope wraps a domain event taken from the outbox.
+func NewEnvelope(e models.DomainEvent) Envelope {
+	return Envelope{
+		ID:        "evt_" + strconv.FormatInt(e.ID, 10),
+		Type:      e.Type,
+		CreatedAt: e.CreatedAt.UTC(),
+		Data:      e.Payload,
+	}
+}
+
+// Marshal returns the JSON body of an envelope.
+func (e Envelope) Marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// NewSecret returns a random endpoint secret.
+func NewSecret() (string, error) {
+	s, err := randomHex(24)
+	if err != nil {
+		return "", err
+	}
+	return secretPrefix + s, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Sign returns the signature header of a body sent at t: "t=<unix seconds>,v1=<hex HMAC>" where the HMAC
+// is of "<unix seconds>.<body>". The timestamp lets receivers reject replayed deliveries.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, ts, body))
+}
+
+func mac(secret, ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte{'.'})
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// Verify checks the signature header of a body received now, signatures older than tolerance are rejected.
+func Verify(secret, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	var ts string
+	var sigs [][]byte
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return ErrMalformed
+		}
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sig, err := hex.DecodeString(v)
+			if err != nil {
+				return ErrMalformed
+			}
+			sigs = append(sigs, sig)
+		}
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return ErrMalformed
+	}
+	if d := now.Sub(time.Unix(sec, 0)); d > tolerance || d < -tolerance {
+		return ErrExpired
+	}
+	want := mac(secret, ts, body)
+	for _, sig := range sigs {
+		if hmac.Equal(sig, want) {
+			return nil
+		}
+	}
+	return ErrSignature
+}
+
+// Policy is how failed deliveries are retried.
+type Policy struct {
+	// MaxAttempts is how many times a delivery is tried before it is dead-lettered.
+	MaxAttempts int `toml:"max-attempts"`
+	// RetryBase is the delay after the first failure, it doubles with every further one up to RetryMax.
+	RetryBase time.Duration `toml:"retry-base"`
+	RetryMax  time.Duration `toml:"retry-max"`
+}
+
+// Backoff returns the delay before retrying a delivery that failed attempts times.
+func (p Policy) Backoff(attempts int) time.Duration {
+	d := p.RetryBase
+	for i := 1; i < attempts && d < p.RetryMax; i++ {
+		d *= 2
+	}
+	return min(d, p.RetryMax)
+}
+
+// Result is the outcome of a delivery attempt. Response bodies aren't kept, they are up to endpoints
+// and may echo anything.
+type Result struct {
+	StatusCode int
+	Err        error
+	Duration   time.Duration
+}
+
+// OK reports whether the endpoint accepted the delivery with a 2xx status.
+func (r Result) OK() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Sender posts deliveries to endpoints.
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender returns a sender giving up on endpoints after timeout. Redirects aren't followed,
+// endpoints must answer themselves. Only public addresses are connected to unless allowPrivate is set,
+// e.g. for local development, and proxies aren't used.
+func NewSender(timeout time.Duration, allowPrivate bool) *Sender {
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = dialPublic
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &Sender{client: &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// Send posts the body of a delivery of an event type to url signed with secret.
+func (s *Sender) Send(ctx context.Context, url, secret string, deliveryID int64, eventType string, body []byte,
+) Result {
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Result{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tk-api-webhooks/1")
+	req.Header.Set(EventHeader, eventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(deliveryID, 10))
+	req.Header.Set(SignatureHeader, Sign(secret, start, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return Result{Err: err, Duration: time.Since(start)}
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrain))
+	return Result{StatusCode: resp.StatusCode, Duration: time.Since(start)}
+}
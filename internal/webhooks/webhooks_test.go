@@ -0,0 +1,100 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cronnoss/tk-api/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(secret, secretPrefix))
+	body := []byte(`{"id":"evt_1"}`)
+	now := time.Unix(1_800_000_000, 0)
+
+	sig := Sign(secret, now, body)
+	require.NoError(t, Verify(secret, sig, body, now.Add(time.Minute), 5*time.Minute))
+	require.ErrorIs(t, Verify(secret, sig, []byte(`{"id":"evt_2"}`), now, 5*time.Minute), ErrSignature)
+	require.ErrorIs(t, Verify("whsec_other", sig, body, now, 5*time.Minute), ErrSignature)
+	require.ErrorIs(t, Verify(secret, sig, body, now.Add(time.Hour), 5*time.Minute), ErrExpired, "replayed")
+	require.ErrorIs(t, Verify(secret, "v1=00", body, now, 5*time.Minute), ErrMalformed)
+	require.ErrorIs(t, Verify(secret, "t=1,v1=zz", body, now, 5*time.Minute), ErrMalformed)
+}
+
+func TestBackoff(t *testing.T) {
+	p := Policy{MaxAttempts: 8, RetryBase: 30 * time.Second, RetryMax: 5 * time.Minute}
+	require.Equal(t, 30*time.Second, p.Backoff(1))
+	require.Equal(t, time.Minute, p.Backoff(2))
+	require.Equal(t, 4*time.Minute, p.Backoff(4))
+	require.Equal(t, 5*time.Minute, p.Backoff(5))
+	require.Equal(t, 5*time.Minute, p.Backoff(60))
+}
+
+func TestSend(t *testing.T) {
+	secret := "whsec_test"
+	var got *http.Request
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("thanks"))
+	}))
+	defer srv.Close()
+	s := NewSender(time.Second, true)
+
+	body := []byte(`{"type":"order.paid"}`)
+	res := s.Send(context.Background(), srv.URL, secret, 7, domain.OrderPaid, body)
+	require.True(t, res.OK(), res.Err)
+	require.Equal(t, http.StatusAccepted, res.StatusCode)
+	require.Equal(t, body, gotBody)
+	require.Equal(t, domain.OrderPaid, got.Header.Get(EventHeader))
+	require.Equal(t, "7", got.Header.Get(DeliveryHeader))
+	require.NoError(t, Verify(secret, got.Header.Get(SignatureHeader), gotBody, time.Now(), time.Minute))
+
+	res = s.Send(context.Background(), srv.URL+"/moved", secret, 7, domain.OrderPaid, body)
+	require.False(t, res.OK(), "redirects aren't followed")
+	require.Equal(t, http.StatusFound, res.StatusCode)
+
+	res = NewSender(time.Second, false).Send(context.Background(), srv.URL, secret, 7, domain.OrderPaid, body)
+	require.ErrorIs(t, res.Err, ErrNotPublic, "loopback endpoints are refused")
+
+	srv.Close()
+	res = s.Send(context.Background(), srv.URL, secret, 7, domain.OrderPaid, body)
+	require.False(t, res.OK())
+	require.Error(t, res.Err)
+}
+
+func TestPublic(t *testing.T) {
+	for ip, public := range map[string]bool{
+		"93.184.216.34":   true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"::1":             false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"::ffff:10.0.0.1": false,
+		"224.0.0.1":       false,
+	} {
+		require.Equal(t, public, Public(netip.MustParseAddr(ip)), ip)
+	}
+}
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE idempotency_keys
+(
+    key           text                                   NOT NULL PRIMARY KEY,
+    method        text                                   NOT NULL,
+    path          text                                   NOT NULL,
+    fingerprint   text                                   NOT NULL,
+    status_code   integer                                NOT NULL DEFAULT 0,
+    content_type  text                                   NOT NULL DEFAULT '',
+    response_body bytea,
+    created_at    timestamp with time zone DEFAULT now() NOT NULL,
+    expires_at    timestamp with time zone               NOT NULL,
+    completed_at  timestamp with time zone
+);
+
+CREATE INDEX idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
+-- +goose StatementEnd
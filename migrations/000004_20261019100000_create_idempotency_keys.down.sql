@@ -0,0 +1,5 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE idempotency_keys;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE quarantined_items
+(
+    id         serial                                 NOT NULL PRIMARY KEY,
+    entity     text                                   NOT NULL,
+    source     text                                   NOT NULL,
+    payload    jsonb                                  NOT NULL,
+    errors     jsonb                                  NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+-- +goose StatementEnd
@@ -0,0 +1,5 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE quarantined_items;
+-- +goose StatementEnd
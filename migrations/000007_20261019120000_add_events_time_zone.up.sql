@@ -0,0 +1,7 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE events
+    ADD COLUMN time_zone text NOT NULL DEFAULT 'UTC';
+
+CREATE INDEX events_date_idx ON events (date);
+-- +goose StatementEnd
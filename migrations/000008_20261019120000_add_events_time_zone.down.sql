@@ -0,0 +1,8 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX events_date_idx;
+
+ALTER TABLE events
+    DROP COLUMN time_zone;
+-- +goose StatementEnd
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE venues
+(
+    id         serial                                 NOT NULL PRIMARY KEY,
+    name       text                                   NOT NULL,
+    address    text                                   NOT NULL DEFAULT '',
+    time_zone  text                                   NOT NULL DEFAULT 'UTC',
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone
+);
+
+CREATE TABLE halls
+(
+    id         serial                                 NOT NULL PRIMARY KEY,
+    venue_id   integer                                NOT NULL,
+    name       text                                   NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone,
+
+    FOREIGN KEY (venue_id) REFERENCES venues (id)
+);
+
+CREATE TABLE sections
+(
+    id         serial                                 NOT NULL PRIMARY KEY,
+    hall_id    integer                                NOT NULL,
+    name       text                                   NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone,
+
+    FOREIGN KEY (hall_id) REFERENCES halls (id)
+);
+
+ALTER TABLE events
+    ADD COLUMN hall_id integer REFERENCES halls (id);
+
+ALTER TABLE places
+    ADD COLUMN event_id    integer,
+    ADD COLUMN hall_id     integer REFERENCES halls (id),
+    ADD COLUMN section_id  integer REFERENCES sections (id),
+    ADD COLUMN row_label   text    NOT NULL DEFAULT '',
+    ADD COLUMN seat_number integer NOT NULL DEFAULT 0;
+
+CREATE INDEX places_event_id_idx ON places (event_id);
+CREATE INDEX places_hall_id_idx ON places (hall_id) WHERE event_id IS NULL;
+-- +goose StatementEnd
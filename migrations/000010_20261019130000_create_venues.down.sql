@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE places
+    DROP COLUMN seat_number,
+    DROP COLUMN row_label,
+    DROP COLUMN section_id,
+    DROP COLUMN hall_id,
+    DROP COLUMN event_id;
+
+ALTER TABLE events
+    DROP COLUMN hall_id;
+
+DROP TABLE sections;
+DROP TABLE halls;
+DROP TABLE venues;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE holds
+(
+    id          serial                                 NOT NULL PRIMARY KEY,
+    event_id    integer                                NOT NULL,
+    expires_at  timestamp with time zone               NOT NULL,
+    created_at  timestamp with time zone DEFAULT now() NOT NULL,
+    released_at timestamp with time zone,
+
+    FOREIGN KEY (event_id) REFERENCES events (id)
+);
+
+CREATE INDEX holds_expires_at_idx ON holds (expires_at) WHERE released_at IS NULL;
+
+CREATE TABLE hold_places
+(
+    hold_id  integer NOT NULL,
+    place_id integer NOT NULL,
+
+    PRIMARY KEY (hold_id, place_id),
+    FOREIGN KEY (hold_id) REFERENCES holds (id) ON DELETE CASCADE,
+    FOREIGN KEY (place_id) REFERENCES places (id) ON DELETE CASCADE
+);
+
+ALTER TABLE places
+    ADD COLUMN hold_id       integer REFERENCES holds (id),
+    ADD COLUMN is_accessible boolean NOT NULL DEFAULT false;
+
+CREATE INDEX places_hold_id_idx ON places (hold_id);
+-- +goose StatementEnd
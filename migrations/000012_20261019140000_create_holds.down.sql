@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE places
+    DROP COLUMN is_accessible,
+    DROP COLUMN hold_id;
+
+DROP TABLE hold_places;
+DROP TABLE holds;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- btree_gist lets event_id share GiST indexes with the place geometry.
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE INDEX places_event_bounds_idx ON places
+    USING gist (event_id, box(point(x, y), point(x + width, y + height)));
+CREATE INDEX places_event_center_idx ON places
+    USING gist (event_id, point(x + width / 2, y + height / 2)) WHERE is_available;
+-- +goose StatementEnd
@@ -0,0 +1,6 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX places_event_center_idx;
+DROP INDEX places_event_bounds_idx;
+-- +goose StatementEnd
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE price_categories
+(
+    id         serial                                 NOT NULL PRIMARY KEY,
+    event_id   integer                                NOT NULL,
+    name       text                                   NOT NULL,
+    amount     bigint                                 NOT NULL CHECK (amount >= 0),
+    currency   char(3)                                NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone,
+
+    FOREIGN KEY (event_id) REFERENCES events (id)
+);
+
+CREATE INDEX price_categories_event_id_idx ON price_categories (event_id);
+
+ALTER TABLE places
+    ADD COLUMN price_category_id integer REFERENCES price_categories (id) ON DELETE SET NULL;
+
+CREATE TABLE orders
+(
+    id         serial                                 NOT NULL PRIMARY KEY,
+    event_id   integer                                NOT NULL,
+    hold_id    integer UNIQUE,
+    status     text                                   NOT NULL,
+    currency   char(3)                                NOT NULL,
+    subtotal   bigint                                 NOT NULL,
+    total      bigint                                 NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone,
+
+    FOREIGN KEY (event_id) REFERENCES events (id),
+    FOREIGN KEY (hold_id) REFERENCES holds (id)
+);
+
+CREATE TABLE order_items
+(
+    id                serial  NOT NULL PRIMARY KEY,
+    order_id          integer NOT NULL,
+    place_id          integer NOT NULL,
+    price_category_id integer,
+    amount            bigint  NOT NULL,
+
+    FOREIGN KEY (order_id) REFERENCES orders (id),
+    FOREIGN KEY (place_id) REFERENCES places (id),
+    FOREIGN KEY (price_category_id) REFERENCES price_categories (id) ON DELETE SET NULL
+);
+
+CREATE INDEX order_items_order_id_idx ON order_items (order_id);
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE order_items;
+DROP TABLE orders;
+
+ALTER TABLE places
+    DROP COLUMN price_category_id;
+
+DROP TABLE price_categories;
+-- +goose StatementEnd
@@ -0,0 +1,64 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE promotions
+(
+    id                    serial                                 NOT NULL PRIMARY KEY,
+    code                  text                                   NOT NULL DEFAULT '',
+    name                  text                                   NOT NULL,
+    kind                  text                                   NOT NULL,
+    percent               integer                                NOT NULL DEFAULT 0 CHECK (percent BETWEEN 0 AND 100),
+    amount                bigint                                 NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    currency              text                                   NOT NULL DEFAULT '',
+    buy_n                 integer                                NOT NULL DEFAULT 0,
+    get_m                 integer                                NOT NULL DEFAULT 0,
+    show_id               integer REFERENCES shows (id),
+    event_id              integer REFERENCES events (id),
+    price_category_id     integer REFERENCES price_categories (id) ON DELETE CASCADE,
+    valid_from            timestamp with time zone,
+    valid_to              timestamp with time zone,
+    max_uses              integer                                NOT NULL DEFAULT 0,
+    max_uses_per_customer integer                                NOT NULL DEFAULT 0,
+    uses                  integer                                NOT NULL DEFAULT 0,
+    is_active             boolean                                NOT NULL DEFAULT true,
+    created_at            timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at            timestamp with time zone
+);
+
+CREATE UNIQUE INDEX promotions_code_idx ON promotions (code) WHERE code <> '';
+
+ALTER TABLE orders
+    ADD COLUMN customer_id integer;
+
+ALTER TABLE order_items
+    ADD COLUMN discount bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE order_discounts
+(
+    id           serial  NOT NULL PRIMARY KEY,
+    order_id     integer NOT NULL,
+    promotion_id integer NOT NULL,
+    code         text    NOT NULL,
+    name         text    NOT NULL,
+    description  text    NOT NULL,
+    amount       bigint  NOT NULL,
+
+    FOREIGN KEY (order_id) REFERENCES orders (id),
+    FOREIGN KEY (promotion_id) REFERENCES promotions (id)
+);
+
+CREATE INDEX order_discounts_order_id_idx ON order_discounts (order_id);
+
+CREATE TABLE promotion_redemptions
+(
+    promotion_id integer                                NOT NULL,
+    order_id     integer                                NOT NULL,
+    customer_id  integer,
+    created_at   timestamp with time zone DEFAULT now() NOT NULL,
+
+    PRIMARY KEY (promotion_id, order_id),
+    FOREIGN KEY (promotion_id) REFERENCES promotions (id),
+    FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+
+CREATE INDEX promotion_redemptions_customer_id_idx ON promotion_redemptions (customer_id, promotion_id);
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE promotion_redemptions;
+DROP TABLE order_discounts;
+
+ALTER TABLE order_items
+    DROP COLUMN discount;
+
+ALTER TABLE orders
+    DROP COLUMN customer_id;
+
+DROP TABLE promotions;
+-- +goose StatementEnd
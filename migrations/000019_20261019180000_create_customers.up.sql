@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE customers
+(
+    id              serial                                 NOT NULL PRIMARY KEY,
+    email           text                                   NOT NULL,
+    name            text                                   NOT NULL,
+    phone           text                                   NOT NULL DEFAULT '',
+    marketing_email boolean                                NOT NULL DEFAULT false,
+    marketing_sms   boolean                                NOT NULL DEFAULT false,
+    created_at      timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at      timestamp with time zone,
+    erased_at       timestamp with time zone
+);
+
+-- Erased customers have no email.
+CREATE UNIQUE INDEX customers_email_idx ON customers (lower(email)) WHERE email <> '';
+
+ALTER TABLE holds
+    ADD COLUMN customer_id integer REFERENCES customers (id);
+
+CREATE INDEX holds_customer_id_idx ON holds (customer_id);
+
+ALTER TABLE orders
+    ADD FOREIGN KEY (customer_id) REFERENCES customers (id);
+
+CREATE INDEX orders_customer_id_idx ON orders (customer_id);
+
+ALTER TABLE promotion_redemptions
+    ADD FOREIGN KEY (customer_id) REFERENCES customers (id);
+-- +goose StatementEnd
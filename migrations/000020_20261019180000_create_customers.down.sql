@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE promotion_redemptions
+    DROP CONSTRAINT promotion_redemptions_customer_id_fkey;
+
+DROP INDEX orders_customer_id_idx;
+
+ALTER TABLE orders
+    DROP CONSTRAINT orders_customer_id_fkey;
+
+ALTER TABLE holds
+    DROP COLUMN customer_id;
+
+DROP TABLE customers;
+-- +goose StatementEnd
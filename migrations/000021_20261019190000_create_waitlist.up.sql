@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE waitlist_entries
+(
+    id          serial                                 NOT NULL PRIMARY KEY,
+    event_id    integer                                NOT NULL,
+    customer_id integer                                NOT NULL,
+    quantity    integer                                NOT NULL CHECK (quantity > 0),
+    max_price   bigint                                 NOT NULL DEFAULT 0,
+    status      text                                   NOT NULL,
+    hold_id     integer,
+    created_at  timestamp with time zone DEFAULT now() NOT NULL,
+    offered_at  timestamp with time zone,
+    updated_at  timestamp with time zone,
+
+    FOREIGN KEY (event_id) REFERENCES events (id),
+    FOREIGN KEY (customer_id) REFERENCES customers (id),
+    FOREIGN KEY (hold_id) REFERENCES holds (id)
+);
+
+-- A customer waits for an event once at a time.
+CREATE UNIQUE INDEX waitlist_entries_waiting_idx ON waitlist_entries (event_id, customer_id)
+    WHERE status = 'waiting';
+CREATE INDEX waitlist_entries_queue_idx ON waitlist_entries (created_at, id) WHERE status = 'waiting';
+-- +goose StatementEnd
@@ -0,0 +1,5 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE waitlist_entries;
+-- +goose StatementEnd
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders
+    ADD COLUMN refunded bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE refunds
+(
+    id         serial                                 NOT NULL PRIMARY KEY,
+    order_id   integer                                NOT NULL,
+    amount     bigint                                 NOT NULL,
+    fee        bigint                                 NOT NULL,
+    reason     text                                   NOT NULL DEFAULT '',
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+
+    FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+
+CREATE INDEX refunds_order_id_idx ON refunds (order_id);
+
+CREATE TABLE exchanges
+(
+    id         serial                                 NOT NULL PRIMARY KEY,
+    order_id   integer                                NOT NULL,
+    event_id   integer                                NOT NULL,
+    difference bigint                                 NOT NULL,
+    fee        bigint                                 NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+
+    FOREIGN KEY (order_id) REFERENCES orders (id),
+    FOREIGN KEY (event_id) REFERENCES events (id)
+);
+
+CREATE INDEX exchanges_order_id_idx ON exchanges (order_id);
+
+ALTER TABLE order_items
+    ADD COLUMN event_id       integer REFERENCES events (id),
+    ADD COLUMN status         text    NOT NULL DEFAULT 'issued',
+    ADD COLUMN refund_id      integer REFERENCES refunds (id),
+    ADD COLUMN exchanged_from integer REFERENCES order_items (id);
+
+UPDATE order_items
+SET event_id = orders.event_id
+FROM orders
+WHERE orders.id = order_items.order_id;
+
+ALTER TABLE order_items
+    ALTER COLUMN event_id SET NOT NULL;
+
+CREATE TABLE audit_log
+(
+    id         serial                                 NOT NULL PRIMARY KEY,
+    entity     text                                   NOT NULL,
+    entity_id  integer                                NOT NULL,
+    action     text                                   NOT NULL,
+    details    jsonb                                  NOT NULL DEFAULT '{}',
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE INDEX audit_log_entity_idx ON audit_log (entity, entity_id, id);
+-- +goose StatementEnd
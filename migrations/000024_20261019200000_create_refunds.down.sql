@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE audit_log;
+
+ALTER TABLE order_items
+    DROP COLUMN exchanged_from,
+    DROP COLUMN refund_id,
+    DROP COLUMN status,
+    DROP COLUMN event_id;
+
+DROP TABLE exchanges;
+DROP TABLE refunds;
+
+ALTER TABLE orders
+    DROP COLUMN refunded;
+-- +goose StatementEnd
@@ -0,0 +1,5 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE order_items
+    ADD COLUMN used_at timestamp with time zone;
+-- +goose StatementEnd
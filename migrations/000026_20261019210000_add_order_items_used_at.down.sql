@@ -0,0 +1,6 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE order_items
+    DROP COLUMN used_at;
+-- +goose StatementEnd
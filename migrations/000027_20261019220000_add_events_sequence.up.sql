@@ -0,0 +1,6 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE events
+    ADD COLUMN sequence     integer NOT NULL DEFAULT 0,
+    ADD COLUMN cancelled_at timestamp with time zone;
+-- +goose StatementEnd
@@ -0,0 +1,7 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE events
+    DROP COLUMN cancelled_at,
+    DROP COLUMN sequence;
+-- +goose StatementEnd
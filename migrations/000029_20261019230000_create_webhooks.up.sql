@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE webhooks
+(
+    id         serial                                 NOT NULL PRIMARY KEY,
+    url        text                                   NOT NULL,
+    secret     text                                   NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+CREATE TABLE webhook_event_types
+(
+    webhook_id integer NOT NULL,
+    event_type text    NOT NULL,
+
+    PRIMARY KEY (webhook_id, event_type),
+    FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
+CREATE INDEX webhook_event_types_type_idx ON webhook_event_types (event_type);
+
+-- Deliveries are the outbox of webhooks, the dispatcher sends the pending ones when they are due.
+CREATE TABLE webhook_deliveries
+(
+    id              serial                                 NOT NULL PRIMARY KEY,
+    webhook_id      integer                                NOT NULL,
+    event_id        text                                   NOT NULL,
+    event_type      text                                   NOT NULL,
+    payload         jsonb                                  NOT NULL,
+    status          text                                   NOT NULL,
+    attempts        integer                                NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone               NOT NULL,
+    last_error      text                                   NOT NULL DEFAULT '',
+    created_at      timestamp with time zone DEFAULT now() NOT NULL,
+    delivered_at    timestamp with time zone,
+    updated_at      timestamp with time zone,
+
+    FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, id);
+
+CREATE TABLE webhook_attempts
+(
+    id          serial                                 NOT NULL PRIMARY KEY,
+    delivery_id integer                                NOT NULL,
+    status_code integer                                NOT NULL DEFAULT 0,
+    error       text                                   NOT NULL DEFAULT '',
+    duration_ms bigint                                 NOT NULL DEFAULT 0,
+    created_at  timestamp with time zone DEFAULT now() NOT NULL,
+
+    FOREIGN KEY (delivery_id) REFERENCES webhook_deliveries (id) ON DELETE CASCADE
+);
+CREATE INDEX webhook_attempts_delivery_idx ON webhook_attempts (delivery_id, id);
+-- +goose StatementEnd
@@ -0,0 +1,8 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE webhook_attempts;
+DROP TABLE webhook_deliveries;
+DROP TABLE webhook_event_types;
+DROP TABLE webhooks;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Domain events are the outbox of in-process subscribers, they are written in the transaction of the change
+-- they describe and dispatched in ID order.
+CREATE TABLE domain_events
+(
+    id            bigserial                              NOT NULL PRIMARY KEY,
+    type          text                                   NOT NULL,
+    payload       jsonb                                  NOT NULL,
+    created_at    timestamp with time zone DEFAULT now() NOT NULL,
+    claimed_until timestamp with time zone,
+    dispatched_at timestamp with time zone
+);
+CREATE INDEX domain_events_pending_idx ON domain_events (id) WHERE dispatched_at IS NULL;
+CREATE INDEX domain_events_dispatched_idx ON domain_events (dispatched_at) WHERE dispatched_at IS NOT NULL;
+
+-- An event dispatched again doesn't queue another delivery.
+CREATE UNIQUE INDEX webhook_deliveries_event_idx ON webhook_deliveries (webhook_id, event_id);
+-- +goose StatementEnd
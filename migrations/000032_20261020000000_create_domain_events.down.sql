@@ -0,0 +1,6 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX webhook_deliveries_event_idx;
+DROP TABLE domain_events;
+-- +goose StatementEnd
@@ -0,0 +1,6 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Headers of the stored response that are replayed along with it, e.g. Location and ETag.
+ALTER TABLE idempotency_keys
+    ADD COLUMN response_headers jsonb NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
@@ -0,0 +1,6 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE idempotency_keys
+    DROP COLUMN response_headers;
+-- +goose StatementEnd
@@ -0,0 +1,7 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Places synced from the remote API are stored by their IDs, places created here, hall layouts and
+-- their copies, take IDs from 1000000000 on so the two don't collide (models.FirstLocalPlaceID).
+SELECT setval(pg_get_serial_sequence('places', 'id'),
+              GREATEST(1000000000, (SELECT COALESCE(max(id), 0) + 1 FROM places)), false);
+-- +goose StatementEnd
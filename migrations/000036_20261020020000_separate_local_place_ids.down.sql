@@ -0,0 +1,6 @@
+-- +goose Up
+-- +goose Down
+-- +goose StatementBegin
+SELECT setval(pg_get_serial_sequence('places', 'id'),
+              (SELECT COALESCE(max(id), 0) + 1 FROM places WHERE id < 1000000000), false);
+-- +goose StatementEnd